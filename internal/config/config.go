@@ -17,6 +17,14 @@ type ColorConfig struct {
 	Queued  int `mapstructure:"queued"`
 }
 
+// NotifyConfig picks how and when gh-observer gets the user's attention;
+// see internal/notify for the backend and trigger names.
+type NotifyConfig struct {
+	Backends     []string `mapstructure:"backends"`
+	Triggers     []string `mapstructure:"triggers"`
+	MainBranches []string `mapstructure:"main_branches"`
+}
+
 type Config struct {
 	RefreshInterval     time.Duration     `mapstructure:"refresh_interval"`
 	RepoRefreshInterval time.Duration     `mapstructure:"repo_refresh_interval"`
@@ -26,13 +34,30 @@ type Config struct {
 	EnableLinks         bool              `mapstructure:"enable_links"`
 	PresumedAverages    map[string]string `mapstructure:"presumed_averages"`
 
+	// FetchConcurrency caps how many per-run history and job listings run
+	// at once across every view sharing the GitHub session.
+	FetchConcurrency int `mapstructure:"fetch_concurrency"`
+
+	// WebhookReconcileInterval is how often the TUI still polls while
+	// --webhook-listen delivers updates, to catch missed deliveries.
+	WebhookReconcileInterval time.Duration `mapstructure:"webhook_reconcile_interval"`
+
+	// ServeTargets are watched by `gh observer serve` in addition to any
+	// given on its command line; ServeListen overrides its socket path.
+	ServeTargets []string `mapstructure:"serve_targets"`
+	ServeListen  string   `mapstructure:"serve_listen"`
+
 	// Copilot code review detection (issue #409). When wait_for_copilot is
 	// true (default), the TUI gates exit on Copilot review completion in PR
 	// mode. The timing parameters mirror template-repo's wait_for_copilot.sh.
-	WaitForCopilot       bool          `mapstructure:"wait_for_copilot"`
-	CopilotMaxWait       time.Duration `mapstructure:"copilot_max_wait"`
-	CopilotPollInterval  time.Duration `mapstructure:"copilot_poll_interval"`
-	CopilotInitialDelay  time.Duration `mapstructure:"copilot_initial_delay"`
+	WaitForCopilot      bool          `mapstructure:"wait_for_copilot"`
+	CopilotMaxWait      time.Duration `mapstructure:"copilot_max_wait"`
+	CopilotPollInterval time.Duration `mapstructure:"copilot_poll_interval"`
+	CopilotInitialDelay time.Duration `mapstructure:"copilot_initial_delay"`
+
+	// Notify configures notifications. No backends (the default) means
+	// none are sent; --notify overrides the backends.
+	Notify NotifyConfig `mapstructure:"notify"`
 }
 
 func Load() (*Config, error) {
@@ -49,10 +74,15 @@ func Load() (*Config, error) {
 	v.SetDefault("colors.queued", 8)   // Gray
 	v.SetDefault("enable_links", true)
 	v.SetDefault("presumed_averages.DCO", "1s")
+	v.SetDefault("fetch_concurrency", 4)
+	v.SetDefault("webhook_reconcile_interval", "2m")
 	v.SetDefault("wait_for_copilot", true)
 	v.SetDefault("copilot_max_wait", "180s")
 	v.SetDefault("copilot_poll_interval", "10s")
 	v.SetDefault("copilot_initial_delay", "15s")
+	v.SetDefault("notify.backends", []string{})
+	v.SetDefault("notify.triggers", []string{"pr_done", "first_failure", "copilot_review", "main_failed"})
+	v.SetDefault("notify.main_branches", []string{"main", "master"})
 
 	// Config location: ~/.config/gh-observer/config.yaml
 	home, err := os.UserHomeDir()
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -44,6 +45,12 @@ func TestLoad_DefaultValues(t *testing.T) {
 	if cfg.EnableLinks != true {
 		t.Errorf("EnableLinks = %v, want true", cfg.EnableLinks)
 	}
+	if cfg.FetchConcurrency != 4 {
+		t.Errorf("FetchConcurrency = %d, want 4", cfg.FetchConcurrency)
+	}
+	if cfg.WebhookReconcileInterval != 2*time.Minute {
+		t.Errorf("WebhookReconcileInterval = %v, want 2m", cfg.WebhookReconcileInterval)
+	}
 }
 
 func TestLoad_CustomValues(t *testing.T) {
@@ -57,6 +64,11 @@ func TestLoad_CustomValues(t *testing.T) {
 
 	configContent := `refresh_interval: 30s
 enable_links: false
+fetch_concurrency: 8
+webhook_reconcile_interval: 5m
+serve_targets:
+  - octo/hello#12
+  - octo/infra
 colors:
   success: 2
   failure: 1
@@ -91,6 +103,15 @@ colors:
 	if cfg.EnableLinks != false {
 		t.Errorf("EnableLinks = %v, want false", cfg.EnableLinks)
 	}
+	if cfg.FetchConcurrency != 8 {
+		t.Errorf("FetchConcurrency = %d, want 8", cfg.FetchConcurrency)
+	}
+	if cfg.WebhookReconcileInterval != 5*time.Minute {
+		t.Errorf("WebhookReconcileInterval = %v, want 5m", cfg.WebhookReconcileInterval)
+	}
+	if want := []string{"octo/hello#12", "octo/infra"}; !slices.Equal(cfg.ServeTargets, want) {
+		t.Errorf("ServeTargets = %v, want %v", cfg.ServeTargets, want)
+	}
 }
 
 func TestLoad_PartialConfig(t *testing.T) {
@@ -400,3 +421,46 @@ copilot_initial_delay: 30s
 		t.Errorf("CopilotInitialDelay = %v, want 30s", cfg.CopilotInitialDelay)
 	}
 }
+
+func TestLoad_Notify(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(cfg.Notify.Backends) != 0 {
+		t.Errorf("Notify.Backends = %v, want none by default", cfg.Notify.Backends)
+	}
+	if want := []string{"pr_done", "first_failure", "copilot_review", "main_failed"}; !slices.Equal(cfg.Notify.Triggers, want) {
+		t.Errorf("Notify.Triggers = %v, want %v", cfg.Notify.Triggers, want)
+	}
+
+	configDir := filepath.Join(tmpDir, ".config", "gh-observer")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	configContent := `notify:
+  backends: [bell, desktop]
+  triggers: [first_failure, main_failed]
+  main_branches: [trunk]
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if want := []string{"bell", "desktop"}; !slices.Equal(cfg.Notify.Backends, want) {
+		t.Errorf("Notify.Backends = %v, want %v", cfg.Notify.Backends, want)
+	}
+	if want := []string{"first_failure", "main_failed"}; !slices.Equal(cfg.Notify.Triggers, want) {
+		t.Errorf("Notify.Triggers = %v, want %v", cfg.Notify.Triggers, want)
+	}
+	if want := []string{"trunk"}; !slices.Equal(cfg.Notify.MainBranches, want) {
+		t.Errorf("Notify.MainBranches = %v, want %v", cfg.Notify.MainBranches, want)
+	}
+}
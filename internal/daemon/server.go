@@ -0,0 +1,206 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+)
+
+const (
+	// defaultLongPoll is how long /v1/status?since=N waits for a change
+	// when the client doesn't say.
+	defaultLongPoll = 30 * time.Second
+
+	// maxLongPoll caps a client's ?timeout=.
+	maxLongPoll = 5 * time.Minute
+
+	// sseKeepAlive is how often an idle event stream gets a comment line,
+	// so proxies and clients don't time it out.
+	sseKeepAlive = 30 * time.Second
+)
+
+// NewHandler serves store:
+//
+//	GET /v1/status                  current snapshot
+//	GET /v1/status?since=N          long-poll: wait for a version newer
+//	                                than N (up to ?timeout=, default 30s)
+//	GET /v1/status?target=KEY       one target (e.g. owner/repo#123)
+//	GET /v1/events                  server-sent events, one "status"
+//	                                event per new version
+func NewHandler(store *Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		handleStatus(w, r, store)
+	})
+	mux.HandleFunc("GET /v1/events", func(w http.ResponseWriter, r *http.Request) {
+		handleEvents(w, r, store)
+	})
+	return mux
+}
+
+func handleStatus(w http.ResponseWriter, r *http.Request, store *Store) {
+	q := r.URL.Query()
+	key := q.Get("target")
+	if key != "" {
+		if _, ok := store.Get(key); !ok {
+			http.Error(w, "unknown target "+key, http.StatusNotFound)
+			return
+		}
+	}
+
+	snap := store.Snapshot()
+	if since := q.Get("since"); since != "" {
+		version, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			http.Error(w, "since must be a version number", http.StatusBadRequest)
+			return
+		}
+		timeout := defaultLongPoll
+		if t := q.Get("timeout"); t != "" {
+			if timeout, err = time.ParseDuration(t); err != nil || timeout < 0 {
+				http.Error(w, "timeout must be a duration such as 30s", http.StatusBadRequest)
+				return
+			}
+			timeout = min(timeout, maxLongPoll)
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		// A timeout isn't an error: the client gets the unchanged
+		// snapshot and asks again with the same version.
+		snap, _ = store.Wait(ctx, version)
+	}
+
+	if key != "" {
+		status, _ := store.Get(key)
+		writeJSON(w, struct {
+			Version uint64 `json:"version"`
+			TargetStatus
+		}{snap.Version, status})
+		return
+	}
+	writeJSON(w, snap)
+}
+
+func handleEvents(w http.ResponseWriter, r *http.Request, store *Store) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	// A reconnecting EventSource resumes from the last version it saw.
+	var since uint64
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		since, _ = strconv.ParseUint(id, 10, 64)
+	}
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), sseKeepAlive)
+		snap, err := store.Wait(ctx, since)
+		cancel()
+		switch {
+		case r.Context().Err() != nil:
+			return
+		case err != nil:
+			fmt.Fprint(w, ": keepalive\n\n")
+		default:
+			data, err := json.Marshal(snap)
+			if err != nil {
+				debug.Log("daemon event encode failed", "err", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: status\ndata: %s\n\n", snap.Version, data)
+			since = snap.Version
+		}
+		flusher.Flush()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		debug.Log("daemon response encode failed", "err", err)
+	}
+}
+
+// DefaultSocketPath is where the daemon listens when no address is given:
+// $XDG_RUNTIME_DIR/gh-observer.sock, or gh-observer.sock in the user cache
+// directory when that isn't set.
+func DefaultSocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gh-observer.sock"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-observer", "gh-observer.sock"), nil
+}
+
+// Listen opens addr: a host:port, which must be loopback since the status
+// includes private repos' check names, or otherwise a Unix socket path.
+// The socket is readable only by the current user. A stale socket left by
+// a daemon that didn't exit cleanly is replaced; a live one is an error.
+func Listen(addr string) (net.Listener, error) {
+	if host, _, err := net.SplitHostPort(addr); err == nil && !strings.Contains(addr, "/") {
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return nil, fmt.Errorf("refusing to serve on %s: only loopback addresses (127.0.0.1, [::1], localhost) are allowed", addr)
+		}
+		return net.Listen("tcp", addr)
+	}
+
+	if conn, err := net.Dial("unix", addr); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another daemon is already listening on %s", addr)
+	}
+	if err := os.Remove(addr); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("removing stale socket: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(addr), 0o700); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(addr, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve serves handler on ln until ctx is done, then shuts down. Requests
+// run under ctx, so long polls and event streams end with it rather than
+// holding the shutdown open.
+func Serve(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			srv.Close()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore() *Store {
+	return NewStore([]Target{{Owner: "octo", Repo: "hello", PR: 12}, {Owner: "octo", Repo: "infra"}})
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decoding %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestHandleStatus(t *testing.T) {
+	store := newTestStore()
+	store.Set(TargetStatus{Target: "octo/hello#12", PR: &PRStatus{Number: 12, Summary: Summary{State: StateSuccess}}})
+	srv := httptest.NewServer(NewHandler(store))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantTargets int
+	}{
+		{name: "all targets", query: "", wantStatus: http.StatusOK, wantTargets: 2},
+		{name: "since an older version returns at once", query: "?since=1", wantStatus: http.StatusOK, wantTargets: 2},
+		{name: "long poll times out unchanged", query: "?since=2&timeout=10ms", wantStatus: http.StatusOK, wantTargets: 2},
+		{name: "bad since", query: "?since=abc", wantStatus: http.StatusBadRequest},
+		{name: "bad timeout", query: "?since=2&timeout=soon", wantStatus: http.StatusBadRequest},
+		{name: "unknown target", query: "?target=octo/other", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var snap Snapshot
+			if got := getJSON(t, srv.URL+"/v1/status"+tt.query, &snap); got != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", got, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && (snap.Version != 2 || len(snap.Targets) != tt.wantTargets) {
+				t.Errorf("snapshot = %+v, want version 2 with %d targets", snap, tt.wantTargets)
+			}
+		})
+	}
+
+	t.Run("one target", func(t *testing.T) {
+		var one struct {
+			Version uint64 `json:"version"`
+			TargetStatus
+		}
+		if got := getJSON(t, srv.URL+"/v1/status?target=octo/hello%2312", &one); got != http.StatusOK {
+			t.Fatalf("status = %d", got)
+		}
+		if one.Version != 2 || one.Target != "octo/hello#12" || one.PR == nil || one.PR.Summary.State != StateSuccess {
+			t.Errorf("got %+v, want the green PR at version 2", one)
+		}
+	})
+
+	t.Run("long poll woken by a change", func(t *testing.T) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			store.Set(TargetStatus{Target: "octo/infra", Error: "boom"})
+		}()
+		var snap Snapshot
+		getJSON(t, srv.URL+"/v1/status?since=2&timeout=5s", &snap)
+		if snap.Version != 3 {
+			t.Errorf("version = %d, want 3 after the change", snap.Version)
+		}
+	})
+}
+
+func TestHandleEvents(t *testing.T) {
+	store := newTestStore()
+	srv := httptest.NewServer(NewHandler(store))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	nextEvent := func() (id string, snap Snapshot) {
+		t.Helper()
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &snap); err != nil {
+					t.Fatal(err)
+				}
+				return id, snap
+			}
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return "", snap
+	}
+
+	if id, snap := nextEvent(); id != "1" || snap.Version != 1 {
+		t.Errorf("first event id %q version %d, want the initial snapshot", id, snap.Version)
+	}
+	store.Set(TargetStatus{Target: "octo/hello#12", PR: &PRStatus{Number: 12, Summary: Summary{State: StatePending}}})
+	if id, snap := nextEvent(); id != "2" || snap.Targets[0].PR == nil {
+		t.Errorf("second event id %q = %+v, want the PR update", id, snap)
+	}
+}
+
+func TestListen(t *testing.T) {
+	t.Run("non-loopback refused", func(t *testing.T) {
+		for _, addr := range []string{"0.0.0.0:0", ":0", "example.com:80"} {
+			if ln, err := Listen(addr); err == nil {
+				ln.Close()
+				t.Errorf("Listen(%q) succeeded, want refusal", addr)
+			}
+		}
+	})
+
+	t.Run("loopback", func(t *testing.T) {
+		ln, err := Listen("127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ln.Close()
+	})
+
+	t.Run("unix socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "run", "gh-observer.sock")
+		ln, err := Listen(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("socket mode = %o, want 600", perm)
+		}
+		if second, err := Listen(path); err == nil {
+			second.Close()
+			t.Error("second Listen on a live socket succeeded")
+		}
+		ln.Close()
+
+		// A socket file left behind (the listener closing removes it, so
+		// recreate one) is replaced rather than failing the restart.
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		ln, err = Listen(path)
+		if err != nil {
+			t.Fatalf("Listen over a stale socket: %v", err)
+		}
+		ln.Close()
+	})
+}
@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
+)
+
+// Overall states reported in Summary.State.
+const (
+	StateNone    = "none"    // no checks yet
+	StatePending = "pending" // some checks haven't completed
+	StateSuccess = "success" // all completed, none failed
+	StateFailure = "failure" // at least one failed, even if others still run
+)
+
+// Check is one check run or status context.
+type Check struct {
+	Name        string     `json:"name"`
+	Workflow    string     `json:"workflow,omitempty"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	URL         string     `json:"url,omitempty"`
+}
+
+// Summary counts a PR's checks and reduces them to one State, which is
+// what a prompt or status bar usually shows.
+type Summary struct {
+	State   string `json:"state"`
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Running int    `json:"running"`
+	Queued  int    `json:"queued"`
+}
+
+// PRStatus is the state of one PR's checks.
+type PRStatus struct {
+	Number  int     `json:"number"`
+	Title   string  `json:"title,omitempty"`
+	HeadSHA string  `json:"head_sha,omitempty"`
+	Summary Summary `json:"summary"`
+	Checks  []Check `json:"checks"`
+}
+
+// Run is a workflow run not attached to a PR (pushes, schedules, manual
+// dispatches), as repo mode lists them.
+type Run struct {
+	ID         int64     `json:"id"`
+	Workflow   string    `json:"workflow"`
+	Title      string    `json:"title,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+	Event      string    `json:"event,omitempty"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RepoStatus is the state of a repo's open PRs and standalone runs, as
+// repo mode shows them: those still running or recently finished.
+// OmittedPRs counts open PRs left out to stay within the query budget;
+// they are the least active ones.
+type RepoStatus struct {
+	PRs        []PRStatus `json:"prs"`
+	OmittedPRs int        `json:"omitted_prs,omitempty"`
+	Runs       []Run      `json:"runs"`
+}
+
+// TargetStatus is the latest known state of one Target. Exactly one of PR
+// and Repo is set once the first poll succeeds. A failed poll keeps the
+// last good data and reports the failure in Error.
+type TargetStatus struct {
+	Target           string      `json:"target"`
+	CheckedAt        time.Time   `json:"checked_at,omitzero"`
+	Error            string      `json:"error,omitempty"`
+	RateLimitedUntil time.Time   `json:"rate_limited_until,omitzero"`
+	PR               *PRStatus   `json:"pr,omitempty"`
+	Repo             *RepoStatus `json:"repo,omitempty"`
+}
+
+// Snapshot is every target's status at one store version.
+type Snapshot struct {
+	Version uint64         `json:"version"`
+	Targets []TargetStatus `json:"targets"`
+}
+
+// checksFromRuns converts check runs to their JSON form, sorted by
+// workflow and name so the output doesn't churn with API ordering.
+func checksFromRuns(runs []ghclient.CheckRunInfo) []Check {
+	checks := make([]Check, 0, len(runs))
+	for _, r := range runs {
+		checks = append(checks, Check{
+			Name:        r.Name,
+			Workflow:    r.WorkflowName,
+			Status:      r.Status,
+			Conclusion:  r.Conclusion,
+			StartedAt:   r.StartedAt,
+			CompletedAt: r.CompletedAt,
+			URL:         r.DetailsURL,
+		})
+	}
+	slices.SortFunc(checks, func(a, b Check) int {
+		return cmp.Or(cmp.Compare(a.Workflow, b.Workflow), cmp.Compare(a.Name, b.Name))
+	})
+	return checks
+}
+
+// summarize counts checks by state. A failure wins over pending checks, as
+// the TUI's exit code does: the PR is already red.
+func summarize(checks []Check) Summary {
+	s := Summary{Total: len(checks)}
+	for _, c := range checks {
+		switch {
+		case c.Status == "in_progress":
+			s.Running++
+		case c.Status != "completed":
+			s.Queued++
+		case ghclient.FailureConclusion(c.Conclusion):
+			s.Failed++
+		default:
+			s.Passed++
+		}
+	}
+	switch {
+	case s.Failed > 0:
+		s.State = StateFailure
+	case s.Total == 0:
+		s.State = StateNone
+	case s.Running+s.Queued > 0:
+		s.State = StatePending
+	default:
+		s.State = StateSuccess
+	}
+	return s
+}
+
+// prStatus builds a PRStatus from its check runs.
+func prStatus(number int, title, headSHA string, runs []ghclient.CheckRunInfo) PRStatus {
+	checks := checksFromRuns(runs)
+	return PRStatus{Number: number, Title: title, HeadSHA: headSHA, Summary: summarize(checks), Checks: checks}
+}
+
+// repoStatus builds a RepoStatus from a repo's watch: the PRs and
+// standalone runs still worth showing, as repo mode lists them.
+func repoStatus(r watch.Repo) RepoStatus {
+	status := RepoStatus{PRs: []PRStatus{}, OmittedPRs: r.OmittedPRs, Runs: []Run{}}
+	for _, n := range r.PRNumbers() {
+		pr := r.PRs[n]
+		status.PRs = append(status.PRs, prStatus(n, pr.Title, pr.HeadSHA, slices.Concat(pr.CheckRuns, pr.ExtraCheckRuns)))
+	}
+	for _, run := range r.Runs {
+		status.Runs = append(status.Runs, Run{
+			ID:         run.RunID,
+			Workflow:   run.WorkflowName,
+			Title:      run.DisplayTitle,
+			Branch:     run.HeadBranch,
+			Event:      run.Event,
+			Status:     run.Status,
+			Conclusion: run.Conclusion,
+			CreatedAt:  run.CreatedAt,
+		})
+	}
+	slices.SortFunc(status.Runs, func(a, b Run) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return status
+}
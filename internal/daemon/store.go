@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Store holds each target's latest status under a version number that
+// increases whenever any of them changes, so clients can ask "anything
+// newer than what I have?" and block until there is.
+type Store struct {
+	mu      sync.Mutex
+	version uint64
+	targets map[string]TargetStatus
+	// changed is closed (and replaced) on every change, waking waiters.
+	changed chan struct{}
+}
+
+// NewStore returns a store listing targets with no data yet, at version 1
+// so a client starting from 0 gets the initial snapshot immediately.
+func NewStore(targets []Target) *Store {
+	s := &Store{version: 1, targets: make(map[string]TargetStatus), changed: make(chan struct{})}
+	for _, t := range targets {
+		s.targets[t.Key()] = TargetStatus{Target: t.Key()}
+	}
+	return s
+}
+
+// Set records a target's status. Only a change other than CheckedAt bumps
+// the version: a poll that finds nothing new doesn't wake anyone.
+func (s *Store) Set(status TargetStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.targets[status.Target]
+	s.targets[status.Target] = status
+	old.CheckedAt = status.CheckedAt
+	if reflect.DeepEqual(old, status) {
+		return
+	}
+	s.version++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Get returns one target's status.
+func (s *Store) Get(key string) (TargetStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.targets[key]
+	return status, ok
+}
+
+// Snapshot returns every target's status, sorted by key.
+func (s *Store) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshotLocked()
+}
+
+func (s *Store) snapshotLocked() Snapshot {
+	snap := Snapshot{Version: s.version, Targets: make([]TargetStatus, 0, len(s.targets))}
+	for _, status := range s.targets {
+		snap.Targets = append(snap.Targets, status)
+	}
+	slices.SortFunc(snap.Targets, func(a, b TargetStatus) int { return cmp.Compare(a.Target, b.Target) })
+	return snap
+}
+
+// Wait returns the first snapshot newer than version since, blocking until
+// there is one or ctx is done (then it returns ctx.Err() and the current
+// snapshot).
+func (s *Store) Wait(ctx context.Context, since uint64) (Snapshot, error) {
+	for {
+		s.mu.Lock()
+		snap, changed := s.snapshotLocked(), s.changed
+		s.mu.Unlock()
+		if snap.Version > since {
+			return snap, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return snap, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStoreVersioning(t *testing.T) {
+	targets := []Target{{Owner: "octo", Repo: "hello", PR: 12}, {Owner: "octo", Repo: "infra"}}
+	store := NewStore(targets)
+
+	snap := store.Snapshot()
+	if snap.Version != 1 || len(snap.Targets) != 2 || snap.Targets[0].Target != "octo/hello#12" {
+		t.Fatalf("initial snapshot = %+v, want version 1 listing both targets in key order", snap)
+	}
+
+	pending := TargetStatus{Target: "octo/hello#12", CheckedAt: time.Now(), PR: &PRStatus{Number: 12, Summary: Summary{State: StatePending}}}
+	store.Set(pending)
+	if v := store.Snapshot().Version; v != 2 {
+		t.Errorf("version after a change = %d, want 2", v)
+	}
+
+	pending.CheckedAt = pending.CheckedAt.Add(time.Minute)
+	store.Set(pending)
+	if v := store.Snapshot().Version; v != 2 {
+		t.Errorf("version after an unchanged poll = %d, want still 2", v)
+	}
+	if got, _ := store.Get("octo/hello#12"); !got.CheckedAt.Equal(pending.CheckedAt) {
+		t.Errorf("CheckedAt = %v, want the latest poll %v", got.CheckedAt, pending.CheckedAt)
+	}
+
+	store.Set(TargetStatus{Target: "octo/hello#12", PR: &PRStatus{Number: 12, Summary: Summary{State: StateSuccess}}})
+	if v := store.Snapshot().Version; v != 3 {
+		t.Errorf("version after the PR went green = %d, want 3", v)
+	}
+}
+
+func TestStoreWait(t *testing.T) {
+	store := NewStore([]Target{{Owner: "octo", Repo: "hello", PR: 12}})
+
+	snap, err := store.Wait(context.Background(), 0)
+	if err != nil || snap.Version != 1 {
+		t.Fatalf("Wait(0) = %d, %v; want the initial snapshot at once", snap.Version, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if snap, err := store.Wait(ctx, 1); err != context.DeadlineExceeded || snap.Version != 1 {
+		t.Errorf("Wait with no change = %d, %v; want the current snapshot and the context error", snap.Version, err)
+	}
+
+	done := make(chan Snapshot)
+	go func() {
+		snap, _ := store.Wait(context.Background(), 1)
+		done <- snap
+	}()
+	store.Set(TargetStatus{Target: "octo/hello#12", Error: "boom"})
+	select {
+	case snap := <-done:
+		if snap.Version != 2 || snap.Targets[0].Error != "boom" {
+			t.Errorf("woken with %+v, want version 2 carrying the error", snap)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait not woken by Set")
+	}
+}
@@ -0,0 +1,59 @@
+// Package daemon keeps a set of PRs and repos watched in the background
+// and serves their current state as JSON, so editors, shell prompts and
+// status bars can share one poller (and one rate limit) instead of each
+// running their own.
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// Target is something the daemon watches: a PR when PR is set, otherwise
+// every active PR and workflow run on the repo.
+type Target struct {
+	Owner string
+	Repo  string
+	PR    int
+}
+
+// IsPR reports whether the target is a single PR.
+func (t Target) IsPR() bool {
+	return t.PR != 0
+}
+
+// Key identifies the target in the API: "owner/repo#123" for a PR,
+// "owner/repo" for a repo.
+func (t Target) Key() string {
+	if t.IsPR() {
+		return fmt.Sprintf("%s/%s#%d", t.Owner, t.Repo, t.PR)
+	}
+	return t.Owner + "/" + t.Repo
+}
+
+// ParseTarget parses "owner/repo#123", a PR URL, "owner/repo", or a repo
+// URL.
+func ParseTarget(arg string) (Target, error) {
+	if owner, repo, pr, err := ghclient.ParsePRURL(arg); err == nil {
+		return Target{Owner: owner, Repo: repo, PR: pr}, nil
+	}
+	if slug, num, ok := strings.Cut(arg, "#"); ok {
+		pr, err := strconv.Atoi(num)
+		if err != nil || pr <= 0 {
+			return Target{}, fmt.Errorf("invalid target %q: PR number must be a positive integer", arg)
+		}
+		owner, repo, err := ghclient.ParseRepoArg(slug)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid target %q: %w", arg, err)
+		}
+		return Target{Owner: owner, Repo: repo, PR: pr}, nil
+	}
+	owner, repo, err := ghclient.ParseRepoArg(arg)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid target %q (expected owner/repo, owner/repo#123, or a PR or repo URL)", arg)
+	}
+	return Target{Owner: owner, Repo: repo}, nil
+}
@@ -0,0 +1,35 @@
+package daemon
+
+import "testing"
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Target
+		wantKey string
+		wantErr bool
+	}{
+		{arg: "octo/hello#12", want: Target{Owner: "octo", Repo: "hello", PR: 12}, wantKey: "octo/hello#12"},
+		{arg: "https://github.com/octo/hello/pull/12", want: Target{Owner: "octo", Repo: "hello", PR: 12}, wantKey: "octo/hello#12"},
+		{arg: "octo/hello", want: Target{Owner: "octo", Repo: "hello"}, wantKey: "octo/hello"},
+		{arg: "https://github.com/octo/hello", want: Target{Owner: "octo", Repo: "hello"}, wantKey: "octo/hello"},
+		{arg: "octo/hello#", wantErr: true},
+		{arg: "octo/hello#-3", wantErr: true},
+		{arg: "hello#12", wantErr: true},
+		{arg: "not a target", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := ParseTarget(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTarget(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want || got.Key() != tt.wantKey {
+				t.Errorf("ParseTarget(%q) = %+v (key %q), want %+v (key %q)", tt.arg, got, got.Key(), tt.want, tt.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
+)
+
+// Options are the polling settings shared by every watcher.
+type Options struct {
+	// PRInterval is the base interval a PR is polled at.
+	PRInterval time.Duration
+	// RepoInterval is the base interval a repo is polled at.
+	RepoInterval time.Duration
+	// FadeSuccess and FadeFailure are how long a repo's completed checks
+	// and runs stay listed.
+	FadeSuccess time.Duration
+	FadeFailure time.Duration
+	// Filter narrows every repo's PRs and runs.
+	Filter ghclient.RepoFilter
+}
+
+// Watch polls every target until ctx is done, publishing each result to
+// store. All watchers share api, and with it the session's rate limit
+// and fetch pool.
+func Watch(ctx context.Context, api ghclient.API, store *Store, targets []Target, opts Options) {
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Go(func() { watchTarget(ctx, api, store, t, opts) })
+	}
+	wg.Wait()
+}
+
+// feed is a target's watch, polled the way the TUI polls it.
+type feed interface {
+	Poll(ctx context.Context) error
+	quota() watch.Quota
+	nextPoll(base time.Duration, now time.Time) time.Duration
+	// fill sets status's PR or Repo from what the watch holds.
+	fill(status *TargetStatus)
+}
+
+type prFeed struct{ watch.PR }
+
+func (f *prFeed) quota() watch.Quota { return f.Quota }
+
+func (f *prFeed) nextPoll(base time.Duration, now time.Time) time.Duration {
+	return f.NextPoll(base, nil, now)
+}
+
+func (f *prFeed) fill(status *TargetStatus) {
+	pr := prStatus(f.Number, f.Title, f.HeadSHA, f.CheckRuns)
+	status.PR = &pr
+}
+
+type repoFeed struct{ watch.Repo }
+
+func (f *repoFeed) quota() watch.Quota { return f.Quota }
+
+func (f *repoFeed) nextPoll(base time.Duration, now time.Time) time.Duration {
+	return f.NextPoll(base, nil, 0, now)
+}
+
+func (f *repoFeed) fill(status *TargetStatus) {
+	repo := repoStatus(f.Repo)
+	status.Repo = &repo
+}
+
+// newFeed returns t's watch and its base poll interval. A PR's title and
+// head commit are refetched every poll, since the daemon outlives pushes.
+func newFeed(api ghclient.API, t Target, opts Options) (feed, time.Duration) {
+	if t.IsPR() {
+		pr := watch.NewPR(api, t.Owner, t.Repo, t.PR)
+		pr.RefreshInfo = true
+		return &prFeed{pr}, opts.PRInterval
+	}
+	repo := watch.NewRepo(api, t.Owner, t.Repo, opts.FadeSuccess, opts.FadeFailure)
+	repo.Filter = opts.Filter
+	return &repoFeed{repo}, opts.RepoInterval
+}
+
+// watchTarget is one target's poll loop. It polls at the watch's adaptive
+// interval, and holds off while rate limited or nearly out of quota.
+func watchTarget(ctx context.Context, api ghclient.API, store *Store, t Target, opts Options) {
+	f, base := newFeed(api, t, opts)
+	for {
+		err := f.Poll(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+		status, _ := store.Get(t.Key())
+		status.Target = t.Key()
+		status.CheckedAt = now
+		status.Error, status.RateLimitedUntil = "", time.Time{}
+		if f.quota().Received {
+			f.fill(&status)
+		}
+		if err != nil {
+			debug.Log("daemon poll failed", "target", t.Key(), "err", err)
+			status.Error = err.Error()
+		}
+		if wait := f.quota().Wait(api, now); wait > 0 {
+			status.RateLimitedUntil = now.Add(wait)
+		}
+		store.Set(status)
+
+		wait := max(f.nextPoll(base, now), f.quota().Hold(api, base, now))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
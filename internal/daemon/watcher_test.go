@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   Summary
+	}{
+		{name: "no checks", want: Summary{State: StateNone}},
+		{
+			name:   "pending",
+			checks: []Check{{Status: "completed", Conclusion: "success"}, {Status: "in_progress"}, {Status: "queued"}},
+			want:   Summary{State: StatePending, Total: 3, Passed: 1, Running: 1, Queued: 1},
+		},
+		{
+			name:   "failure while others run",
+			checks: []Check{{Status: "completed", Conclusion: "timed_out"}, {Status: "in_progress"}},
+			want:   Summary{State: StateFailure, Total: 2, Failed: 1, Running: 1},
+		},
+		{
+			name:   "success with skips",
+			checks: []Check{{Status: "completed", Conclusion: "success"}, {Status: "completed", Conclusion: "skipped"}},
+			want:   Summary{State: StateSuccess, Total: 2, Passed: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarize(tt.checks); got != tt.want {
+				t.Errorf("summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newFakeAPI(t *testing.T) (*fakegithub.Server, ghclient.API) {
+	t.Helper()
+	srv := fakegithub.New("octo", "hello")
+	t.Cleanup(srv.Close)
+	api, err := ghclient.NewSession("test-token", ghclient.WithEndpoints(srv.URL(), srv.GraphQLURL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, api
+}
+
+func TestPoll(t *testing.T) {
+	srv, api := newFakeAPI(t)
+	now := srv.Now()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", CreatedAt: now,
+		Jobs: []fakegithub.Job{{Name: "build", Duration: time.Minute}, {Name: "test", Duration: 5 * time.Minute, Conclusion: "failure"}},
+	})
+	srv.AddRun(fakegithub.Run{
+		ID: 200, WorkflowID: 8, Name: "Nightly", HeadSHA: "main1", HeadBranch: "main", Event: "schedule", CreatedAt: now,
+		Jobs: []fakegithub.Job{{Name: "soak", Duration: time.Hour}},
+	})
+	srv.Advance(2 * time.Minute)
+	ctx := context.Background()
+	opts := Options{FadeSuccess: time.Hour, FadeFailure: time.Hour}
+	poll := func(f feed) (TargetStatus, error) {
+		var status TargetStatus
+		err := f.Poll(ctx)
+		f.fill(&status)
+		return status, err
+	}
+
+	t.Run("pr", func(t *testing.T) {
+		f, _ := newFeed(api, Target{Owner: "octo", Repo: "hello", PR: 12}, opts)
+		status, err := poll(f)
+		if err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+		want := Summary{State: StatePending, Total: 2, Passed: 1, Running: 1}
+		if status.PR.Title != "Add widgets" || status.PR.Summary != want {
+			t.Errorf("PR = %q %+v, want %q %+v", status.PR.Title, status.PR.Summary, "Add widgets", want)
+		}
+		if got := f.nextPoll(time.Minute, srv.Now()); got != time.Minute {
+			t.Errorf("next poll in %v while running, want the base interval", got)
+		}
+
+		srv.Advance(5 * time.Minute)
+		if status, err = poll(f); err != nil {
+			t.Fatalf("second poll: %v", err)
+		}
+		if status.PR.Summary.State != StateFailure {
+			t.Errorf("state = %q, want failure", status.PR.Summary.State)
+		}
+		if got := f.nextPoll(time.Minute, srv.Now()); got != 2*time.Minute {
+			t.Errorf("next poll in %v once idle, want the slow interval", got)
+		}
+	})
+
+	t.Run("repo", func(t *testing.T) {
+		f, _ := newFeed(api, Target{Owner: "octo", Repo: "hello"}, opts)
+		status, err := poll(f)
+		if err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+		if status.Repo == nil || len(status.Repo.PRs) != 1 || status.Repo.PRs[0].Number != 12 {
+			t.Fatalf("repo = %+v, want PR 12", status.Repo)
+		}
+		if len(status.Repo.Runs) != 1 || status.Repo.Runs[0].Workflow != "Nightly" || status.Repo.Runs[0].Status == "completed" {
+			t.Errorf("runs = %+v, want the running Nightly run", status.Repo.Runs)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+// Package eventlog keeps the check and run completions repo mode observed
+// in a small state file, so a restarted watcher (or one that slept through
+// them) can still show what happened.
+package eventlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Limits on what the log keeps: the newest MaxEvents, none older than
+// MaxAge.
+const (
+	MaxEvents = 500
+	MaxAge    = 7 * 24 * time.Hour
+)
+
+// Event is one observed transition: a PR check or a branch run finishing.
+type Event struct {
+	At      time.Time `json:"at"`
+	Repo    string    `json:"repo"`    // owner/name
+	Subject string    `json:"subject"` // "PR #142", or the branch of a run
+	Name    string    `json:"name"`    // "CI / test", or the run's title
+	Outcome string    `json:"outcome"` // the check or run conclusion
+	URL     string    `json:"url,omitempty"`
+	// Key identifies the transition, so seeing it again on a later poll
+	// or after a restart doesn't record it twice.
+	Key string `json:"key"`
+}
+
+// Text describes the event without its time or repo: "PR #142 CI / test
+// failed".
+func (e Event) Text() string {
+	return fmt.Sprintf("%s %s %s", e.Subject, e.Name, OutcomeVerb(e.Outcome))
+}
+
+// OutcomeVerb is how a conclusion reads in an event, or "" for the ones
+// not worth an event (skipped, neutral, stale).
+func OutcomeVerb(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "succeeded"
+	case "failure":
+		return "failed"
+	case "timed_out":
+		return "timed out"
+	case "cancelled":
+		return "was cancelled"
+	case "action_required":
+		return "needs action"
+	case "startup_failure":
+		return "failed to start"
+	default:
+		return ""
+	}
+}
+
+// Log is the recorded events, oldest first, and the file they persist to.
+// It is safe for concurrent use. A nil *Log records nothing, so callers
+// don't have to check whether the state file could be opened.
+type Log struct {
+	mu     sync.Mutex
+	path   string
+	events []Event
+	keys   map[string]bool
+	// version counts changes; saved is the version last written.
+	version, saved uint64
+
+	// saveMu serializes saves, so a slow one can't land after a newer one.
+	saveMu sync.Mutex
+}
+
+// DefaultPath is where the log lives when no path is given:
+// $XDG_STATE_HOME/gh-observer/events.json, or events.json in the
+// gh-observer user cache directory when that isn't set.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "gh-observer", "events.json"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-observer", "events.json"), nil
+}
+
+// Open loads the log at path. A missing file is an empty log, created on
+// the first Save.
+func Open(path string) (*Log, error) {
+	l := &Log{path: path, keys: make(map[string]bool)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	l.add(events, time.Now())
+	l.saved = l.version
+	return l, nil
+}
+
+// Record adds the events not already in the log, dropping any past
+// MaxAge or beyond MaxEvents. It reports whether the log changed.
+func (l *Log) Record(now time.Time, events ...Event) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.add(events, now)
+}
+
+func (l *Log) add(events []Event, now time.Time) bool {
+	var added []string
+	for _, e := range events {
+		if l.keys[e.Key] || now.Sub(e.At) > MaxAge {
+			continue
+		}
+		l.events = append(l.events, e)
+		l.keys[e.Key] = true
+		added = append(added, e.Key)
+	}
+	if len(added) == 0 {
+		return false
+	}
+	slices.SortStableFunc(l.events, func(a, b Event) int { return a.At.Compare(b.At) })
+	if over := len(l.events) - MaxEvents; over > 0 {
+		l.events = l.events[over:]
+	}
+	if i := slices.IndexFunc(l.events, func(e Event) bool { return now.Sub(e.At) <= MaxAge }); i > 0 {
+		l.events = l.events[i:]
+	}
+	// Keep dropped keys out of the set, or it would grow without bound.
+	// A dropped event seen again is past MaxAge or older than MaxEvents
+	// newer ones, so it is dropped again, and doesn't count as a change.
+	clear(l.keys)
+	for _, e := range l.events {
+		l.keys[e.Key] = true
+	}
+	if !slices.ContainsFunc(added, func(key string) bool { return l.keys[key] }) {
+		return false
+	}
+	l.version++
+	return true
+}
+
+// Events returns the events that match keep, newest first. A nil keep
+// matches every event.
+func (l *Log) Events(keep func(Event) bool) []Event {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var events []Event
+	for _, e := range slices.Backward(l.events) {
+		if keep == nil || keep(e) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Save writes the log to its file, readable only by the current user. The
+// file is replaced atomically, so a watcher killed mid-write leaves the
+// previous state rather than a truncated one. Saves run one at a time,
+// and one finding its snapshot already written does nothing.
+func (l *Log) Save() error {
+	if l == nil {
+		return nil
+	}
+	l.saveMu.Lock()
+	defer l.saveMu.Unlock()
+	l.mu.Lock()
+	version := l.version
+	if version == l.saved {
+		l.mu.Unlock()
+		return nil
+	}
+	data, err := json.MarshalIndent(l.events, "", "  ")
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := l.write(data); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.saved = version
+	l.mu.Unlock()
+	return nil
+}
+
+// write replaces the log's file with data.
+func (l *Log) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".events-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
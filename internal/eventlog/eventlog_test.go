@@ -0,0 +1,151 @@
+package eventlog
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func event(key string, at time.Time) Event {
+	return Event{At: at, Repo: "octo/hello", Subject: "PR #1", Name: "CI / " + key, Outcome: "success", Key: key}
+}
+
+func keys(events []Event) []string {
+	var keys []string
+	for _, e := range events {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		have        []Event
+		record      []Event
+		later       time.Duration // how long after now to record
+		wantChanged bool
+		want        []string // newest first
+	}{
+		{
+			name:        "new events, newest first",
+			record:      []Event{event("b", now.Add(-time.Minute)), event("a", now.Add(-time.Hour))},
+			wantChanged: true,
+			want:        []string{"b", "a"},
+		},
+		{
+			name:   "already recorded",
+			have:   []Event{event("a", now.Add(-time.Hour))},
+			record: []Event{event("a", now.Add(-time.Hour))},
+			want:   []string{"a"},
+		},
+		{
+			name:   "past MaxAge",
+			record: []Event{event("old", now.Add(-MaxAge-time.Minute))},
+		},
+		{
+			name:        "ages out old events",
+			have:        []Event{event("a", now.Add(-MaxAge+time.Minute))},
+			record:      []Event{event("b", now.Add(time.Minute))},
+			later:       2 * time.Minute,
+			wantChanged: true,
+			want:        []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Log{keys: make(map[string]bool)}
+			l.add(tt.have, now)
+			if got := l.Record(now.Add(tt.later), tt.record...); got != tt.wantChanged {
+				t.Errorf("Record() = %v, want %v", got, tt.wantChanged)
+			}
+			if got := keys(l.Events(nil)); !slices.Equal(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordKeepsNewestMaxEvents(t *testing.T) {
+	now := time.Now()
+	l := &Log{keys: make(map[string]bool)}
+	var events []Event
+	for i := range MaxEvents + 10 {
+		events = append(events, event(fmt.Sprint(i), now.Add(time.Duration(i-MaxEvents-10)*time.Second)))
+	}
+	l.Record(now, events...)
+	got := l.Events(nil)
+	if len(got) != MaxEvents || got[0].Key != fmt.Sprint(MaxEvents+9) || got[len(got)-1].Key != "10" {
+		t.Fatalf("kept %d events, %s..%s, want %d, %d..10", len(got), got[0].Key, got[len(got)-1].Key, MaxEvents, MaxEvents+9)
+	}
+	// The ones dropped for being oldest stay dropped.
+	if l.Record(now, events[0]) {
+		t.Error("re-recording a dropped event changed the log")
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "events.json")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open(missing) error: %v", err)
+	}
+	now := time.Now()
+	l.Record(now, event("a", now.Add(-time.Minute)), event("b", now))
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if got := keys(reopened.Events(nil)); !slices.Equal(got, []string{"b", "a"}) {
+		t.Errorf("reopened events = %v, want [b a]", got)
+	}
+	if reopened.Record(now, event("a", now.Add(-time.Minute))) {
+		t.Error("reopened log re-recorded an event it had")
+	}
+	onlyA := reopened.Events(func(e Event) bool { return e.Key == "a" })
+	if len(onlyA) != 1 || onlyA[0].Text() != "PR #1 CI / a succeeded" {
+		t.Errorf("filtered events = %+v, want a", onlyA)
+	}
+}
+
+func TestSaveKeepsNewest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open(missing) error: %v", err)
+	}
+	now := time.Now()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		l.Record(now, event(fmt.Sprint(i), now.Add(time.Duration(i)*time.Second)))
+		wg.Go(func() {
+			if err := l.Save(); err != nil {
+				t.Errorf("Save() error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if got := len(reopened.Events(nil)); got != 20 {
+		t.Errorf("saved %d events, want all 20: an older snapshot landed last", got)
+	}
+}
+
+func TestNilLog(t *testing.T) {
+	var l *Log
+	if l.Record(time.Now(), event("a", time.Now())) || l.Events(nil) != nil || l.Save() != nil {
+		t.Error("nil log should record, list and save nothing")
+	}
+}
@@ -0,0 +1,544 @@
+package fakegithub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// graphQLRequest is the body githubv4 posts.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// handleGraphQL answers each query gh-observer sends, recognized by a field
+// unique to it; anything else gets a GraphQL error.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var op string
+	var data map[string]any
+	switch {
+	case strings.Contains(req.Query, "viewer{"):
+		op, data = "viewer", map[string]any{"viewer": map[string]any{"login": s.main.owner}}
+	case strings.Contains(req.Query, "search(") && strings.Contains(req.Query, "ISSUE"):
+		query, _ := req.Variables["query"].(string)
+		op, data = "searchPullRequests", s.searchPRsData(query, cursorVar(req.Variables["cursor"]))
+	case strings.Contains(req.Query, "search("):
+		query, _ := req.Variables["query"].(string)
+		op, data = "searchRepos", s.searchReposData(query, cursorVar(req.Variables["cursor"]))
+	case strings.Contains(req.Query, "pullRequests("):
+		op, data = "repoPullRequests", s.repoPullRequestsData(req.Query, req.Variables)
+	case strings.Contains(req.Query, "... on PullRequest"):
+		op, data = "repoPullRequestChecks", s.repoPullRequestChecksData(req.Query, req.Variables)
+	case strings.Contains(req.Query, "... on Commit"):
+		id, _ := req.Variables["id"].(string)
+		op, data = "commitContexts", s.commitContextsData(id, cursorVar(req.Variables["contextsCursor"]))
+	case strings.Contains(req.Query, "reviewRequests("):
+		op, data = "copilotReview", s.repoVar(req.Variables).copilotReviewData(intVar(req.Variables["prNumber"]))
+	case strings.Contains(req.Query, "statusCheckRollup"):
+		op, data = "pullRequest", s.repoVar(req.Variables).pullRequestData(intVar(req.Variables["prNumber"]), cursorVar(req.Variables["contextsCursor"]))
+	case strings.Contains(req.Query, "object(oid:"):
+		sha, _ := req.Variables["oid"].(string)
+		op, data = "commit", s.repoVar(req.Variables).commitData(sha)
+	case strings.Contains(req.Query, "node(id:"):
+		op, data = "workflowHistory", s.workflowHistoryData(req.Query, req.Variables)
+	}
+	if op == "" {
+		s.requests = append(s.requests, "POST /graphql unknown")
+		writeJSON(w, map[string]any{"errors": []any{map[string]any{"message": "fakegithub: unsupported query"}}})
+		return
+	}
+	s.requests = append(s.requests, "POST /graphql "+op)
+	data["rateLimit"] = map[string]any{
+		"cost":      1,
+		"remaining": s.remaining,
+		"resetAt":   s.reset.UTC().Format(time.RFC3339),
+	}
+	writeJSON(w, map[string]any{"data": data})
+}
+
+// repoVar is the repository named by the $owner and $repo variables of a
+// single-repository query. An unknown one is an empty repository, which
+// answers with null nodes as GitHub does.
+func (s *Server) repoVar(variables map[string]any) *Repo {
+	owner, _ := variables["owner"].(string)
+	name, _ := variables["repo"].(string)
+	if r := s.repo(owner, name); r != nil {
+		return r
+	}
+	return &Repo{s: s, owner: owner, name: name}
+}
+
+// intVar reads a numeric GraphQL variable (decoded as float64).
+func intVar(v any) int {
+	f, _ := v.(float64)
+	return int(f)
+}
+
+// pageSize is how many nodes the fake returns per connection page, as
+// GitHub does for first: 100.
+const pageSize = 100
+
+// cursorVar reads a cursor variable. The fake's cursors are the offset of
+// the next node; null (the first page) is 0.
+func cursorVar(v any) int {
+	str, _ := v.(string)
+	n, _ := strconv.Atoi(str)
+	return n
+}
+
+// page returns nodes[after:after+pageSize] and its pageInfo.
+func page(nodes []any, after int) ([]any, map[string]any) {
+	after = min(after, len(nodes))
+	end := min(after+pageSize, len(nodes))
+	return nodes[after:end], map[string]any{"hasNextPage": end < len(nodes), "endCursor": strconv.Itoa(end)}
+}
+
+// prNodeID and commitNodeID are the GraphQL node IDs the fake gives PRs
+// and their head commits. PR IDs name the repository, since PR numbers
+// repeat across repositories.
+func (r *Repo) prNodeID(number int) string {
+	return fmt.Sprintf("PR_%s/%s#%d", r.owner, r.name, number)
+}
+
+func commitNodeID(sha string) string {
+	return "C_" + sha
+}
+
+// prByNodeID finds the PR a prNodeID names.
+func (s *Server) prByNodeID(id string) (*Repo, PullRequest, bool) {
+	slug, numStr, ok := strings.Cut(strings.TrimPrefix(id, "PR_"), "#")
+	owner, name, _ := strings.Cut(slug, "/")
+	number, err := strconv.Atoi(numStr)
+	r := s.repo(owner, name)
+	if !ok || err != nil || r == nil {
+		return nil, PullRequest{}, false
+	}
+	pr, ok := r.prs[number]
+	return r, pr, ok
+}
+
+// prRuns returns the runs on the PR's head commit, newest first.
+func (r *Repo) prRuns(pr PullRequest) []Run {
+	var runs []Run
+	for _, run := range r.sortedRuns() {
+		if run.HeadSHA == pr.HeadSHA {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// contextNodes renders every check on the PR head as rollup nodes.
+func (r *Repo) contextNodes(pr PullRequest, repoQuery bool) []any {
+	nodes := []any{}
+	for _, run := range r.prRuns(pr) {
+		for _, j := range run.jobs(r.s.now) {
+			nodes = append(nodes, r.checkRunJSON(run, j, repoQuery))
+		}
+	}
+	return nodes
+}
+
+// contextsJSON renders the page of the PR head's rollup contexts after the
+// given offset.
+func (r *Repo) contextsJSON(pr PullRequest, repoQuery bool, after int) map[string]any {
+	nodes, info := page(r.contextNodes(pr, repoQuery), after)
+	return map[string]any{"nodes": nodes, "pageInfo": info}
+}
+
+// commitJSON renders the PR head commit with a page of its check rollup.
+// The GraphQL client rejects fields a query didn't select, so the shape
+// follows the query: the repo-wide one selects the commit's node ID and
+// oid but no annotations or workflow node IDs.
+func (r *Repo) commitJSON(pr PullRequest, repoQuery bool, after int) map[string]any {
+	commit := map[string]any{
+		"pushedDate":        pr.PushedAt.UTC().Format(time.RFC3339),
+		"committedDate":     pr.PushedAt.UTC().Format(time.RFC3339),
+		"statusCheckRollup": map[string]any{"contexts": r.contextsJSON(pr, repoQuery, after)},
+	}
+	if repoQuery {
+		commit["id"] = commitNodeID(pr.HeadSHA)
+		commit["oid"] = pr.HeadSHA
+	}
+	return commit
+}
+
+// rollupState is the head's overall check state as statusCheckRollup
+// reports it, or "" when it has no checks.
+func (r *Repo) rollupState(pr PullRequest) string {
+	state := ""
+	for _, run := range r.prRuns(pr) {
+		for _, j := range run.jobs(r.s.now) {
+			switch {
+			case j.status != "completed":
+				return "PENDING"
+			case j.conclusion == "failure" || j.conclusion == "timed_out":
+				state = "FAILURE"
+			case state == "":
+				state = "SUCCESS"
+			}
+		}
+	}
+	return state
+}
+
+// workflowNodeID is the GraphQL node ID the fake gives a workflow.
+func workflowNodeID(workflowID int64) string {
+	return fmt.Sprintf("W_%d", workflowID)
+}
+
+// checkRunJSON renders a job as a CheckRun rollup node.
+func (r *Repo) checkRunJSON(run Run, j jobState, repoQuery bool) map[string]any {
+	workflow := map[string]any{"id": workflowNodeID(run.WorkflowID), "databaseId": run.WorkflowID, "name": run.Name}
+	node := map[string]any{
+		"__typename":  "CheckRun",
+		"name":        j.job.Name,
+		"summary":     "",
+		"status":      strings.ToUpper(j.status),
+		"detailsUrl":  r.jobURL(run, j),
+		"annotations": map[string]any{"nodes": []any{}},
+		"checkSuite": map[string]any{
+			"workflowRun": map[string]any{
+				"databaseId": run.ID,
+				"workflow":   workflow,
+			},
+			"app": map[string]any{"name": "GitHub Actions", "slug": "github-actions"},
+		},
+	}
+	if j.startedAt != nil {
+		node["startedAt"] = j.startedAt.UTC().Format(time.RFC3339)
+	}
+	if j.completedAt != nil {
+		node["completedAt"] = j.completedAt.UTC().Format(time.RFC3339)
+		node["conclusion"] = strings.ToUpper(j.conclusion)
+	}
+	if repoQuery {
+		delete(node, "annotations")
+	}
+	return node
+}
+
+func (r *Repo) pullRequestData(number, after int) map[string]any {
+	pr, ok := r.prs[number]
+	if !ok {
+		return map[string]any{"repository": map[string]any{"pullRequest": nil}}
+	}
+	return map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+		"commits": map[string]any{"nodes": []any{map[string]any{"commit": r.commitJSON(pr, false, after)}}},
+	}}}
+}
+
+// repoListPattern matches each aliased repository in the batched open PR
+// listing, capturing the alias and its owner, name and cursor variables.
+var repoListPattern = regexp.MustCompile(`(\w+): ?repository\(owner: ?\$(\w+), ?name: ?\$(\w+)\)\{pullRequests\([^)]*?after: ?\$(\w+)`)
+
+// repoPullRequestsData answers the batched open PR listing: for each
+// aliased repository, a page of its PRs. An unknown repository is null, as
+// GitHub answers one the token can't see.
+func (s *Server) repoPullRequestsData(query string, variables map[string]any) map[string]any {
+	data := map[string]any{}
+	for _, m := range repoListPattern.FindAllStringSubmatch(query, -1) {
+		alias := m[1]
+		owner, _ := variables[m[2]].(string)
+		name, _ := variables[m[3]].(string)
+		r := s.repo(owner, name)
+		if r == nil {
+			data[alias] = nil
+			continue
+		}
+		data[alias] = map[string]any{"pullRequests": r.pullRequestsJSON(variables, cursorVar(variables[m[4]]))}
+	}
+	return data
+}
+
+// pullRequestsJSON renders a page of open PRs, most recently pushed first
+// (standing in for most recently updated, newest number breaking ties),
+// each with its author, draft flag and head's rollup state only. It honors
+// the labels (any of) and baseRefName arguments.
+func (r *Repo) pullRequestsJSON(variables map[string]any, after int) map[string]any {
+	labels, _ := variables["labels"].([]any)
+	base, _ := variables["baseRef"].(string)
+	all := []any{}
+	for _, pr := range r.sortedPRs() {
+		if base != "" && pr.Base != base {
+			continue
+		}
+		if len(labels) > 0 && !slices.ContainsFunc(labels, func(l any) bool {
+			name, _ := l.(string)
+			return slices.Contains(pr.Labels, name)
+		}) {
+			continue
+		}
+		all = append(all, r.listedPRJSON(pr))
+	}
+	nodes, info := page(all, after)
+	return map[string]any{"nodes": nodes, "pageInfo": info, "totalCount": len(all)}
+}
+
+// sortedPRs returns the open PRs most recently pushed first, as GitHub
+// orders them by update time.
+func (r *Repo) sortedPRs() []PullRequest {
+	prs := make([]PullRequest, 0, len(r.prs))
+	for _, pr := range r.prs {
+		prs = append(prs, pr)
+	}
+	slices.SortFunc(prs, func(a, b PullRequest) int {
+		if c := b.PushedAt.Compare(a.PushedAt); c != 0 {
+			return c
+		}
+		return b.Number - a.Number
+	})
+	return prs
+}
+
+// listedPRJSON is a PR as the listing and the PR search return it: node ID,
+// author, draft flag, update time and the head's rollup state.
+func (r *Repo) listedPRJSON(pr PullRequest) map[string]any {
+	var rollup any
+	if state := r.rollupState(pr); state != "" {
+		rollup = map[string]any{"state": state}
+	}
+	return map[string]any{
+		"id":        r.prNodeID(pr.Number),
+		"number":    pr.Number,
+		"isDraft":   pr.Draft,
+		"updatedAt": pr.PushedAt.UTC().Format(time.RFC3339),
+		"author":    map[string]any{"login": pr.Author},
+		"commits":   map[string]any{"nodes": []any{map[string]any{"commit": map[string]any{"statusCheckRollup": rollup}}}},
+	}
+}
+
+// repoPullRequestChecksData answers a batch of aliased PR nodes, from any
+// repositories, with each PR's title and the first page of its head's
+// check rollup.
+func (s *Server) repoPullRequestChecksData(query string, variables map[string]any) map[string]any {
+	data := map[string]any{}
+	for _, m := range nodeAliasPattern.FindAllStringSubmatch(query, -1) {
+		alias, variable := m[1], m[2]
+		nodeID, _ := variables[variable].(string)
+		r, pr, ok := s.prByNodeID(nodeID)
+		if !ok {
+			data[alias] = nil
+			continue
+		}
+		data[alias] = map[string]any{
+			"number":  pr.Number,
+			"title":   pr.Title,
+			"commits": map[string]any{"nodes": []any{map[string]any{"commit": r.commitJSON(pr, true, 0)}}},
+		}
+	}
+	return data
+}
+
+// commitContextsData answers a further page of a PR head's rollup.
+func (s *Server) commitContextsData(id string, after int) map[string]any {
+	sha, _ := strings.CutPrefix(id, "C_")
+	for _, r := range s.repos {
+		for _, pr := range r.prs {
+			if pr.HeadSHA == sha {
+				return map[string]any{"node": map[string]any{
+					"statusCheckRollup": map[string]any{"contexts": r.contextsJSON(pr, true, after)},
+				}}
+			}
+		}
+	}
+	return map[string]any{"node": nil}
+}
+
+// searchReposData answers a repository search for the org:NAME and
+// topic:NAME qualifiers, in the order repositories were added (standing in
+// for most recently updated). Other qualifiers are ignored.
+func (s *Server) searchReposData(query string, after int) map[string]any {
+	var org, topic string
+	for term := range strings.FieldsSeq(query) {
+		if v, ok := strings.CutPrefix(term, "org:"); ok {
+			org = v
+		}
+		if v, ok := strings.CutPrefix(term, "topic:"); ok {
+			topic = v
+		}
+	}
+	all := []any{}
+	for _, r := range s.repos {
+		if !strings.EqualFold(r.owner, org) || (topic != "" && !slices.Contains(r.topics, topic)) {
+			continue
+		}
+		all = append(all, map[string]any{"name": r.name, "owner": map[string]any{"login": r.owner}})
+	}
+	nodes, info := page(all, after)
+	return map[string]any{"search": map[string]any{
+		"repositoryCount": len(all), "nodes": nodes, "pageInfo": info,
+	}}
+}
+
+// searchPRsData answers an open PR search on the author:, review-requested:,
+// label:, base: and draft:false qualifiers, ignoring the rest.
+func (s *Server) searchPRsData(query string, after int) map[string]any {
+	var author, reviewer, base string
+	var labels []string
+	noDrafts := false
+	for term := range strings.FieldsSeq(query) {
+		key, value, _ := strings.Cut(term, ":")
+		switch key {
+		case "author":
+			author = value
+		case "review-requested":
+			reviewer = value
+		case "label":
+			for l := range strings.SplitSeq(value, ",") {
+				labels = append(labels, strings.Trim(l, `"`))
+			}
+		case "base":
+			base = value
+		case "draft":
+			noDrafts = value == "false"
+		}
+	}
+	if author == "@me" {
+		author = s.main.owner
+	}
+	if reviewer == "@me" {
+		reviewer = s.main.owner
+	}
+	all := []any{}
+	for _, r := range s.repos {
+		for _, pr := range r.sortedPRs() {
+			switch {
+			case author != "" && !strings.EqualFold(pr.Author, author),
+				reviewer != "" && !slices.ContainsFunc(pr.ReviewRequested, func(l string) bool { return strings.EqualFold(l, reviewer) }),
+				base != "" && pr.Base != base,
+				len(labels) > 0 && !slices.ContainsFunc(labels, func(l string) bool { return slices.Contains(pr.Labels, l) }),
+				noDrafts && pr.Draft:
+				continue
+			}
+			node := r.listedPRJSON(pr)
+			node["repository"] = map[string]any{"name": r.name, "owner": map[string]any{"login": r.owner}}
+			all = append(all, node)
+		}
+	}
+	nodes, info := page(all, after)
+	return map[string]any{"search": map[string]any{
+		"issueCount": len(all), "nodes": nodes, "pageInfo": info,
+	}}
+}
+
+func (r *Repo) copilotReviewData(number int) map[string]any {
+	reviews := []any{}
+	if pr, ok := r.prs[number]; ok && pr.CopilotReview != "" {
+		reviews = append(reviews, map[string]any{
+			"author":      map[string]any{"login": "copilot-pull-request-reviewer"},
+			"state":       pr.CopilotReview,
+			"submittedAt": r.s.now.UTC().Format(time.RFC3339),
+			"commit":      map[string]any{"oid": pr.HeadSHA},
+			"body":        "",
+		})
+	}
+	return map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+		"reviewRequests": map[string]any{"nodes": []any{}},
+		"reviews":        map[string]any{"nodes": reviews},
+	}}}
+}
+
+func (r *Repo) commitData(sha string) map[string]any {
+	var pushed time.Time
+	for _, pr := range r.prs {
+		if pr.HeadSHA == sha {
+			pushed = pr.PushedAt
+		}
+	}
+	for _, run := range r.runs {
+		if pushed.IsZero() && run.HeadSHA == sha {
+			pushed = run.pushedAt()
+		}
+	}
+	if pushed.IsZero() {
+		return map[string]any{"repository": map[string]any{"object": nil}}
+	}
+	return map[string]any{"repository": map[string]any{"object": map[string]any{
+		"pushedDate":    pushed.UTC().Format(time.RFC3339),
+		"committedDate": pushed.UTC().Format(time.RFC3339),
+	}}}
+}
+
+// nodeAliasPattern matches each aliased node in a batched query (workflow
+// history, repo PR checks), capturing the alias and its ID variable.
+var nodeAliasPattern = regexp.MustCompile(`(\w+): ?node\(id: ?\$(\w+)\)`)
+
+// workflowHistoryData answers the batched history query: for each aliased
+// workflow node, its 15 newest runs with every check run. Workflow IDs are
+// unique across the fake's repositories, as on GitHub.
+func (s *Server) workflowHistoryData(query string, variables map[string]any) map[string]any {
+	data := map[string]any{}
+	for _, m := range nodeAliasPattern.FindAllStringSubmatch(query, -1) {
+		alias, variable := m[1], m[2]
+		nodeID, _ := variables[variable].(string)
+		idStr, ok := strings.CutPrefix(nodeID, "W_")
+		workflowID, err := strconv.ParseInt(idStr, 10, 64)
+		r := s.workflowRepo(workflowID)
+		if !ok || err != nil || r == nil {
+			data[alias] = nil
+			continue
+		}
+
+		runs := []any{}
+		for _, run := range r.sortedRuns() {
+			if run.WorkflowID != workflowID {
+				continue
+			}
+			if len(runs) == 15 {
+				break
+			}
+			status, _, _ := run.status(s.now)
+			checkRuns := []any{}
+			for _, j := range run.jobs(s.now) {
+				cr := map[string]any{"name": j.job.Name, "status": strings.ToUpper(j.status)}
+				if j.startedAt != nil {
+					cr["startedAt"] = j.startedAt.UTC().Format(time.RFC3339)
+				}
+				if j.completedAt != nil {
+					cr["completedAt"] = j.completedAt.UTC().Format(time.RFC3339)
+					cr["conclusion"] = strings.ToUpper(j.conclusion)
+				}
+				checkRuns = append(checkRuns, cr)
+			}
+			runs = append(runs, map[string]any{
+				"databaseId": run.ID,
+				"createdAt":  run.CreatedAt.UTC().Format(time.RFC3339),
+				"checkSuite": map[string]any{
+					"status":    strings.ToUpper(status),
+					"commit":    map[string]any{"oid": run.HeadSHA},
+					"checkRuns": map[string]any{"nodes": checkRuns},
+				},
+			})
+		}
+		data[alias] = map[string]any{"runs": map[string]any{"nodes": runs}}
+	}
+	return data
+}
+
+// workflowRepo is the repository with runs of the given workflow, if any.
+func (s *Server) workflowRepo(workflowID int64) *Repo {
+	for _, r := range s.repos {
+		for _, run := range r.runs {
+			if run.WorkflowID == workflowID {
+				return r
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package fakegithub
+
+import (
+	"time"
+)
+
+// Job is a scripted workflow job, timed from its run's CreatedAt; a zero
+// Duration never finishes, and a Gate must open before it starts.
+type Job struct {
+	Name         string
+	QueuedAfter  time.Duration
+	StartedAfter time.Duration
+	Duration     time.Duration
+	Conclusion   string
+	Labels       []string
+	RunnerName   string
+	Gate         *Gate
+}
+
+// Gate is an environment's protection rules, holding a job "waiting" until
+// a reviewer approves (CanApprove: the token's user may) and WaitTimer ends.
+type Gate struct {
+	EnvironmentID int64
+	Environment   string
+	Reviewers     []string // logins, or "org/slug" for a team
+	WaitTimer     time.Duration
+	CanApprove    bool
+}
+
+// DeploymentReview is an approval or rejection posted for a run's waiting
+// deployment.
+type DeploymentReview struct {
+	EnvironmentID int64
+	State         string // "approved" or "rejected"
+	Comment       string
+	At            time.Time
+}
+
+// opens reports when the gate lets a job queued at queued through, and
+// whether that was a rejection. ok is false while it is still closed.
+func (g Gate) opens(run Run, queued time.Time) (at time.Time, rejected, ok bool) {
+	at = queued.Add(g.WaitTimer)
+	if len(g.Reviewers) == 0 {
+		return at, false, true
+	}
+	review, ok := run.reviews[g.EnvironmentID]
+	if !ok {
+		return time.Time{}, false, false
+	}
+	if review.State == "rejected" {
+		return review.At, true, true
+	}
+	return later(at, review.At), false, true
+}
+
+// later returns whichever of a and b is later.
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Run is a scripted workflow run, visible from CreatedAt. "pull_request"
+// runs on a PR's HeadSHA are its checks; past finished runs are history.
+type Run struct {
+	ID         int64
+	WorkflowID int64
+	Name       string
+	Path       string
+	HeadSHA    string
+	HeadBranch string
+	Event      string
+	Actor      string
+	CreatedAt  time.Time
+	PushedAt   time.Time
+	Jobs       []Job
+
+	// reviews are the deployment reviews posted for the run's gates, by
+	// environment ID. Shared across copies of the run.
+	reviews map[int64]DeploymentReview
+}
+
+// PullRequest is a scripted open pull request whose checks are the jobs of
+// every run on HeadSHA. Base defaults to "main".
+type PullRequest struct {
+	Number          int
+	Title           string
+	Author          string
+	Labels          []string
+	Base            string
+	Draft           bool
+	HeadSHA         string
+	CreatedAt       time.Time
+	PushedAt        time.Time
+	CopilotReview   string
+	ReviewRequested []string
+}
+
+// Runner is a scripted self-hosted runner, registered on a repository or
+// an organization.
+type Runner struct {
+	ID     int64
+	Name   string
+	OS     string
+	Online bool
+	Busy   bool
+	Labels []string
+}
+
+// jobState is a Job resolved against the clock.
+type jobState struct {
+	job         Job
+	id          int64
+	status      string
+	conclusion  string
+	createdAt   time.Time
+	startedAt   *time.Time
+	completedAt *time.Time
+}
+
+// state resolves the job at now, reporting false while it doesn't exist
+// yet.
+func (j Job) state(run Run, index int, now time.Time) (jobState, bool) {
+	created := run.CreatedAt.Add(j.QueuedAfter)
+	if now.Before(created) {
+		return jobState{}, false
+	}
+	s := jobState{job: j, id: run.ID*100 + int64(index) + 1, status: "queued", createdAt: created}
+
+	started := run.CreatedAt.Add(max(j.StartedAfter, j.QueuedAfter))
+	if j.Gate != nil {
+		opened, rejected, ok := j.Gate.opens(run, created)
+		if !ok || now.Before(opened) {
+			s.status = "waiting"
+			return s, true
+		}
+		if rejected {
+			s.status, s.conclusion, s.completedAt = "completed", "failure", &opened
+			return s, true
+		}
+		started = later(started, opened)
+	}
+	if now.Before(started) {
+		return s, true
+	}
+	s.status = "in_progress"
+	s.startedAt = &started
+
+	if j.Duration <= 0 {
+		return s, true
+	}
+	completed := started.Add(j.Duration)
+	if now.Before(completed) {
+		return s, true
+	}
+	s.status = "completed"
+	s.completedAt = &completed
+	s.conclusion = j.Conclusion
+	if s.conclusion == "" {
+		s.conclusion = "success"
+	}
+	return s, true
+}
+
+// jobs resolves every job of the run that exists at now.
+func (r Run) jobs(now time.Time) []jobState {
+	var states []jobState
+	for i, j := range r.Jobs {
+		if s, ok := j.state(r, i, now); ok {
+			states = append(states, s)
+		}
+	}
+	return states
+}
+
+// status derives the run's status, conclusion, and last-update time from
+// its jobs at now: queued until a job starts, waiting while any job waits
+// on a gate, completed once every job has.
+func (r Run) status(now time.Time) (status, conclusion string, updated time.Time) {
+	jobs := r.jobs(now)
+	status, updated = "queued", r.CreatedAt
+	if len(jobs) == 0 {
+		return status, "", updated
+	}
+	complete, started, waiting := true, false, false
+	conclusion = "success"
+	for _, j := range jobs {
+		for _, t := range []*time.Time{&j.createdAt, j.startedAt, j.completedAt} {
+			if t != nil && t.After(updated) {
+				updated = *t
+			}
+		}
+		if j.startedAt != nil {
+			started = true
+		}
+		if j.status == "waiting" {
+			waiting = true
+		}
+		if j.status != "completed" {
+			complete = false
+		} else if j.conclusion != "success" && j.conclusion != "skipped" && j.conclusion != "neutral" {
+			conclusion = "failure"
+		}
+	}
+	switch {
+	case complete && len(jobs) == len(r.Jobs):
+		return "completed", conclusion, updated
+	case waiting:
+		return "waiting", "", updated
+	case started:
+		return "in_progress", "", updated
+	}
+	return status, "", updated
+}
+
+// pushedAt is when the run's head commit was pushed.
+func (r Run) pushedAt() time.Time {
+	if r.PushedAt.IsZero() {
+		return r.CreatedAt
+	}
+	return r.PushedAt
+}
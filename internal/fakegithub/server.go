@@ -0,0 +1,668 @@
+// Package fakegithub fakes the GitHub REST and GraphQL APIs gh-observer
+// uses, with scripted PRs and runs on a clock tests advance.
+package fakegithub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is a fake GitHub serving the repository New created, plus any
+// added with AddRepo. Safe for concurrent use.
+type Server struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	now       time.Time
+	main      *Repo
+	repos     []*Repo
+	remaining int
+	reset     time.Time
+	requests  []string
+	// orgRunners are the organizations' runners, by lowercased login;
+	// an organization without an entry can't list them.
+	orgRunners map[string][]Runner
+}
+
+// Repo is one repository on a Server, holding its pull requests, runs and
+// files. The Server's own Add methods script the repository New created.
+type Repo struct {
+	s           *Server
+	owner, name string
+	topics      []string
+	prs         map[int]PullRequest
+	runs        map[int64]Run
+	files       map[string]string
+	runners     []Runner
+}
+
+// New starts a fake serving owner/repo with its clock at the current time
+// and a full rate limit quota resetting in an hour. Call Close when done.
+func New(owner, repo string) *Server {
+	now := time.Now().Truncate(time.Second)
+	s := &Server{
+		now:       now,
+		remaining: 5000,
+		reset:     now.Add(time.Hour),
+	}
+	s.main = s.AddRepo(owner, repo)
+
+	mux := http.NewServeMux()
+	prefix := "/repos/{owner}/{repo}"
+	mux.HandleFunc("GET "+prefix+"/pulls/{number}", s.handlePull)
+	mux.HandleFunc("GET "+prefix+"/actions/runs", s.handleRepoRuns)
+	mux.HandleFunc("GET "+prefix+"/actions/runs/{id}", s.handleRun)
+	mux.HandleFunc("GET "+prefix+"/actions/runs/{id}/jobs", s.handleRunJobs)
+	mux.HandleFunc("GET "+prefix+"/actions/runs/{id}/pending_deployments", s.handlePendingDeployments)
+	mux.HandleFunc("POST "+prefix+"/actions/runs/{id}/pending_deployments", s.handleReviewDeployments)
+	mux.HandleFunc("GET "+prefix+"/actions/workflows", s.handleWorkflows)
+	mux.HandleFunc("GET "+prefix+"/actions/workflows/{id}", s.handleWorkflow)
+	mux.HandleFunc("GET "+prefix+"/actions/workflows/{id}/runs", s.handleWorkflowRuns)
+	mux.HandleFunc("GET "+prefix+"/contents/{path...}", s.handleContents)
+	mux.HandleFunc("GET "+prefix+"/actions/runners", s.handleRepoRunners)
+	mux.HandleFunc("GET /orgs/{org}/actions/runners", s.handleOrgRunners)
+	mux.HandleFunc("POST /graphql", s.handleGraphQL)
+	s.srv = httptest.NewServer(s.wrap(mux))
+	return s
+}
+
+// AddRepo adds another repository, tagged with topics for organization
+// search, and returns it for scripting.
+func (s *Server) AddRepo(owner, name string, topics ...string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := &Repo{
+		s:      s,
+		owner:  owner,
+		name:   name,
+		topics: topics,
+		prs:    make(map[int]PullRequest),
+		runs:   make(map[int64]Run),
+		files:  make(map[string]string),
+	}
+	s.repos = append(s.repos, r)
+	return r
+}
+
+// repo looks up a repository by owner and name, as GitHub does ignoring
+// case. Called with the lock held.
+func (s *Server) repo(owner, name string) *Repo {
+	for _, r := range s.repos {
+		if strings.EqualFold(r.owner, owner) && strings.EqualFold(r.name, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL is the REST base URL, with the trailing slash go-github requires.
+func (s *Server) URL() string {
+	return s.srv.URL + "/"
+}
+
+// GraphQLURL is the GraphQL endpoint.
+func (s *Server) GraphQLURL() string {
+	return s.srv.URL + "/graphql"
+}
+
+// Now returns the fake clock's time.
+func (s *Server) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Advance moves the fake clock forward by d.
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// AddPullRequest adds or replaces an open pull request on the repository
+// New created; see Repo.AddPullRequest.
+func (s *Server) AddPullRequest(pr PullRequest) {
+	s.main.AddPullRequest(pr)
+}
+
+// AddRun adds or replaces a workflow run on the repository New created;
+// see Repo.AddRun.
+func (s *Server) AddRun(run Run) {
+	s.main.AddRun(run)
+}
+
+// AddFile serves content at path on the repository New created; see
+// Repo.AddFile.
+func (s *Server) AddFile(path, content string) {
+	s.main.AddFile(path, content)
+}
+
+// AddRunner registers a self-hosted runner on the repository New created;
+// see Repo.AddRunner.
+func (s *Server) AddRunner(runner Runner) {
+	s.main.AddRunner(runner)
+}
+
+// AddOrgRunners registers self-hosted runners on organization org. Until
+// it is called for an organization, listing that organization's runners
+// is refused, as for a token without admin access.
+func (s *Server) AddOrgRunners(org string, runners ...Runner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.orgRunners == nil {
+		s.orgRunners = make(map[string][]Runner)
+	}
+	org = strings.ToLower(org)
+	s.orgRunners[org] = append(s.orgRunners[org], runners...)
+}
+
+// AddPullRequest adds or replaces an open pull request. CreatedAt and
+// PushedAt default to the current fake time, and Base to "main".
+func (r *Repo) AddPullRequest(pr PullRequest) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if pr.Base == "" {
+		pr.Base = "main"
+	}
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = r.s.now
+	}
+	if pr.PushedAt.IsZero() {
+		pr.PushedAt = r.s.now
+	}
+	r.prs[pr.Number] = pr
+}
+
+// AddRun adds or replaces a workflow run. CreatedAt defaults to the current
+// fake time, Event to "pull_request", and Path to a file named after the
+// workflow ID.
+func (r *Repo) AddRun(run Run) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = r.s.now
+	}
+	if run.Event == "" {
+		run.Event = "pull_request"
+	}
+	if run.Path == "" {
+		run.Path = fmt.Sprintf(".github/workflows/workflow-%d.yml", run.WorkflowID)
+	}
+	run.reviews = make(map[int64]DeploymentReview)
+	if prev, ok := r.runs[run.ID]; ok {
+		run.reviews = prev.reviews
+	}
+	r.runs[run.ID] = run
+}
+
+// AddRunner registers a self-hosted runner on the repository.
+func (r *Repo) AddRunner(runner Runner) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.runners = append(r.runners, runner)
+}
+
+// AddFile serves content at path from the contents API (e.g. a workflow
+// file for critical path analysis).
+func (r *Repo) AddFile(path, content string) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.files[path] = content
+}
+
+// DeploymentReviews returns the deployment reviews posted for a run on the
+// repository New created, in environment ID order.
+func (s *Server) DeploymentReviews(runID int64) []DeploymentReview {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reviews := slices.Collect(maps.Values(s.main.runs[runID].reviews))
+	slices.SortFunc(reviews, func(a, b DeploymentReview) int { return int(a.EnvironmentID - b.EnvironmentID) })
+	return reviews
+}
+
+// SetRateLimit sets the quota reported on every response.
+func (s *Server) SetRateLimit(remaining int, reset time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remaining, s.reset = remaining, reset
+}
+
+// Requests returns every request served so far, as "METHOD /path" for REST
+// and "POST /graphql <operation>" for GraphQL, in order.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// wrap records the request and adds rate limit headers to every response.
+func (s *Server) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		if r.URL.Path != "/graphql" {
+			s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.reset.Unix(), 10))
+		s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handler is the signature of every REST handler: it runs with the lock
+// held on the repository the path names, and returns the JSON body, or an
+// error status and message.
+type handler func(repo *Repo, r *http.Request) (any, int, string)
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, h handler) {
+	s.mu.Lock()
+	repo := s.repo(r.PathValue("owner"), r.PathValue("repo"))
+	var body any
+	status, msg := http.StatusNotFound, "Not Found"
+	if repo != nil {
+		body, status, msg = h(repo, r)
+	}
+	s.mu.Unlock()
+	if status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+	writeJSON(w, body)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
+
+func (s *Server) handlePull(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		n, _ := strconv.Atoi(r.PathValue("number"))
+		pr, ok := repo.prs[n]
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		return map[string]any{
+			"number":     pr.Number,
+			"title":      pr.Title,
+			"state":      "open",
+			"created_at": pr.CreatedAt.UTC().Format(time.RFC3339),
+			"head":       map[string]any{"sha": pr.HeadSHA},
+		}, 0, ""
+	})
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		run, ok := repo.visibleRun(r.PathValue("id"))
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		return repo.runJSON(run), 0, ""
+	})
+}
+
+func (s *Server) handleRunJobs(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		run, ok := repo.visibleRun(r.PathValue("id"))
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		jobs := []any{}
+		for _, j := range run.jobs(repo.s.now) {
+			jobs = append(jobs, repo.jobJSON(run, j))
+		}
+		return map[string]any{"total_count": len(jobs), "jobs": jobs}, 0, ""
+	})
+}
+
+// handlePendingDeployments lists the gates the run's jobs are waiting on,
+// one per environment.
+func (s *Server) handlePendingDeployments(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		run, ok := repo.visibleRun(r.PathValue("id"))
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		deployments := []any{}
+		for _, j := range repo.waitingGates(run) {
+			deployments = append(deployments, repo.pendingDeploymentJSON(j))
+		}
+		return deployments, 0, ""
+	})
+}
+
+// handleReviewDeployments records an approval or rejection of the run's
+// waiting deployments. As on GitHub, every environment named must be one
+// the run is waiting on and the user can approve.
+func (s *Server) handleReviewDeployments(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		run, ok := repo.visibleRun(r.PathValue("id"))
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		var req struct {
+			EnvironmentIDs []int64 `json:"environment_ids"`
+			State          string  `json:"state"`
+			Comment        string  `json:"comment"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.EnvironmentIDs) == 0 ||
+			(req.State != "approved" && req.State != "rejected") {
+			return nil, http.StatusUnprocessableEntity, "Invalid request"
+		}
+		gates := make(map[int64]jobState)
+		for _, j := range repo.waitingGates(run) {
+			gates[j.job.Gate.EnvironmentID] = j
+		}
+		for _, id := range req.EnvironmentIDs {
+			j, ok := gates[id]
+			if !ok || !j.job.Gate.CanApprove {
+				return nil, http.StatusUnprocessableEntity, fmt.Sprintf("No pending deployment for environment %d that you can review", id)
+			}
+		}
+		deployments := []any{}
+		for _, id := range req.EnvironmentIDs {
+			run.reviews[id] = DeploymentReview{EnvironmentID: id, State: req.State, Comment: req.Comment, At: repo.s.now}
+			deployments = append(deployments, map[string]any{"id": id, "environment": gates[id].job.Gate.Environment})
+		}
+		return deployments, 0, ""
+	})
+}
+
+// handleRepoRunners lists the repository's self-hosted runners.
+func (s *Server) handleRepoRunners(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		return runnersJSON(repo.runners), 0, ""
+	})
+}
+
+// handleOrgRunners lists an organization's self-hosted runners, or refuses
+// for an organization none were registered on.
+func (s *Server) handleOrgRunners(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	runners, ok := s.orgRunners[strings.ToLower(r.PathValue("org"))]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusForbidden, "Resource not accessible by integration")
+		return
+	}
+	writeJSON(w, runnersJSON(runners))
+}
+
+func runnersJSON(runners []Runner) map[string]any {
+	list := []any{}
+	for _, r := range runners {
+		status := "offline"
+		if r.Online {
+			status = "online"
+		}
+		labels := []any{}
+		for _, l := range r.Labels {
+			labels = append(labels, map[string]any{"name": l, "type": "custom"})
+		}
+		list = append(list, map[string]any{
+			"id":     r.ID,
+			"name":   r.Name,
+			"os":     r.OS,
+			"status": status,
+			"busy":   r.Busy,
+			"labels": labels,
+		})
+	}
+	return map[string]any{"total_count": len(list), "runners": list}
+}
+
+// handleRepoRuns lists runs across the repo, honoring the status, created
+// (">=" RFC 3339), actor and branch filters repo mode sends.
+func (s *Server) handleRepoRuns(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		return repo.listRuns(r, 0), 0, ""
+	})
+}
+
+func (s *Server) handleWorkflowRuns(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		return repo.listRuns(r, id), 0, ""
+	})
+}
+
+func (s *Server) handleWorkflows(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		var workflows []any
+		for _, id := range repo.workflowIDs() {
+			workflows = append(workflows, repo.workflowJSON(id))
+		}
+		return map[string]any{"total_count": len(workflows), "workflows": workflows}, 0, ""
+	})
+}
+
+func (s *Server) handleWorkflow(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if !slices.Contains(repo.workflowIDs(), id) {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		return repo.workflowJSON(id), 0, ""
+	})
+}
+
+func (s *Server) handleContents(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, func(repo *Repo, r *http.Request) (any, int, string) {
+		path := r.PathValue("path")
+		content, ok := repo.files[path]
+		if !ok {
+			return nil, http.StatusNotFound, "Not Found"
+		}
+		return map[string]any{
+			"type":     "file",
+			"path":     path,
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		}, 0, ""
+	})
+}
+
+// visibleRun looks up a run that exists at the current fake time.
+func (r *Repo) visibleRun(idParam string) (Run, bool) {
+	id, _ := strconv.ParseInt(idParam, 10, 64)
+	run, ok := r.runs[id]
+	if !ok || r.s.now.Before(run.CreatedAt) {
+		return Run{}, false
+	}
+	return run, true
+}
+
+// sortedRuns returns the runs visible at the current fake time, newest
+// first, as the runs list endpoints order them.
+func (r *Repo) sortedRuns() []Run {
+	var runs []Run
+	for _, run := range r.runs {
+		if !r.s.now.Before(run.CreatedAt) {
+			runs = append(runs, run)
+		}
+	}
+	slices.SortFunc(runs, func(a, b Run) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return int(b.ID - a.ID)
+	})
+	return runs
+}
+
+// listRuns serves a runs list, restricted to workflowID when non-zero.
+func (r *Repo) listRuns(req *http.Request, workflowID int64) map[string]any {
+	q := req.URL.Query()
+	var since time.Time
+	if created, ok := strings.CutPrefix(q.Get("created"), ">="); ok {
+		since, _ = time.Parse(time.RFC3339, created)
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage <= 0 {
+		perPage = 30
+	}
+
+	runs := []any{}
+	for _, run := range r.sortedRuns() {
+		if len(runs) == perPage {
+			break
+		}
+		status, _, _ := run.status(r.s.now)
+		switch {
+		case workflowID != 0 && run.WorkflowID != workflowID:
+		case q.Get("status") != "" && q.Get("status") != status:
+		case q.Get("actor") != "" && q.Get("actor") != run.Actor:
+		case q.Get("branch") != "" && q.Get("branch") != run.HeadBranch:
+		case run.CreatedAt.Before(since):
+		default:
+			runs = append(runs, r.runJSON(run))
+		}
+	}
+	return map[string]any{"total_count": len(runs), "workflow_runs": runs}
+}
+
+// workflowIDs lists the distinct workflows that have runs, in ID order.
+func (r *Repo) workflowIDs() []int64 {
+	var ids []int64
+	for _, run := range r.runs {
+		if !slices.Contains(ids, run.WorkflowID) {
+			ids = append(ids, run.WorkflowID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (r *Repo) workflowJSON(id int64) map[string]any {
+	wf := map[string]any{"id": id, "state": "active"}
+	for _, run := range r.runs {
+		if run.WorkflowID == id {
+			wf["name"], wf["path"] = run.Name, run.Path
+			break
+		}
+	}
+	return wf
+}
+
+func (r *Repo) runJSON(run Run) map[string]any {
+	status, conclusion, updated := run.status(r.s.now)
+	j := map[string]any{
+		"id":             run.ID,
+		"name":           run.Name,
+		"display_title":  run.Name,
+		"path":           run.Path,
+		"head_sha":       run.HeadSHA,
+		"head_branch":    run.HeadBranch,
+		"event":          run.Event,
+		"actor":          map[string]any{"login": run.Actor},
+		"workflow_id":    run.WorkflowID,
+		"status":         status,
+		"run_attempt":    1,
+		"created_at":     run.CreatedAt.UTC().Format(time.RFC3339),
+		"run_started_at": run.CreatedAt.UTC().Format(time.RFC3339),
+		"updated_at":     updated.UTC().Format(time.RFC3339),
+		"head_commit": map[string]any{
+			"message":   run.Name,
+			"timestamp": run.pushedAt().UTC().Format(time.RFC3339),
+		},
+	}
+	if conclusion != "" {
+		j["conclusion"] = conclusion
+	}
+	return j
+}
+
+func (r *Repo) jobJSON(run Run, j jobState) map[string]any {
+	job := map[string]any{
+		"id":            j.id,
+		"run_id":        run.ID,
+		"run_attempt":   1,
+		"name":          j.job.Name,
+		"workflow_name": run.Name,
+		"head_sha":      run.HeadSHA,
+		"status":        j.status,
+		"created_at":    j.createdAt.UTC().Format(time.RFC3339),
+		"html_url":      r.jobURL(run, j),
+		"labels":        j.job.Labels,
+		"runner_name":   j.job.RunnerName,
+	}
+	if j.startedAt != nil {
+		job["started_at"] = j.startedAt.UTC().Format(time.RFC3339)
+	}
+	if j.completedAt != nil {
+		job["completed_at"] = j.completedAt.UTC().Format(time.RFC3339)
+		job["conclusion"] = j.conclusion
+	}
+	return job
+}
+
+// waitingGates returns one waiting job per environment the run is held at,
+// in the order the jobs are listed.
+func (r *Repo) waitingGates(run Run) []jobState {
+	var gated []jobState
+	seen := make(map[int64]bool)
+	for _, j := range run.jobs(r.s.now) {
+		if j.status != "waiting" || seen[j.job.Gate.EnvironmentID] {
+			continue
+		}
+		seen[j.job.Gate.EnvironmentID] = true
+		gated = append(gated, j)
+	}
+	return gated
+}
+
+// pendingDeploymentJSON is the pending deployment for a job waiting on its
+// gate, whose wait timer started when the job was queued.
+func (r *Repo) pendingDeploymentJSON(j jobState) map[string]any {
+	gate := j.job.Gate
+	reviewers := []any{}
+	for _, name := range gate.Reviewers {
+		if org, slug, ok := strings.Cut(name, "/"); ok {
+			reviewers = append(reviewers, map[string]any{
+				"type":     "Team",
+				"reviewer": map[string]any{"slug": slug, "name": slug, "organization": map[string]any{"login": org}},
+			})
+			continue
+		}
+		reviewers = append(reviewers, map[string]any{"type": "User", "reviewer": map[string]any{"login": name}})
+	}
+	return map[string]any{
+		"environment": map[string]any{
+			"id":       gate.EnvironmentID,
+			"name":     gate.Environment,
+			"html_url": fmt.Sprintf("https://github.com/%s/%s/deployments/%s", r.owner, r.name, gate.Environment),
+		},
+		"wait_timer":               int(gate.WaitTimer / time.Minute),
+		"wait_timer_started_at":    j.createdAt.UTC().Format(time.RFC3339),
+		"current_user_can_approve": gate.CanApprove,
+		"reviewers":                reviewers,
+	}
+}
+
+// jobURL is the job's html_url / check run detailsUrl, in the form
+// ParseRunIDFromURL understands.
+func (r *Repo) jobURL(run Run, j jobState) string {
+	return fmt.Sprintf("https://github.com/%s/%s/actions/runs/%d/job/%d", r.owner, r.name, run.ID, j.id)
+}
@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+// API is every GitHub operation gh-observer performs, plus the rate-limit
+// state it polls around. Session implements it.
+type API interface {
+	FetchPRInfo(ctx context.Context, owner, repo string, prNumber int) (*PRInfo, error)
+	FetchCheckRuns(ctx context.Context, owner, repo string, prNumber int) ([]CheckRunInfo, time.Time, int, error)
+	FetchCopilotReview(ctx context.Context, owner, repo string, prNumber int, headSHA string) (CopilotReview, int, error)
+
+	FetchRunInfo(ctx context.Context, owner, repo string, runID int64) (*RunInfo, int, error)
+	FetchRunJobs(ctx context.Context, owner, repo string, runID int64) ([]WorkflowJobInfo, int, error)
+	FetchWorkflowGraph(ctx context.Context, owner, repo, path, ref string) (*WorkflowGraph, error)
+	FetchPendingDeployments(ctx context.Context, owner, repo string, runID int64) ([]PendingDeployment, int, error)
+	ReviewPendingDeployments(ctx context.Context, owner, repo string, runID int64, environmentIDs []int64, approve bool, comment string) error
+
+	DiscoverWorkflows(ctx context.Context, owner, repo string, checkRuns []CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) (map[int64]int64, []int64, error)
+	FetchJobAverages(ctx context.Context, owner, repo string, checkRuns []CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) (map[string]time.Duration, map[int64]int64, []int64, error)
+	FetchWorkflowHistoryDetail(ctx context.Context, owner, repo string, workflowID int64) (*WorkflowHistory, error)
+	FetchWorkflowHistories(ctx context.Context, owner, repo string, workflows []WorkflowRef) (map[int64]*WorkflowHistory, map[int64]error)
+
+	FetchRepoCheckRuns(ctx context.Context, owner, repo string, filter RepoFilter) (prs map[int]PRCheckData, omitted int, rateLimitRemaining int, err error)
+	FetchReposCheckRuns(ctx context.Context, repos []RepoRef, filter RepoFilter) (map[RepoRef]RepoCheckRuns, int, error)
+	FindOrgRepos(ctx context.Context, org, topic string) (repos []RepoRef, total int, err error)
+	FetchMyPRCheckRuns(ctx context.Context, reviewRequested bool, filter RepoFilter) (map[RepoRef]RepoCheckRuns, int, error)
+	FetchRepoWorkflowRuns(ctx context.Context, owner, repo string, fadeWindow time.Duration, filter RepoFilter) ([]BranchRunData, int, error)
+	EnrichRepoRunsWithJobs(ctx context.Context, owner, repo string, runs []BranchRunData, cache *CompletedRunJobs) ([]BranchRunData, int, error)
+	FetchRepoRunners(ctx context.Context, owner, repo string) ([]Runner, int, error)
+	FetchOrgRunners(ctx context.Context, org string) ([]Runner, int, error)
+
+	ResolveWorkflow(ctx context.Context, owner, repo, workflow string) (Workflow, error)
+	FetchStatsRuns(ctx context.Context, owner, repo string, workflowID int64, since time.Time, maxRuns int) ([]StatsRun, error)
+
+	RateLimitPausedUntil(resources ...string) time.Time
+	RateLimitResetAt(resource string) time.Time
+	RateLimitResumeAt(err error) (time.Time, bool)
+}
+
+// FetchPRInfo implements API.
+func (s *Session) FetchPRInfo(ctx context.Context, owner, repo string, prNumber int) (*PRInfo, error) {
+	return FetchPRInfo(ctx, s.rest, owner, repo, prNumber)
+}
+
+// FetchCheckRuns implements API; see fetchCheckRunsGraphQL.
+func (s *Session) FetchCheckRuns(ctx context.Context, owner, repo string, prNumber int) ([]CheckRunInfo, time.Time, int, error) {
+	return fetchCheckRunsGraphQL(ctx, s.graphql, owner, repo, prNumber)
+}
+
+// FetchCopilotReview implements API.
+func (s *Session) FetchCopilotReview(ctx context.Context, owner, repo string, prNumber int, headSHA string) (CopilotReview, int, error) {
+	return fetchCopilotReview(ctx, s.graphql, owner, repo, prNumber, headSHA)
+}
+
+// FetchRunInfo implements API.
+func (s *Session) FetchRunInfo(ctx context.Context, owner, repo string, runID int64) (*RunInfo, int, error) {
+	return fetchRunInfo(ctx, s.rest, s.graphql, owner, repo, runID)
+}
+
+// FetchRunJobs implements API.
+func (s *Session) FetchRunJobs(ctx context.Context, owner, repo string, runID int64) ([]WorkflowJobInfo, int, error) {
+	return FetchRunJobs(ctx, s.rest, owner, repo, runID)
+}
+
+// FetchWorkflowGraph implements API.
+func (s *Session) FetchWorkflowGraph(ctx context.Context, owner, repo, path, ref string) (*WorkflowGraph, error) {
+	return FetchWorkflowGraph(ctx, s.rest, owner, repo, path, ref)
+}
+
+// FetchPendingDeployments implements API.
+func (s *Session) FetchPendingDeployments(ctx context.Context, owner, repo string, runID int64) ([]PendingDeployment, int, error) {
+	return FetchPendingDeployments(ctx, s.rest, owner, repo, runID)
+}
+
+// ReviewPendingDeployments implements API.
+func (s *Session) ReviewPendingDeployments(ctx context.Context, owner, repo string, runID int64, environmentIDs []int64, approve bool, comment string) error {
+	return ReviewPendingDeployments(ctx, s.rest, owner, repo, runID, environmentIDs, approve, comment)
+}
+
+// DiscoverWorkflows implements API.
+func (s *Session) DiscoverWorkflows(ctx context.Context, owner, repo string, checkRuns []CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) (map[int64]int64, []int64, error) {
+	return DiscoverWorkflows(ctx, s.rest, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
+}
+
+// FetchJobAverages implements API.
+func (s *Session) FetchJobAverages(ctx context.Context, owner, repo string, checkRuns []CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) (map[string]time.Duration, map[int64]int64, []int64, error) {
+	return FetchJobAverages(ctx, s.rest, s.pool, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
+}
+
+// FetchWorkflowHistoryDetail implements API.
+func (s *Session) FetchWorkflowHistoryDetail(ctx context.Context, owner, repo string, workflowID int64) (*WorkflowHistory, error) {
+	return FetchWorkflowHistoryDetail(ctx, s.rest, s.pool, owner, repo, workflowID)
+}
+
+// FetchWorkflowHistories implements API; see fetchWorkflowHistories.
+func (s *Session) FetchWorkflowHistories(ctx context.Context, owner, repo string, workflows []WorkflowRef) (map[int64]*WorkflowHistory, map[int64]error) {
+	return fetchWorkflowHistories(ctx, s.rest, s.graphql, s.pool, owner, repo, workflows)
+}
+
+// FetchRepoCheckRuns implements API; see fetchRepoCheckRunsGraphQL.
+func (s *Session) FetchRepoCheckRuns(ctx context.Context, owner, repo string, filter RepoFilter) (map[int]PRCheckData, int, int, error) {
+	filter, err := s.resolveRepoFilter(ctx, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	ref := RepoRef{Owner: owner, Name: repo}
+	result, rateLimit, err := fetchRepoCheckRunsGraphQL(ctx, s.graphql, []RepoRef{ref}, filter, repoQueryCostBudget)
+	if err != nil {
+		return nil, 0, rateLimit, err
+	}
+	return result[ref].PRs, result[ref].Omitted, rateLimit, nil
+}
+
+// FetchReposCheckRuns implements API: FetchRepoCheckRuns across repos in
+// one set of batched queries.
+func (s *Session) FetchReposCheckRuns(ctx context.Context, repos []RepoRef, filter RepoFilter) (map[RepoRef]RepoCheckRuns, int, error) {
+	filter, err := s.resolveRepoFilter(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fetchRepoCheckRunsGraphQL(ctx, s.graphql, repos, filter, reposQueryCostBudget(len(repos)))
+}
+
+// FindOrgRepos implements API; see findOrgRepos.
+func (s *Session) FindOrgRepos(ctx context.Context, org, topic string) ([]RepoRef, int, error) {
+	return findOrgRepos(ctx, s.graphql, org, topic, MaxDashboardRepos)
+}
+
+// FetchMyPRCheckRuns implements API; see fetchMyPRCheckRuns. The search
+// lists every repo at once, so the budget is a single repo's.
+func (s *Session) FetchMyPRCheckRuns(ctx context.Context, reviewRequested bool, filter RepoFilter) (map[RepoRef]RepoCheckRuns, int, error) {
+	return fetchMyPRCheckRuns(ctx, s.graphql, reviewRequested, filter, repoQueryCostBudget)
+}
+
+// FetchRepoWorkflowRuns implements API.
+func (s *Session) FetchRepoWorkflowRuns(ctx context.Context, owner, repo string, fadeWindow time.Duration, filter RepoFilter) ([]BranchRunData, int, error) {
+	filter, err := s.resolveRepoFilter(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return FetchRepoWorkflowRuns(ctx, s.rest, owner, repo, fadeWindow, filter)
+}
+
+// EnrichRepoRunsWithJobs implements API.
+func (s *Session) EnrichRepoRunsWithJobs(ctx context.Context, owner, repo string, runs []BranchRunData, cache *CompletedRunJobs) ([]BranchRunData, int, error) {
+	return EnrichRepoRunsWithJobs(ctx, s.rest, s.pool, owner, repo, runs, cache)
+}
+
+// FetchRepoRunners implements API.
+func (s *Session) FetchRepoRunners(ctx context.Context, owner, repo string) ([]Runner, int, error) {
+	return FetchRepoRunners(ctx, s.rest, owner, repo)
+}
+
+// FetchOrgRunners implements API.
+func (s *Session) FetchOrgRunners(ctx context.Context, org string) ([]Runner, int, error) {
+	return FetchOrgRunners(ctx, s.rest, org)
+}
+
+// ResolveWorkflow implements API.
+func (s *Session) ResolveWorkflow(ctx context.Context, owner, repo, workflow string) (Workflow, error) {
+	return ResolveWorkflow(ctx, s.rest, owner, repo, workflow)
+}
+
+// FetchStatsRuns implements API.
+func (s *Session) FetchStatsRuns(ctx context.Context, owner, repo string, workflowID int64, since time.Time, maxRuns int) ([]StatsRun, error) {
+	return FetchStatsRuns(ctx, s.rest, owner, repo, workflowID, since, maxRuns)
+}
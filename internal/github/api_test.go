@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func newFakeAPI(t *testing.T, srv *fakegithub.Server) *Session {
+	t.Helper()
+	session, err := NewSession("test-token", WithEndpoints(srv.URL(), srv.GraphQLURL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+func TestSessionAgainstFakeGitHub(t *testing.T) {
+	ctx := context.Background()
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	now := srv.Now()
+
+	// Two weeks of history for the CI workflow, then the PR's own run.
+	srv.AddRun(fakegithub.Run{
+		ID: 90, WorkflowID: 7, Name: "CI", HeadSHA: "old", CreatedAt: now.Add(-24 * time.Hour),
+		Jobs: []fakegithub.Job{{Name: "build", Duration: 2 * time.Minute}, {Name: "test", Duration: 4 * time.Minute}},
+	})
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{
+			{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute},
+			{Name: "test", QueuedAfter: time.Minute, StartedAfter: 90 * time.Second, Duration: time.Minute, Conclusion: "failure"},
+		},
+	})
+
+	api := newFakeAPI(t, srv)
+
+	info, err := api.FetchPRInfo(ctx, "octo", "hello", 12)
+	if err != nil {
+		t.Fatalf("FetchPRInfo: %v", err)
+	}
+	if info.Title != "Add widgets" || info.HeadSHA != "abc123" {
+		t.Errorf("PR info = %+v", info)
+	}
+
+	phases := []struct {
+		advance time.Duration
+		want    map[string]string
+	}{
+		{advance: 0, want: map[string]string{"build": "queued"}},
+		{advance: 30 * time.Second, want: map[string]string{"build": "in_progress"}},
+		{advance: 45 * time.Second, want: map[string]string{"build": "completed", "test": "queued"}},
+		{advance: 2 * time.Minute, want: map[string]string{"build": "completed", "test": "completed"}},
+	}
+	for _, phase := range phases {
+		srv.Advance(phase.advance)
+		checks, pushed, remaining, err := api.FetchCheckRuns(ctx, "octo", "hello", 12)
+		if err != nil {
+			t.Fatalf("FetchCheckRuns: %v", err)
+		}
+		if !pushed.Equal(now) || remaining != 5000 {
+			t.Errorf("pushed %v remaining %d, want %v 5000", pushed, remaining, now)
+		}
+		got := map[string]string{}
+		for _, c := range checks {
+			got[c.Name] = c.Status
+			if c.WorkflowID != 7 || c.WorkflowRunID != 100 {
+				t.Errorf("%s: workflow %d run %d, want 7 100", c.Name, c.WorkflowID, c.WorkflowRunID)
+			}
+		}
+		if len(got) != len(phase.want) {
+			t.Errorf("after %v: checks %v, want %v", phase.advance, got, phase.want)
+		}
+		for name, status := range phase.want {
+			if got[name] != status {
+				t.Errorf("after %v: %s = %q, want %q", phase.advance, name, got[name], status)
+			}
+		}
+	}
+
+	history, err := api.FetchWorkflowHistoryDetail(ctx, "octo", "hello", 7)
+	if err != nil {
+		t.Fatalf("FetchWorkflowHistoryDetail: %v", err)
+	}
+	if history == nil || history.Averages["build"] == 0 || history.Averages["test"] == 0 {
+		t.Fatalf("history = %+v, want build and test averages", history)
+	}
+
+	run, _, err := api.FetchRunInfo(ctx, "octo", "hello", 100)
+	if err != nil {
+		t.Fatalf("FetchRunInfo: %v", err)
+	}
+	if run.Status != "completed" || run.Conclusion != "failure" || !run.HeadPushedTime.Time.Equal(now) {
+		t.Errorf("run info = %+v", run)
+	}
+}
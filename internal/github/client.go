@@ -1,7 +1,6 @@
 package github
 
 import (
-	"context"
 	"fmt"
 	"math"
 	"os"
@@ -9,11 +8,12 @@ import (
 	"strings"
 
 	"github.com/fini-net/gh-observer/internal/debug"
-	"github.com/google/go-github/v90/github"
 	"github.com/shurcooL/githubv4"
 )
 
-// GetToken retrieves the GitHub token from GITHUB_TOKEN env var or gh CLI
+// GetToken retrieves the GitHub token from GITHUB_TOKEN env var or gh CLI.
+// Call it once at startup and hand the token to NewSession; the gh CLI
+// fallback spawns a process.
 func GetToken() (string, error) {
 	// Try GITHUB_TOKEN env var first
 	token := os.Getenv("GITHUB_TOKEN")
@@ -36,20 +36,6 @@ func GetToken() (string, error) {
 	return token, nil
 }
 
-// NewClient creates a GitHub API client using GITHUB_TOKEN env var or gh CLI
-func NewClient(ctx context.Context) (*github.Client, error) {
-	token, err := GetToken()
-	if err != nil {
-		return nil, err
-	}
-	return NewClientFromToken(token)
-}
-
-// NewClientFromToken creates a GitHub API client using an already-obtained token.
-func NewClientFromToken(token string) (*github.Client, error) {
-	return github.NewClient(github.WithAuthToken(token))
-}
-
 // safeGraphQLInt converts an architecture-dependent int to githubv4.Int
 // (backed by int32) with a bounds check, preventing silent truncation on
 // platforms where int is 64-bit. Returns an error if the value is out of
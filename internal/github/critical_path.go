@@ -0,0 +1,251 @@
+package github
+
+import (
+	"sort"
+	"time"
+)
+
+// CriticalPathStep is one graph job on the critical path, spanning all its
+// instances; Wait precedes it, and Estimated marks a projected End.
+type CriticalPathStep struct {
+	JobID     string
+	Instance  string
+	Instances int
+	Start     time.Time
+	End       time.Time
+	Wait      time.Duration
+	Estimated bool
+}
+
+// Duration returns how long the step ran (End - Start).
+func (s CriticalPathStep) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// CriticalPath is the chain of dependent jobs that determined (or, for a
+// running workflow, is projected to determine) when the run completes.
+type CriticalPath struct {
+	Steps []CriticalPathStep
+}
+
+// Total returns the wall-clock span from the first step's start (including
+// its wait) to the last step's end.
+func (p *CriticalPath) Total() time.Duration {
+	if p == nil || len(p.Steps) == 0 {
+		return 0
+	}
+	first := p.Steps[0]
+	return p.Steps[len(p.Steps)-1].End.Sub(first.Start.Add(-first.Wait))
+}
+
+// Contains reports whether the API job name is the blocking instance of a
+// step, for highlighting rows in the job table.
+func (p *CriticalPath) Contains(apiName string) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Steps {
+		if s.Instance == apiName {
+			return true
+		}
+	}
+	return false
+}
+
+// Bottleneck returns the index of the longest-running step, the one worth
+// optimizing first, or -1 for an empty path.
+func (p *CriticalPath) Bottleneck() int {
+	best := -1
+	if p == nil {
+		return best
+	}
+	for i, s := range p.Steps {
+		if best < 0 || s.Duration() > p.Steps[best].Duration() {
+			best = i
+		}
+	}
+	return best
+}
+
+// graphSpan is the resolved timing of one graph job.
+type graphSpan struct {
+	start, end time.Time
+	queued     time.Time
+	instance   string
+	instances  int
+	estimated  bool
+}
+
+// ComputeCriticalPath walks back from the last job to finish along the
+// latest-finishing needs, projecting unfinished jobs from averages (may be
+// nil). It returns nil when no job has timing.
+func ComputeCriticalPath(graph *WorkflowGraph, jobs []WorkflowJobInfo, averages map[string]time.Duration, now time.Time) *CriticalPath {
+	if graph == nil || len(graph.Jobs) == 0 {
+		return nil
+	}
+
+	instances := map[string][]WorkflowJobInfo{}
+	for _, job := range jobs {
+		if id := graph.MatchJob(job.Name); id != "" {
+			instances[id] = append(instances[id], job)
+		}
+	}
+
+	spans := map[string]*graphSpan{}
+	visiting := map[string]bool{}
+	var resolve func(id string) *graphSpan
+	resolve = func(id string) *graphSpan {
+		if s, ok := spans[id]; ok {
+			return s
+		}
+		if visiting[id] {
+			// Cycle: GitHub rejects these, so just cut it.
+			return nil
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		node := graph.Jobs[id]
+		var depsEnd time.Time
+		for _, need := range node.Needs {
+			if dep := resolve(need); dep != nil && dep.end.After(depsEnd) {
+				depsEnd = dep.end
+			}
+		}
+		span := spanFromInstances(node, instances[id], averages, depsEnd, now)
+		spans[id] = span
+		return span
+	}
+
+	ids := make([]string, 0, len(graph.Jobs))
+	for id := range graph.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var sink string
+	for _, id := range ids {
+		s := resolve(id)
+		if s == nil || s.end.IsZero() {
+			continue
+		}
+		if sink == "" || s.end.After(spans[sink].end) {
+			sink = id
+		}
+	}
+	if sink == "" {
+		return nil
+	}
+
+	var reversed []CriticalPathStep
+	for id := sink; id != ""; {
+		span := spans[id]
+		reversed = append(reversed, CriticalPathStep{
+			JobID:     id,
+			Instance:  span.instance,
+			Instances: span.instances,
+			Start:     span.start,
+			End:       span.end,
+			Estimated: span.estimated,
+		})
+
+		next := ""
+		for _, need := range sortedCopy(graph.Jobs[id].Needs) {
+			dep := spans[need]
+			if dep == nil || dep.end.IsZero() {
+				continue
+			}
+			if next == "" || dep.end.After(spans[next].end) {
+				next = need
+			}
+		}
+		id = next
+	}
+
+	path := &CriticalPath{Steps: make([]CriticalPathStep, 0, len(reversed))}
+	for i := len(reversed) - 1; i >= 0; i-- {
+		step := reversed[i]
+		if len(path.Steps) > 0 {
+			step.Wait = max(step.Start.Sub(path.Steps[len(path.Steps)-1].End), 0)
+		} else if q := spans[step.JobID].queued; !q.IsZero() {
+			step.Wait = max(step.Start.Sub(q), 0)
+		}
+		path.Steps = append(path.Steps, step)
+	}
+	return path
+}
+
+// spanFromInstances resolves a graph job's span. depsEnd is when its last
+// dependency ends (zero when it has none).
+func spanFromInstances(node *WorkflowGraphJob, jobs []WorkflowJobInfo, averages map[string]time.Duration, depsEnd, now time.Time) *graphSpan {
+	span := &graphSpan{instances: len(jobs)}
+
+	if len(jobs) == 0 {
+		// Not created yet (blocked on needs) or skipped without a job row:
+		// project it after its dependencies using the display-name average.
+		name := node.Name
+		if name == "" {
+			name = node.ID
+		}
+		avg, ok := averages[name]
+		if !ok || depsEnd.IsZero() {
+			return span
+		}
+		span.start = depsEnd
+		span.end = depsEnd.Add(avg)
+		span.instance = name
+		span.estimated = true
+		return span
+	}
+
+	for _, job := range jobs {
+		var start, end time.Time
+		estimated := false
+		avg := averages[job.Name]
+
+		switch {
+		case job.StartedAt != nil && job.CompletedAt != nil && job.Status == "completed":
+			start, end = job.StartedAt.Time, job.CompletedAt.Time
+		case job.StartedAt != nil && job.Status == "in_progress":
+			start = job.StartedAt.Time
+			end = start.Add(avg)
+			if end.Before(now) {
+				end = now
+			}
+			estimated = true
+		case job.Status == "completed":
+			// Skipped or cancelled before starting: no runtime.
+			continue
+		default:
+			// Queued or waiting on a runner: it can't start before now or
+			// before its dependencies finish.
+			start = now
+			if depsEnd.After(start) {
+				start = depsEnd
+			}
+			end = start.Add(avg)
+			estimated = true
+		}
+
+		if span.start.IsZero() || start.Before(span.start) {
+			span.start = start
+		}
+		if job.CreatedAt != nil && (span.queued.IsZero() || job.CreatedAt.Time.Before(span.queued)) {
+			span.queued = job.CreatedAt.Time
+		}
+		if span.instance == "" || end.After(span.end) {
+			span.end = end
+			span.instance = job.Name
+			span.estimated = estimated
+		}
+	}
+	return span
+}
+
+// sortedCopy returns a sorted copy of ss so iteration order (and therefore
+// tie-breaking) is deterministic.
+func sortedCopy(ss []string) []string {
+	out := append([]string(nil), ss...)
+	sort.Strings(out)
+	return out
+}
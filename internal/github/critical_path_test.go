@@ -0,0 +1,118 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+// graphJob builds a job row that was created at queued and ran from start
+// to end (minutes after base). A negative end leaves it in progress; a
+// negative start leaves it queued.
+func graphJob(name string, base time.Time, queued, start, end int) WorkflowJobInfo {
+	at := func(min int) *github.Timestamp {
+		return &github.Timestamp{Time: base.Add(time.Duration(min) * time.Minute)}
+	}
+	job := WorkflowJobInfo{Name: name, Status: "queued", CreatedAt: at(queued)}
+	if start >= 0 {
+		job.Status = "in_progress"
+		job.StartedAt = at(start)
+	}
+	if end >= 0 {
+		job.Status = "completed"
+		job.Conclusion = "success"
+		job.CompletedAt = at(end)
+	}
+	return job
+}
+
+func TestComputeCriticalPath(t *testing.T) {
+	graph, err := ParseWorkflowGraph([]byte(testWorkflowYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("completed run follows the slowest matrix instance", func(t *testing.T) {
+		jobs := []WorkflowJobInfo{
+			graphJob("lint", base, 0, 1, 3),
+			graphJob("Test (1.25)", base, 3, 4, 10),
+			graphJob("Test (1.26)", base, 3, 4, 15),
+			graphJob("E2E linux", base, 3, 5, 12),
+			graphJob("E2E macos", base, 3, 6, 13),
+			graphJob("release / publish", base, 15, 16, 18),
+		}
+		path := ComputeCriticalPath(graph, jobs, nil, base.Add(time.Hour))
+		if path == nil {
+			t.Fatal("expected a path")
+		}
+		var got []string
+		for _, s := range path.Steps {
+			got = append(got, s.Instance)
+		}
+		want := []string{"lint", "Test (1.26)", "release / publish"}
+		if len(got) != len(want) {
+			t.Fatalf("path = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("path = %v, want %v", got, want)
+			}
+		}
+		if path.Steps[0].Wait != time.Minute || path.Steps[1].Wait != time.Minute {
+			t.Errorf("waits = %v, %v; want 1m, 1m", path.Steps[0].Wait, path.Steps[1].Wait)
+		}
+		if path.Steps[1].Instances != 2 {
+			t.Errorf("instances = %d, want 2", path.Steps[1].Instances)
+		}
+		if path.Total() != 18*time.Minute {
+			t.Errorf("total = %v, want 18m", path.Total())
+		}
+		if path.Bottleneck() != 1 {
+			t.Errorf("bottleneck = %d, want 1 (Test)", path.Bottleneck())
+		}
+		if !path.Contains("Test (1.26)") || path.Contains("Test (1.25)") {
+			t.Error("Contains should only match the blocking instance")
+		}
+	})
+
+	t.Run("running run projects from averages", func(t *testing.T) {
+		now := base.Add(5 * time.Minute)
+		jobs := []WorkflowJobInfo{
+			graphJob("lint", base, 0, 1, 3),
+			graphJob("Test (1.25)", base, 3, 4, -1),
+			graphJob("E2E linux", base, 3, -1, -1),
+		}
+		averages := map[string]time.Duration{
+			"Test (1.25)": 20 * time.Minute,
+			"E2E linux":   5 * time.Minute,
+			"release":     2 * time.Minute,
+		}
+		path := ComputeCriticalPath(graph, jobs, averages, now)
+		if path == nil || len(path.Steps) != 3 {
+			t.Fatalf("path = %+v, want lint → test → release", path)
+		}
+		test := path.Steps[1]
+		if !test.Estimated || !test.End.Equal(base.Add(24*time.Minute)) {
+			t.Errorf("test step = %+v, want estimated end at +24m", test)
+		}
+		release := path.Steps[2]
+		if release.JobID != "release" || !release.Estimated || !release.End.Equal(base.Add(26*time.Minute)) {
+			t.Errorf("release step = %+v, want projected end at +26m", release)
+		}
+	})
+
+	t.Run("no graph or no timing", func(t *testing.T) {
+		if ComputeCriticalPath(nil, nil, nil, base) != nil {
+			t.Error("expected nil without a graph")
+		}
+		if ComputeCriticalPath(graph, nil, nil, base) != nil {
+			t.Error("expected nil without jobs")
+		}
+		var empty *CriticalPath
+		if empty.Total() != 0 || empty.Contains("lint") || empty.Bottleneck() != -1 {
+			t.Error("nil path helpers should be zero-valued")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+)
+
+// PendingDeployment is an environment a waiting run is held at by the
+// environment's protection rules: required reviewers, a wait timer, or
+// both.
+type PendingDeployment struct {
+	EnvironmentID int64
+	Environment   string
+	URL           string
+	// Reviewers are the required reviewers, as "@login" for a user and
+	// "@org/slug" for a team.
+	Reviewers []string
+	// WaitTimer is how long the environment holds every deployment, from
+	// WaitTimerStartedAt; zero when it has no timer.
+	WaitTimer          time.Duration
+	WaitTimerStartedAt time.Time
+	// CanApprove is whether the authenticated user is one of the required
+	// reviewers, and so can approve or reject the deployment.
+	CanApprove bool
+}
+
+// WaitRemaining is how much of the wait timer is left at now, zero when
+// there is no timer or it has run out.
+func (d PendingDeployment) WaitRemaining(now time.Time) time.Duration {
+	if d.WaitTimer <= 0 || d.WaitTimerStartedAt.IsZero() {
+		return 0
+	}
+	return max(0, d.WaitTimerStartedAt.Add(d.WaitTimer).Sub(now))
+}
+
+// FetchPendingDeployments lists the environments run runID is waiting on.
+func FetchPendingDeployments(ctx context.Context, client *github.Client, owner, repo string, runID int64) ([]PendingDeployment, int, error) {
+	rateLimitRemaining := 5000
+
+	pending, resp, err := client.Actions.GetPendingDeployments(ctx, owner, repo, runID)
+	if err != nil {
+		return nil, rateLimitRemaining, fmt.Errorf("failed to fetch pending deployments for run %d: %w", runID, err)
+	}
+	if resp != nil {
+		rateLimitRemaining = resp.Rate.Remaining
+	}
+
+	deployments := make([]PendingDeployment, 0, len(pending))
+	for _, p := range pending {
+		deployments = append(deployments, convertPendingDeployment(p, owner))
+	}
+
+	debug.Log("fetch pending deployments", "run_id", runID, "count", len(deployments), "rate_limit_remaining", rateLimitRemaining)
+
+	return deployments, rateLimitRemaining, nil
+}
+
+// convertPendingDeployment converts a go-github PendingDeployment. Team
+// reviewers are named within their organization, which for an
+// environment's reviewers is the repo owner when GitHub leaves it out.
+func convertPendingDeployment(p *github.PendingDeployment, owner string) PendingDeployment {
+	env := p.GetEnvironment()
+	d := PendingDeployment{
+		EnvironmentID: env.GetID(),
+		Environment:   env.GetName(),
+		URL:           env.GetHTMLURL(),
+		WaitTimer:     time.Duration(p.GetWaitTimer()) * time.Minute,
+		CanApprove:    p.GetCurrentUserCanApprove(),
+	}
+	if p.WaitTimerStartedAt != nil {
+		d.WaitTimerStartedAt = p.WaitTimerStartedAt.Time
+	}
+	for _, r := range p.Reviewers {
+		switch reviewer := r.Reviewer.(type) {
+		case *github.User:
+			d.Reviewers = append(d.Reviewers, "@"+reviewer.GetLogin())
+		case *github.Team:
+			org := reviewer.GetOrganization().GetLogin()
+			if org == "" {
+				org = owner
+			}
+			d.Reviewers = append(d.Reviewers, "@"+org+"/"+reviewer.GetSlug())
+		}
+	}
+	return d
+}
+
+// ReviewPendingDeployments approves (or, with approve false, rejects) run
+// runID's deployments to environmentIDs, leaving comment on the review.
+// GitHub refuses unless the authenticated user is a required reviewer of
+// every one of them.
+func ReviewPendingDeployments(ctx context.Context, client *github.Client, owner, repo string, runID int64, environmentIDs []int64, approve bool, comment string) error {
+	state := "rejected"
+	if approve {
+		state = "approved"
+	}
+	_, _, err := client.Actions.PendingDeployments(ctx, owner, repo, runID, &github.PendingDeploymentsRequest{
+		EnvironmentIDs: environmentIDs,
+		State:          state,
+		Comment:        comment,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to review deployments for run %d: %w", runID, err)
+	}
+
+	debug.Log("review pending deployments", "run_id", runID, "environments", environmentIDs, "state", state)
+
+	return nil
+}
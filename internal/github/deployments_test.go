@@ -0,0 +1,99 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestPendingDeploymentsAgainstFakeGitHub(t *testing.T) {
+	ctx := context.Background()
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "Deploy", HeadSHA: "abc123", HeadBranch: "main", Event: "push",
+		Jobs: []fakegithub.Job{
+			{Name: "build", Duration: time.Minute},
+			{Name: "staging", QueuedAfter: time.Minute, Duration: time.Minute, Gate: &fakegithub.Gate{
+				EnvironmentID: 1, Environment: "staging", WaitTimer: 5 * time.Minute,
+			}},
+			{Name: "production", QueuedAfter: time.Minute, Duration: time.Minute, Gate: &fakegithub.Gate{
+				EnvironmentID: 2, Environment: "production", Reviewers: []string{"alice", "octo/deployers"},
+				WaitTimer: 10 * time.Minute, CanApprove: true,
+			}},
+			{Name: "audit", QueuedAfter: time.Minute, Duration: time.Minute, Gate: &fakegithub.Gate{
+				EnvironmentID: 3, Environment: "audit", Reviewers: []string{"bob"},
+			}},
+		},
+	})
+	api := newFakeAPI(t, srv)
+	srv.Advance(3 * time.Minute)
+
+	deployments, remaining, err := api.FetchPendingDeployments(ctx, "octo", "hello", 100)
+	if err != nil {
+		t.Fatalf("FetchPendingDeployments: %v", err)
+	}
+	if remaining != 5000 {
+		t.Errorf("rate limit remaining = %d, want 5000", remaining)
+	}
+	if len(deployments) != 3 {
+		t.Fatalf("got %d pending deployments, want 3: %+v", len(deployments), deployments)
+	}
+	prod := deployments[1]
+	if prod.EnvironmentID != 2 || prod.Environment != "production" || !prod.CanApprove {
+		t.Errorf("production = %+v", prod)
+	}
+	if want := []string{"@alice", "@octo/deployers"}; !slices.Equal(prod.Reviewers, want) {
+		t.Errorf("reviewers = %v, want %v", prod.Reviewers, want)
+	}
+	if got := prod.WaitRemaining(srv.Now()); got != 8*time.Minute {
+		t.Errorf("wait remaining = %v, want 8m", got)
+	}
+	if deployments[2].CanApprove {
+		t.Error("audit approvable, want only its reviewer to be able to")
+	}
+
+	// Approving production is recorded with its comment; bob's audit gate
+	// can't be reviewed by this user.
+	if err := api.ReviewPendingDeployments(ctx, "octo", "hello", 100, []int64{3}, true, ""); err == nil {
+		t.Error("reviewing audit succeeded, want an error")
+	}
+	if err := api.ReviewPendingDeployments(ctx, "octo", "hello", 100, []int64{2}, true, "ship it"); err != nil {
+		t.Fatalf("ReviewPendingDeployments: %v", err)
+	}
+	reviews := srv.DeploymentReviews(100)
+	if len(reviews) != 1 || reviews[0].State != "approved" || reviews[0].Comment != "ship it" {
+		t.Errorf("reviews = %+v, want production approved with comment", reviews)
+	}
+
+	// Production still waits out its timer; staging's has run out.
+	srv.Advance(3 * time.Minute)
+	deployments, _, err = api.FetchPendingDeployments(ctx, "octo", "hello", 100)
+	if err != nil {
+		t.Fatalf("FetchPendingDeployments: %v", err)
+	}
+	var envs []string
+	for _, d := range deployments {
+		envs = append(envs, d.Environment)
+	}
+	if want := []string{"production", "audit"}; !slices.Equal(envs, want) {
+		t.Errorf("pending after 6m = %v, want %v", envs, want)
+	}
+
+	// The jobs follow their gates.
+	jobs, _, err := api.FetchRunJobs(ctx, "octo", "hello", 100)
+	if err != nil {
+		t.Fatalf("FetchRunJobs: %v", err)
+	}
+	for _, j := range jobs {
+		if j.Name == "staging" && j.Status != "in_progress" {
+			t.Errorf("staging = %s, want in_progress once its timer ran out", j.Status)
+		}
+		if j.Name == "production" && j.Status != "waiting" {
+			t.Errorf("production = %s, want waiting on its timer", j.Status)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+)
+
+// maxConditionalEntries bounds the conditional-request cache. Repo mode on a
+// busy repo touches a few hundred job-list URLs per fade window; older
+// entries are evicted first.
+const maxConditionalEntries = 1024
+
+// conditionalEntry is a cached 200 response for one REST URL.
+type conditionalEntry struct {
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+	storedAt     time.Time
+}
+
+// ConditionalTransport makes GETs conditional on the last ETag or
+// Last-Modified, replaying the cached body as a 200 when GitHub answers 304.
+type ConditionalTransport struct {
+	Base http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*conditionalEntry
+}
+
+// NewConditionalTransport wraps base (http.DefaultTransport when nil).
+func NewConditionalTransport(base http.RoundTripper) *ConditionalTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &ConditionalTransport{Base: base, entries: make(map[string]*conditionalEntry)}
+}
+
+// conditionalKey identifies a cached response. Accept is part of the key
+// because go-github varies it per endpoint (preview media types), and the
+// same URL with a different media type is a different representation.
+func conditionalKey(req *http.Request) string {
+	return req.URL.String() + "\x00" + req.Header.Get("Accept")
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ConditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := conditionalKey(req)
+	t.mu.Lock()
+	entry := t.entries[key]
+	t.mu.Unlock()
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		debug.Log("conditional request not modified", "url", req.URL.Path)
+		return entry.replay(req, resp.Header), nil
+
+	case resp.StatusCode == http.StatusOK:
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return resp, nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.store(key, &conditionalEntry{
+			etag:         etag,
+			lastModified: lastModified,
+			header:       resp.Header.Clone(),
+			body:         body,
+			storedAt:     time.Now(),
+		})
+	}
+	return resp, nil
+}
+
+// store records entry under key, evicting the oldest entry when full.
+func (t *ConditionalTransport) store(key string, entry *conditionalEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok && len(t.entries) >= maxConditionalEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range t.entries {
+			if oldestKey == "" || e.storedAt.Before(oldest) {
+				oldestKey, oldest = k, e.storedAt
+			}
+		}
+		delete(t.entries, oldestKey)
+	}
+	t.entries[key] = entry
+}
+
+// replay builds a 200 response from the cached entry. Rate-limit and date
+// headers come from the live 304 (fresh), everything else from the cache.
+func (e *conditionalEntry) replay(req *http.Request, fresh http.Header) *http.Response {
+	header := e.header.Clone()
+	for name, values := range fresh {
+		if strings.HasPrefix(name, "X-Ratelimit-") || name == "Date" {
+			header[name] = values
+		}
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
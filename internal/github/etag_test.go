@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestConditionalTransport(t *testing.T) {
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.Header().Set("X-RateLimit-Remaining", "4990")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Write([]byte(`{"total_count":1,"jobs":[{"id":1,"name":"build","status":"completed"}]}`))
+	}))
+	defer server.Close()
+
+	transport := NewConditionalTransport(nil)
+	client, _ := github.NewClient(
+		github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")),
+		github.WithTransport(transport),
+	)
+
+	for i, wantRemaining := range []int{4999, 4990, 4990} {
+		jobs, resp, err := client.Actions.ListWorkflowJobs(context.Background(), "owner", "repo", 1, nil)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+		if len(jobs.Jobs) != 1 || jobs.Jobs[0].GetName() != "build" {
+			t.Errorf("request %d: jobs = %+v, want cached build job", i, jobs.Jobs)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("request %d: status = %d, want 304 replayed as 200", i, resp.StatusCode)
+		}
+		if resp.Rate.Remaining != wantRemaining {
+			t.Errorf("request %d: rate remaining = %d, want %d from the live response", i, resp.Rate.Remaining, wantRemaining)
+		}
+	}
+	if requests.Load() != 3 || notModified.Load() != 2 {
+		t.Errorf("requests = %d, not modified = %d; want 3 and 2", requests.Load(), notModified.Load())
+	}
+}
+
+func TestConditionalTransportSkipsUncacheable(t *testing.T) {
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			conditional.Add(1)
+		}
+		if r.URL.Path == "/etag" {
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewConditionalTransport(nil)}
+	for range 2 {
+		resp, err := client.Get(server.URL + "/plain")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		resp, err = client.Post(server.URL+"/etag", "text/plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if conditional.Load() != 0 {
+		t.Errorf("conditional requests = %d, want 0 for responses without validators and non-GET", conditional.Load())
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/fini-net/gh-observer/internal/debug"
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 type BigInt int64
@@ -50,21 +49,32 @@ type Annotation struct {
 // rows must not participate in timing calculations: StartedAt is used only
 // to drive the in_progress runtime display while a review is pending, and
 // CompletedAt stays nil.
+//
+// QueuedAt, Labels and RunnerName are only populated for rows adapted from
+// the REST jobs API (WorkflowJobInfoToCheckRuns); the GraphQL rollup does
+// not expose runner data, so PR-check rows leave them empty.
+//
+// WorkflowNodeID is the workflow's GraphQL node ID, set only on PR-check
+// rows; FetchWorkflowHistories uses it to batch history into one query.
 type CheckRunInfo struct {
-	Name          string
-	WorkflowName  string
-	AppName       string
-	Summary       string
-	Status        string
-	Conclusion    string
-	StartedAt     *time.Time
-	CompletedAt   *time.Time
-	DetailsURL    string
-	Annotations   []Annotation
-	WorkflowRunID int64
-	WorkflowID    int64
-	Kind          string
-	ReviewState   string
+	Name           string
+	WorkflowName   string
+	AppName        string
+	Summary        string
+	Status         string
+	Conclusion     string
+	StartedAt      *time.Time
+	CompletedAt    *time.Time
+	DetailsURL     string
+	Annotations    []Annotation
+	WorkflowRunID  int64
+	WorkflowID     int64
+	WorkflowNodeID string
+	Kind           string
+	ReviewState    string
+	QueuedAt       *time.Time
+	Labels         []string
+	RunnerName     string
 }
 
 // contextNode represents a union type in the StatusCheckRollup
@@ -95,6 +105,7 @@ type contextNode struct {
 			WorkflowRun struct {
 				DatabaseID BigInt `graphql:"databaseId"`
 				Workflow   struct {
+					ID         string
 					DatabaseID BigInt `graphql:"databaseId"`
 					Name       string
 				}
@@ -136,9 +147,7 @@ type pullRequestQuery struct {
 			} `graphql:"commits(last: 1)"`
 		} `graphql:"pullRequest(number: $prNumber)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
-	RateLimit struct {
-		Remaining int
-	}
+	RateLimit graphQLRateLimit
 }
 
 type graphqlQuerier interface {
@@ -212,25 +221,26 @@ func contextNodesToCheckRuns(nodes []contextNode) []CheckRunInfo {
 		}
 
 		checkRuns = append(checkRuns, CheckRunInfo{
-			Name:          checkRun.Name,
-			WorkflowName:  workflowName,
-			AppName:       appName,
-			Summary:       checkRun.Summary,
-			Status:        strings.ToLower(checkRun.Status),
-			Conclusion:    strings.ToLower(checkRun.Conclusion),
-			StartedAt:     startedAt,
-			CompletedAt:   completedAt,
-			DetailsURL:    checkRun.DetailsURL,
-			Annotations:   annotations,
-			WorkflowRunID: workflowRunID,
-			WorkflowID:    workflowID,
+			Name:           checkRun.Name,
+			WorkflowName:   workflowName,
+			AppName:        appName,
+			Summary:        checkRun.Summary,
+			Status:         strings.ToLower(checkRun.Status),
+			Conclusion:     strings.ToLower(checkRun.Conclusion),
+			StartedAt:      startedAt,
+			CompletedAt:    completedAt,
+			DetailsURL:     checkRun.DetailsURL,
+			Annotations:    annotations,
+			WorkflowRunID:  workflowRunID,
+			WorkflowID:     workflowID,
+			WorkflowNodeID: checkRun.CheckSuite.WorkflowRun.Workflow.ID,
 		})
 	}
 
 	return checkRuns
 }
 
-// FetchCheckRunsGraphQL fetches check runs with workflow names using GraphQL
+// fetchCheckRunsGraphQL fetches check runs with workflow names using GraphQL
 // with cursor-based pagination to handle PRs with more than 100 status contexts.
 // Also returns the head commit's push time (pushedDate, falling back to
 // committedDate when pushedDate is absent) so callers can render queue
@@ -238,13 +248,6 @@ func contextNodesToCheckRuns(nodes []contextNode) []CheckRunInfo {
 // (possibly stale) commit time. The push time is populated from the first
 // page only; if the PR has no commits or the first page errors, the zero
 // value is returned and callers must fall back.
-func FetchCheckRunsGraphQL(ctx context.Context, token, owner, repo string, prNumber int) ([]CheckRunInfo, time.Time, int, error) {
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(ctx, src)
-	client := githubv4.NewClient(httpClient)
-	return fetchCheckRunsGraphQL(ctx, client, owner, repo, prNumber)
-}
-
 func fetchCheckRunsGraphQL(ctx context.Context, client graphqlQuerier, owner, repo string, prNumber int) ([]CheckRunInfo, time.Time, int, error) {
 	var allCheckRuns []CheckRunInfo
 	var headPushedTime time.Time
@@ -95,6 +95,7 @@ func makeCheckRunNode(f checkRunContextFields) contextNode {
 				WorkflowRun struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
@@ -131,6 +132,7 @@ func makeCheckRunNode(f checkRunContextFields) contextNode {
 				WorkflowRun struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
@@ -143,12 +145,14 @@ func makeCheckRunNode(f checkRunContextFields) contextNode {
 				WorkflowRun: struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
 				}{
 					DatabaseID: BigInt(f.WorkflowRunID),
 					Workflow: struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}{DatabaseID: BigInt(f.WorkflowID), Name: f.WorkflowName},
@@ -665,7 +669,7 @@ func makeContextNodeCheckRun(name, status, conclusion string) contextNode {
 
 func makeTestQuery(checkRunNames []string, hasNextPage bool, endCursor string, rateLimitRemaining int) *pullRequestQuery {
 	q := &pullRequestQuery{
-		RateLimit: struct{ Remaining int }{Remaining: rateLimitRemaining},
+		RateLimit: graphQLRateLimit{Remaining: rateLimitRemaining},
 	}
 
 	var nodes []contextNode
@@ -753,7 +757,7 @@ func TestFetchCheckRunsGraphQL_MultiPagePagination(t *testing.T) {
 
 func TestFetchCheckRunsGraphQL_EmptyCommits(t *testing.T) {
 	q := &pullRequestQuery{
-		RateLimit: struct{ Remaining int }{Remaining: 4999},
+		RateLimit: graphQLRateLimit{Remaining: 4999},
 	}
 	mock := &mockQuerier{
 		responses: []mockResponse{{query: q}},
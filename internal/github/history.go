@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -38,9 +39,12 @@ func ParseRunIDFromURL(detailsURL string) (int64, error) {
 // The knownRunIDToWorkflowID and knownFetchedWorkflowIDs parameters enable incremental
 // fetching: run IDs already mapped to workflow IDs are cached, and workflow IDs already
 // fetched are skipped. New mappings and newly-fetched workflow IDs are returned for caching.
+// Historical runs' jobs are listed concurrently through pool (see FetchPool);
+// if ctx is cancelled partway, the context's error is returned.
 func FetchJobAverages(
 	ctx context.Context,
 	client *github.Client,
+	pool *FetchPool,
 	owner, repo string,
 	checkRuns []CheckRunInfo,
 	knownRunIDToWorkflowID map[int64]int64,
@@ -151,7 +155,10 @@ func FetchJobAverages(
 		return nil, newRunIDToWorkflowID, workflowIDsToFetch, nil
 	}
 
-	averages = averageJobDurations(ctx, client, owner, repo, historicalRunIDs)
+	averages = averageJobDurations(ctx, client, pool, owner, repo, historicalRunIDs)
+	if err := ctx.Err(); err != nil {
+		return nil, newRunIDToWorkflowID, workflowIDsToFetch, err
+	}
 
 	return averages, newRunIDToWorkflowID, workflowIDsToFetch, nil
 }
@@ -161,26 +168,51 @@ func FetchJobAverages(
 func averageJobDurations(
 	ctx context.Context,
 	client *github.Client,
+	pool *FetchPool,
 	owner, repo string,
 	runIDs []int64,
 ) map[string]time.Duration {
-	jobDurations := map[string][]time.Duration{}
-	for _, runID := range runIDs {
-		jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{
+	return averagesFromJobs(listHistoryJobs(ctx, client, pool, owner, repo, runIDs))
+}
+
+// listHistoryJobs lists each run's latest-attempt jobs through pool,
+// flattened in runIDs order; failed listings are skipped.
+func listHistoryJobs(
+	ctx context.Context,
+	client *github.Client,
+	pool *FetchPool,
+	owner, repo string,
+	runIDs []int64,
+) []*github.WorkflowJob {
+	perRun := make([][]*github.WorkflowJob, len(runIDs))
+	_ = pool.Each(ctx, len(runIDs), func(ctx context.Context, i int) {
+		jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runIDs[i], &github.ListWorkflowJobsOptions{
 			Filter:      "latest",
 			ListOptions: github.ListOptions{PerPage: 100},
 		})
 		if err != nil {
+			return
+		}
+		perRun[i] = jobs.Jobs
+	})
+	var all []*github.WorkflowJob
+	for _, jobs := range perRun {
+		all = append(all, jobs...)
+	}
+	return all
+}
+
+// averagesFromJobs computes the weighted average duration per job name from
+// historical jobs supplied newest-first.
+func averagesFromJobs(jobs []*github.WorkflowJob) map[string]time.Duration {
+	jobDurations := map[string][]time.Duration{}
+	for _, job := range jobs {
+		if job.Name == nil || job.StartedAt == nil || job.CompletedAt == nil {
 			continue
 		}
-		for _, job := range jobs.Jobs {
-			if job.Name == nil || job.StartedAt == nil || job.CompletedAt == nil {
-				continue
-			}
-			dur := job.CompletedAt.Sub(job.StartedAt.Time)
-			if dur > 0 {
-				jobDurations[*job.Name] = append(jobDurations[*job.Name], dur)
-			}
+		dur := job.CompletedAt.Sub(job.StartedAt.Time)
+		if dur > 0 {
+			jobDurations[*job.Name] = append(jobDurations[*job.Name], dur)
 		}
 	}
 
@@ -196,15 +228,15 @@ func averageJobDurations(
 	//
 	// Known limitation: this invariant holds for the FetchWorkflowHistory call
 	// site (one workflow at a time, so runIDs are strictly newest-first), but
-	// NOT for the legacy FetchJobAverages snapshot-mode path, which builds
-	// historicalRunIDs by concatenating each workflow's newest-first runs while
+	// NOT for the legacy FetchJobAverages path (still used by run-mode
+	// snapshots), which builds historicalRunIDs by concatenating each workflow's newest-first runs while
 	// ranging over workflowIDsToFetch (a map, non-deterministic order). When two
 	// workflows in the same PR share a bare job name (e.g. "build", "test"), the
 	// merged per-name slice is "whichever workflow iterated first, newest-first
 	// within that workflow, then the other workflow's runs appended" — not true
 	// chronological newest-first. The decay then biases toward an arbitrary
 	// workflow, nondeterministically across runs. The TUI path is unaffected
-	// because it calls averageJobDurations once per workflow. Sorting runs by
+	// because it derives averages once per workflow. Sorting runs by
 	// actual timestamp across workflows before weighting would close this gap
 	// (the run timestamp is already available on WorkflowRun, no new API call),
 	// but that is deferred as a follow-up.
@@ -322,14 +354,76 @@ func DiscoverWorkflows(
 	return newRunIDToWorkflowID, workflowIDsToFetch, nil
 }
 
+// WorkflowHistory is one workflow's history: job averages, queue-latency
+// samples per RunnerLabelKey (newest first) and flakiness per job name.
+type WorkflowHistory struct {
+	Averages     map[string]time.Duration
+	QueueByLabel map[string][]time.Duration
+	Flaky        map[string]FlakyStats
+}
+
+// FlakyStats counts the recent runs of a job (Runs) and those where it
+// failed and then passed on the same commit (Retried).
+type FlakyStats struct {
+	Retried int
+	Runs    int
+}
+
+// IsFlaky reports whether the job has failed-then-passed at least once in
+// recent history.
+func (f FlakyStats) IsFlaky() bool {
+	return f.Retried > 0
+}
+
+// historyRun is one historical workflow run with every attempt's jobs.
+// Jobs from the latest attempt feed the averages; earlier attempts only
+// feed flakiness scoring.
+type historyRun struct {
+	ID        int64
+	HeadSHA   string
+	CreatedAt time.Time
+	Attempt   int64
+	Jobs      []*github.WorkflowJob
+}
+
+// latestJobs returns the jobs belonging to the run's final attempt. Jobs
+// without a run_attempt are treated as latest (the "latest" filter omits
+// nothing, and older API responses may not carry the field).
+func (r historyRun) latestJobs() []*github.WorkflowJob {
+	var latest []*github.WorkflowJob
+	for _, job := range r.Jobs {
+		if job.RunAttempt == nil || *job.RunAttempt == r.Attempt {
+			latest = append(latest, job)
+		}
+	}
+	return latest
+}
+
 // FetchWorkflowHistory fetches historical job durations for a single workflow.
 // Returns averaged durations per job name for the given workflow.
 func FetchWorkflowHistory(
 	ctx context.Context,
 	client *github.Client,
+	pool *FetchPool,
 	owner, repo string,
 	workflowID int64,
 ) (map[string]time.Duration, error) {
+	history, err := FetchWorkflowHistoryDetail(ctx, client, pool, owner, repo, workflowID)
+	if err != nil || history == nil {
+		return nil, err
+	}
+	return history.Averages, nil
+}
+
+// FetchWorkflowHistoryDetail derives a workflow's job averages and queue
+// samples from its recent completed runs, or nil when it has none.
+func FetchWorkflowHistoryDetail(
+	ctx context.Context,
+	client *github.Client,
+	pool *FetchPool,
+	owner, repo string,
+	workflowID int64,
+) (*WorkflowHistory, error) {
 	runs, _, err := client.Actions.ListWorkflowRunsByID(ctx, owner, repo, workflowID, &github.ListWorkflowRunsOptions{
 		Status:      "completed",
 		ListOptions: github.ListOptions{PerPage: 10},
@@ -341,20 +435,126 @@ func FetchWorkflowHistory(
 
 	debug.Log("fetch workflow history", "workflow_id", workflowID, "runs", len(runs.WorkflowRuns))
 
-	var historicalRunIDs []int64
-	for _, run := range runs.WorkflowRuns {
-		if run.ID != nil {
-			historicalRunIDs = append(historicalRunIDs, *run.ID)
+	history := listHistoryRuns(ctx, client, pool, owner, repo, runs.WorkflowRuns)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	var jobs []*github.WorkflowJob
+	for _, run := range history {
+		jobs = append(jobs, run.latestJobs()...)
+	}
+
+	return &WorkflowHistory{
+		Averages:     averagesFromJobs(jobs),
+		QueueByLabel: historyQueueSamplesByLabel(jobs),
+		Flaky:        flakyStatsFromRuns(history),
+	}, nil
+}
+
+// listHistoryRuns lists each run's jobs through pool, in runs order, with
+// every attempt of re-run runs; failed listings are skipped.
+func listHistoryRuns(ctx context.Context, client *github.Client, pool *FetchPool, owner, repo string, runs []*github.WorkflowRun) []historyRun {
+	var pending []historyRun
+	for _, run := range runs {
+		if run.ID == nil {
+			continue
+		}
+		hr := historyRun{
+			ID:      *run.ID,
+			HeadSHA: run.GetHeadSHA(),
+			Attempt: int64(max(run.GetRunAttempt(), 1)),
 		}
+		if run.CreatedAt != nil {
+			hr.CreatedAt = run.CreatedAt.Time
+		}
+		pending = append(pending, hr)
 	}
 
-	if len(historicalRunIDs) == 0 {
-		return nil, nil
+	listed := make([]bool, len(pending))
+	_ = pool.Each(ctx, len(pending), func(ctx context.Context, i int) {
+		hr := &pending[i]
+		filter := "latest"
+		if hr.Attempt > 1 {
+			filter = "all"
+		}
+		jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, hr.ID, &github.ListWorkflowJobsOptions{
+			Filter:      filter,
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			debug.Log("history job listing failed", "run_id", hr.ID, "err", err)
+			return
+		}
+		hr.Jobs = jobs.Jobs
+		listed[i] = true
+	})
+
+	var history []historyRun
+	for i, hr := range pending {
+		if listed[i] {
+			history = append(history, hr)
+		}
 	}
+	return history
+}
 
-	averages := averageJobDurations(ctx, client, owner, repo, historicalRunIDs)
+// flakyStatsFromRuns scores each job name across runs, counting a run once
+// when the job passed after failing in an earlier attempt or same-SHA run.
+func flakyStatsFromRuns(runs []historyRun) map[string]FlakyStats {
+	// Oldest first, so "earlier run on the same SHA" is a prefix scan.
+	ordered := slices.Clone(runs)
+	slices.SortStableFunc(ordered, func(a, b historyRun) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	stats := map[string]FlakyStats{}
+	// failedOnSHA[sha][job] is true once any earlier run on sha failed job.
+	failedOnSHA := map[string]map[string]bool{}
+
+	for _, run := range ordered {
+		failedEarlierAttempt := map[string]bool{}
+		for _, job := range run.Jobs {
+			if job.RunAttempt != nil && *job.RunAttempt < run.Attempt && FailureJobConclusion(job.GetConclusion()) {
+				failedEarlierAttempt[job.GetName()] = true
+			}
+		}
+
+		for _, job := range run.latestJobs() {
+			name := job.GetName()
+			if name == "" {
+				continue
+			}
+			s := stats[name]
+			s.Runs++
+			if job.GetConclusion() == "success" &&
+				(failedEarlierAttempt[name] || (run.HeadSHA != "" && failedOnSHA[run.HeadSHA][name])) {
+				s.Retried++
+			}
+			stats[name] = s
+		}
 
-	return averages, nil
+		if run.HeadSHA == "" {
+			continue
+		}
+		for _, job := range run.Jobs {
+			if !FailureJobConclusion(job.GetConclusion()) {
+				continue
+			}
+			if failedOnSHA[run.HeadSHA] == nil {
+				failedOnSHA[run.HeadSHA] = map[string]bool{}
+			}
+			failedOnSHA[run.HeadSHA][job.GetName()] = true
+		}
+	}
+
+	if len(stats) == 0 {
+		return nil
+	}
+	return stats
 }
 
 // DiscoverAdvSecWorkflows matches GitHub Advanced Security checks to their
@@ -0,0 +1,238 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// historyWorkflowsPerQuery caps how many workflows one batched query
+// covers. Each workflow selects up to 15 runs × 100 check runs, so larger
+// batches risk GitHub's "Resource limits for this query exceeded" error (see
+// repoContextNode); PRs with more workflows are split across queries.
+const historyWorkflowsPerQuery = 10
+
+// WorkflowRef names a workflow for FetchWorkflowHistories: its REST ID,
+// which keys the results, and its GraphQL node ID (CheckRunInfo's
+// WorkflowNodeID). NodeID may be empty, in which case that workflow falls
+// back to FetchWorkflowHistoryDetail.
+type WorkflowRef struct {
+	ID     int64
+	NodeID string
+}
+
+// WorkflowRefs returns a WorkflowRef for each of workflowIDs, taking node
+// IDs from the check runs that carry them.
+func WorkflowRefs(checkRuns []CheckRunInfo, workflowIDs []int64) []WorkflowRef {
+	nodeIDs := map[int64]string{}
+	for _, cr := range checkRuns {
+		if cr.WorkflowID > 0 && cr.WorkflowNodeID != "" {
+			nodeIDs[cr.WorkflowID] = cr.WorkflowNodeID
+		}
+	}
+	refs := make([]WorkflowRef, 0, len(workflowIDs))
+	for _, id := range workflowIDs {
+		refs = append(refs, WorkflowRef{ID: id, NodeID: nodeIDs[id]})
+	}
+	return refs
+}
+
+// historyCheckRun is one check run (job) of a historical check suite.
+type historyCheckRun struct {
+	Name        string
+	Status      string
+	Conclusion  string
+	StartedAt   githubv4.DateTime
+	CompletedAt githubv4.DateTime
+}
+
+// historyWorkflowNode is each aliased workflow's selection: 15 recent runs
+// to find 10 completed, with every attempt's check runs.
+type historyWorkflowNode struct {
+	Workflow struct {
+		Runs struct {
+			Nodes []struct {
+				DatabaseID BigInt `graphql:"databaseId"`
+				CreatedAt  githubv4.DateTime
+				CheckSuite struct {
+					Status string
+					Commit struct {
+						OID string `graphql:"oid"`
+					}
+					CheckRuns struct {
+						Nodes []historyCheckRun
+					} `graphql:"checkRuns(first: 100, filterBy: {checkType: ALL})"`
+				}
+			}
+		} `graphql:"runs(first: 15, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"... on Workflow"`
+}
+
+// historyQueryType builds the batched query struct for n workflows: fields
+// W0..Wn-1 aliased as w0..wn-1 over node(id: $w0) and so on, plus the
+// rateLimit every query selects. githubv4 derives queries from struct
+// types, so a variable number of aliases needs a type built at runtime.
+func historyQueryType(n int) reflect.Type {
+	fields := make([]reflect.StructField, 0, n+1)
+	for i := range n {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("W%d", i),
+			Type: reflect.TypeFor[historyWorkflowNode](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"w%d: node(id: $w%d)"`, i, i)),
+		})
+	}
+	fields = append(fields, reflect.StructField{
+		Name: "RateLimit",
+		Type: reflect.TypeFor[graphQLRateLimit](),
+	})
+	return reflect.StructOf(fields)
+}
+
+// fetchWorkflowHistoriesGraphQL fetches every ref's history (each needs a
+// NodeID) in batched aliased queries. QueueByLabel is never set.
+func fetchWorkflowHistoriesGraphQL(ctx context.Context, client graphqlQuerier, refs []WorkflowRef) (map[int64]*WorkflowHistory, error) {
+	histories := make(map[int64]*WorkflowHistory, len(refs))
+	for batch := range slices.Chunk(refs, historyWorkflowsPerQuery) {
+		query := reflect.New(historyQueryType(len(batch)))
+		variables := make(map[string]any, len(batch))
+		for i, ref := range batch {
+			variables[fmt.Sprintf("w%d", i)] = githubv4.ID(ref.NodeID)
+		}
+		if err := client.Query(ctx, query.Interface(), variables); err != nil {
+			debug.Log("batched workflow history query failed", "workflows", len(batch), "err", err)
+			return nil, err
+		}
+		for i, ref := range batch {
+			node := query.Elem().Field(i).Interface().(historyWorkflowNode)
+			runs := historyRunsFromNode(node)
+			debug.Log("fetch workflow history (graphql)", "workflow_id", ref.ID, "runs", len(runs))
+			if len(runs) == 0 {
+				continue
+			}
+			var jobs []*github.WorkflowJob
+			for _, run := range runs {
+				jobs = append(jobs, run.latestJobs()...)
+			}
+			histories[ref.ID] = &WorkflowHistory{
+				Averages: averagesFromJobs(jobs),
+				Flaky:    flakyStatsFromRuns(runs),
+			}
+		}
+	}
+	return histories, nil
+}
+
+// historyRunsFromNode keeps a workflow's 10 newest completed runs,
+// numbering attempts per job name in start order.
+func historyRunsFromNode(node historyWorkflowNode) []historyRun {
+	var runs []historyRun
+	for _, n := range node.Workflow.Runs.Nodes {
+		if len(runs) == 10 {
+			break
+		}
+		if !strings.EqualFold(n.CheckSuite.Status, "completed") {
+			continue
+		}
+
+		byName := map[string][]historyCheckRun{}
+		var names []string
+		for _, cr := range n.CheckSuite.CheckRuns.Nodes {
+			if _, ok := byName[cr.Name]; !ok {
+				names = append(names, cr.Name)
+			}
+			byName[cr.Name] = append(byName[cr.Name], cr)
+		}
+		attempts := 1
+		for _, crs := range byName {
+			attempts = max(attempts, len(crs))
+		}
+
+		hr := historyRun{
+			ID:        int64(n.DatabaseID),
+			HeadSHA:   n.CheckSuite.Commit.OID,
+			CreatedAt: n.CreatedAt.Time,
+			Attempt:   int64(attempts),
+		}
+		for _, name := range names {
+			crs := byName[name]
+			slices.SortStableFunc(crs, func(a, b historyCheckRun) int {
+				return a.StartedAt.Compare(b.StartedAt.Time)
+			})
+			for i, cr := range crs {
+				hr.Jobs = append(hr.Jobs, historyJobFromCheckRun(cr, int64(attempts-len(crs)+i+1)))
+			}
+		}
+		runs = append(runs, hr)
+	}
+	return runs
+}
+
+// historyJobFromCheckRun adapts a check run to the REST job shape the
+// averaging and flakiness helpers consume.
+func historyJobFromCheckRun(cr historyCheckRun, attempt int64) *github.WorkflowJob {
+	job := &github.WorkflowJob{
+		Name:       github.Ptr(cr.Name),
+		Status:     github.Ptr(strings.ToLower(cr.Status)),
+		Conclusion: github.Ptr(strings.ToLower(cr.Conclusion)),
+		RunAttempt: github.Ptr(attempt),
+	}
+	if !cr.StartedAt.IsZero() {
+		job.StartedAt = &github.Timestamp{Time: cr.StartedAt.Time}
+	}
+	if !cr.CompletedAt.IsZero() {
+		job.CompletedAt = &github.Timestamp{Time: cr.CompletedAt.Time}
+	}
+	return job
+}
+
+// fetchWorkflowHistories fetches each ref's history by workflow ID, over
+// GraphQL where it can and REST otherwise; failures are reported in errs.
+func fetchWorkflowHistories(
+	ctx context.Context,
+	client *github.Client,
+	gql graphqlQuerier,
+	pool *FetchPool,
+	owner, repo string,
+	refs []WorkflowRef,
+) (map[int64]*WorkflowHistory, map[int64]error) {
+	var batched, rest []WorkflowRef
+	for _, ref := range refs {
+		if ref.NodeID != "" {
+			batched = append(batched, ref)
+		} else {
+			rest = append(rest, ref)
+		}
+	}
+
+	histories := map[int64]*WorkflowHistory{}
+	if len(batched) > 0 {
+		fetched, err := fetchWorkflowHistoriesGraphQL(ctx, gql, batched)
+		if err != nil {
+			rest = append(rest, batched...)
+		} else {
+			histories = fetched
+		}
+	}
+
+	var errs map[int64]error
+	for _, ref := range rest {
+		history, err := FetchWorkflowHistoryDetail(ctx, client, pool, owner, repo, ref.ID)
+		if err != nil {
+			if errs == nil {
+				errs = map[int64]error{}
+			}
+			errs[ref.ID] = err
+			continue
+		}
+		if history != nil {
+			histories[ref.ID] = history
+		}
+	}
+	return histories, errs
+}
@@ -0,0 +1,197 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+	"github.com/shurcooL/githubv4"
+)
+
+// addHistoryWorkflows scripts n workflows (IDs 1..n), each with two
+// completed runs yesterday and one run on the PR head, and returns the
+// PR's check runs as the check-rollup query reports them.
+func addHistoryWorkflows(t *testing.T, srv *fakegithub.Server, api *Session, n int) []CheckRunInfo {
+	t.Helper()
+	now := srv.Now()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 1, HeadSHA: "head"})
+	for wf := int64(1); wf <= int64(n); wf++ {
+		for i, took := range []time.Duration{3 * time.Minute, time.Minute} {
+			srv.AddRun(fakegithub.Run{
+				ID: wf*100 + int64(i), WorkflowID: wf, Name: "wf", HeadSHA: "old",
+				CreatedAt: now.Add(-24*time.Hour + time.Duration(i)*time.Hour),
+				Jobs:      []fakegithub.Job{{Name: "build", Duration: took}},
+			})
+		}
+		srv.AddRun(fakegithub.Run{
+			ID: wf*100 + 50, WorkflowID: wf, Name: "wf", HeadSHA: "head", CreatedAt: now,
+			Jobs: []fakegithub.Job{{Name: "build"}},
+		})
+	}
+	checks, _, _, err := api.FetchCheckRuns(context.Background(), "octo", "hello", 1)
+	if err != nil {
+		t.Fatalf("FetchCheckRuns: %v", err)
+	}
+	return checks
+}
+
+func TestFetchWorkflowHistories(t *testing.T) {
+	ctx := context.Background()
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+
+	checks := addHistoryWorkflows(t, srv, api, 12)
+	var ids []int64
+	for wf := int64(1); wf <= 12; wf++ {
+		ids = append(ids, wf)
+	}
+	refs := WorkflowRefs(checks, ids)
+	for _, ref := range refs {
+		if ref.NodeID == "" {
+			t.Fatalf("workflow %d has no node ID", ref.ID)
+		}
+	}
+
+	before := len(srv.Requests())
+	histories, errs := api.FetchWorkflowHistories(ctx, "octo", "hello", refs)
+	if len(errs) != 0 {
+		t.Fatalf("errs = %v", errs)
+	}
+	requests := srv.Requests()[before:]
+	want := []string{"POST /graphql workflowHistory", "POST /graphql workflowHistory"}
+	if !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v (12 workflows in batches of 10, no REST)", requests, want)
+	}
+
+	for _, id := range ids {
+		rest, err := api.FetchWorkflowHistoryDetail(ctx, "octo", "hello", id)
+		if err != nil {
+			t.Fatalf("FetchWorkflowHistoryDetail(%d): %v", id, err)
+		}
+		got := histories[id]
+		if got == nil {
+			t.Fatalf("workflow %d missing from histories", id)
+		}
+		if got.Averages["build"] != rest.Averages["build"] {
+			t.Errorf("workflow %d: build average %v, want %v as over REST", id, got.Averages["build"], rest.Averages["build"])
+		}
+		if got.Flaky["build"] != rest.Flaky["build"] {
+			t.Errorf("workflow %d: build flakiness %+v, want %+v as over REST", id, got.Flaky["build"], rest.Flaky["build"])
+		}
+	}
+}
+
+// failingQuerier fails every query, standing in for a GraphQL outage.
+type failingQuerier struct{}
+
+func (failingQuerier) Query(context.Context, interface{}, map[string]interface{}) error {
+	return errors.New("graphql unavailable")
+}
+
+func TestFetchWorkflowHistoriesFallsBackToREST(t *testing.T) {
+	tests := []struct {
+		name        string
+		failGraphQL bool
+		dropNodeIDs bool
+	}{
+		{name: "graphql error", failGraphQL: true},
+		{name: "no node IDs", dropNodeIDs: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakegithub.New("octo", "hello")
+			defer srv.Close()
+			api := newFakeAPI(t, srv)
+			checks := addHistoryWorkflows(t, srv, api, 2)
+			if tt.failGraphQL {
+				api.graphql = failingQuerier{}
+			}
+			refs := WorkflowRefs(checks, []int64{1, 2, 3})
+			if tt.dropNodeIDs {
+				for i := range refs {
+					refs[i].NodeID = ""
+				}
+			}
+
+			before := len(srv.Requests())
+			histories, errs := api.FetchWorkflowHistories(context.Background(), "octo", "hello", refs)
+			if len(errs) != 0 {
+				t.Fatalf("errs = %v", errs)
+			}
+			for _, id := range []int64{1, 2} {
+				if histories[id] == nil || histories[id].Averages["build"] == 0 {
+					t.Errorf("workflow %d history = %+v, want a build average", id, histories[id])
+				}
+			}
+			if _, ok := histories[3]; ok {
+				t.Errorf("workflow 3 has no runs but got history %+v", histories[3])
+			}
+			restCalls := 0
+			for _, r := range srv.Requests()[before:] {
+				if strings.HasPrefix(r, "GET ") {
+					restCalls++
+				}
+			}
+			if restCalls == 0 {
+				t.Error("expected the REST fallback to be used")
+			}
+		})
+	}
+}
+
+func TestHistoryRunsFromNode(t *testing.T) {
+	at := func(min int) githubv4.DateTime {
+		return githubv4.DateTime{Time: time.Date(2026, 1, 1, 12, min, 0, 0, time.UTC)}
+	}
+	var node historyWorkflowNode
+	type runNode = struct {
+		DatabaseID BigInt `graphql:"databaseId"`
+		CreatedAt  githubv4.DateTime
+		CheckSuite struct {
+			Status string
+			Commit struct {
+				OID string `graphql:"oid"`
+			}
+			CheckRuns struct {
+				Nodes []historyCheckRun
+			} `graphql:"checkRuns(first: 100, filterBy: {checkType: ALL})"`
+		}
+	}
+	addRun := func(id int64, status string, checks ...historyCheckRun) {
+		var n runNode
+		n.DatabaseID = BigInt(id)
+		n.CheckSuite.Status = status
+		n.CheckSuite.Commit.OID = "sha"
+		n.CheckSuite.CheckRuns.Nodes = checks
+		node.Workflow.Runs.Nodes = append(node.Workflow.Runs.Nodes, n)
+	}
+	// Newest first: an in-progress run (skipped), then a run whose "test"
+	// job failed and was re-run to success while "build" ran once.
+	addRun(3, "IN_PROGRESS", historyCheckRun{Name: "build", Status: "IN_PROGRESS", StartedAt: at(50)})
+	addRun(2, "COMPLETED",
+		historyCheckRun{Name: "test", Status: "COMPLETED", Conclusion: "SUCCESS", StartedAt: at(20), CompletedAt: at(25)},
+		historyCheckRun{Name: "build", Status: "COMPLETED", Conclusion: "SUCCESS", StartedAt: at(0), CompletedAt: at(2)},
+		historyCheckRun{Name: "test", Status: "COMPLETED", Conclusion: "FAILURE", StartedAt: at(2), CompletedAt: at(4)},
+	)
+
+	runs := historyRunsFromNode(node)
+	if len(runs) != 1 || runs[0].ID != 2 || runs[0].Attempt != 2 {
+		t.Fatalf("runs = %+v, want only run 2 with 2 attempts", runs)
+	}
+	latest := map[string]time.Duration{}
+	for _, job := range runs[0].latestJobs() {
+		latest[job.GetName()] = job.CompletedAt.Sub(job.StartedAt.Time)
+	}
+	if latest["test"] != 5*time.Minute || latest["build"] != 2*time.Minute {
+		t.Errorf("latest attempt durations = %v, want test 5m (the re-run) and build 2m", latest)
+	}
+	flaky := flakyStatsFromRuns(runs)
+	if flaky["test"] != (FlakyStats{Retried: 1, Runs: 1}) || flaky["build"] != (FlakyStats{Runs: 1}) {
+		t.Errorf("flaky = %+v, want test retried once and build clean", flaky)
+	}
+}
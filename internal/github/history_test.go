@@ -227,6 +227,7 @@ func TestAverageJobDurations(t *testing.T) {
 			averages := averageJobDurations(
 				context.Background(),
 				client,
+				NewFetchPool(DefaultFetchConcurrency),
 				"owner",
 				"repo",
 				tt.runIDs,
@@ -620,6 +621,7 @@ func TestFetchWorkflowHistory(t *testing.T) {
 			averages, err := FetchWorkflowHistory(
 				context.Background(),
 				client,
+				NewFetchPool(DefaultFetchConcurrency),
 				"owner",
 				"repo",
 				tt.workflowID,
@@ -824,3 +826,125 @@ func TestApplyPresumedAverages(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchWorkflowHistoryDetailQueueByLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/workflows/789/runs":
+			w.Write([]byte(`{"workflow_runs":[{"id":1},{"id":2}]}`))
+		case "/repos/owner/repo/actions/runs/1/jobs":
+			w.Write([]byte(`{"jobs":[
+				{"name":"train","labels":["self-hosted","gpu-large"],"created_at":"2024-01-01T00:00:00Z","started_at":"2024-01-01T00:05:00Z","completed_at":"2024-01-01T00:10:00Z"},
+				{"name":"lint","labels":["ubuntu-latest"],"created_at":"2024-01-01T00:00:00Z","started_at":"2024-01-01T00:00:10Z","completed_at":"2024-01-01T00:01:00Z"}
+			]}`))
+		case "/repos/owner/repo/actions/runs/2/jobs":
+			w.Write([]byte(`{"jobs":[
+				{"name":"train","labels":["self-hosted","gpu-large"],"created_at":"2024-01-01T00:00:00Z","started_at":"2024-01-01T00:09:00Z","completed_at":"2024-01-01T00:14:00Z"},
+				{"name":"never-started","labels":["self-hosted","gpu-large"],"created_at":"2024-01-01T00:00:00Z"}
+			]}`))
+		}
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	history, err := FetchWorkflowHistoryDetail(context.Background(), client, NewFetchPool(DefaultFetchConcurrency), "owner", "repo", 789)
+	if err != nil {
+		t.Fatalf("FetchWorkflowHistoryDetail() error = %v", err)
+	}
+	if history == nil {
+		t.Fatal("FetchWorkflowHistoryDetail() = nil, want history")
+	}
+
+	if history.Averages["lint"] != 50*time.Second {
+		t.Errorf("Averages[lint] = %v, want 50s", history.Averages["lint"])
+	}
+
+	gpu := history.QueueByLabel["self-hosted, gpu-large"]
+	if len(gpu) != 2 || gpu[0] != 5*time.Minute || gpu[1] != 9*time.Minute {
+		t.Errorf("QueueByLabel[gpu] = %v, want [5m 9m] newest first", gpu)
+	}
+	hosted := history.QueueByLabel["ubuntu-latest"]
+	if len(hosted) != 1 || hosted[0] != 10*time.Second {
+		t.Errorf("QueueByLabel[ubuntu-latest] = %v, want [10s]", hosted)
+	}
+}
+
+func TestFlakyStatsFromRuns(t *testing.T) {
+	job := func(name, conclusion string, attempt int64) *github.WorkflowJob {
+		return &github.WorkflowJob{Name: &name, Conclusion: &conclusion, RunAttempt: &attempt}
+	}
+	t0 := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	runs := []historyRun{
+		// Newest first, as listHistoryRuns returns them.
+		{ID: 4, HeadSHA: "bbb", CreatedAt: t0.Add(3 * time.Hour), Attempt: 1, Jobs: []*github.WorkflowJob{
+			job("test", "success", 1), job("lint", "success", 1),
+		}},
+		{ID: 3, HeadSHA: "bbb", CreatedAt: t0.Add(2 * time.Hour), Attempt: 1, Jobs: []*github.WorkflowJob{
+			job("test", "failure", 1), job("lint", "success", 1),
+		}},
+		{ID: 2, HeadSHA: "aaa", CreatedAt: t0.Add(time.Hour), Attempt: 2, Jobs: []*github.WorkflowJob{
+			job("test", "failure", 1), job("lint", "success", 1),
+			job("test", "success", 2), job("lint", "success", 2),
+		}},
+		{ID: 1, HeadSHA: "zzz", CreatedAt: t0, Attempt: 1, Jobs: []*github.WorkflowJob{
+			job("test", "failure", 1), job("lint", "failure", 1),
+		}},
+	}
+
+	got := flakyStatsFromRuns(runs)
+
+	if want := (FlakyStats{Retried: 2, Runs: 4}); got["test"] != want {
+		t.Errorf("test = %+v, want %+v (one re-run attempt + one same-SHA rerun)", got["test"], want)
+	}
+	if want := (FlakyStats{Retried: 0, Runs: 4}); got["lint"] != want {
+		t.Errorf("lint = %+v, want %+v (failure on zzz never passed)", got["lint"], want)
+	}
+	if got["lint"].IsFlaky() || !got["test"].IsFlaky() {
+		t.Error("IsFlaky mismatch")
+	}
+}
+
+func TestFetchWorkflowHistoryDetailFlaky(t *testing.T) {
+	var filters = map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/workflows/789/runs":
+			w.Write([]byte(`{"workflow_runs":[
+				{"id":2,"head_sha":"abc","run_attempt":2,"created_at":"2024-01-02T00:00:00Z"},
+				{"id":1,"head_sha":"def","run_attempt":1,"created_at":"2024-01-01T00:00:00Z"}
+			]}`))
+		case "/repos/owner/repo/actions/runs/2/jobs":
+			filters["2"] = r.URL.Query().Get("filter")
+			w.Write([]byte(`{"jobs":[
+				{"name":"test","conclusion":"failure","run_attempt":1,"started_at":"2024-01-02T00:00:00Z","completed_at":"2024-01-02T00:09:00Z"},
+				{"name":"test","conclusion":"success","run_attempt":2,"started_at":"2024-01-02T00:10:00Z","completed_at":"2024-01-02T00:12:00Z"}
+			]}`))
+		case "/repos/owner/repo/actions/runs/1/jobs":
+			filters["1"] = r.URL.Query().Get("filter")
+			w.Write([]byte(`{"jobs":[
+				{"name":"test","conclusion":"success","run_attempt":1,"started_at":"2024-01-01T00:00:00Z","completed_at":"2024-01-01T00:02:00Z"}
+			]}`))
+		}
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	history, err := FetchWorkflowHistoryDetail(context.Background(), client, NewFetchPool(DefaultFetchConcurrency), "owner", "repo", 789)
+	if err != nil {
+		t.Fatalf("FetchWorkflowHistoryDetail() error = %v", err)
+	}
+
+	if filters["2"] != "all" || filters["1"] != "latest" {
+		t.Errorf("job filters = %v, want all for the re-run and latest otherwise", filters)
+	}
+	if want := (FlakyStats{Retried: 1, Runs: 2}); history.Flaky["test"] != want {
+		t.Errorf("Flaky[test] = %+v, want %+v", history.Flaky["test"], want)
+	}
+	// The failed first attempt (9m) must not leak into the average.
+	if history.Averages["test"] != 2*time.Minute {
+		t.Errorf("Averages[test] = %v, want 2m from latest attempts only", history.Averages["test"])
+	}
+}
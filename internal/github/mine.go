@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/shurcooL/githubv4"
+)
+
+// searchPRsQuery is a page of an open pull request search. Each PR carries
+// what the repo listing has, plus its repository.
+type searchPRsQuery struct {
+	Search struct {
+		IssueCount int
+		Nodes      []struct {
+			PullRequest struct {
+				listedPR
+				Repository struct {
+					Name  string
+					Owner struct {
+						Login string
+					}
+				}
+			} `graphql:"... on PullRequest"`
+		}
+		PageInfo pageInfo
+	} `graphql:"search(query: $query, type: ISSUE, first: 100, after: $cursor)"`
+	RateLimit graphQLRateLimit
+}
+
+// myPRSearches are the searches behind --mine: the viewer's open PRs and,
+// with reviewRequested, the open PRs awaiting the viewer's review. filter's
+// Labels, Base and NoDrafts become search qualifiers; its Author must be
+// empty, since the searches pick the author themselves.
+func myPRSearches(reviewRequested bool, filter RepoFilter) []string {
+	qualifiers := "is:pr is:open archived:false sort:updated-desc"
+	if len(filter.Labels) > 0 {
+		labels := make([]string, len(filter.Labels))
+		for i, l := range filter.Labels {
+			if strings.ContainsAny(l, " ,") {
+				l = `"` + l + `"`
+			}
+			labels[i] = l
+		}
+		qualifiers += " label:" + strings.Join(labels, ",")
+	}
+	if filter.Base != "" {
+		qualifiers += " base:" + filter.Base
+	}
+	if filter.NoDrafts {
+		qualifiers += " draft:false"
+	}
+	searches := []string{qualifiers + " author:" + ViewerAlias}
+	if reviewRequested {
+		searches = append(searches, qualifiers+" review-requested:"+ViewerAlias)
+	}
+	return searches
+}
+
+// fetchMyPRCheckRuns is fetchRepoCheckRunsGraphQL for the viewer's open
+// PRs in every repo, found by search (see myPRSearches).
+func fetchMyPRCheckRuns(ctx context.Context, client graphqlQuerier, reviewRequested bool, filter RepoFilter, budget int) (map[RepoRef]RepoCheckRuns, int, error) {
+	cost := &repoQueryCost{budget: budget, remaining: 5000}
+	result := make(map[RepoRef]RepoCheckRuns)
+	seen := make(map[string]bool)
+	var refs []repoPRRef
+	for _, search := range myPRSearches(reviewRequested, filter) {
+		var cursor *githubv4.String
+		for first := true; first || cursor != nil; first = false {
+			if !first && cost.exhausted() {
+				debug.Log("pr search truncated by budget", "query", search, "listed", len(refs))
+				break
+			}
+			var query searchPRsQuery
+			variables := map[string]any{
+				"query":  githubv4.String(search),
+				"cursor": cursor,
+			}
+			if err := client.Query(ctx, &query, variables); err != nil {
+				debug.Log("pr search failed", "query", search, "err", err)
+				return nil, cost.remaining, err
+			}
+			cost.observe(query.RateLimit)
+			for _, node := range query.Search.Nodes {
+				pr := node.PullRequest
+				if seen[pr.ID] {
+					continue
+				}
+				seen[pr.ID] = true
+				repo := RepoRef{Owner: pr.Repository.Owner.Login, Name: pr.Repository.Name}
+				if _, ok := result[repo]; !ok {
+					result[repo] = RepoCheckRuns{PRs: make(map[int]PRCheckData)}
+				}
+				refs = append(refs, pr.listedPR.ref(repo))
+			}
+			cursor = nil
+			if query.Search.PageInfo.HasNextPage {
+				cursor = &query.Search.PageInfo.EndCursor
+			}
+		}
+	}
+
+	rankPRRefs(refs)
+	fetched, err := fetchRankedPRs(ctx, client, refs, cost, result)
+	if err != nil {
+		return nil, cost.remaining, err
+	}
+
+	debug.Log("pr search checks success", "repos", len(result), "matching_prs", len(refs),
+		"fetched", fetched, "omitted", len(refs)-fetched,
+		"cost", cost.spent, "rate_limit_remaining", cost.remaining)
+	return result, cost.remaining, nil
+}
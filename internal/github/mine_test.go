@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestFetchMyPRCheckRuns(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	lib := srv.AddRepo("other", "lib")
+
+	// Both *fakegithub.Server (for octo/hello) and *fakegithub.Repo script PRs.
+	type scripted interface {
+		AddPullRequest(fakegithub.PullRequest)
+		AddRun(fakegithub.Run)
+	}
+	addPR := func(r scripted, pr fakegithub.PullRequest) {
+		pr.HeadSHA = fmt.Sprintf("sha%d", pr.Number)
+		r.AddPullRequest(pr)
+		r.AddRun(fakegithub.Run{
+			ID: int64(pr.Number), WorkflowID: 10, Name: "CI", HeadSHA: pr.HeadSHA, Event: "pull_request",
+			CreatedAt: srv.Now().Add(-time.Minute), Jobs: []fakegithub.Job{{Name: "test", Duration: time.Hour}},
+		})
+	}
+	addPR(srv, fakegithub.PullRequest{Number: 1, Author: "octo", Labels: []string{"bug"}})
+	addPR(srv, fakegithub.PullRequest{Number: 2, Author: "dependabot", ReviewRequested: []string{"octo"}})
+	addPR(lib, fakegithub.PullRequest{Number: 7, Author: "octo", Base: "release"})
+	addPR(lib, fakegithub.PullRequest{Number: 8, Author: "octo", Draft: true})
+	addPR(lib, fakegithub.PullRequest{Number: 9, Author: "someone"})
+
+	tests := []struct {
+		name            string
+		reviewRequested bool
+		filter          RepoFilter
+		want            []string
+	}{
+		{name: "authored", want: []string{"octo/hello#1", "other/lib#7", "other/lib#8"}},
+		{name: "review requested", reviewRequested: true, want: []string{"octo/hello#1", "octo/hello#2", "other/lib#7", "other/lib#8"}},
+		{name: "no drafts", filter: RepoFilter{NoDrafts: true}, want: []string{"octo/hello#1", "other/lib#7"}},
+		{name: "label", filter: RepoFilter{Labels: []string{"bug"}}, want: []string{"octo/hello#1"}},
+		{name: "base", filter: RepoFilter{Base: "release"}, want: []string{"other/lib#7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, _, err := fetchMyPRCheckRuns(context.Background(), api.graphql, tt.reviewRequested, tt.filter, repoQueryCostBudget)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			var got []string
+			for repo, checks := range repos {
+				for n, pr := range checks.PRs {
+					if len(pr.CheckRuns) != 1 {
+						t.Errorf("%s#%d has %d checks, want 1", repo, n, len(pr.CheckRuns))
+					}
+					got = append(got, fmt.Sprintf("%s#%d", repo, n))
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("PRs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMyPRSearches(t *testing.T) {
+	got := myPRSearches(true, RepoFilter{Labels: []string{"bug", "good first issue"}, Base: "main", NoDrafts: true})
+	base := `is:pr is:open archived:false sort:updated-desc label:bug,"good first issue" base:main draft:false`
+	want := []string{base + " author:@me", base + " review-requested:@me"}
+	if !slices.Equal(got, want) {
+		t.Errorf("searches = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/shurcooL/githubv4"
+)
+
+// MaxDashboardRepos caps how many repos one multi-repo watch follows. Each
+// repo costs two REST calls per poll for its standalone runs, so past this
+// the poll interval would stretch too far to be useful.
+const MaxDashboardRepos = 50
+
+// searchReposQuery is a page of a repository search.
+type searchReposQuery struct {
+	Search struct {
+		RepositoryCount int
+		Nodes           []struct {
+			Repository struct {
+				Name  string
+				Owner struct {
+					Login string
+				}
+			} `graphql:"... on Repository"`
+		}
+		PageInfo pageInfo
+	} `graphql:"search(query: $query, type: REPOSITORY, first: 100, after: $cursor)"`
+	RateLimit graphQLRateLimit
+}
+
+// findOrgRepos lists org's unarchived repos, restricted to those tagged
+// topic when it is set, most recently updated first. It returns at most
+// limit of them, and how many matched in all.
+func findOrgRepos(ctx context.Context, client graphqlQuerier, org, topic string, limit int) ([]RepoRef, int, error) {
+	search := fmt.Sprintf("org:%s archived:false sort:updated-desc", org)
+	if topic != "" {
+		search += " topic:" + topic
+	}
+
+	var repos []RepoRef
+	var cursor *githubv4.String
+	for {
+		var query searchReposQuery
+		variables := map[string]any{
+			"query":  githubv4.String(search),
+			"cursor": cursor,
+		}
+		if err := client.Query(ctx, &query, variables); err != nil {
+			debug.Log("org repo search failed", "org", org, "topic", topic, "err", err)
+			return nil, 0, err
+		}
+		for _, node := range query.Search.Nodes {
+			if len(repos) == limit {
+				break
+			}
+			repos = append(repos, RepoRef{Owner: node.Repository.Owner.Login, Name: node.Repository.Name})
+		}
+		if len(repos) == limit || !query.Search.PageInfo.HasNextPage {
+			debug.Log("org repo search", "org", org, "topic", topic, "matched", query.Search.RepositoryCount, "kept", len(repos))
+			return repos, query.Search.RepositoryCount, nil
+		}
+		cursor = &query.Search.PageInfo.EndCursor
+	}
+}
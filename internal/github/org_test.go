@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestFindOrgRepos(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	srv.AddRepo("octo", "api", "backend")
+	srv.AddRepo("octo", "web", "frontend")
+	srv.AddRepo("octo", "worker", "backend", "queue")
+	srv.AddRepo("other", "api", "backend")
+
+	tests := []struct {
+		name      string
+		topic     string
+		limit     int
+		want      []string
+		wantTotal int
+	}{
+		{name: "whole org", limit: 10, want: []string{"octo/hello", "octo/api", "octo/web", "octo/worker"}, wantTotal: 4},
+		{name: "topic", topic: "backend", limit: 10, want: []string{"octo/api", "octo/worker"}, wantTotal: 2},
+		{name: "capped", limit: 2, want: []string{"octo/hello", "octo/api"}, wantTotal: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, total, err := findOrgRepos(context.Background(), api.graphql, "octo", tt.topic, tt.limit)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			var got []string
+			for _, r := range repos {
+				got = append(got, r.String())
+			}
+			if !slices.Equal(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("repos = %v (of %d), want %v (of %d)", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
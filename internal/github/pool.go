@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultFetchConcurrency is how many independent fetches a session runs at
+// once when fetch_concurrency isn't configured. It stays well under
+// maxIdleConnsPerHost so pooled connections are reused, and under GitHub's
+// guidance against bursts of concurrent requests (secondary rate limits).
+const DefaultFetchConcurrency = 4
+
+// FetchPool bounds how many independent fetches run at once across
+// everything sharing a Session.
+type FetchPool struct {
+	slots chan struct{}
+}
+
+// NewFetchPool returns a pool running at most limit fetches at once
+// (at least one).
+func NewFetchPool(limit int) *FetchPool {
+	return &FetchPool{slots: make(chan struct{}, max(limit, 1))}
+}
+
+// Each runs fn(ctx, i) for every i in [0, n) in the pool's free slots and
+// waits, returning ctx.Err() once ctx is done. fn must not call Each.
+func (p *FetchPool) Each(ctx context.Context, n int, fn func(ctx context.Context, i int)) error {
+	if p == nil {
+		for i := range n {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fn(ctx, i)
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+dispatch:
+	for i := range n {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Go(func() {
+			defer func() { <-p.slots }()
+			fn(ctx, i)
+		})
+	}
+	wg.Wait()
+	return ctx.Err()
+}
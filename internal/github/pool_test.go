@@ -0,0 +1,99 @@
+package github
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchPoolEach(t *testing.T) {
+	tests := []struct {
+		name  string
+		pool  *FetchPool
+		limit int32
+	}{
+		{name: "nil pool runs serially", pool: nil, limit: 1},
+		{name: "limit 1", pool: NewFetchPool(1), limit: 1},
+		{name: "limit 3", pool: NewFetchPool(3), limit: 3},
+		{name: "non-positive limit treated as 1", pool: NewFetchPool(0), limit: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, peak atomic.Int32
+			results := make([]int, 10)
+			err := tt.pool.Each(context.Background(), len(results), func(_ context.Context, i int) {
+				n := inFlight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				results[i] = i * i
+				inFlight.Add(-1)
+			})
+			if err != nil {
+				t.Fatalf("Each() error = %v", err)
+			}
+			if got := peak.Load(); got > tt.limit {
+				t.Errorf("peak concurrency = %d, want at most %d", got, tt.limit)
+			}
+			for i, got := range results {
+				if got != i*i {
+					t.Errorf("results[%d] = %d, want %d", i, got, i*i)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchPoolEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := NewFetchPool(2)
+	var mu sync.Mutex
+	var started []int
+	err := pool.Each(ctx, 10, func(ctx context.Context, i int) {
+		mu.Lock()
+		started = append(started, i)
+		mu.Unlock()
+		if i == 1 {
+			cancel()
+		}
+		<-ctx.Done()
+	})
+	if err != context.Canceled {
+		t.Errorf("Each() error = %v, want context.Canceled", err)
+	}
+	if len(started) > 3 {
+		t.Errorf("started %v after cancellation, want no more than the in-flight calls", started)
+	}
+}
+
+// TestFetchPoolSharedLimit checks that concurrent callers of one pool share
+// its limit, as the PR, run and repo models do through their session.
+func TestFetchPoolSharedLimit(t *testing.T) {
+	pool := NewFetchPool(2)
+	var inFlight, peak atomic.Int32
+	fn := func(_ context.Context, _ int) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+	}
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Go(func() { _ = pool.Each(context.Background(), 4, fn) })
+	}
+	wg.Wait()
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency across callers = %d, want at most 2", got)
+	}
+}
@@ -19,7 +19,7 @@ var (
 // PRInfo contains metadata about a pull request. Only PR-level fields
 // (number, title, head SHA, created-at) come from REST; the head commit's
 // push time is sourced separately from the GraphQL check-runs query
-// (FetchCheckRunsGraphQL), which fetches pushedDate in the same round-trip
+// (fetchCheckRunsGraphQL), which fetches pushedDate in the same round-trip
 // as the StatusCheckRollup. The REST commit endpoint only exposes
 // committer/author timestamps, not pushedDate, so it is not used here.
 type PRInfo struct {
@@ -121,9 +121,9 @@ func ParsePRURL(prURL string) (owner, repo string, prNumber int, err error) {
 // FetchPRInfo retrieves metadata about a pull request. Only the PR-level
 // fields (number, title, head SHA, created-at) come from REST; the head
 // commit's push time is sourced from the GraphQL check-runs query
-// (FetchCheckRunsGraphQL) which fetches pushedDate in the same round-trip
+// (fetchCheckRunsGraphQL) which fetches pushedDate in the same round-trip
 // as the StatusCheckRollup. Callers that need the push time should consume
-// the time.Time returned by FetchCheckRunsGraphQL.
+// the time.Time returned by fetchCheckRunsGraphQL.
 func FetchPRInfo(ctx context.Context, client *github.Client, owner, repo string, prNumber int) (*PRInfo, error) {
 	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
 	if err != nil {
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+// unlabeledRunnerKey groups jobs whose runs-on labels are unknown — PR-check
+// rows sourced from GraphQL, or jobs the API returned without labels.
+const unlabeledRunnerKey = "(unlabeled)"
+
+// RunnerLabelKey groups a job by its whole runs-on label set, in order,
+// so each self-hosted pool gets its own bucket.
+func RunnerLabelKey(labels []string) string {
+	if len(labels) == 0 {
+		return unlabeledRunnerKey
+	}
+	return strings.Join(labels, ", ")
+}
+
+// JobQueueLatency returns how long a job has waited for a runner, up to
+// now if it hasn't started, or false when it has no queue time.
+func JobQueueLatency(check CheckRunInfo, now time.Time) (time.Duration, bool) {
+	if check.QueuedAt == nil || check.QueuedAt.IsZero() {
+		return 0, false
+	}
+	end := now
+	if check.StartedAt != nil && !check.StartedAt.IsZero() {
+		end = *check.StartedAt
+	} else if check.Status == "completed" {
+		// Completed without ever starting (cancelled or skipped while
+		// queued): there is no runner wait to attribute.
+		return 0, false
+	}
+	wait := end.Sub(*check.QueuedAt)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// QueueSamplesByLabel groups the queue latency of each job in checkRuns by
+// RunnerLabelKey. Jobs without a queue timestamp are skipped, so PR-check
+// rows from GraphQL contribute nothing. Returns nil when no samples exist.
+func QueueSamplesByLabel(checkRuns []CheckRunInfo, now time.Time) map[string][]time.Duration {
+	var samples map[string][]time.Duration
+	for _, cr := range checkRuns {
+		wait, ok := JobQueueLatency(cr, now)
+		if !ok {
+			continue
+		}
+		if samples == nil {
+			samples = make(map[string][]time.Duration)
+		}
+		key := RunnerLabelKey(cr.Labels)
+		samples[key] = append(samples[key], wait)
+	}
+	return samples
+}
+
+// historyQueueSamplesByLabel collects StartedAt - CreatedAt for completed
+// historical jobs, grouped by RunnerLabelKey. Jobs that never started are
+// skipped. Order within each label follows the input (newest run first).
+func historyQueueSamplesByLabel(jobs []*github.WorkflowJob) map[string][]time.Duration {
+	var samples map[string][]time.Duration
+	for _, job := range jobs {
+		if job.CreatedAt == nil || job.StartedAt == nil {
+			continue
+		}
+		wait := job.StartedAt.Sub(job.CreatedAt.Time)
+		if wait < 0 {
+			continue
+		}
+		if samples == nil {
+			samples = make(map[string][]time.Duration)
+		}
+		key := RunnerLabelKey(job.Labels)
+		samples[key] = append(samples[key], wait)
+	}
+	return samples
+}
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunnerLabelKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   string
+	}{
+		{name: "no labels", labels: nil, want: "(unlabeled)"},
+		{name: "single hosted label", labels: []string{"ubuntu-latest"}, want: "ubuntu-latest"},
+		{name: "self-hosted pool keeps runs-on order", labels: []string{"self-hosted", "gpu-large"}, want: "self-hosted, gpu-large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RunnerLabelKey(tt.labels); got != tt.want {
+				t.Errorf("RunnerLabelKey(%v) = %q, want %q", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobQueueLatency(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	queued := now.Add(-10 * time.Minute)
+	started := now.Add(-7 * time.Minute)
+
+	tests := []struct {
+		name   string
+		check  CheckRunInfo
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "no queue timestamp",
+			check:  CheckRunInfo{Status: "in_progress", StartedAt: &started},
+			wantOK: false,
+		},
+		{
+			name:   "started job uses start time",
+			check:  CheckRunInfo{Status: "in_progress", QueuedAt: &queued, StartedAt: &started},
+			want:   3 * time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "still queued counts wait so far",
+			check:  CheckRunInfo{Status: "queued", QueuedAt: &queued},
+			want:   10 * time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "completed without starting is skipped",
+			check:  CheckRunInfo{Status: "completed", Conclusion: "cancelled", QueuedAt: &queued},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := JobQueueLatency(tt.check, now)
+			if ok != tt.wantOK {
+				t.Fatalf("JobQueueLatency() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("JobQueueLatency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueSamplesByLabel(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	queued := now.Add(-4 * time.Minute)
+	started := now.Add(-1 * time.Minute)
+
+	checks := []CheckRunInfo{
+		{Name: "train", Status: "queued", QueuedAt: &queued, Labels: []string{"self-hosted", "gpu-large"}},
+		{Name: "train-2", Status: "in_progress", QueuedAt: &queued, StartedAt: &started, Labels: []string{"self-hosted", "gpu-large"}},
+		{Name: "graphql-check", Status: "in_progress", StartedAt: &started},
+	}
+
+	samples := QueueSamplesByLabel(checks, now)
+	gpu := samples["self-hosted, gpu-large"]
+	if len(gpu) != 2 || gpu[0] != 4*time.Minute || gpu[1] != 3*time.Minute {
+		t.Errorf("samples[gpu] = %v, want [4m 3m]", gpu)
+	}
+	if _, ok := samples[unlabeledRunnerKey]; ok {
+		t.Errorf("GraphQL rows without QueuedAt should not contribute samples, got %v", samples[unlabeledRunnerKey])
+	}
+
+	if got := QueueSamplesByLabel(nil, now); got != nil {
+		t.Errorf("QueueSamplesByLabel(nil) = %v, want nil", got)
+	}
+}
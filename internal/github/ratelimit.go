@@ -0,0 +1,254 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// secondaryRateLimitPause is how long to stand down after a secondary
+// (abuse) rate limit response that carries no Retry-After. GitHub's docs say
+// to wait at least a minute before retrying in that case.
+const secondaryRateLimitPause = time.Minute
+
+// Rate limit resources, as GitHub names them in X-RateLimit-Resource.
+// Each has its own quota: spending one doesn't limit the others.
+const (
+	ResourceCore    = "core"
+	ResourceGraphQL = "graphql"
+	ResourceSearch  = "search"
+)
+
+// RateLimitedError is returned, without touching the network, for requests
+// made while the governor is paused.
+type RateLimitedError struct {
+	Until  time.Time
+	Reason string
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited (%s) until %s", e.Reason, e.Until.Format(time.TimeOnly))
+}
+
+// RateLimitGovernor tracks, per resource, whether GitHub has told us to
+// stop; requests to a paused resource fail fast with RateLimitedError.
+type RateLimitGovernor struct {
+	mu sync.Mutex
+	// pauses and resets are keyed by resource. Secondary limits and
+	// Retry-After hold every resource, under "".
+	pauses map[string]ratePause
+	resets map[string]time.Time
+	now    func() time.Time
+}
+
+type ratePause struct {
+	until  time.Time
+	reason string
+}
+
+// pause extends resource's pause to until. An earlier until never shortens
+// an existing pause: two limits in force means waiting for the later one.
+func (g *RateLimitGovernor) pause(resource string, until time.Time, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until.After(g.pauses[resource].until) {
+		debug.Log("rate limit pause", "resource", resource, "reason", reason, "until", until)
+		if g.pauses == nil {
+			g.pauses = make(map[string]ratePause)
+		}
+		g.pauses[resource] = ratePause{until: until, reason: reason}
+	}
+}
+
+// PausedUntil returns when requests spending any of resources may resume
+// and why they're paused, or the zero time when they aren't. A pause on
+// every resource counts whichever are asked about.
+func (g *RateLimitGovernor) PausedUntil(resources ...string) (time.Time, string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	latest := g.pauses[""]
+	for _, r := range resources {
+		if p := g.pauses[r]; p.until.After(latest.until) {
+			latest = p
+		}
+	}
+	if !g.now().Before(latest.until) {
+		return time.Time{}, ""
+	}
+	return latest.until, latest.reason
+}
+
+// observeReset records when resource's current quota window ends, for
+// ResetAt's callers budgeting their polls.
+func (g *RateLimitGovernor) observeReset(resource string, reset time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resets == nil {
+		g.resets = make(map[string]time.Time)
+	}
+	g.resets[resource] = reset
+}
+
+// ResetAt returns when resource's most recently observed quota window
+// resets (in the past once it has), or the zero time when no response has
+// reported one.
+func (g *RateLimitGovernor) ResetAt(resource string) time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.resets[resource]
+}
+
+// observeResponse updates the governor from a REST or GraphQL HTTP response.
+func (g *RateLimitGovernor) observeResponse(resp *http.Response) {
+	now := g.now()
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = requestResource(resp.Request)
+	}
+
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		g.observeReset(resource, time.Unix(reset, 0))
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			g.pause("", now.Add(wait), "Retry-After")
+			return
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			g.pause(resource, time.Unix(reset, 0), resource+" quota exhausted")
+			return
+		}
+	}
+
+	// Secondary limits sometimes arrive as a bare 403/429 whose body says
+	// so. A 403 can also be a plain permission error, so only treat it as
+	// a limit when the body mentions one.
+	if resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode == http.StatusForbidden && bodyMentionsRateLimit(resp)) {
+		g.pause("", now.Add(secondaryRateLimitPause), "secondary rate limit")
+	}
+}
+
+// observeGraphQL updates the governor from a query's rateLimit field: once
+// the remaining points can't cover another query of the same cost, pause
+// GraphQL until the window resets.
+func (g *RateLimitGovernor) observeGraphQL(rl graphQLRateLimit) {
+	if rl.ResetAt.IsZero() {
+		return
+	}
+	g.observeReset(ResourceGraphQL, rl.ResetAt.Time)
+	if rl.Remaining >= max(rl.Cost, 1) {
+		return
+	}
+	g.pause(ResourceGraphQL, rl.ResetAt.Time, "GraphQL points exhausted")
+}
+
+// requestResource is the resource req spends, for responses that don't
+// say and for gating requests before they're sent.
+func requestResource(req *http.Request) string {
+	if req == nil || req.URL == nil {
+		return ResourceCore
+	}
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/graphql"):
+		return ResourceGraphQL
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return ResourceSearch
+	default:
+		return ResourceCore
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// bodyMentionsRateLimit peeks at an error response body (restoring it for
+// go-github's own error parsing) for GitHub's rate limit wording.
+func bodyMentionsRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit")
+}
+
+// governedTransport consults the governor before every request and feeds
+// it every response.
+type governedTransport struct {
+	base     http.RoundTripper
+	governor *RateLimitGovernor
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *governedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if until, reason := t.governor.PausedUntil(requestResource(req)); !until.IsZero() {
+		return nil, &RateLimitedError{Until: until, Reason: reason}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.governor.observeResponse(resp)
+	return resp, nil
+}
+
+// graphQLRateLimit is the rateLimit field every GraphQL query selects.
+type graphQLRateLimit struct {
+	Cost      int
+	Remaining int
+	ResetAt   githubv4.DateTime
+}
+
+// RateLimitResumeAt reports whether err is a rate limit and, if so, when to
+// retry: the governor's own fail-fast error, or go-github's primary and
+// secondary rate limit errors. Session.RateLimitResumeAt also treats any
+// error while its governor is paused as a limit.
+func RateLimitResumeAt(err error) (time.Time, bool) {
+	if err == nil {
+		return time.Time{}, false
+	}
+	var limited *RateLimitedError
+	if errors.As(err, &limited) {
+		return limited.Until, true
+	}
+	var primary *github.RateLimitError
+	if errors.As(err, &primary) {
+		return primary.Rate.Reset.Time, true
+	}
+	var secondary *github.AbuseRateLimitError
+	if errors.As(err, &secondary) {
+		wait := secondaryRateLimitPause
+		if secondary.RetryAfter != nil {
+			wait = *secondary.RetryAfter
+		}
+		return time.Now().Add(wait), true
+	}
+	return time.Time{}, false
+}
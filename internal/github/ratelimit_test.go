@@ -0,0 +1,255 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second, wantOK: true},
+		{value: "", wantOK: false},
+		{value: "soon", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRateLimitGovernorObserveResponse(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		want   time.Time
+	}{
+		{name: "429 with Retry-After", status: 429, header: map[string]string{"Retry-After": "30"}, want: now.Add(30 * time.Second)},
+		{name: "403 with Retry-After", status: 403, header: map[string]string{"Retry-After": "60"}, want: now.Add(time.Minute)},
+		{name: "primary quota exhausted", status: 403, header: map[string]string{
+			"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10),
+		}, want: reset},
+		{name: "search quota exhausted leaves core alone", status: 403, header: map[string]string{
+			"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10), "X-RateLimit-Resource": "search",
+		}},
+		{name: "last request of the window succeeds", status: 200, header: map[string]string{
+			"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10),
+		}, want: reset},
+		{name: "secondary limit without headers", status: 403, body: `{"message":"You have exceeded a secondary rate limit"}`, want: now.Add(secondaryRateLimitPause)},
+		{name: "permission denied is not a limit", status: 403, body: `{"message":"Resource not accessible by integration"}`},
+		{name: "ok", status: 200, header: map[string]string{"X-RateLimit-Remaining": "4000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &RateLimitGovernor{now: func() time.Time { return now }}
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			g.observeResponse(resp)
+			got, _ := g.PausedUntil(ResourceCore)
+			if !got.Equal(tt.want) {
+				t.Errorf("paused until %v, want %v", got, tt.want)
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+				t.Errorf("body not restored: %q", body)
+			}
+		})
+	}
+}
+
+func TestRateLimitGovernorObserveGraphQL(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	reset := githubv4.DateTime{Time: now.Add(30 * time.Minute)}
+
+	g := &RateLimitGovernor{now: func() time.Time { return now }}
+	g.observeGraphQL(graphQLRateLimit{Cost: 5, Remaining: 10, ResetAt: reset})
+	if until, _ := g.PausedUntil(ResourceGraphQL); !until.IsZero() {
+		t.Errorf("paused with points to spare: %v", until)
+	}
+	if got := g.ResetAt(ResourceGraphQL); !got.Equal(reset.Time) {
+		t.Errorf("ResetAt(graphql) = %v, want %v", got, reset.Time)
+	}
+	if got := g.ResetAt(ResourceCore); !got.IsZero() {
+		t.Errorf("ResetAt(core) = %v, want GraphQL's reset kept apart", got)
+	}
+	g.observeGraphQL(graphQLRateLimit{Cost: 5, Remaining: 4, ResetAt: reset})
+	if until, _ := g.PausedUntil(ResourceGraphQL); !until.Equal(reset.Time) {
+		t.Errorf("paused until %v, want resetAt %v", until, reset.Time)
+	}
+	if until, _ := g.PausedUntil(ResourceCore); !until.IsZero() {
+		t.Errorf("spent GraphQL points paused REST until %v", until)
+	}
+
+	// A shorter limit never cuts an existing pause short.
+	g.pause("", now.Add(time.Minute), "Retry-After")
+	if until, _ := g.PausedUntil(ResourceGraphQL); !until.Equal(reset.Time) {
+		t.Errorf("pause shortened to %v", until)
+	}
+	if until, _ := g.PausedUntil(ResourceCore); !until.Equal(now.Add(time.Minute)) {
+		t.Errorf("Retry-After paused REST until %v, want a minute", until)
+	}
+	// A passed reset is still reported, so pollers know the quota refilled.
+	now = reset.Time.Add(time.Minute)
+	if got := g.ResetAt(ResourceGraphQL); !got.Equal(reset.Time) {
+		t.Errorf("ResetAt(graphql) after the reset = %v, want %v", got, reset.Time)
+	}
+}
+
+func TestGovernedTransportFailsFastWhilePaused(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	g := &RateLimitGovernor{now: time.Now}
+	client := &http.Client{Transport: &governedTransport{base: http.DefaultTransport, governor: g}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	resp.Body.Close()
+
+	_, err = client.Get(server.URL)
+	var limited *RateLimitedError
+	if !errors.As(err, &limited) {
+		t.Fatalf("second request err = %v, want RateLimitedError", err)
+	}
+	if wait := time.Until(limited.Until); wait < 110*time.Second || wait > 120*time.Second {
+		t.Errorf("resume in %v, want ~2m", wait)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1 (second one never sent)", requests.Load())
+	}
+}
+
+func TestRequestResource(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.github.com/repos/o/r/actions/runs", ResourceCore},
+		{"https://api.github.com/graphql", ResourceGraphQL},
+		{"https://ghe.example.com/api/graphql", ResourceGraphQL},
+		{"https://api.github.com/search/issues?q=x", ResourceSearch},
+		{"https://ghe.example.com/api/v3/search/issues", ResourceSearch},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if got := requestResource(req); got != tt.want {
+			t.Errorf("requestResource(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGovernedTransportPausesOnlyExhaustedResource(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+			w.Header().Set("X-RateLimit-Remaining", "4000")
+			return
+		}
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+	}))
+	defer server.Close()
+
+	g := &RateLimitGovernor{now: time.Now}
+	client := &http.Client{Transport: &governedTransport{base: http.DefaultTransport, governor: g}}
+	for _, path := range []string{"/repos/o/r", "/graphql"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	// core is spent; GraphQL still answers.
+	var limited *RateLimitedError
+	if _, err := client.Get(server.URL + "/repos/o/r"); !errors.As(err, &limited) {
+		t.Errorf("REST err = %v, want RateLimitedError", err)
+	}
+	resp, err := client.Get(server.URL + "/graphql")
+	if err != nil {
+		t.Fatalf("GraphQL err = %v, want it let through", err)
+	}
+	resp.Body.Close()
+	if requests.Load() != 3 {
+		t.Errorf("requests = %d, want 3", requests.Load())
+	}
+}
+
+func TestRateLimitResumeAt(t *testing.T) {
+	reset := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	retry := 90 * time.Second
+
+	tests := []struct {
+		name   string
+		err    error
+		wantOK bool
+		want   func(time.Time) bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain error", err: errors.New("boom")},
+		{name: "governor", err: fmt.Errorf("wrapped: %w", &RateLimitedError{Until: reset}), wantOK: true,
+			want: func(got time.Time) bool { return got.Equal(reset) }},
+		{name: "primary", err: &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}}, wantOK: true,
+			want: func(got time.Time) bool { return got.Equal(reset) }},
+		{name: "secondary", err: &github.AbuseRateLimitError{RetryAfter: &retry}, wantOK: true,
+			want: func(got time.Time) bool { d := time.Until(got); return d > 80*time.Second && d <= retry }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RateLimitResumeAt(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !tt.want(got) {
+				t.Errorf("resume at %v", got)
+			}
+		})
+	}
+
+	// Sanity check that go-github surfaces the governor's error.
+	g := &RateLimitGovernor{now: time.Now}
+	g.pause(ResourceCore, reset, "test")
+	client, _ := github.NewClient(github.WithTransport(&governedTransport{base: http.DefaultTransport, governor: g}))
+	_, _, err := client.Actions.ListWorkflowJobs(context.Background(), "owner", "repo", 1, nil)
+	if got, ok := RateLimitResumeAt(err); !ok || !got.Equal(reset) {
+		t.Errorf("go-github error %v: resume at %v, %v", err, got, ok)
+	}
+}
@@ -0,0 +1,311 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+)
+
+// Recording file names inside a --record directory.
+const (
+	recordingMetaFile      = "session.json"
+	recordingExchangesFile = "exchanges.jsonl"
+)
+
+// RecordingMeta describes what a recording watched, so --replay can rebuild
+// the same session without re-resolving arguments (which would shell out to
+// gh and git in whatever directory the replay happens to run in).
+type RecordingMeta struct {
+	Started  time.Time `json:"started"`
+	Mode     string    `json:"mode"` // "pr", "run" or "repo"
+	Owner    string    `json:"owner"`
+	Repo     string    `json:"repo"`
+	PRNumber int       `json:"pr_number,omitempty"`
+	RunID    int64     `json:"run_id,omitempty"`
+}
+
+// Exchange is one recorded request and its response. Offset is when the
+// request was sent relative to RecordingMeta.Started; Duration is how long
+// the response took. Requests that never got a response (including ones
+// the rate-limit governor refused) carry Error instead of a status.
+type Exchange struct {
+	Offset      time.Duration `json:"offset"`
+	Duration    time.Duration `json:"duration"`
+	Method      string        `json:"method"`
+	URL         string        `json:"url"`
+	RequestBody string        `json:"request_body,omitempty"`
+	Status      int           `json:"status,omitempty"`
+	Header      http.Header   `json:"header,omitempty"`
+	Body        string        `json:"body,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// scrubbedToken replaces the token wherever it would otherwise appear in a
+// recording.
+const scrubbedToken = "[REDACTED]"
+
+// Recorder writes every exchange passing through it to a recording
+// directory, leaving out request headers and redacting the token.
+type Recorder struct {
+	base  http.RoundTripper
+	token string
+	start time.Time
+	now   func() time.Time
+
+	mu  sync.Mutex
+	out *os.File
+	enc *json.Encoder
+}
+
+// NewRecorder creates dir (if needed), writes meta with the current time as
+// Started, and returns a Recorder to attach to a session with WithRecorder.
+// token is what to scrub.
+func NewRecorder(dir, token string, meta RecordingMeta) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating recording directory: %w", err)
+	}
+	meta.Started = time.Now()
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, recordingMetaFile), append(metaJSON, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("writing recording metadata: %w", err)
+	}
+	out, err := os.Create(filepath.Join(dir, recordingExchangesFile))
+	if err != nil {
+		return nil, fmt.Errorf("creating recording: %w", err)
+	}
+	return &Recorder{token: token, start: meta.Started, now: time.Now, out: out, enc: json.NewEncoder(out)}, nil
+}
+
+// Close flushes and closes the recording.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.out.Close()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	ex := Exchange{Offset: r.now().Sub(r.start), Method: req.Method, URL: req.URL.String()}
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			body.Close()
+			ex.RequestBody = r.scrub(string(b))
+		}
+	}
+
+	sent := time.Now()
+	resp, err := r.base.RoundTrip(req)
+	ex.Duration = time.Since(sent)
+	if err != nil {
+		ex.Error = r.scrub(err.Error())
+		r.write(ex)
+		return nil, err
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		ex.Error = readErr.Error()
+	}
+	ex.Status = resp.StatusCode
+	ex.Header = resp.Header.Clone()
+	ex.Body = r.scrub(string(body))
+	r.write(ex)
+	return resp, nil
+}
+
+func (r *Recorder) scrub(s string) string {
+	if r.token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, r.token, scrubbedToken)
+}
+
+func (r *Recorder) write(ex Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(ex); err != nil {
+		debug.Log("recording write failed", "err", err)
+	}
+}
+
+// Replayer answers requests from a recording, replaying its timeline
+// against the wall clock scaled by speed (see WithBaseTransport).
+type Replayer struct {
+	meta  RecordingMeta
+	speed float64
+	start time.Time
+	now   func() time.Time
+
+	// exchanges maps a normalized request to its recorded responses in
+	// recording order.
+	exchanges map[string][]Exchange
+}
+
+// OpenReplay loads the recording in dir for replay at speed (1 is real
+// time, 10 is ten times faster).
+func OpenReplay(dir string, speed float64) (*Replayer, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be positive, got %v", speed)
+	}
+	metaJSON, err := os.ReadFile(filepath.Join(dir, recordingMetaFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading recording metadata: %w", err)
+	}
+	var meta RecordingMeta
+	if err := json.Unmarshal(metaJSON, &meta); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", recordingMetaFile, err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, recordingExchangesFile))
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close()
+
+	r := &Replayer{meta: meta, speed: speed, start: time.Now(), now: time.Now, exchanges: make(map[string][]Exchange)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var ex Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", recordingExchangesFile, line, err)
+		}
+		key := replayKey(ex.Method, ex.URL, ex.RequestBody)
+		r.exchanges[key] = append(r.exchanges[key], ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading recording: %w", err)
+	}
+	return r, nil
+}
+
+// Meta returns what the recording watched.
+func (r *Replayer) Meta() RecordingMeta {
+	return r.meta
+}
+
+// timestampPattern matches the RFC 3339 timestamps GitHub returns, and the
+// bare dates gh-observer puts in created>= history filters.
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?`)
+
+// replayKey identifies a request independent of when it was sent: the host
+// is dropped (so a replay needn't match the recording's API endpoint) and
+// dates are blanked (history queries filter on a window ending now).
+func replayKey(method, rawURL, body string) string {
+	target := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		target = u.Path
+		if u.RawQuery != "" {
+			query, err := url.QueryUnescape(u.RawQuery)
+			if err != nil {
+				query = u.RawQuery
+			}
+			target += "?" + query
+		}
+	}
+	normalize := func(s string) string { return timestampPattern.ReplaceAllString(s, "DATE") }
+	return method + " " + normalize(target) + "\x00" + normalize(body)
+}
+
+// recordedNow is the point in the recording the replay has reached.
+func (r *Replayer) recordedNow() time.Duration {
+	return time.Duration(float64(r.now().Sub(r.start)) * r.speed)
+}
+
+// shift maps a recorded instant onto the replay's timeline.
+func (r *Replayer) shift(t time.Time) time.Time {
+	return r.start.Add(time.Duration(float64(t.Sub(r.meta.Started)) / r.speed))
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = string(b)
+	}
+
+	candidates := r.exchanges[replayKey(req.Method, req.URL.String(), body)]
+	if len(candidates) == 0 {
+		debug.Log("replay miss", "method", req.Method, "url", req.URL.String())
+		return r.response(req, Exchange{
+			Status: http.StatusNotFound,
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   `{"message":"Not Found in recording"}`,
+		}), nil
+	}
+	ex := candidates[0]
+	reached := r.recordedNow()
+	for _, c := range candidates[1:] {
+		if c.Offset > reached {
+			break
+		}
+		ex = c
+	}
+
+	if ex.Duration > 0 {
+		select {
+		case <-time.After(time.Duration(float64(ex.Duration) / r.speed)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	return r.response(req, ex), nil
+}
+
+// response builds the replayed response with timestamps shifted.
+func (r *Replayer) response(req *http.Request, ex Exchange) *http.Response {
+	body := timestampPattern.ReplaceAllStringFunc(ex.Body, func(s string) string {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return s // a bare date; nothing reads those from responses
+		}
+		return r.shift(t).UTC().Format(time.RFC3339)
+	})
+	header := ex.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Content-Length") // shifting can change the body's length
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(r.shift(time.Unix(reset, 0)).Unix(), 10))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		StatusCode:    ex.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
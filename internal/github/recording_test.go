@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	const token = "ghp_secret123"
+
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute}},
+	})
+	pushed := srv.Now()
+
+	// Record two polls two minutes apart: queued, then completed.
+	rec, err := NewRecorder(dir, token, RecordingMeta{Mode: "pr", Owner: "octo", Repo: "hello", PRNumber: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recClock := rec.start
+	rec.now = func() time.Time { return recClock }
+	live, err := NewSession(token, WithEndpoints(srv.URL(), srv.GraphQLURL()), WithRecorder(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, _, _, err := live.FetchCheckRuns(ctx, "octo", "hello", 12); err != nil {
+			t.Fatalf("live FetchCheckRuns: %v", err)
+		}
+		srv.Advance(2 * time.Minute)
+		recClock = recClock.Add(2 * time.Minute)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := os.ReadFile(filepath.Join(dir, recordingExchangesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(recording), token) {
+		t.Error("recording contains the token")
+	}
+
+	tests := []struct {
+		name    string
+		speed   float64
+		advance time.Duration // replay wall time until the second poll
+	}{
+		{name: "real time", speed: 1, advance: 2 * time.Minute},
+		{name: "accelerated", speed: 4, advance: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, err := OpenReplay(dir, tt.speed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta := replay.Meta(); meta.Mode != "pr" || meta.PRNumber != 12 {
+				t.Errorf("meta = %+v, want the recorded PR", meta)
+			}
+			clock := replay.start
+			replay.now = func() time.Time { return clock }
+			client, err := NewSession("replay", WithBaseTransport(replay))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checks, gotPushed, _, err := client.FetchCheckRuns(ctx, "octo", "hello", 12)
+			if err != nil {
+				t.Fatalf("replayed FetchCheckRuns: %v", err)
+			}
+			if len(checks) != 1 || checks[0].Status != "queued" {
+				t.Errorf("first poll = %+v, want build queued", checks)
+			}
+			if want := replay.shift(pushed).Truncate(time.Second); !gotPushed.Equal(want) {
+				t.Errorf("pushed = %v, want %v shifted onto the replay timeline", gotPushed, want)
+			}
+
+			clock = clock.Add(tt.advance)
+			checks, _, _, err = client.FetchCheckRuns(ctx, "octo", "hello", 12)
+			if err != nil {
+				t.Fatalf("replayed FetchCheckRuns: %v", err)
+			}
+			if len(checks) != 1 || checks[0].Status != "completed" {
+				t.Errorf("second poll = %+v, want build completed", checks)
+			}
+
+			if _, err := client.FetchPRInfo(ctx, "octo", "hello", 12); err == nil {
+				t.Error("FetchPRInfo succeeded, want a miss for a request never recorded")
+			}
+		})
+	}
+}
+
+func TestReplayKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b [3]string // method, URL, body
+		same bool
+	}{
+		{
+			name: "host ignored",
+			a:    [3]string{"GET", "https://api.github.com/repos/o/r/actions/runs/1", ""},
+			b:    [3]string{"GET", "http://127.0.0.1:1234/repos/o/r/actions/runs/1", ""},
+			same: true,
+		},
+		{
+			name: "history window dates ignored",
+			a:    [3]string{"GET", "https://api.github.com/repos/o/r/actions/workflows/7/runs?created=%3E%3D2026-10-04&status=success", ""},
+			b:    [3]string{"GET", "https://api.github.com/repos/o/r/actions/workflows/7/runs?created=%3E%3D2026-10-18&status=success", ""},
+			same: true,
+		},
+		{
+			name: "different query",
+			a:    [3]string{"GET", "https://api.github.com/repos/o/r/actions/runs?status=in_progress", ""},
+			b:    [3]string{"GET", "https://api.github.com/repos/o/r/actions/runs?status=queued", ""},
+		},
+		{
+			name: "different GraphQL variables",
+			a:    [3]string{"POST", "https://api.github.com/graphql", `{"variables":{"prNumber":1}}`},
+			b:    [3]string{"POST", "https://api.github.com/graphql", `{"variables":{"prNumber":2}}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := replayKey(tt.a[0], tt.a[1], tt.a[2])
+			b := replayKey(tt.b[0], tt.b[1], tt.b[2])
+			if (a == b) != tt.same {
+				t.Errorf("keys equal = %v, want %v:\n%q\n%q", a == b, tt.same, a, b)
+			}
+		})
+	}
+}
@@ -16,6 +16,17 @@ var (
 	gitHTTPSRemoteRE = regexp.MustCompile(`^https?://github\.com/([a-zA-Z0-9_.-]+)/([a-zA-Z0-9_.-]+?)(?:\.git)?/?$`)
 )
 
+// RepoRef names a repository, as repo mode's multi-repo calls take them.
+type RepoRef struct {
+	Owner string
+	Name  string
+}
+
+// String renders r as "owner/name".
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Name
+}
+
 // ParseRepoArg extracts owner and repo from a string in "owner/repo" or
 // "https://github.com/owner/repo" format. PR URLs and Actions run URLs are
 // rejected — this is only for repo-level arguments.
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ViewerAlias stands for the authenticated user in RepoFilter.Author, as
+// in gh's own --author @me.
+const ViewerAlias = "@me"
+
+// RepoFilter narrows repo mode's PRs and standalone runs; the zero value
+// matches everything.
+type RepoFilter struct {
+	// Author is a PR author / run actor login, or ViewerAlias.
+	Author string
+	// Labels keeps PRs carrying any of these labels.
+	Labels []string
+	// Base is the PR base branch, and the branch of standalone runs.
+	Base string
+	// NoDrafts drops draft PRs.
+	NoDrafts bool
+}
+
+// IsZero reports whether f matches everything.
+func (f RepoFilter) IsZero() bool {
+	return f.Author == "" && len(f.Labels) == 0 && f.Base == "" && !f.NoDrafts
+}
+
+// Equal reports whether f and other filter the same way.
+func (f RepoFilter) Equal(other RepoFilter) bool {
+	return f.Author == other.Author && slices.Equal(f.Labels, other.Labels) &&
+		f.Base == other.Base && f.NoDrafts == other.NoDrafts
+}
+
+// String renders f in the syntax ParseRepoFilter reads, e.g.
+// "author:@me label:bug base:main -draft". The zero filter is "".
+func (f RepoFilter) String() string {
+	var terms []string
+	if f.Author != "" {
+		terms = append(terms, "author:"+f.Author)
+	}
+	for _, l := range f.Labels {
+		terms = append(terms, "label:"+l)
+	}
+	if f.Base != "" {
+		terms = append(terms, "base:"+f.Base)
+	}
+	if f.NoDrafts {
+		terms = append(terms, "-draft")
+	}
+	return strings.Join(terms, " ")
+}
+
+// ParseRepoFilter reads the filter prompt's syntax: space-separated
+// author:LOGIN, label:NAME (repeatable), base:BRANCH and -draft terms.
+// An empty string is the zero filter.
+func ParseRepoFilter(s string) (RepoFilter, error) {
+	var f RepoFilter
+	for term := range strings.FieldsSeq(s) {
+		if term == "-draft" {
+			f.NoDrafts = true
+			continue
+		}
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			return RepoFilter{}, fmt.Errorf("invalid filter term %q: want author:LOGIN, label:NAME, base:BRANCH or -draft", term)
+		}
+		switch key {
+		case "author":
+			f.Author = value
+		case "label":
+			if !slices.Contains(f.Labels, value) {
+				f.Labels = append(f.Labels, value)
+			}
+		case "base":
+			f.Base = value
+		default:
+			return RepoFilter{}, fmt.Errorf("unknown filter %q: want author, label, base or -draft", key)
+		}
+	}
+	return f, nil
+}
+
+// filtersListed reports whether matchesListedPR can reject a listed PR.
+func (f RepoFilter) filtersListed() bool {
+	return f.Author != "" || f.NoDrafts
+}
+
+// matchesListedPR applies the filters the pullRequests query can't.
+func (f RepoFilter) matchesListedPR(author string, isDraft bool) bool {
+	if f.NoDrafts && isDraft {
+		return false
+	}
+	return f.Author == "" || strings.EqualFold(f.Author, author)
+}
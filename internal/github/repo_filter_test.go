@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestParseRepoFilter(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RepoFilter
+		wantStr string
+		wantErr bool
+	}{
+		{input: "", want: RepoFilter{}, wantStr: ""},
+		{input: "author:@me", want: RepoFilter{Author: "@me"}, wantStr: "author:@me"},
+		{
+			input:   "  -draft label:bug base:main   label:ci label:bug author:octo ",
+			want:    RepoFilter{Author: "octo", Labels: []string{"bug", "ci"}, Base: "main", NoDrafts: true},
+			wantStr: "author:octo label:bug label:ci base:main -draft",
+		},
+		{input: "author:", wantErr: true},
+		{input: "bug", wantErr: true},
+		{input: "state:open", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRepoFilter(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantStr)
+			}
+			if again, _ := ParseRepoFilter(got.String()); !again.Equal(got) {
+				t.Errorf("round trip = %+v, want %+v", again, got)
+			}
+			if got.IsZero() != (tt.wantStr == "") {
+				t.Errorf("IsZero() = %v for %q", got.IsZero(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestFetchRepoCheckRunsFiltered(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	for _, pr := range []fakegithub.PullRequest{
+		{Number: 1, Author: "octo", Labels: []string{"bug"}},
+		{Number: 2, Author: "octo", Draft: true},
+		{Number: 3, Author: "hubot", Labels: []string{"bug", "ci"}},
+		{Number: 4, Author: "octo", Base: "release"},
+	} {
+		pr.HeadSHA = fmt.Sprintf("sha%d", pr.Number)
+		srv.AddPullRequest(pr)
+	}
+
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{filter: "", want: []int{1, 2, 3, 4}},
+		{filter: "author:@me", want: []int{1, 2, 4}},
+		{filter: "author:hubot", want: []int{3}},
+		{filter: "label:bug", want: []int{1, 3}},
+		{filter: "label:ci label:nope", want: []int{3}},
+		{filter: "base:main -draft", want: []int{1, 3}},
+		{filter: "author:@me base:main -draft", want: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := ParseRepoFilter(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prs, omitted, _, err := api.FetchRepoCheckRuns(context.Background(), "octo", "hello", filter)
+			if err != nil {
+				t.Fatalf("FetchRepoCheckRuns: %v", err)
+			}
+			var got []int
+			for n := range prs {
+				got = append(got, n)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) || omitted != 0 {
+				t.Errorf("PRs = %v (omitted %d), want %v", got, omitted, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRepoWorkflowRunsFiltered(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	for _, run := range []fakegithub.Run{
+		{ID: 1, Actor: "octo", HeadBranch: "main"},
+		{ID: 2, Actor: "hubot", HeadBranch: "main"},
+		{ID: 3, Actor: "octo", HeadBranch: "feature"},
+	} {
+		run.WorkflowID, run.Name, run.Event = 10, "Deploy", "push"
+		run.Jobs = []fakegithub.Job{{Name: "deploy"}}
+		srv.AddRun(run)
+	}
+
+	tests := []struct {
+		filter RepoFilter
+		want   []int64
+	}{
+		{filter: RepoFilter{}, want: []int64{1, 2, 3}},
+		{filter: RepoFilter{Author: ViewerAlias}, want: []int64{1, 3}},
+		{filter: RepoFilter{Base: "main"}, want: []int64{1, 2}},
+		{filter: RepoFilter{Author: ViewerAlias, Base: "main", Labels: []string{"bug"}}, want: []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter.String(), func(t *testing.T) {
+			runs, _, err := api.FetchRepoWorkflowRuns(context.Background(), "octo", "hello", time.Hour, tt.filter)
+			if err != nil {
+				t.Fatalf("FetchRepoWorkflowRuns: %v", err)
+			}
+			var got []int64
+			for _, r := range runs {
+				got = append(got, r.RunID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("runs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRepoFilterCachesViewer(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	for range 3 {
+		f, err := api.resolveRepoFilter(context.Background(), RepoFilter{Author: ViewerAlias})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Author != "octo" {
+			t.Errorf("Author = %q, want octo", f.Author)
+		}
+	}
+	if got := srv.Requests(); !slices.Equal(got, []string{"POST /graphql viewer"}) {
+		t.Errorf("requests = %v, want one viewer query", got)
+	}
+}
@@ -2,22 +2,41 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/fini-net/gh-observer/internal/debug"
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
-// maxPRsPerQuery caps the number of open PRs fetched per repo query.
-// GitHub's GraphQL server-side query timeout is ~10s. Fetching check rollups
-// (contexts(first: 100)) for each PR scales linearly: prLimit=50 504s on
-// high-traffic repos like grafana/grafana (137 workflows, thousands of open
-// PRs), while prLimit=10 returns in ~6s even for grafana. 10 most-recently-
-// updated PRs is a useful persistent overview; the fade-out window means
-// older completed checks disappear from the display soon anyway.
-const maxPRsPerQuery = 10
+// RepoPRsPerQuery is how many PRs' check rollups one repo-mode query
+// fetches. GitHub's GraphQL server-side query timeout is ~10s, and fetching
+// check rollups (contexts(first: 100)) scales linearly with the PR count:
+// 50 PRs per query 504s on high-traffic repos like grafana/grafana (137
+// workflows, thousands of open PRs), while 10 returns in ~6s even there.
+// Repos with more open PRs get several of these queries per poll, within
+// repoQueryCostBudget.
+const RepoPRsPerQuery = 10
+
+// ReposPerListQuery is how many repositories' open PR listings one query
+// fetches, each as an aliased repository field. A listing page costs a
+// point or two, so a dashboard of 25 repos lists in three queries.
+const ReposPerListQuery = 10
+
+// repoQueryCostBudget caps the GraphQL points one repo-mode poll spends on
+// check rollups, about 40 PRs; the rest are reported as omitted.
+const repoQueryCostBudget = 50
+
+// reposQueryCostBudget is repoQueryCostBudget for a poll across n repos:
+// two more points per extra repo, enough for its listing. Check rollups
+// are fetched busiest first across every repo, so the budget for them
+// doesn't grow with the repo count.
+func reposQueryCostBudget(n int) int {
+	return repoQueryCostBudget + 2*max(n-1, 0)
+}
 
 // PRCheckData holds check run data for a single PR in repo mode.
 // HeadSHA is the PR head commit OID, used to dedupe standalone branch runs
@@ -32,6 +51,14 @@ type PRCheckData struct {
 	HeadSHA        string
 }
 
+// RepoCheckRuns is one repo's share of a multi-repo checks fetch.
+type RepoCheckRuns struct {
+	PRs map[int]PRCheckData
+	// Omitted counts the repo's open PRs matching the filter that the
+	// query budget left out.
+	Omitted int
+}
+
 // repoContextNode is the union type for StatusCheckRollup contexts in the
 // repo-mode query. It is a trimmed copy of contextNode from graphql.go that
 // OMITS the annotations(first: 5) field. Annotations are the single most
@@ -55,6 +82,7 @@ type repoContextNode struct {
 			WorkflowRun struct {
 				DatabaseID BigInt `graphql:"databaseId"`
 				Workflow   struct {
+					ID         string
 					DatabaseID BigInt `graphql:"databaseId"`
 					Name       string
 				}
@@ -73,81 +101,340 @@ type repoContextNode struct {
 	} `graphql:"... on StatusContext"`
 }
 
-// repoPRQuery fetches open PRs with their check rollup in a single query.
-// Uses repoContextNode (no annotations) to stay under GitHub's query cost limit.
-type repoPRQuery struct {
-	Repository struct {
-		PullRequests struct {
+// pageInfo is a connection's cursor state.
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   githubv4.String
+}
+
+// repoPRConnection is a page of a repo's open PRs without their check
+// contexts: just enough (the head's rollup state, author, draft flag and
+// last update) to decide which PRs are worth a detailed fetch. At 100 PRs
+// per page it costs a point or two.
+type repoPRConnection struct {
+	Nodes      []listedPR
+	PageInfo   pageInfo
+	TotalCount int
+}
+
+// listedPR is an open PR as the listing and the PR search return it.
+type listedPR struct {
+	ID        string
+	Number    int
+	IsDraft   bool
+	UpdatedAt githubv4.DateTime
+	Author    struct {
+		Login string
+	}
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup struct {
+					State string
+				}
+			}
+		}
+	} `graphql:"commits(last: 1)"`
+}
+
+// ref ranks pr, listed in repo, for the detail fetch.
+func (pr listedPR) ref(repo RepoRef) repoPRRef {
+	ref := repoPRRef{Repo: repo, ID: pr.ID, Number: pr.Number, UpdatedAt: pr.UpdatedAt.Time}
+	if len(pr.Commits.Nodes) > 0 {
+		switch pr.Commits.Nodes[0].Commit.StatusCheckRollup.State {
+		case "PENDING", "EXPECTED":
+			ref.Active = true
+		}
+	}
+	return ref
+}
+
+// repoPRListQueryType builds the listing query for n repos, each aliased
+// rN with its own $oN, $nN and cursor $cN.
+func repoPRListQueryType(n int) reflect.Type {
+	fields := make([]reflect.StructField, 0, n+1)
+	for i := range n {
+		repository := reflect.StructOf([]reflect.StructField{{
+			Name: "PullRequests",
+			Type: reflect.TypeFor[repoPRConnection](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"pullRequests(first: 100, after: $c%d, states: OPEN, labels: $labels, baseRefName: $baseRef, orderBy: {field: UPDATED_AT, direction: DESC})"`, i)),
+		}})
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("R%d", i),
+			Type: repository,
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"r%d: repository(owner: $o%d, name: $n%d)"`, i, i, i)),
+		})
+	}
+	fields = append(fields, reflect.StructField{
+		Name: "RateLimit",
+		Type: reflect.TypeFor[graphQLRateLimit](),
+	})
+	return reflect.StructOf(fields)
+}
+
+// repoContexts is a page of a commit's check rollup contexts. It uses
+// repoContextNode (no annotations) to stay under GitHub's query cost limit.
+type repoContexts struct {
+	Nodes    []repoContextNode
+	PageInfo pageInfo
+}
+
+// repoCommit is a PR head commit with the first page of its check rollup.
+// ID is the commit's node ID, for fetching further pages.
+type repoCommit struct {
+	ID                string
+	PushedDate        githubv4.DateTime `graphql:"pushedDate"`
+	CommittedDate     githubv4.DateTime `graphql:"committedDate"`
+	OID               githubv4.String   `graphql:"oid"`
+	StatusCheckRollup struct {
+		Contexts repoContexts `graphql:"contexts(first: 100)"`
+	}
+}
+
+// repoPRNode is one aliased PR in a batched detail query.
+type repoPRNode struct {
+	PullRequest struct {
+		Number  int
+		Title   string
+		Commits struct {
 			Nodes []struct {
-				Number  int
-				Title   string
-				Commits struct {
-					Nodes []struct {
-						Commit struct {
-							PushedDate        githubv4.DateTime `graphql:"pushedDate"`
-							CommittedDate     githubv4.DateTime `graphql:"committedDate"`
-							OID               githubv4.String   `graphql:"oid"`
-							StatusCheckRollup struct {
-								Contexts struct {
-									Nodes    []repoContextNode
-									PageInfo struct {
-										HasNextPage bool
-										EndCursor   githubv4.String
-									}
-								} `graphql:"contexts(first: 100)"`
-							}
-						}
-					}
-				} `graphql:"commits(last: 1)"`
+				Commit repoCommit
+			}
+		} `graphql:"commits(last: 1)"`
+	} `graphql:"... on PullRequest"`
+}
+
+// repoContextsQuery fetches a further page of a commit's check rollup.
+type repoContextsQuery struct {
+	Node struct {
+		Commit struct {
+			StatusCheckRollup struct {
+				Contexts repoContexts `graphql:"contexts(first: 100, after: $contextsCursor)"`
 			}
-		} `graphql:"pullRequests(first: $prLimit, states: OPEN, orderBy: {field: UPDATED_AT, direction: DESC})"`
-	} `graphql:"repository(owner: $owner, name: $repo)"`
-	RateLimit struct {
-		Remaining int
+		} `graphql:"... on Commit"`
+	} `graphql:"node(id: $id)"`
+	RateLimit graphQLRateLimit
+}
+
+// repoPRDetailQueryType builds the batched detail query for n PRs: fields
+// P0..Pn-1 aliased as p0..pn-1 over node(id: $p0) and so on, as
+// historyQueryType does for workflows.
+func repoPRDetailQueryType(n int) reflect.Type {
+	fields := make([]reflect.StructField, 0, n+1)
+	for i := range n {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("P%d", i),
+			Type: reflect.TypeFor[repoPRNode](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"p%d: node(id: $p%d)"`, i, i)),
+		})
 	}
+	fields = append(fields, reflect.StructField{
+		Name: "RateLimit",
+		Type: reflect.TypeFor[graphQLRateLimit](),
+	})
+	return reflect.StructOf(fields)
 }
 
-// FetchRepoCheckRunsGraphQL fetches check runs for all open PRs in a repo
-// using a single batched GraphQL query. Returns a map of PR number to PRCheckData
-// and the remaining rate limit.
-//
-// Note: each PR's status contexts are capped at 100 (no cursor pagination).
-// PRs with more than 100 status contexts will show an incomplete set; a future
-// enhancement can fall back to per-PR pagination for PRs that hit the cap.
+// repoPRRef is a listed open PR, ranked for the detail fetch.
+type repoPRRef struct {
+	Repo      RepoRef
+	ID        string
+	Number    int
+	UpdatedAt time.Time
+	// Active is whether the head's checks are still pending, per the
+	// rollup state (PENDING, or EXPECTED for required checks not yet
+	// reported).
+	Active bool
+}
+
+// repoQueryCost tracks GraphQL spend over one poll.
+type repoQueryCost struct {
+	spent, budget, remaining int
+}
+
+// observe records a query's reported cost and the quota left after it.
+// Queries report cost 0 when the server omits it; count them as 1.
+func (c *repoQueryCost) observe(rl graphQLRateLimit) {
+	c.spent += max(rl.Cost, 1)
+	c.remaining = min(c.remaining, rl.Remaining)
+}
+
+func (c *repoQueryCost) exhausted() bool {
+	return c.spent >= c.budget
+}
+
+// fetchRepoCheckRunsGraphQL fetches the check runs of repos' open PRs
+// matching filter, active PRs first, until budget GraphQL points are spent.
+// It returns each repo's PRs and omitted count, and the remaining rate limit.
 //
-// The query deliberately omits the annotations(first: 5) field to stay under
-// GitHub's GraphQL query cost limit on high-traffic repos. Repo mode never
-// renders inline error annotations (only single-PR mode does), so CheckRunInfo
-// entries from this path have an empty Annotations slice.
-func FetchRepoCheckRunsGraphQL(ctx context.Context, token, owner, repo string) (map[int]PRCheckData, int, error) {
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(ctx, src)
-	client := githubv4.NewClient(httpClient)
-	return fetchRepoCheckRunsGraphQL(ctx, client, owner, repo)
-}
-
-func fetchRepoCheckRunsGraphQL(ctx context.Context, client graphqlQuerier, owner, repo string) (map[int]PRCheckData, int, error) {
-	var query repoPRQuery
-	variables := map[string]any{
-		"owner":   githubv4.String(owner),
-		"repo":    githubv4.String(repo),
-		"prLimit": githubv4.Int(maxPRsPerQuery),
-	}
-
-	err := client.Query(ctx, &query, variables)
+// The queries deliberately omit the annotations(first: 5) field to stay
+// under GitHub's GraphQL query cost limit on high-traffic repos. Repo mode
+// never renders inline error annotations (only single-PR mode does), so
+// CheckRunInfo entries from this path have an empty Annotations slice.
+func fetchRepoCheckRunsGraphQL(ctx context.Context, client graphqlQuerier, repos []RepoRef, filter RepoFilter, budget int) (map[RepoRef]RepoCheckRuns, int, error) {
+	cost := &repoQueryCost{budget: budget, remaining: 5000}
+	refs, unlisted, err := listRepoPRs(ctx, client, repos, filter, cost)
 	if err != nil {
-		debug.Log("repo graphql query failed", "owner", owner, "repo", repo, "err", err)
-		return nil, 5000, err
+		return nil, cost.remaining, err
 	}
 
-	debug.Log("repo graphql query success", "owner", owner, "repo", repo,
-		"pr_count", len(query.Repository.PullRequests.Nodes),
-		"rate_limit_remaining", query.RateLimit.Remaining)
+	result := make(map[RepoRef]RepoCheckRuns, len(repos))
+	for _, repo := range repos {
+		result[repo] = RepoCheckRuns{PRs: make(map[int]PRCheckData), Omitted: unlisted[repo]}
+	}
+	fetched, err := fetchRankedPRs(ctx, client, refs, cost, result)
+	if err != nil {
+		return nil, cost.remaining, err
+	}
+
+	debug.Log("repo graphql query success", "repos", len(repos),
+		"matching_prs", len(refs), "fetched", fetched, "omitted", len(refs)-fetched,
+		"cost", cost.spent, "rate_limit_remaining", cost.remaining)
+	return result, cost.remaining, nil
+}
+
+// repoListing is one repo's progress through the open PR listing.
+type repoListing struct {
+	repo          RepoRef
+	cursor        *githubv4.String
+	listed, total int
+}
+
+// listRepoPRs lists repos' open PRs matching filter, active first, then
+// most recently updated, with the count per repo the budget left unlisted.
+// Unlisted PRs are only counted when filter has no author or -draft part,
+// since those are applied to listed PRs and can't be known for the rest.
+func listRepoPRs(ctx context.Context, client graphqlQuerier, repos []RepoRef, filter RepoFilter, cost *repoQueryCost) ([]repoPRRef, map[RepoRef]int, error) {
+	var labels *[]githubv4.String
+	if len(filter.Labels) > 0 {
+		l := make([]githubv4.String, 0, len(filter.Labels))
+		for _, label := range filter.Labels {
+			l = append(l, githubv4.String(label))
+		}
+		labels = &l
+	}
+	var baseRef *githubv4.String
+	if filter.Base != "" {
+		baseRef = githubv4.NewString(githubv4.String(filter.Base))
+	}
+
+	listings := make([]*repoListing, 0, len(repos))
+	for _, repo := range repos {
+		listings = append(listings, &repoListing{repo: repo})
+	}
 
-	rateLimitRemaining := query.RateLimit.Remaining
+	var refs []repoPRRef
+	pending := listings
+	// Every repo's first page is listed; further pages stop once the
+	// budget is spent.
+paging:
+	for first := true; len(pending) > 0; first = false {
+		var more []*repoListing
+		for chunk := range slices.Chunk(pending, ReposPerListQuery) {
+			if !first && cost.exhausted() {
+				break paging
+			}
+			query := reflect.New(repoPRListQueryType(len(chunk)))
+			variables := map[string]any{
+				"labels":  labels,
+				"baseRef": baseRef,
+			}
+			for i, l := range chunk {
+				variables[fmt.Sprintf("o%d", i)] = githubv4.String(l.repo.Owner)
+				variables[fmt.Sprintf("n%d", i)] = githubv4.String(l.repo.Name)
+				variables[fmt.Sprintf("c%d", i)] = l.cursor
+			}
+			if err := client.Query(ctx, query.Interface(), variables); err != nil {
+				debug.Log("repo graphql list query failed", "repos", len(chunk), "first", chunk[0].repo.String(), "err", err)
+				return nil, nil, err
+			}
+			cost.observe(query.Elem().FieldByName("RateLimit").Interface().(graphQLRateLimit))
+
+			for i, l := range chunk {
+				prs := query.Elem().Field(i).Field(0).Interface().(repoPRConnection)
+				l.total = prs.TotalCount
+				l.listed += len(prs.Nodes)
+				for _, pr := range prs.Nodes {
+					if filter.matchesListedPR(pr.Author.Login, pr.IsDraft) {
+						refs = append(refs, pr.ref(l.repo))
+					}
+				}
+				if prs.PageInfo.HasNextPage {
+					cursor := prs.PageInfo.EndCursor
+					l.cursor = &cursor
+					more = append(more, l)
+				}
+			}
+		}
+		pending = more
+	}
 
-	result := make(map[int]PRCheckData)
-	for _, pr := range query.Repository.PullRequests.Nodes {
+	rankPRRefs(refs)
+
+	unlisted := make(map[RepoRef]int, len(listings))
+	if !filter.filtersListed() {
+		for _, l := range listings {
+			unlisted[l.repo] = max(l.total-l.listed, 0)
+		}
+	}
+	return refs, unlisted, nil
+}
+
+// rankPRRefs orders refs for the detail fetch: active first, then most
+// recently updated. It is stable, so PRs updated at the same moment keep
+// the API's order.
+func rankPRRefs(refs []repoPRRef) {
+	slices.SortStableFunc(refs, func(a, b repoPRRef) int {
+		switch {
+		case a.Active != b.Active && a.Active:
+			return -1
+		case a.Active != b.Active:
+			return 1
+		default:
+			return b.UpdatedAt.Compare(a.UpdatedAt)
+		}
+	})
+}
+
+// fetchRankedPRs fetches check rollups for ranked refs into result, which
+// must already hold every ref's repo, RepoPRsPerQuery at a time until the
+// budget is spent; the first batch always runs. Refs past the budget are
+// counted in their repo's Omitted. Returns how many refs were fetched.
+func fetchRankedPRs(ctx context.Context, client graphqlQuerier, refs []repoPRRef, cost *repoQueryCost, result map[RepoRef]RepoCheckRuns) (int, error) {
+	fetched := 0
+	for batch := range slices.Chunk(refs, RepoPRsPerQuery) {
+		if fetched > 0 && cost.exhausted() {
+			break
+		}
+		if err := fetchRepoPRBatch(ctx, client, batch, cost, result); err != nil {
+			return fetched, err
+		}
+		fetched += len(batch)
+	}
+	for _, ref := range refs[fetched:] {
+		r := result[ref.Repo]
+		r.Omitted++
+		result[ref.Repo] = r
+	}
+	return fetched, nil
+}
+
+// fetchRepoPRBatch fetches one batch of PRs' check rollups into result,
+// following any commit's contexts past the first page while budget lasts.
+func fetchRepoPRBatch(ctx context.Context, client graphqlQuerier, batch []repoPRRef, cost *repoQueryCost, result map[RepoRef]RepoCheckRuns) error {
+	query := reflect.New(repoPRDetailQueryType(len(batch)))
+	variables := make(map[string]any, len(batch))
+	for i, ref := range batch {
+		variables[fmt.Sprintf("p%d", i)] = githubv4.ID(ref.ID)
+	}
+	if err := client.Query(ctx, query.Interface(), variables); err != nil {
+		debug.Log("repo graphql detail query failed", "prs", len(batch), "err", err)
+		return err
+	}
+	cost.observe(query.Elem().FieldByName("RateLimit").Interface().(graphQLRateLimit))
+
+	for i, ref := range batch {
+		pr := query.Elem().Field(i).Interface().(repoPRNode).PullRequest
 		if len(pr.Commits.Nodes) == 0 {
 			continue
 		}
@@ -160,13 +447,32 @@ func fetchRepoCheckRunsGraphQL(ctx context.Context, client graphqlQuerier, owner
 			headPushedTime = commit.CommittedDate.Time
 		}
 
-		checkRuns := repoContextNodesToCheckRuns(commit.StatusCheckRollup.Contexts.Nodes)
+		contexts := commit.StatusCheckRollup.Contexts
+		nodes := contexts.Nodes
+		for contexts.PageInfo.HasNextPage && !cost.exhausted() {
+			var page repoContextsQuery
+			err := client.Query(ctx, &page, map[string]any{
+				"id":             githubv4.ID(commit.ID),
+				"contextsCursor": contexts.PageInfo.EndCursor,
+			})
+			if err != nil {
+				debug.Log("repo graphql contexts query failed", "pr", pr.Number, "err", err)
+				return err
+			}
+			cost.observe(page.RateLimit)
+			contexts = page.Node.Commit.StatusCheckRollup.Contexts
+			nodes = append(nodes, contexts.Nodes...)
+		}
+		if contexts.PageInfo.HasNextPage {
+			debug.Log("repo graphql contexts truncated by budget", "pr", pr.Number, "contexts", len(nodes))
+		}
 
+		checkRuns := repoContextNodesToCheckRuns(nodes)
 		if len(checkRuns) == 0 && headPushedTime.IsZero() {
 			continue
 		}
 
-		result[pr.Number] = PRCheckData{
+		result[ref.Repo].PRs[pr.Number] = PRCheckData{
 			Number:         pr.Number,
 			Title:          pr.Title,
 			CheckRuns:      checkRuns,
@@ -174,8 +480,7 @@ func fetchRepoCheckRunsGraphQL(ctx context.Context, client graphqlQuerier, owner
 			HeadSHA:        string(commit.OID),
 		}
 	}
-
-	return result, rateLimitRemaining, nil
+	return nil
 }
 
 // repoContextNodesToCheckRuns converts repoContextNode slice to CheckRunInfo.
@@ -236,17 +541,18 @@ func repoContextNodesToCheckRuns(nodes []repoContextNode) []CheckRunInfo {
 		}
 
 		checkRuns = append(checkRuns, CheckRunInfo{
-			Name:          checkRun.Name,
-			WorkflowName:  workflowName,
-			AppName:       appName,
-			Summary:       checkRun.Summary,
-			Status:        strings.ToLower(checkRun.Status),
-			Conclusion:    strings.ToLower(checkRun.Conclusion),
-			StartedAt:     startedAt,
-			CompletedAt:   completedAt,
-			DetailsURL:    checkRun.DetailsURL,
-			WorkflowRunID: workflowRunID,
-			WorkflowID:    workflowID,
+			Name:           checkRun.Name,
+			WorkflowName:   workflowName,
+			AppName:        appName,
+			Summary:        checkRun.Summary,
+			Status:         strings.ToLower(checkRun.Status),
+			Conclusion:     strings.ToLower(checkRun.Conclusion),
+			StartedAt:      startedAt,
+			CompletedAt:    completedAt,
+			DetailsURL:     checkRun.DetailsURL,
+			WorkflowRunID:  workflowRunID,
+			WorkflowID:     workflowID,
+			WorkflowNodeID: checkRun.CheckSuite.WorkflowRun.Workflow.ID,
 		})
 	}
 
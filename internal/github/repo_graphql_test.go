@@ -3,9 +3,13 @@ package github
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/fini-net/gh-observer/internal/fakegithub"
 	"github.com/shurcooL/githubv4"
 )
 
@@ -27,6 +31,7 @@ func makeRepoCheckRunNode(name, status, conclusion, workflowName, appName string
 				WorkflowRun struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
@@ -47,6 +52,7 @@ func makeRepoCheckRunNode(name, status, conclusion, workflowName, appName string
 				WorkflowRun struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
@@ -59,12 +65,14 @@ func makeRepoCheckRunNode(name, status, conclusion, workflowName, appName string
 				WorkflowRun: struct {
 					DatabaseID BigInt `graphql:"databaseId"`
 					Workflow   struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}
 				}{
 					DatabaseID: BigInt(workflowRunID),
 					Workflow: struct {
+						ID         string
 						DatabaseID BigInt `graphql:"databaseId"`
 						Name       string
 					}{
@@ -228,142 +236,61 @@ func TestRepoContextNodesToCheckRuns(t *testing.T) {
 	}
 }
 
-// repoMockQuerier is a mock graphqlQuerier for repo-mode tests. Unlike
-// mockQuerier in graphql_test.go (hardwired to *pullRequestQuery), this one
-// serves *repoPRQuery responses.
-type repoMockQuerier struct {
-	responses []*repoPRQuery
-	errs      []error
-	calls     int
+// addRepoPR scripts an open PR whose head has one run of the given jobs.
+func addRepoPR(srv *fakegithub.Server, number int, title string, jobs ...fakegithub.Job) {
+	sha := fmt.Sprintf("sha%d", number)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: number, Title: title, HeadSHA: sha})
+	if len(jobs) > 0 {
+		srv.AddRun(fakegithub.Run{
+			ID: int64(number), WorkflowID: 10, Name: "CI", HeadSHA: sha, Event: "pull_request",
+			CreatedAt: srv.Now().Add(-time.Hour), Jobs: jobs,
+		})
+	}
 }
 
-func (m *repoMockQuerier) Query(_ context.Context, q interface{}, _ map[string]interface{}) error {
-	if m.calls >= len(m.responses) {
-		return fmt.Errorf("unexpected repo query call #%d", m.calls+1)
-	}
-	var err error
-	if m.calls < len(m.errs) {
-		err = m.errs[m.calls]
-	}
-	resp := m.responses[m.calls]
-	m.calls++
-	if err != nil {
-		return err
+// graphQLOps counts the GraphQL requests in requests by operation.
+func graphQLOps(requests []string) map[string]int {
+	ops := make(map[string]int)
+	for _, r := range requests {
+		if op, ok := strings.CutPrefix(r, "POST /graphql "); ok {
+			ops[op]++
+		}
 	}
-	target := q.(*repoPRQuery)
-	*target = *resp
-	return nil
+	return ops
 }
 
-// makeRepoPRQuery builds a *repoPRQuery with one PR carrying the given head SHA
-// and a single CheckRun context node.
-func makeRepoPRQuery(prNumber int, title, headSHA, checkName, workflowName string, rateLimit int) *repoPRQuery {
-	startedAt := githubv4.DateTime{}
-	startedAt.Time = time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-
-	q := &repoPRQuery{}
-	q.RateLimit.Remaining = rateLimit
-
-	pr := struct {
-		Number  int
-		Title   string
-		Commits struct {
-			Nodes []struct {
-				Commit struct {
-					PushedDate        githubv4.DateTime `graphql:"pushedDate"`
-					CommittedDate     githubv4.DateTime `graphql:"committedDate"`
-					OID               githubv4.String   `graphql:"oid"`
-					StatusCheckRollup struct {
-						Contexts struct {
-							Nodes    []repoContextNode
-							PageInfo struct {
-								HasNextPage bool
-								EndCursor   githubv4.String
-							}
-						} `graphql:"contexts(first: 100)"`
-					}
-				}
-			}
-		} `graphql:"commits(last: 1)"`
-	}{
-		Number: prNumber,
-		Title:  title,
-	}
-	pr.Commits.Nodes = append(pr.Commits.Nodes, struct {
-		Commit struct {
-			PushedDate        githubv4.DateTime `graphql:"pushedDate"`
-			CommittedDate     githubv4.DateTime `graphql:"committedDate"`
-			OID               githubv4.String   `graphql:"oid"`
-			StatusCheckRollup struct {
-				Contexts struct {
-					Nodes    []repoContextNode
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   githubv4.String
-					}
-				} `graphql:"contexts(first: 100)"`
-			}
-		}
-	}{
-		Commit: struct {
-			PushedDate        githubv4.DateTime `graphql:"pushedDate"`
-			CommittedDate     githubv4.DateTime `graphql:"committedDate"`
-			OID               githubv4.String   `graphql:"oid"`
-			StatusCheckRollup struct {
-				Contexts struct {
-					Nodes    []repoContextNode
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   githubv4.String
-					}
-				} `graphql:"contexts(first: 100)"`
-			}
-		}{
-			OID: githubv4.String(headSHA),
-			StatusCheckRollup: struct {
-				Contexts struct {
-					Nodes    []repoContextNode
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   githubv4.String
-					}
-				} `graphql:"contexts(first: 100)"`
-			}{
-				Contexts: struct {
-					Nodes    []repoContextNode
-					PageInfo struct {
-						HasNextPage bool
-						EndCursor   githubv4.String
-					}
-				}{
-					Nodes: []repoContextNode{
-						makeRepoCheckRunNode(checkName, "IN_PROGRESS", "", workflowName, "GitHub Actions", startedAt, githubv4.DateTime{}, "https://example.com", 100, 10),
-					},
-				},
-			},
-		},
-	})
-	q.Repository.PullRequests.Nodes = append(q.Repository.PullRequests.Nodes, pr)
-	return q
+// fetchHelloChecks fetches the fake's octo/hello alone, as
+// Session.FetchRepoCheckRuns does.
+func fetchHelloChecks(api *Session, budget int) (map[int]PRCheckData, int, int, error) {
+	hello := RepoRef{Owner: "octo", Name: "hello"}
+	result, rateLimit, err := fetchRepoCheckRunsGraphQL(context.Background(), api.graphql, []RepoRef{hello}, RepoFilter{}, budget)
+	return result[hello].PRs, result[hello].Omitted, rateLimit, err
 }
 
 // TestFetchRepoCheckRunsGraphQLHeadSHA verifies that the GraphQL oid field
 // is requested and propagated into PRCheckData.HeadSHA — the foundation of
 // the issue #331 SHA-based dedup.
 func TestFetchRepoCheckRunsGraphQLHeadSHA(t *testing.T) {
-	mock := &repoMockQuerier{
-		responses: []*repoPRQuery{
-			makeRepoPRQuery(7, "Add feature", "abc123def456", "build", "CI", 4900),
-		},
-	}
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	srv.SetRateLimit(4900, srv.Now().Add(time.Hour))
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 7, Title: "Add feature", HeadSHA: "abc123def456"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 10, Name: "CI", HeadSHA: "abc123def456", Event: "pull_request",
+		CreatedAt: srv.Now(), Jobs: []fakegithub.Job{{Name: "build"}},
+	})
 
-	prs, rateLimit, err := fetchRepoCheckRunsGraphQL(context.Background(), mock, "owner", "repo")
+	prs, omitted, rateLimit, err := fetchHelloChecks(api, repoQueryCostBudget)
 	if err != nil {
-		t.Fatalf("fetchRepoCheckRunsGraphQL error: %v", err)
+		t.Fatalf("fetchHelloChecks error: %v", err)
 	}
 	if rateLimit != 4900 {
 		t.Errorf("rateLimit = %d, want 4900", rateLimit)
 	}
+	if omitted != 0 {
+		t.Errorf("omitted = %d, want 0", omitted)
+	}
 	if len(prs) != 1 {
 		t.Fatalf("prs = %d, want 1", len(prs))
 	}
@@ -374,24 +301,29 @@ func TestFetchRepoCheckRunsGraphQLHeadSHA(t *testing.T) {
 	if pr.HeadSHA != "abc123def456" {
 		t.Errorf("pr.HeadSHA = %q, want %q (oid must propagate from GraphQL)", pr.HeadSHA, "abc123def456")
 	}
+	if pr.Title != "Add feature" {
+		t.Errorf("pr.Title = %q, want %q", pr.Title, "Add feature")
+	}
 	if len(pr.CheckRuns) != 1 {
 		t.Fatalf("pr.CheckRuns = %d, want 1", len(pr.CheckRuns))
 	}
 	if pr.CheckRuns[0].Name != "build" {
 		t.Errorf("check name = %q, want %q", pr.CheckRuns[0].Name, "build")
 	}
+	if pr.CheckRuns[0].WorkflowName != "CI" {
+		t.Errorf("workflow name = %q, want %q", pr.CheckRuns[0].WorkflowName, "CI")
+	}
 }
 
 // TestFetchRepoCheckRunsGraphQLEmptySHA verifies a PR whose commit OID is empty
 // propagates an empty HeadSHA rather than erroring.
 func TestFetchRepoCheckRunsGraphQLEmptySHA(t *testing.T) {
-	mock := &repoMockQuerier{
-		responses: []*repoPRQuery{
-			makeRepoPRQuery(1, "No SHA", "", "lint", "CI", 4900),
-		},
-	}
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 1, Title: "No SHA", PushedAt: srv.Now()})
 
-	prs, _, err := fetchRepoCheckRunsGraphQL(context.Background(), mock, "owner", "repo")
+	prs, _, _, err := fetchHelloChecks(api, repoQueryCostBudget)
 	if err != nil {
 		t.Fatalf("error: %v", err)
 	}
@@ -402,3 +334,183 @@ func TestFetchRepoCheckRunsGraphQLEmptySHA(t *testing.T) {
 		t.Errorf("HeadSHA = %q, want empty", prs[1].HeadSHA)
 	}
 }
+
+func TestFetchRepoCheckRunsGraphQLBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		prs         int
+		active      []int // PRs with a job still running
+		budget      int
+		wantFetched int
+		wantOmitted int
+		wantOps     map[string]int
+	}{
+		{
+			name:        "more than one batch",
+			prs:         25,
+			budget:      repoQueryCostBudget,
+			wantFetched: 25,
+			wantOps:     map[string]int{"repoPullRequests": 1, "repoPullRequestChecks": 3},
+		},
+		{
+			name:        "listing spans pages",
+			prs:         120,
+			budget:      repoQueryCostBudget,
+			wantFetched: 120,
+			wantOps:     map[string]int{"repoPullRequests": 2, "repoPullRequestChecks": 12},
+		},
+		{
+			name:        "budget leaves idle PRs out",
+			prs:         25,
+			active:      []int{3, 5},
+			budget:      3,
+			wantFetched: 20,
+			wantOmitted: 5,
+			wantOps:     map[string]int{"repoPullRequests": 1, "repoPullRequestChecks": 2},
+		},
+		{
+			name:        "first batch always runs",
+			prs:         25,
+			active:      []int{1},
+			budget:      1,
+			wantFetched: 10,
+			wantOmitted: 15,
+			wantOps:     map[string]int{"repoPullRequests": 1, "repoPullRequestChecks": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fakegithub.New("octo", "hello")
+			defer srv.Close()
+			api := newFakeAPI(t, srv)
+			for n := 1; n <= tt.prs; n++ {
+				job := fakegithub.Job{Name: "build", Duration: time.Minute}
+				if slices.Contains(tt.active, n) {
+					job.Duration = 0
+				}
+				addRepoPR(srv, n, fmt.Sprintf("PR %d", n), job)
+			}
+
+			prs, omitted, _, err := fetchHelloChecks(api, tt.budget)
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if len(prs) != tt.wantFetched || omitted != tt.wantOmitted {
+				t.Errorf("fetched %d, omitted %d; want %d, %d", len(prs), omitted, tt.wantFetched, tt.wantOmitted)
+			}
+			for _, n := range tt.active {
+				if _, ok := prs[n]; !ok {
+					t.Errorf("active PR #%d was left out", n)
+				}
+			}
+			if ops := graphQLOps(srv.Requests()); !maps.Equal(ops, tt.wantOps) {
+				t.Errorf("queries = %v, want %v", ops, tt.wantOps)
+			}
+		})
+	}
+}
+
+// PRs the budget left unlisted may not match an author filter, so they
+// aren't counted as omitted; listed matches past the budget are.
+func TestFetchRepoCheckRunsGraphQLOmittedFiltered(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	for n := 1; n <= 150; n++ {
+		author := "alice"
+		if n%2 == 0 {
+			author = "bob"
+		}
+		srv.AddPullRequest(fakegithub.PullRequest{Number: n, Title: fmt.Sprintf("PR %d", n), Author: author, HeadSHA: fmt.Sprintf("sha%d", n)})
+	}
+
+	hello := RepoRef{Owner: "octo", Name: "hello"}
+	result, _, err := fetchRepoCheckRunsGraphQL(context.Background(), api.graphql, []RepoRef{hello}, RepoFilter{Author: "alice"}, 1)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// The first page lists 100 PRs, 50 of them alice's; one batch of ten
+	// is fetched and the other 40 are omitted.
+	if got := result[hello]; len(got.PRs) != 10 || got.Omitted != 40 {
+		t.Errorf("fetched %d, omitted %d; want 10, 40", len(got.PRs), got.Omitted)
+	}
+}
+
+func TestFetchRepoCheckRunsGraphQLContextPages(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+	var jobs []fakegithub.Job
+	for i := range 250 {
+		jobs = append(jobs, fakegithub.Job{Name: fmt.Sprintf("shard-%d", i), Duration: time.Minute})
+	}
+	addRepoPR(srv, 1, "Big matrix", jobs...)
+
+	prs, _, _, err := fetchHelloChecks(api, repoQueryCostBudget)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if got := len(prs[1].CheckRuns); got != 250 {
+		t.Errorf("check runs = %d, want 250 (contexts past the first page must be followed)", got)
+	}
+	if got := graphQLOps(srv.Requests())["commitContexts"]; got != 2 {
+		t.Errorf("commitContexts queries = %d, want 2", got)
+	}
+}
+
+func TestFetchReposCheckRunsGraphQL(t *testing.T) {
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	api := newFakeAPI(t, srv)
+
+	// Twelve repos take two listing queries. Each has a PR #1, idle except
+	// in api and web where it is still building; web also has an older
+	// idle PR #2.
+	repos := []RepoRef{{Owner: "octo", Name: "hello"}}
+	for i := range 11 {
+		repos = append(repos, RepoRef{Owner: "octo", Name: fmt.Sprintf("svc%d", i)})
+	}
+	repos[3].Name, repos[11].Name = "api", "web"
+	addRepoPR(srv, 1, "hello change", fakegithub.Job{Name: "lint", Duration: time.Minute})
+	for i, ref := range repos[1:] {
+		r := srv.AddRepo(ref.Owner, ref.Name)
+		job := fakegithub.Job{Name: "build", Duration: time.Minute}
+		if ref.Name == "api" || ref.Name == "web" {
+			job.Duration = 0
+		}
+		sha := fmt.Sprintf("%s-1", ref.Name)
+		r.AddPullRequest(fakegithub.PullRequest{Number: 1, Title: ref.Name + " change", HeadSHA: sha})
+		r.AddRun(fakegithub.Run{ID: int64(100 + i), WorkflowID: int64(10 + i), Name: "CI", HeadSHA: sha, CreatedAt: srv.Now().Add(-time.Hour), Jobs: []fakegithub.Job{job}})
+		if ref.Name == "web" {
+			r.AddPullRequest(fakegithub.PullRequest{Number: 2, Title: "web idle", HeadSHA: "web-2", PushedAt: srv.Now().Add(-time.Hour)})
+		}
+	}
+	result, _, err := fetchRepoCheckRunsGraphQL(context.Background(), api.graphql, repos, RepoFilter{}, 3)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	// Budget 3 covers the two listings and one batch of ten: both active
+	// PRs, then the most recently updated idle ones.
+	if ops := graphQLOps(srv.Requests()); !maps.Equal(ops, map[string]int{"repoPullRequests": 2, "repoPullRequestChecks": 1}) {
+		t.Errorf("queries = %v, want two listings and one batch", ops)
+	}
+	for _, name := range []string{"api", "web"} {
+		pr, ok := result[RepoRef{Owner: "octo", Name: name}].PRs[1]
+		if !ok || pr.Title != name+" change" || len(pr.CheckRuns) != 1 {
+			t.Errorf("%s PR #1 = %+v (%v), want its active build", name, pr, ok)
+		}
+	}
+	fetched, omitted := 0, 0
+	for _, r := range result {
+		fetched += len(r.PRs)
+		omitted += r.Omitted
+	}
+	if fetched != 10 || omitted != 3 {
+		t.Errorf("fetched %d, omitted %d; want 10, 3", fetched, omitted)
+	}
+	if got := result[RepoRef{Owner: "octo", Name: "web"}].Omitted; got != 1 {
+		t.Errorf("web omitted = %d, want its idle PR #2", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fini-net/gh-observer/internal/debug"
@@ -28,20 +29,30 @@ type BranchRunData struct {
 	Conclusion   string
 	CreatedAt    time.Time
 	RunStartedAt time.Time
+	UpdatedAt    time.Time
+	RunAttempt   int
 	Jobs         []CheckRunInfo
 }
 
-// FetchRepoWorkflowRuns lists standalone (non-PR) workflow runs on a repo that
-// are either currently active or completed within fadeWindow. It issues two
-// ListRepositoryWorkflowRuns calls (in_progress, then recently-created) with
-// ExcludePullRequests set so PR-triggered runs are not double-counted against
-// the PR GraphQL query.
+// FetchRepoWorkflowRuns lists the workflow runs on a repo that are either
+// currently active, waiting on an environment's approval, or completed
+// within fadeWindow. It issues four ListRepositoryWorkflowRuns calls
+// (in_progress, queued, waiting, then recently-created) with
+// ExcludePullRequests set, which only drops the unused pull_requests
+// payload: PR-triggered runs are still listed, so their jobs' runner labels
+// reach the queue stats, and the watch reconciles them against the PR
+// GraphQL query.
+//
+// filter's Author and Base (already resolved from ViewerAlias) are passed
+// as the actor and branch filters.
 //
 // Returns the deduplicated run list and the minimum rate-limit remaining observed.
-func FetchRepoWorkflowRuns(ctx context.Context, client *github.Client, owner, repo string, fadeWindow time.Duration) ([]BranchRunData, int, error) {
+func FetchRepoWorkflowRuns(ctx context.Context, client *github.Client, owner, repo string, fadeWindow time.Duration, filter RepoFilter) ([]BranchRunData, int, error) {
 	rateLimitRemaining := 5000
 
 	inProgress := &github.ListWorkflowRunsOptions{
+		Actor:               filter.Author,
+		Branch:              filter.Base,
 		ExcludePullRequests: true,
 		Status:              "in_progress",
 		ListOptions:         github.ListOptions{PerPage: 100},
@@ -54,11 +65,53 @@ func FetchRepoWorkflowRuns(ctx context.Context, client *github.Client, owner, re
 		rateLimitRemaining = rl1
 	}
 
+	// Runs still waiting for a runner, however long ago they were created:
+	// a job stuck behind a missing runner is what the runner pane warns
+	// about.
+	queued := &github.ListWorkflowRunsOptions{
+		Actor:               filter.Author,
+		Branch:              filter.Base,
+		ExcludePullRequests: true,
+		Status:              "queued",
+		ListOptions:         github.ListOptions{PerPage: 100},
+	}
+	queuedRuns, rlq, err := fetchRepoRunPage(ctx, client, owner, repo, queued)
+	if err != nil {
+		debug.Log("failed to fetch queued repo runs", "owner", owner, "repo", repo, "err", err)
+	} else {
+		activeRuns = append(activeRuns, queuedRuns...)
+		if rlq < rateLimitRemaining {
+			rateLimitRemaining = rlq
+		}
+	}
+
+	// Runs held at an environment's protection rules are neither in
+	// progress nor, once they've waited longer than the fade window,
+	// recent, yet they're the ones that need someone's attention.
+	waiting := &github.ListWorkflowRunsOptions{
+		Actor:               filter.Author,
+		Branch:              filter.Base,
+		ExcludePullRequests: true,
+		Status:              "waiting",
+		ListOptions:         github.ListOptions{PerPage: 100},
+	}
+	waitingRuns, rlw, err := fetchRepoRunPage(ctx, client, owner, repo, waiting)
+	if err != nil {
+		debug.Log("failed to fetch waiting repo runs", "owner", owner, "repo", repo, "err", err)
+	} else {
+		activeRuns = append(activeRuns, waitingRuns...)
+		if rlw < rateLimitRemaining {
+			rateLimitRemaining = rlw
+		}
+	}
+
 	// Recently completed: filter by creation date to bound the result set.
 	// RFC3339 (not time.DateOnly) so a 30m fade window queries the last 30
 	// minutes, not the whole calendar day — the date-only form made the
 	// server-side scan ~10x more expensive and triggered 504s on busy repos.
 	recent := &github.ListWorkflowRunsOptions{
+		Actor:               filter.Author,
+		Branch:              filter.Base,
 		ExcludePullRequests: true,
 		Created:             ">=" + time.Now().Add(-fadeWindow).Format(time.RFC3339),
 		ListOptions:         github.ListOptions{PerPage: 100},
@@ -122,13 +175,14 @@ func fetchRepoRunPage(ctx context.Context, client *github.Client, owner, repo st
 
 func convertBranchRun(run *github.WorkflowRun) BranchRunData {
 	data := BranchRunData{
-		RunID:      run.GetID(),
-		HeadBranch: run.GetHeadBranch(),
-		HeadSHA:    run.GetHeadSHA(),
-		Event:      run.GetEvent(),
-		WorkflowID: run.GetWorkflowID(),
-		Status:     strings.ToLower(run.GetStatus()),
-		Conclusion: strings.ToLower(run.GetConclusion()),
+		RunID:        run.GetID(),
+		HeadBranch:   run.GetHeadBranch(),
+		HeadSHA:      run.GetHeadSHA(),
+		Event:        run.GetEvent(),
+		WorkflowID:   run.GetWorkflowID(),
+		WorkflowName: run.GetName(),
+		Status:       strings.ToLower(run.GetStatus()),
+		Conclusion:   strings.ToLower(run.GetConclusion()),
 	}
 	if run.DisplayTitle != nil && *run.DisplayTitle != "" {
 		data.DisplayTitle = *run.DisplayTitle
@@ -141,33 +195,135 @@ func convertBranchRun(run *github.WorkflowRun) BranchRunData {
 	if run.RunStartedAt != nil {
 		data.RunStartedAt = run.RunStartedAt.Time
 	}
+	if run.UpdatedAt != nil {
+		data.UpdatedAt = run.UpdatedAt.Time
+	}
+	data.RunAttempt = run.GetRunAttempt()
 	return data
 }
 
+// CompletedRunJobs remembers the jobs of completed runs so repo mode doesn't
+// re-list them on every poll: a completed run's jobs can't change unless it
+// is re-run, which bumps its attempt and updated_at. Safe for concurrent use
+// (fetch commands run off the UI goroutine).
+type CompletedRunJobs struct {
+	mu      sync.Mutex
+	entries map[int64]completedRunEntry
+}
+
+type completedRunEntry struct {
+	attempt   int
+	updatedAt time.Time
+	jobs      []CheckRunInfo
+}
+
+// NewCompletedRunJobs returns an empty cache.
+func NewCompletedRunJobs() *CompletedRunJobs {
+	return &CompletedRunJobs{entries: make(map[int64]completedRunEntry)}
+}
+
+// lookup returns the cached jobs for run when it is completed and
+// unchanged since it was cached.
+func (c *CompletedRunJobs) lookup(run BranchRunData) ([]CheckRunInfo, bool) {
+	if c == nil || run.Status != "completed" {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[run.RunID]
+	if !ok || e.attempt != run.RunAttempt || !e.updatedAt.Equal(run.UpdatedAt) {
+		return nil, false
+	}
+	return e.jobs, true
+}
+
+// store caches jobs for a completed run. Runs still in progress aren't
+// cached; their jobs change from poll to poll.
+func (c *CompletedRunJobs) store(run BranchRunData, jobs []CheckRunInfo) {
+	if c == nil || run.Status != "completed" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[run.RunID] = completedRunEntry{attempt: run.RunAttempt, updatedAt: run.UpdatedAt, jobs: jobs}
+}
+
+// retain drops entries for runs no longer listed (faded out of the window),
+// keeping the cache bounded by what's on screen.
+func (c *CompletedRunJobs) retain(runs []BranchRunData) {
+	if c == nil {
+		return
+	}
+	listed := make(map[int64]bool, len(runs))
+	for _, r := range runs {
+		listed[r.RunID] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.entries {
+		if !listed[id] {
+			delete(c.entries, id)
+		}
+	}
+}
+
 // EnrichRepoRunsWithJobs fetches jobs for each run via FetchRunJobs and adapts
 // them to CheckRunInfo so the TUI can reuse display helpers. WorkflowName is
 // copied from the first job (GitHub populates it on WorkflowJob).
 //
+// Completed runs found unchanged in cache (which may be nil) reuse their
+// cached jobs without an API call.
+//
 // Failures on individual runs are non-fatal: the run is kept with an empty
-// Jobs slice so the TUI can still render its header.
-func EnrichRepoRunsWithJobs(ctx context.Context, client *github.Client, owner, repo string, runs []BranchRunData) ([]BranchRunData, int, error) {
+// Jobs slice so the TUI can still render its header. Jobs are listed
+// concurrently through pool; if ctx is cancelled partway, the runs enriched
+// so far are returned with the context's error.
+func EnrichRepoRunsWithJobs(ctx context.Context, client *github.Client, pool *FetchPool, owner, repo string, runs []BranchRunData, cache *CompletedRunJobs) ([]BranchRunData, int, error) {
 	rateLimitRemaining := 5000
+	cache.retain(runs)
+
+	// Cache hits are resolved up front; the misses are fetched through
+	// pool into per-run slots and applied in run order afterwards, so the
+	// result (and the cache) don't depend on which fetch finished first.
+	jobs := make([][]CheckRunInfo, len(runs))
+	resolved := make([]bool, len(runs))
+	remaining := make([]int, len(runs))
+	var misses []int
 	for i := range runs {
-		jobs, rl, err := FetchRunJobs(ctx, client, owner, repo, runs[i].RunID)
+		if checkRuns, ok := cache.lookup(runs[i]); ok {
+			jobs[i], resolved[i] = checkRuns, true
+		} else {
+			misses = append(misses, i)
+		}
+	}
+	if reused := len(runs) - len(misses); reused > 0 {
+		debug.Log("reused cached jobs for completed repo runs", "reused", reused, "total", len(runs))
+	}
+
+	err := pool.Each(ctx, len(misses), func(ctx context.Context, k int) {
+		i := misses[k]
+		runJobs, rl, err := FetchRunJobs(ctx, client, owner, repo, runs[i].RunID)
 		if err != nil {
 			debug.Log("failed to fetch jobs for repo run", "run_id", runs[i].RunID, "err", err)
-			continue
+			return
 		}
-		if rl < rateLimitRemaining {
-			rateLimitRemaining = rl
+		jobs[i], remaining[i], resolved[i] = WorkflowJobInfoToCheckRuns(runJobs), rl, true
+	})
+	for _, i := range misses {
+		if resolved[i] {
+			rateLimitRemaining = min(rateLimitRemaining, remaining[i])
+			cache.store(runs[i], jobs[i])
 		}
+	}
 
-		checkRuns := WorkflowJobInfoToCheckRuns(jobs)
-		runs[i].Jobs = checkRuns
-
-		if len(checkRuns) > 0 && checkRuns[0].WorkflowName != "" {
-			runs[i].WorkflowName = checkRuns[0].WorkflowName
+	for i := range runs {
+		if !resolved[i] {
+			continue
+		}
+		runs[i].Jobs = jobs[i]
+		if len(jobs[i]) > 0 && jobs[i][0].WorkflowName != "" {
+			runs[i].WorkflowName = jobs[i][0].WorkflowName
 		}
 	}
-	return runs, rateLimitRemaining, nil
+	return runs, rateLimitRemaining, err
 }
@@ -103,7 +103,7 @@ func TestFetchRepoWorkflowRunsCreatedFilterFormat(t *testing.T) {
 
 	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
 
-	_, _, err := FetchRepoWorkflowRuns(context.Background(), client, "owner", "repo", 30*time.Minute)
+	_, _, err := FetchRepoWorkflowRuns(context.Background(), client, "owner", "repo", 30*time.Minute, RepoFilter{})
 	if err != nil {
 		t.Fatalf("FetchRepoWorkflowRuns error: %v", err)
 	}
@@ -125,3 +125,57 @@ func TestFetchRepoWorkflowRunsCreatedFilterFormat(t *testing.T) {
 		t.Errorf("created filter timestamp %q is not valid RFC3339: %v", tsStr, err)
 	}
 }
+
+func TestEnrichRepoRunsWithJobsReusesCompletedRuns(t *testing.T) {
+	jobCalls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobCalls[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total_count":1,"jobs":[{"id":1,"name":"build","workflow_name":"CI","status":"completed","conclusion":"success"}]}`))
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	updated := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	runs := func() []BranchRunData {
+		return []BranchRunData{
+			{RunID: 1, Status: "completed", RunAttempt: 1, UpdatedAt: updated},
+			{RunID: 2, Status: "in_progress", RunAttempt: 1, UpdatedAt: updated},
+		}
+	}
+	cache := NewCompletedRunJobs()
+
+	for range 3 {
+		enriched, _, err := EnrichRepoRunsWithJobs(context.Background(), client, NewFetchPool(DefaultFetchConcurrency), "owner", "repo", runs(), cache)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(enriched[0].Jobs) != 1 || enriched[0].WorkflowName != "CI" {
+			t.Errorf("completed run = %+v, want cached jobs and workflow name", enriched[0])
+		}
+	}
+	if got := jobCalls["/repos/owner/repo/actions/runs/1/jobs"]; got != 1 {
+		t.Errorf("completed run jobs calls = %d, want 1", got)
+	}
+	if got := jobCalls["/repos/owner/repo/actions/runs/2/jobs"]; got != 3 {
+		t.Errorf("active run jobs calls = %d, want 3", got)
+	}
+
+	// A re-run bumps the attempt, so the cached jobs are stale.
+	rerun := runs()
+	rerun[0].RunAttempt = 2
+	if _, _, err := EnrichRepoRunsWithJobs(context.Background(), client, NewFetchPool(DefaultFetchConcurrency), "owner", "repo", rerun, cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := jobCalls["/repos/owner/repo/actions/runs/1/jobs"]; got != 2 {
+		t.Errorf("re-run jobs calls = %d, want 2", got)
+	}
+
+	// Runs that faded out of the listing are dropped from the cache.
+	if _, _, err := EnrichRepoRunsWithJobs(context.Background(), client, NewFetchPool(DefaultFetchConcurrency), "owner", "repo", rerun[1:], cache); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cache.lookup(rerun[0]); ok {
+		t.Error("expected faded-out run to be evicted")
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/fini-net/gh-observer/internal/debug"
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 // copilotReviewerLogin is the GitHub App login for Copilot code reviews.
@@ -66,21 +65,12 @@ type copilotReviewQuery struct {
 			} `graphql:"reviews(last: 25)"`
 		} `graphql:"pullRequest(number: $prNumber)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
-	RateLimit struct {
-		Remaining int
-	}
+	RateLimit graphQLRateLimit
 }
 
-// FetchCopilotReview fetches the Copilot code review state for a PR,
+// fetchCopilotReview fetches the Copilot code review state for a PR,
 // comparing the review's commit OID against headSHA to detect stale reviews.
 // Returns the review state, the GraphQL rate limit remaining, and any error.
-func FetchCopilotReview(ctx context.Context, token, owner, repo string, prNumber int, headSHA string) (CopilotReview, int, error) {
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(ctx, src)
-	client := githubv4.NewClient(httpClient)
-	return fetchCopilotReview(ctx, client, owner, repo, prNumber, headSHA)
-}
-
 func fetchCopilotReview(ctx context.Context, client graphqlQuerier, owner, repo string, prNumber int, headSHA string) (CopilotReview, int, error) {
 	var query copilotReviewQuery
 	prNum, err := safeGraphQLInt(prNumber)
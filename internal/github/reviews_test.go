@@ -127,7 +127,7 @@ func makeReviewQuery(requests []struct {
 	Body string
 }, rateLimit int) *copilotReviewQuery {
 	q := &copilotReviewQuery{
-		RateLimit: struct{ Remaining int }{Remaining: rateLimit},
+		RateLimit: graphQLRateLimit{Remaining: rateLimit},
 	}
 	q.Repository.PullRequest.ReviewRequests.Nodes = requests
 	q.Repository.PullRequest.Reviews.Nodes = reviews
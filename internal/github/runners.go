@@ -0,0 +1,170 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+)
+
+// ErrRunnersNotVisible is returned (wrapped) when GitHub refuses to list a
+// repository's or organization's self-hosted runners: listing them needs
+// admin access, and an owner that is a user rather than an organization
+// has no organization runners at all.
+var ErrRunnersNotVisible = errors.New("runners not visible to this token")
+
+// Runner is a self-hosted runner registered on a repository, or on an
+// organization when Org is set.
+type Runner struct {
+	ID     int64
+	Name   string
+	OS     string
+	Status string // "online" or "offline"
+	Busy   bool
+	Labels []string
+	Org    bool
+}
+
+// Online reports whether the runner is connected to GitHub.
+func (r Runner) Online() bool {
+	return r.Status == "online"
+}
+
+// HasLabels reports whether the runner carries every one of labels, which
+// is what GitHub requires to route a job to it. Labels match ignoring case.
+func (r Runner) HasLabels(labels []string) bool {
+	for _, want := range labels {
+		if !slices.ContainsFunc(r.Labels, func(l string) bool { return strings.EqualFold(l, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// FetchRepoRunners lists the self-hosted runners registered on owner/repo.
+func FetchRepoRunners(ctx context.Context, client *github.Client, owner, repo string) ([]Runner, int, error) {
+	return listRunners(ctx, owner+"/"+repo, false, func(opts *github.ListRunnersOptions) (*github.Runners, *github.Response, error) {
+		return client.Actions.ListRunners(ctx, owner, repo, opts)
+	})
+}
+
+// FetchOrgRunners lists the self-hosted runners registered on organization
+// org, which every repository of it can be granted.
+func FetchOrgRunners(ctx context.Context, client *github.Client, org string) ([]Runner, int, error) {
+	return listRunners(ctx, org, true, func(opts *github.ListRunnersOptions) (*github.Runners, *github.Response, error) {
+		return client.Actions.ListOrganizationRunners(ctx, org, opts)
+	})
+}
+
+// listRunners pages through one runners listing. A 403 or 404 is reported
+// as ErrRunnersNotVisible.
+func listRunners(ctx context.Context, scope string, org bool, list func(*github.ListRunnersOptions) (*github.Runners, *github.Response, error)) ([]Runner, int, error) {
+	opts := &github.ListRunnersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	rateLimitRemaining := 5000
+
+	var runners []Runner
+	for {
+		page, resp, err := list(opts)
+		if err != nil {
+			if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+				err = fmt.Errorf("%w: %w", ErrRunnersNotVisible, err)
+			}
+			return nil, rateLimitRemaining, fmt.Errorf("failed to list runners for %s: %w", scope, err)
+		}
+		if resp != nil {
+			rateLimitRemaining = resp.Rate.Remaining
+		}
+
+		for _, r := range page.Runners {
+			runners = append(runners, convertRunner(r, org))
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	debug.Log("fetch runners", "scope", scope, "org", org, "count", len(runners), "rate_limit_remaining", rateLimitRemaining)
+
+	return runners, rateLimitRemaining, nil
+}
+
+// convertRunner converts a go-github Runner to our Runner.
+func convertRunner(r *github.Runner, org bool) Runner {
+	runner := Runner{
+		ID:     r.GetID(),
+		Name:   r.GetName(),
+		OS:     r.GetOS(),
+		Status: r.GetStatus(),
+		Busy:   r.GetBusy(),
+		Org:    org,
+	}
+	for _, l := range r.Labels {
+		runner.Labels = append(runner.Labels, l.GetName())
+	}
+	return runner
+}
+
+// RunnerShortfall is a set of runs-on labels that queued jobs are waiting
+// for but no online runner carries.
+type RunnerShortfall struct {
+	Labels []string
+	// Jobs is how many queued jobs asked for Labels.
+	Jobs int
+	// Offline counts the runners that do carry Labels but are offline.
+	Offline int
+}
+
+// UnmatchedQueuedJobs returns, by RunnerLabelKey, the queued jobs' runs-on
+// label sets no online runner carries, ignoring GitHub-hosted images.
+func UnmatchedQueuedJobs(checks []CheckRunInfo, runners []Runner) []RunnerShortfall {
+	byKey := make(map[string]*RunnerShortfall)
+	var keys []string
+	for _, check := range checks {
+		if check.Status != "queued" || len(check.Labels) == 0 || githubHosted(check.Labels) {
+			continue
+		}
+		key := RunnerLabelKey(check.Labels)
+		if s, ok := byKey[key]; ok {
+			s.Jobs++
+			continue
+		}
+		online := slices.ContainsFunc(runners, func(r Runner) bool { return r.Online() && r.HasLabels(check.Labels) })
+		if online {
+			continue
+		}
+		s := &RunnerShortfall{Labels: check.Labels, Jobs: 1}
+		for _, r := range runners {
+			if r.HasLabels(check.Labels) {
+				s.Offline++
+			}
+		}
+		byKey[key] = s
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	shortfalls := make([]RunnerShortfall, 0, len(keys))
+	for _, key := range keys {
+		shortfalls = append(shortfalls, *byKey[key])
+	}
+	return shortfalls
+}
+
+// githubHosted reports whether labels name only GitHub-hosted runner
+// images.
+func githubHosted(labels []string) bool {
+	for _, l := range labels {
+		l = strings.ToLower(l)
+		if !strings.HasPrefix(l, "ubuntu-") && !strings.HasPrefix(l, "windows-") && !strings.HasPrefix(l, "macos-") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+)
+
+func TestRunnerHasLabels(t *testing.T) {
+	runner := Runner{Labels: []string{"self-hosted", "Linux", "gpu"}}
+	tests := []struct {
+		name   string
+		labels []string
+		want   bool
+	}{
+		{name: "all present", labels: []string{"self-hosted", "gpu"}, want: true},
+		{name: "case-insensitive", labels: []string{"linux"}, want: true},
+		{name: "one missing", labels: []string{"self-hosted", "arm64"}, want: false},
+		{name: "no labels", labels: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runner.HasLabels(tt.labels); got != tt.want {
+				t.Errorf("HasLabels(%v) = %v, want %v", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmatchedQueuedJobs(t *testing.T) {
+	queued := func(labels ...string) CheckRunInfo {
+		return CheckRunInfo{Status: "queued", Labels: labels}
+	}
+	gpuOffline := Runner{Name: "gpu-1", Status: "offline", Labels: []string{"self-hosted", "gpu"}}
+	gpuOnline := Runner{Name: "gpu-2", Status: "online", Busy: true, Labels: []string{"self-hosted", "gpu"}}
+	linux := Runner{Name: "linux-1", Status: "online", Labels: []string{"self-hosted", "linux"}}
+
+	tests := []struct {
+		name    string
+		checks  []CheckRunInfo
+		runners []Runner
+		want    []RunnerShortfall
+	}{
+		{
+			name:    "queued jobs with no online runner are grouped by label set",
+			checks:  []CheckRunInfo{queued("self-hosted", "gpu"), queued("self-hosted", "gpu"), queued("self-hosted", "gpu"), queued("self-hosted", "linux")},
+			runners: []Runner{gpuOffline, linux},
+			want:    []RunnerShortfall{{Labels: []string{"self-hosted", "gpu"}, Jobs: 3, Offline: 1}},
+		},
+		{
+			name:    "a busy online runner still counts",
+			checks:  []CheckRunInfo{queued("self-hosted", "gpu")},
+			runners: []Runner{gpuOffline, gpuOnline},
+			want:    []RunnerShortfall{},
+		},
+		{
+			name:    "no runner at all carries the label",
+			checks:  []CheckRunInfo{queued("arm64")},
+			runners: []Runner{linux},
+			want:    []RunnerShortfall{{Labels: []string{"arm64"}, Jobs: 1}},
+		},
+		{
+			name: "hosted, unlabeled and running jobs are skipped",
+			checks: []CheckRunInfo{
+				queued("ubuntu-latest"), queued("macos-14"), queued(),
+				{Status: "in_progress", Labels: []string{"self-hosted", "gpu"}},
+			},
+			want: []RunnerShortfall{},
+		},
+		{
+			name:   "shortfalls are sorted by label set",
+			checks: []CheckRunInfo{queued("self-hosted", "gpu"), queued("arm64")},
+			want: []RunnerShortfall{
+				{Labels: []string{"arm64"}, Jobs: 1},
+				{Labels: []string{"self-hosted", "gpu"}, Jobs: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnmatchedQueuedJobs(tt.checks, tt.runners); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmatchedQueuedJobs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunnersAgainstFakeGitHub(t *testing.T) {
+	ctx := context.Background()
+	srv := fakegithub.New("octo", "hello")
+	defer srv.Close()
+	srv.AddRunner(fakegithub.Runner{ID: 1, Name: "build-1", OS: "Linux", Online: true, Busy: true, Labels: []string{"self-hosted", "linux"}})
+	api := newFakeAPI(t, srv)
+
+	runners, remaining, err := api.FetchRepoRunners(ctx, "octo", "hello")
+	if err != nil {
+		t.Fatalf("FetchRepoRunners: %v", err)
+	}
+	if remaining != 5000 {
+		t.Errorf("rate limit remaining = %d, want 5000", remaining)
+	}
+	want := []Runner{{ID: 1, Name: "build-1", OS: "Linux", Status: "online", Busy: true, Labels: []string{"self-hosted", "linux"}}}
+	if !reflect.DeepEqual(runners, want) {
+		t.Errorf("repo runners = %+v, want %+v", runners, want)
+	}
+
+	// Organization runners need admin access the token doesn't have yet.
+	if _, _, err := api.FetchOrgRunners(ctx, "octo"); !errors.Is(err, ErrRunnersNotVisible) {
+		t.Errorf("FetchOrgRunners error = %v, want ErrRunnersNotVisible", err)
+	}
+	srv.AddOrgRunners("octo", fakegithub.Runner{ID: 2, Name: "gpu-1", Labels: []string{"self-hosted", "gpu"}})
+	runners, _, err = api.FetchOrgRunners(ctx, "octo")
+	if err != nil {
+		t.Fatalf("FetchOrgRunners: %v", err)
+	}
+	if len(runners) != 1 || !runners[0].Org || runners[0].Online() {
+		t.Errorf("org runners = %+v, want gpu-1 offline on the org", runners)
+	}
+}
@@ -9,20 +9,28 @@ import (
 	"github.com/fini-net/gh-observer/internal/debug"
 	"github.com/google/go-github/v90/github"
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 // WorkflowJobInfo contains status data for a single job within a workflow run.
+//
+// CreatedAt is when the job was queued (the jobs API's created_at), so
+// StartedAt - CreatedAt is the time the job spent waiting for a runner.
+// Labels are the job's `runs-on` labels; RunnerName and RunnerGroupName
+// identify the runner that picked the job up and stay empty until it starts.
 type WorkflowJobInfo struct {
-	Name         string
-	WorkflowName string
-	Status       string
-	Conclusion   string
-	StartedAt    *github.Timestamp
-	CompletedAt  *github.Timestamp
-	HTMLURL      string
-	RunID        int64
-	WorkflowID   int64
+	Name            string
+	WorkflowName    string
+	Status          string
+	Conclusion      string
+	CreatedAt       *github.Timestamp
+	StartedAt       *github.Timestamp
+	CompletedAt     *github.Timestamp
+	HTMLURL         string
+	RunID           int64
+	WorkflowID      int64
+	Labels          []string
+	RunnerName      string
+	RunnerGroupName string
 }
 
 // RunInfo contains metadata about a workflow run (for the header display).
@@ -31,11 +39,13 @@ type WorkflowJobInfo struct {
 // the REST head_commit.timestamp as a last resort when GraphQL is
 // unavailable). The "Pushed Xs ago" header renders this value, so it must
 // reflect the push event — not the (potentially much older) commit author
-// or committer timestamp (issue #349).
+// or committer timestamp (issue #349). WorkflowPath is the workflow file
+// (".github/workflows/ci.yml"), used to fetch the job dependency graph.
 type RunInfo struct {
 	ID             int64
 	DisplayTitle   string
 	HeadSHA        string
+	HeadBranch     string
 	HeadCommitMsg  string
 	HeadPushedTime *github.Timestamp
 	CreatedAt      *github.Timestamp
@@ -43,6 +53,7 @@ type RunInfo struct {
 	Status         string
 	Conclusion     string
 	WorkflowID     int64
+	WorkflowPath   string
 }
 
 // firstLine returns the first line of a multiline string, trimmed.
@@ -72,20 +83,17 @@ type commitPushedDateQuery struct {
 			} `graphql:"... on Commit"`
 		} `graphql:"object(oid: $oid)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
-	RateLimit struct {
-		Remaining int
-	}
+	RateLimit graphQLRateLimit
 }
 
-// fetchCommitPushedTime looks up pushedDate (with committedDate fallback)
+// fetchCommitPushedTimeWithClient looks up pushedDate (with committedDate fallback)
 // for the given SHA via a single GraphQL query. Returns the zero time
 // when the lookup fails or returns no usable timestamp; callers are
 // expected to fall back to another source rather than abort. The second
 // return value is the GraphQL rate limit remaining after the call (0 on
 // error or when the query is skipped), so callers can fold it into the
 // app's rate-limit accounting alongside other GraphQL queries.
-// The unexported helper takes a graphqlQuerier so tests can inject a mock;
-// the public caller builds the client from the token via the wrapper below.
+// It takes a graphqlQuerier so tests can inject a mock.
 func fetchCommitPushedTimeWithClient(ctx context.Context, client graphqlQuerier, owner, repo, sha string) (time.Time, int) {
 	if sha == "" {
 		return time.Time{}, 0
@@ -112,28 +120,11 @@ func fetchCommitPushedTimeWithClient(ctx context.Context, client graphqlQuerier,
 	return time.Time{}, rateLimitRemaining
 }
 
-// fetchCommitPushedTime builds an authenticated GraphQL client from token
-// and delegates to fetchCommitPushedTimeWithClient. A missing token yields
-// the zero time and a 0 rate limit (callers fall back to the REST
-// timestamp and keep their existing rate-limit value).
-func fetchCommitPushedTime(ctx context.Context, token, owner, repo, sha string) (time.Time, int) {
-	if token == "" {
-		return time.Time{}, 0
-	}
-	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	httpClient := oauth2.NewClient(ctx, src)
-	client := githubv4.NewClient(httpClient)
-	return fetchCommitPushedTimeWithClient(ctx, client, owner, repo, sha)
-}
-
-// FetchRunInfo retrieves metadata for a workflow run by its ID. The head
+// fetchRunInfo retrieves metadata for a workflow run by its ID. The head
 // commit's push time is sourced from a GraphQL pushedDate lookup (with
 // committedDate fallback); if that lookup fails, it falls back to the
 // REST head_commit.timestamp so the "Pushed Xs ago" header still renders.
-// A non-empty token is required for the GraphQL path; without it the REST
-// fallback is used directly. Callers that already hold a token should pass
-// it here rather than letting this function re-derive it via GetToken()
-// (which may shell out to `gh auth token`).
+// A nil gql skips the lookup and keeps the REST timestamp.
 //
 // The second return value is the GitHub API rate limit remaining after the
 // GraphQL lookup (or 5000 — the REST default — when the lookup is skipped
@@ -142,16 +133,18 @@ func fetchCommitPushedTime(ctx context.Context, token, owner, repo, sha string)
 // fold it into their rate-limit accounting; when the GraphQL call
 // succeeds its observed value is returned, which may be lower than the
 // REST-side reality and is intentionally conservative.
-func FetchRunInfo(ctx context.Context, client *github.Client, token, owner, repo string, runID int64) (*RunInfo, int, error) {
+func fetchRunInfo(ctx context.Context, client *github.Client, gql graphqlQuerier, owner, repo string, runID int64) (*RunInfo, int, error) {
 	run, _, err := client.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
 	if err != nil {
 		return nil, 5000, fmt.Errorf("failed to fetch workflow run %d: %w", runID, err)
 	}
 
 	info := &RunInfo{
-		ID:         run.GetID(),
-		Status:     run.GetStatus(),
-		WorkflowID: run.GetWorkflowID(),
+		ID:           run.GetID(),
+		HeadBranch:   run.GetHeadBranch(),
+		Status:       run.GetStatus(),
+		WorkflowID:   run.GetWorkflowID(),
+		WorkflowPath: run.GetPath(),
 	}
 
 	if run.Name != nil {
@@ -195,8 +188,8 @@ func FetchRunInfo(ctx context.Context, client *github.Client, token, owner, repo
 	// renders (just with a slightly older timestamp). The rate limit
 	// returned by the lookup is surfaced to the caller even on timestamp
 	// miss so the app's backoff accounting sees this one-shot call.
-	if info.HeadSHA != "" {
-		pushed, rl := fetchCommitPushedTime(ctx, token, owner, repo, info.HeadSHA)
+	if info.HeadSHA != "" && gql != nil {
+		pushed, rl := fetchCommitPushedTimeWithClient(ctx, gql, owner, repo, info.HeadSHA)
 		if rl > 0 {
 			rateLimitRemaining = rl
 		}
@@ -265,8 +258,12 @@ func convertWorkflowJob(job *github.WorkflowJob) WorkflowJobInfo {
 	if job.RunID != nil {
 		info.RunID = *job.RunID
 	}
+	info.CreatedAt = job.CreatedAt
 	info.StartedAt = job.StartedAt
 	info.CompletedAt = job.CompletedAt
+	info.Labels = job.Labels
+	info.RunnerName = job.GetRunnerName()
+	info.RunnerGroupName = job.GetRunnerGroupName()
 
 	return info
 }
@@ -284,6 +281,12 @@ func WorkflowJobInfoToCheckRuns(jobs []WorkflowJobInfo) []CheckRunInfo {
 			DetailsURL:    job.HTMLURL,
 			WorkflowRunID: job.RunID,
 			WorkflowID:    job.WorkflowID,
+			Labels:        job.Labels,
+			RunnerName:    job.RunnerName,
+		}
+		if job.CreatedAt != nil {
+			t := job.CreatedAt.Time
+			cr.QueuedAt = &t
 		}
 		if job.StartedAt != nil {
 			t := job.StartedAt.Time
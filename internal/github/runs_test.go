@@ -46,24 +46,30 @@ func TestConvertWorkflowJob(t *testing.T) {
 		{
 			name: "completed success",
 			job: &github.WorkflowJob{
-				Name:         github.Ptr("test"),
-				WorkflowName: github.Ptr("CI"),
-				Status:       github.Ptr("completed"),
-				Conclusion:   github.Ptr("success"),
-				HTMLURL:      github.Ptr("https://github.com/owner/repo/actions/runs/1/job/1"),
-				RunID:        github.Ptr(int64(1)),
-				StartedAt:    startedAt,
-				CompletedAt:  completedAt,
+				Name:            github.Ptr("test"),
+				WorkflowName:    github.Ptr("CI"),
+				Status:          github.Ptr("completed"),
+				Conclusion:      github.Ptr("success"),
+				HTMLURL:         github.Ptr("https://github.com/owner/repo/actions/runs/1/job/1"),
+				RunID:           github.Ptr(int64(1)),
+				StartedAt:       startedAt,
+				CompletedAt:     completedAt,
+				Labels:          []string{"self-hosted", "gpu-large"},
+				RunnerName:      github.Ptr("gpu-runner-3"),
+				RunnerGroupName: github.Ptr("gpu-pool"),
 			},
 			want: WorkflowJobInfo{
-				Name:         "test",
-				WorkflowName: "CI",
-				Status:       "completed",
-				Conclusion:   "success",
-				HTMLURL:      "https://github.com/owner/repo/actions/runs/1/job/1",
-				RunID:        1,
-				StartedAt:    startedAt,
-				CompletedAt:  completedAt,
+				Name:            "test",
+				WorkflowName:    "CI",
+				Status:          "completed",
+				Conclusion:      "success",
+				HTMLURL:         "https://github.com/owner/repo/actions/runs/1/job/1",
+				RunID:           1,
+				StartedAt:       startedAt,
+				CompletedAt:     completedAt,
+				Labels:          []string{"self-hosted", "gpu-large"},
+				RunnerName:      "gpu-runner-3",
+				RunnerGroupName: "gpu-pool",
 			},
 		},
 		{
@@ -117,6 +123,15 @@ func TestConvertWorkflowJob(t *testing.T) {
 			if got.RunID != tt.want.RunID {
 				t.Errorf("RunID = %d, want %d", got.RunID, tt.want.RunID)
 			}
+			if RunnerLabelKey(got.Labels) != RunnerLabelKey(tt.want.Labels) {
+				t.Errorf("Labels = %v, want %v", got.Labels, tt.want.Labels)
+			}
+			if got.RunnerName != tt.want.RunnerName {
+				t.Errorf("RunnerName = %q, want %q", got.RunnerName, tt.want.RunnerName)
+			}
+			if got.RunnerGroupName != tt.want.RunnerGroupName {
+				t.Errorf("RunnerGroupName = %q, want %q", got.RunnerGroupName, tt.want.RunnerGroupName)
+			}
 		})
 	}
 }
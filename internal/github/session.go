@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// maxIdleConnsPerHost keeps enough connections to api.github.com open for
+// a poll's worth of concurrent fetches (repo mode lists jobs for every
+// active run at once). net/http's default of 2 would re-handshake TLS for
+// most of them on every poll.
+const maxIdleConnsPerHost = 16
+
+// Session is the process's one authenticated connection to GitHub, owning
+// the ETag cache, rate-limit governor and FetchPool. It implements API.
+type Session struct {
+	governor *RateLimitGovernor
+	pool     *FetchPool
+	rest     *github.Client
+	graphql  graphqlQuerier
+	viewer   viewerCache
+}
+
+var _ API = (*Session)(nil)
+
+// sessionOptions collects SessionOption settings.
+type sessionOptions struct {
+	restURL     string
+	graphqlURL  string
+	base        http.RoundTripper
+	recorder    *Recorder
+	concurrency int
+}
+
+// SessionOption customizes NewSession.
+type SessionOption func(*sessionOptions)
+
+// WithEndpoints points the session at restURL (which must end in a slash)
+// and graphqlURL instead of api.github.com, e.g. a fake server in tests.
+func WithEndpoints(restURL, graphqlURL string) SessionOption {
+	return func(o *sessionOptions) {
+		o.restURL, o.graphqlURL = restURL, graphqlURL
+	}
+}
+
+// WithBaseTransport replaces the network under the session's cache and
+// governor, e.g. with a Replayer.
+func WithBaseTransport(rt http.RoundTripper) SessionOption {
+	return func(o *sessionOptions) {
+		o.base = rt
+	}
+}
+
+// WithRecorder records every exchange the session's clients make.
+func WithRecorder(rec *Recorder) SessionOption {
+	return func(o *sessionOptions) {
+		o.recorder = rec
+	}
+}
+
+// WithFetchConcurrency caps how many independent fetches (per-run job
+// listings) the session runs at once; see FetchPool. The default is
+// DefaultFetchConcurrency.
+func WithFetchConcurrency(n int) SessionOption {
+	return func(o *sessionOptions) {
+		o.concurrency = n
+	}
+}
+
+// NewSession builds the session for token. The transport chain, from the
+// clients down, is: recorder (if any), rate-limit governor (so paused
+// requests never leave the process), conditional-request cache, and the
+// session's own connection pool.
+func NewSession(token string, opts ...SessionOption) (*Session, error) {
+	o := sessionOptions{concurrency: DefaultFetchConcurrency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	base := o.base
+	if base == nil {
+		pool := http.DefaultTransport.(*http.Transport).Clone()
+		pool.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		base = pool
+	}
+	governor := &RateLimitGovernor{now: time.Now}
+	var rt http.RoundTripper = &governedTransport{base: NewConditionalTransport(base), governor: governor}
+	if o.recorder != nil {
+		o.recorder.base = rt
+		rt = o.recorder
+	}
+
+	restOpts := []github.ClientOptionsFunc{github.WithAuthToken(token), github.WithTransport(rt)}
+	if o.restURL != "" {
+		restOpts = append(restOpts, github.WithURLs(&o.restURL, &o.restURL))
+	}
+	rest, err := github.NewClient(restOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := oauth2.NewClient(
+		context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: rt}),
+		oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+	)
+	gql := githubv4.NewClient(httpClient)
+	if o.graphqlURL != "" {
+		gql = githubv4.NewEnterpriseClient(o.graphqlURL, httpClient)
+	}
+
+	return &Session{
+		governor: governor,
+		pool:     NewFetchPool(o.concurrency),
+		rest:     rest,
+		graphql:  governedQuerier{base: gql, governor: governor},
+	}, nil
+}
+
+// governedQuerier feeds the rateLimit field every query selects to the
+// governor, so spent GraphQL points pause further queries.
+type governedQuerier struct {
+	base     graphqlQuerier
+	governor *RateLimitGovernor
+}
+
+// Query implements graphqlQuerier.
+func (q governedQuerier) Query(ctx context.Context, query interface{}, variables map[string]interface{}) error {
+	err := q.base.Query(ctx, query, variables)
+	if v := reflect.Indirect(reflect.ValueOf(query)); v.Kind() == reflect.Struct {
+		if f := v.FieldByName("RateLimit"); f.IsValid() {
+			if rl, ok := f.Interface().(graphQLRateLimit); ok {
+				q.governor.observeGraphQL(rl)
+			}
+		}
+	}
+	return err
+}
+
+// RateLimitPausedUntil returns when the session's governor will let
+// requests spending any of resources through again, or the zero time when
+// none is paused. The TUI uses it to schedule the next poll after the
+// reset instead of on the normal interval.
+func (s *Session) RateLimitPausedUntil(resources ...string) time.Time {
+	until, _ := s.governor.PausedUntil(resources...)
+	return until
+}
+
+// RateLimitResetAt returns when resource's quota window resets (or last
+// reset), as last reported by GitHub, or the zero time when unknown. Used
+// to budget polls.
+func (s *Session) RateLimitResetAt(resource string) time.Time {
+	return s.governor.ResetAt(resource)
+}
+
+// RateLimitResumeAt reports whether err is a rate limit and, if so, when to
+// retry; see the package-level RateLimitResumeAt. Any error while GraphQL
+// is paused also counts (a query error after rateLimit showed the points
+// were spent).
+func (s *Session) RateLimitResumeAt(err error) (time.Time, bool) {
+	if until, ok := RateLimitResumeAt(err); ok {
+		return until, true
+	}
+	if err != nil {
+		if until := s.RateLimitPausedUntil(ResourceGraphQL); !until.IsZero() {
+			return until, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// TestSessionSharesGovernor checks that a limit seen by one call pauses
+// every other call through the same session, REST and GraphQL alike, and
+// that a second session is unaffected.
+func TestSessionSharesGovernor(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	session, err := NewSession("test-token", WithEndpoints(server.URL+"/", server.URL+"/graphql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := session.FetchPRInfo(ctx, "owner", "repo", 1); err == nil {
+		t.Fatal("FetchPRInfo succeeded against a 429")
+	}
+	until := session.RateLimitPausedUntil(ResourceCore)
+	if wait := time.Until(until); wait < 110*time.Second || wait > 120*time.Second {
+		t.Fatalf("paused until %v, want ~2m from now", until)
+	}
+
+	_, _, _, err = session.FetchCheckRuns(ctx, "owner", "repo", 1)
+	var limited *RateLimitedError
+	if !errors.As(err, &limited) {
+		t.Errorf("GraphQL err = %v, want RateLimitedError without a request", err)
+	}
+	if got, ok := session.RateLimitResumeAt(errors.New("any error while paused")); !ok || !got.Equal(until) {
+		t.Errorf("RateLimitResumeAt = %v, %v; want %v", got, ok, until)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1", requests.Load())
+	}
+
+	other, _ := NewSession("test-token", WithEndpoints(server.URL+"/", server.URL+"/graphql"))
+	if !other.RateLimitPausedUntil(ResourceCore).IsZero() {
+		t.Error("a new session inherited another session's pause")
+	}
+}
+
+// TestGovernedQuerierObservesRateLimit checks that GraphQL point budgets
+// reach the governor without each fetch function reporting them.
+func TestGovernedQuerierObservesRateLimit(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	g := &RateLimitGovernor{now: time.Now}
+	resp := commitPushedDateQuery{}
+	resp.RateLimit = graphQLRateLimit{Cost: 1, Remaining: 0, ResetAt: githubv4.DateTime{Time: reset}}
+	mock := &mockPushedDateQuerier{responses: []commitPushedDateQuery{resp}}
+
+	fetchCommitPushedTimeWithClient(context.Background(), governedQuerier{base: mock, governor: g}, "owner", "repo", "deadbeef")
+	if until, _ := g.PausedUntil(ResourceGraphQL); !until.Equal(reset) {
+		t.Errorf("paused until %v, want %v", until, reset)
+	}
+}
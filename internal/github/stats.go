@@ -0,0 +1,154 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+)
+
+// StatsRun is one completed workflow run observed by the stats subcommand,
+// with the latest attempt's jobs attached.
+type StatsRun struct {
+	RunID        int64
+	WorkflowID   int64
+	WorkflowName string
+	HeadBranch   string
+	Conclusion   string
+	CreatedAt    time.Time
+	Jobs         []StatsJob
+}
+
+// StatsJob is one job of a StatsRun. QueuedAt is the job's created_at, so
+// StartedAt - QueuedAt is the time it waited for a runner.
+type StatsJob struct {
+	Name        string
+	Conclusion  string
+	Labels      []string
+	QueuedAt    time.Time
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Workflow identifies a workflow resolved by ResolveWorkflow.
+type Workflow struct {
+	ID   int64
+	Name string
+	Path string
+}
+
+// ResolveWorkflow finds a workflow by numeric ID, file name ("ci.yml" or
+// ".github/workflows/ci.yml"), or display name (case-insensitive). File
+// names and display names are matched against the repo's workflow list in
+// one call; a numeric ID is fetched directly.
+func ResolveWorkflow(ctx context.Context, client *github.Client, owner, repo, workflow string) (Workflow, error) {
+	if id, err := strconv.ParseInt(workflow, 10, 64); err == nil {
+		wf, _, err := client.Actions.GetWorkflowByID(ctx, owner, repo, id)
+		if err != nil {
+			return Workflow{}, fmt.Errorf("failed to get workflow %d: %w", id, err)
+		}
+		return Workflow{ID: wf.GetID(), Name: wf.GetName(), Path: wf.GetPath()}, nil
+	}
+
+	workflows, _, err := client.Actions.ListWorkflows(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to list workflows: %w", err)
+	}
+	for _, wf := range workflows.Workflows {
+		if wf.GetPath() == workflow || path.Base(wf.GetPath()) == workflow || strings.EqualFold(wf.GetName(), workflow) {
+			return Workflow{ID: wf.GetID(), Name: wf.GetName(), Path: wf.GetPath()}, nil
+		}
+	}
+	return Workflow{}, fmt.Errorf("no workflow named %q in %s/%s", workflow, owner, repo)
+}
+
+// FetchStatsRuns lists up to maxRuns completed runs since since, newest
+// first, with their jobs; workflowID 0 means every workflow.
+func FetchStatsRuns(ctx context.Context, client *github.Client, owner, repo string, workflowID int64, since time.Time, maxRuns int) ([]StatsRun, error) {
+	opts := &github.ListWorkflowRunsOptions{
+		Status:      "completed",
+		Created:     ">=" + since.UTC().Format(time.RFC3339),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var runs []StatsRun
+	for len(runs) < maxRuns {
+		var page *github.WorkflowRuns
+		var resp *github.Response
+		var err error
+		if workflowID != 0 {
+			page, resp, err = client.Actions.ListWorkflowRunsByID(ctx, owner, repo, workflowID, opts)
+		} else {
+			page, resp, err = client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+		for _, run := range page.WorkflowRuns {
+			if len(runs) == maxRuns {
+				break
+			}
+			runs = append(runs, convertStatsRun(run))
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	for i := range runs {
+		jobs, _, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runs[i].RunID, &github.ListWorkflowJobsOptions{
+			Filter:      "latest",
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			debug.Log("stats job listing failed", "run_id", runs[i].RunID, "err", err)
+			continue
+		}
+		for _, job := range jobs.Jobs {
+			runs[i].Jobs = append(runs[i].Jobs, convertStatsJob(job))
+		}
+	}
+
+	debug.Log("fetched stats runs", "owner", owner, "repo", repo, "workflow_id", workflowID,
+		"since", since, "runs", len(runs))
+
+	return runs, nil
+}
+
+func convertStatsRun(run *github.WorkflowRun) StatsRun {
+	data := StatsRun{
+		RunID:        run.GetID(),
+		WorkflowID:   run.GetWorkflowID(),
+		WorkflowName: run.GetName(),
+		HeadBranch:   run.GetHeadBranch(),
+		Conclusion:   strings.ToLower(run.GetConclusion()),
+	}
+	if run.CreatedAt != nil {
+		data.CreatedAt = run.CreatedAt.Time
+	}
+	return data
+}
+
+func convertStatsJob(job *github.WorkflowJob) StatsJob {
+	data := StatsJob{
+		Name:       job.GetName(),
+		Conclusion: strings.ToLower(job.GetConclusion()),
+		Labels:     job.Labels,
+	}
+	if job.CreatedAt != nil {
+		data.QueuedAt = job.CreatedAt.Time
+	}
+	if job.StartedAt != nil {
+		data.StartedAt = job.StartedAt.Time
+	}
+	if job.CompletedAt != nil {
+		data.CompletedAt = job.CompletedAt.Time
+	}
+	return data
+}
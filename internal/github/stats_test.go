@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestResolveWorkflow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/workflows":
+			w.Write([]byte(`{"total_count":2,"workflows":[
+				{"id":1,"name":"CI","path":".github/workflows/ci.yml"},
+				{"id":2,"name":"Release","path":".github/workflows/release.yaml"}
+			]}`))
+		case "/repos/owner/repo/actions/workflows/2":
+			w.Write([]byte(`{"id":2,"name":"Release","path":".github/workflows/release.yaml"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	tests := []struct {
+		name    string
+		arg     string
+		wantID  int64
+		wantErr bool
+	}{
+		{name: "file name", arg: "ci.yml", wantID: 1},
+		{name: "full path", arg: ".github/workflows/release.yaml", wantID: 2},
+		{name: "display name is case-insensitive", arg: "ci", wantID: 1},
+		{name: "numeric ID", arg: "2", wantID: 2},
+		{name: "unknown", arg: "deploy.yml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf, err := ResolveWorkflow(context.Background(), client, "owner", "repo", tt.arg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", wf)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if wf.ID != tt.wantID {
+				t.Errorf("ID = %d, want %d", wf.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestFetchStatsRuns(t *testing.T) {
+	var gotCreated string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/repos/owner/repo/actions/workflows/7/runs":
+			gotCreated = r.URL.Query().Get("created")
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{"workflow_runs":[{"id":3,"name":"CI","conclusion":"success"}]}`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+			w.Write([]byte(`{"workflow_runs":[
+				{"id":1,"name":"CI","conclusion":"Failure","created_at":"2026-06-02T00:00:00Z"},
+				{"id":2,"name":"CI","conclusion":"success","created_at":"2026-06-01T00:00:00Z"}
+			]}`))
+		case r.URL.Path == "/repos/owner/repo/actions/runs/1/jobs":
+			w.Write([]byte(`{"jobs":[{"name":"build","conclusion":"failure","labels":["ubuntu-latest"],
+				"created_at":"2026-06-02T00:00:00Z","started_at":"2026-06-02T00:00:30Z","completed_at":"2026-06-02T00:05:30Z"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/repo/actions/runs/"):
+			http.Error(w, "boom", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	since := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("paginates and attaches jobs", func(t *testing.T) {
+		runs, err := FetchStatsRuns(context.Background(), client, "owner", "repo", 7, since, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotCreated != ">=2026-05-01T00:00:00Z" {
+			t.Errorf("created filter = %q", gotCreated)
+		}
+		if len(runs) != 3 {
+			t.Fatalf("runs = %d, want 3 across two pages", len(runs))
+		}
+		if runs[0].Conclusion != "failure" {
+			t.Errorf("conclusion = %q, want lowercased failure", runs[0].Conclusion)
+		}
+		if len(runs[0].Jobs) != 1 || runs[0].Jobs[0].StartedAt.Sub(runs[0].Jobs[0].QueuedAt) != 30*time.Second {
+			t.Errorf("run 1 jobs = %+v", runs[0].Jobs)
+		}
+		if len(runs[1].Jobs) != 0 {
+			t.Errorf("failed job listing should leave run without jobs, got %+v", runs[1].Jobs)
+		}
+	})
+
+	t.Run("stops at maxRuns", func(t *testing.T) {
+		runs, err := FetchStatsRuns(context.Background(), client, "owner", "repo", 7, since, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(runs) != 1 || runs[0].RunID != 1 {
+			t.Errorf("runs = %+v, want only run 1", runs)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// viewerQuery fetches the authenticated user's login.
+type viewerQuery struct {
+	Viewer struct {
+		Login string
+	}
+	RateLimit graphQLRateLimit
+}
+
+// viewerCache holds the authenticated user's login once fetched; it can't
+// change for the life of a token.
+type viewerCache struct {
+	mu    sync.Mutex
+	login string
+}
+
+// get returns the cached login, fetching it on first use. A failed fetch
+// isn't cached, so the next poll retries.
+func (c *viewerCache) get(ctx context.Context, client graphqlQuerier) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.login != "" {
+		return c.login, nil
+	}
+	var query viewerQuery
+	if err := client.Query(ctx, &query, nil); err != nil {
+		return "", err
+	}
+	c.login = query.Viewer.Login
+	return c.login, nil
+}
+
+// resolveRepoFilter replaces ViewerAlias in f.Author with the
+// authenticated user's login.
+func (s *Session) resolveRepoFilter(ctx context.Context, f RepoFilter) (RepoFilter, error) {
+	if !strings.EqualFold(f.Author, ViewerAlias) {
+		return f, nil
+	}
+	login, err := s.viewer.get(ctx, s.graphql)
+	if err != nil {
+		return RepoFilter{}, err
+	}
+	f.Author = login
+	return f, nil
+}
@@ -0,0 +1,181 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/google/go-github/v90/github"
+	"go.yaml.in/yaml/v3"
+)
+
+// WorkflowGraph is the job dependency graph parsed from a workflow file's
+// `jobs:` map. Nodes are keyed by job ID (the YAML key), which is what
+// `needs:` refers to.
+type WorkflowGraph struct {
+	Jobs map[string]*WorkflowGraphJob
+}
+
+// WorkflowGraphJob is one `jobs.<id>` entry, with its raw `name:`, whether
+// it has a matrix, and the reusable workflow it `uses:`.
+type WorkflowGraphJob struct {
+	ID     string
+	Name   string
+	Needs  []string
+	Matrix bool
+	Uses   string
+
+	matcher *regexp.Regexp
+}
+
+// workflowFile is the subset of a workflow YAML document the graph needs.
+// `needs` is decoded as a yaml.Node because it may be a string or a list.
+type workflowFile struct {
+	Jobs map[string]struct {
+		Name     string    `yaml:"name"`
+		Needs    yaml.Node `yaml:"needs"`
+		Uses     string    `yaml:"uses"`
+		Strategy struct {
+			Matrix yaml.Node `yaml:"matrix"`
+		} `yaml:"strategy"`
+	} `yaml:"jobs"`
+}
+
+// ParseWorkflowGraph parses a workflow file into its job dependency graph.
+// Needs that reference undefined jobs are dropped (GitHub rejects such
+// workflows, but a half-edited file at an old SHA shouldn't break the view).
+func ParseWorkflowGraph(data []byte) (*WorkflowGraph, error) {
+	var wf workflowFile
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if len(wf.Jobs) == 0 {
+		return nil, fmt.Errorf("workflow has no jobs")
+	}
+
+	graph := &WorkflowGraph{Jobs: make(map[string]*WorkflowGraphJob, len(wf.Jobs))}
+	for id, job := range wf.Jobs {
+		node := &WorkflowGraphJob{
+			ID:     id,
+			Name:   job.Name,
+			Uses:   job.Uses,
+			Matrix: !job.Strategy.Matrix.IsZero(),
+		}
+		switch job.Needs.Kind {
+		case yaml.ScalarNode:
+			node.Needs = []string{job.Needs.Value}
+		case yaml.SequenceNode:
+			for _, n := range job.Needs.Content {
+				node.Needs = append(node.Needs, n.Value)
+			}
+		}
+		node.matcher = jobNameMatcher(node)
+		graph.Jobs[id] = node
+	}
+	for _, node := range graph.Jobs {
+		kept := node.Needs[:0]
+		for _, need := range node.Needs {
+			if _, ok := graph.Jobs[need]; ok {
+				kept = append(kept, need)
+			}
+		}
+		node.Needs = kept
+	}
+	return graph, nil
+}
+
+// expressionRegexp matches a ${{ ... }} expression in a job name.
+var expressionRegexp = regexp.MustCompile(`\$\{\{.*?\}\}`)
+
+// jobNameMatcher matches the API names of a graph job's instances,
+// allowing for expressions, matrix suffixes and reusable-call inner jobs.
+func jobNameMatcher(node *WorkflowGraphJob) *regexp.Regexp {
+	display := node.Name
+	if display == "" {
+		display = node.ID
+	}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range expressionRegexp.FindAllStringIndex(display, -1) {
+		pattern.WriteString(regexp.QuoteMeta(display[last:loc[0]]))
+		pattern.WriteString(".*?")
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(display[last:]))
+	if node.Matrix && !expressionRegexp.MatchString(display) {
+		pattern.WriteString(`( \(.*\))?`)
+	}
+	if node.Uses != "" {
+		pattern.WriteString(`( / .*)?`)
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+// MatchJob returns the ID of the graph job behind apiName, or "". Exact
+// names win, then the lexically first ID.
+func (g *WorkflowGraph) MatchJob(apiName string) string {
+	ids := make([]string, 0, len(g.Jobs))
+	for id := range g.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		node := g.Jobs[id]
+		display := node.Name
+		if display == "" {
+			display = node.ID
+		}
+		if display == apiName {
+			return id
+		}
+	}
+	for _, id := range ids {
+		if g.Jobs[id].matcher.MatchString(apiName) {
+			return id
+		}
+	}
+	return ""
+}
+
+// FetchWorkflowGraph fetches and parses the workflow file at path as of
+// ref, the run's head SHA.
+func FetchWorkflowGraph(ctx context.Context, client *github.Client, owner, repo, path, ref string) (*WorkflowGraph, error) {
+	if path == "" {
+		return nil, fmt.Errorf("run has no workflow path")
+	}
+	// Dynamic runs (e.g. Dependabot, CodeQL default setup) report paths like
+	// "dynamic/..." that don't exist in the repo.
+	if !strings.HasPrefix(path, ".github/workflows/") {
+		return nil, fmt.Errorf("workflow path %q is not a repository file", path)
+	}
+	// Runs triggered from another ref report "path@ref"; the file lives at
+	// the run's head SHA either way.
+	if i := strings.Index(path, "@"); i >= 0 {
+		path = path[:i]
+	}
+
+	file, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s at %s: %w", path, ref, err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	graph, err := ParseWorkflowGraph([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+	debug.Log("fetched workflow graph", "path", path, "ref", ref, "jobs", len(graph.Jobs))
+	return graph, nil
+}
@@ -0,0 +1,168 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+)
+
+const testWorkflowYAML = `
+name: CI
+on: [push]
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps: [{run: make lint}]
+  test:
+    name: Test
+    needs: lint
+    strategy:
+      matrix:
+        go: ["1.25", "1.26"]
+    runs-on: ubuntu-latest
+    steps: [{run: make test}]
+  e2e:
+    name: E2E ${{ matrix.os }}
+    needs: [lint]
+    strategy:
+      matrix:
+        os: [linux, macos]
+    runs-on: ubuntu-latest
+    steps: [{run: make e2e}]
+  release:
+    needs: [test, e2e, missing]
+    uses: ./.github/workflows/release.yml
+`
+
+func TestParseWorkflowGraph(t *testing.T) {
+	graph, err := ParseWorkflowGraph([]byte(testWorkflowYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graph.Jobs) != 4 {
+		t.Fatalf("jobs = %d, want 4", len(graph.Jobs))
+	}
+	if got := graph.Jobs["test"].Needs; !reflect.DeepEqual(got, []string{"lint"}) {
+		t.Errorf("scalar needs = %v, want [lint]", got)
+	}
+	needs := append([]string(nil), graph.Jobs["release"].Needs...)
+	sort.Strings(needs)
+	if !reflect.DeepEqual(needs, []string{"e2e", "test"}) {
+		t.Errorf("release needs = %v, want [e2e test] (undefined job dropped)", needs)
+	}
+	if !graph.Jobs["test"].Matrix || graph.Jobs["lint"].Matrix {
+		t.Error("matrix flag not parsed")
+	}
+	if graph.Jobs["release"].Uses == "" {
+		t.Error("uses not parsed")
+	}
+
+	if _, err := ParseWorkflowGraph([]byte("name: empty\n")); err == nil {
+		t.Error("expected error for workflow without jobs")
+	}
+	if _, err := ParseWorkflowGraph([]byte("jobs: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestWorkflowGraphMatchJob(t *testing.T) {
+	graph, err := ParseWorkflowGraph([]byte(testWorkflowYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		apiName string
+		want    string
+	}{
+		{apiName: "lint", want: "lint"},
+		{apiName: "Test", want: "test"},
+		{apiName: "Test (1.26)", want: "test"},
+		{apiName: "E2E macos", want: "e2e"},
+		{apiName: "release / publish", want: "release"},
+		{apiName: "release", want: "release"},
+		{apiName: "Test / inner", want: ""},
+		{apiName: "deploy", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiName, func(t *testing.T) {
+			if got := graph.MatchJob(tt.apiName); got != tt.want {
+				t.Errorf("MatchJob(%q) = %q, want %q", tt.apiName, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("exact name beats pattern", func(t *testing.T) {
+		g, err := ParseWorkflowGraph([]byte(`
+jobs:
+  a:
+    name: build
+    uses: ./.github/workflows/build.yml
+  b:
+    name: build / docs
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := g.MatchJob("build / docs"); got != "b" {
+			t.Errorf("MatchJob = %q, want b", got)
+		}
+	})
+}
+
+func TestFetchWorkflowGraph(t *testing.T) {
+	var gotRef string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/.github/workflows/ci.yml":
+			gotRef = r.URL.Query().Get("ref")
+			fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`,
+				base64.StdEncoding.EncodeToString([]byte(testWorkflowYAML)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client, _ := github.NewClient(github.WithURLs(ptrTo(server.URL+"/"), ptrTo(server.URL+"/")))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "workflow file", path: ".github/workflows/ci.yml"},
+		{name: "path with ref suffix", path: ".github/workflows/ci.yml@refs/heads/main"},
+		{name: "dynamic workflow", path: "dynamic/github-code-scanning/codeql", wantErr: true},
+		{name: "missing file", path: ".github/workflows/gone.yml", wantErr: true},
+		{name: "no path", path: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRef = ""
+			graph, err := FetchWorkflowGraph(context.Background(), client, "owner", "repo", tt.path, "abc123")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", graph)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotRef != "abc123" {
+				t.Errorf("ref = %q, want head SHA", gotRef)
+			}
+			if len(graph.Jobs) != 4 {
+				t.Errorf("jobs = %d, want 4", len(graph.Jobs))
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package notify tells the user a watch needs their attention while they
+// are looking at another window: a terminal bell, an OSC 9 or OSC 777
+// terminal notification, or a desktop notification on Linux.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Trigger is a moment worth a notification.
+type Trigger string
+
+// The triggers a notifier can be configured with.
+const (
+	// PRDone fires when every check on a watched PR has finished, or a
+	// watched run has.
+	PRDone Trigger = "pr_done"
+	// FirstFailure fires on the first failed check of a commit.
+	FirstFailure Trigger = "first_failure"
+	// CopilotReview fires when the Copilot review is posted.
+	CopilotReview Trigger = "copilot_review"
+	// MainFailed fires when a run on a main branch fails.
+	MainFailed Trigger = "main_failed"
+)
+
+// Triggers lists every trigger, in the order they are documented.
+var Triggers = []Trigger{PRDone, FirstFailure, CopilotReview, MainFailed}
+
+// DefaultMainBranches are the branches MainFailed watches unless
+// configured otherwise.
+var DefaultMainBranches = []string{"main", "master"}
+
+// Notification is one message to deliver.
+type Notification struct {
+	Trigger Trigger
+	Title   string // "octo/hello PR #12"
+	Body    string // "CI / test failed"
+}
+
+// Backend delivers notifications. Terminal backends can't write to the
+// terminal themselves while the TUI owns it, so they return the escape
+// sequence for the caller to print; backends that deliver on their own
+// return "".
+type Backend interface {
+	Name() string
+	Notify(ctx context.Context, n Notification) (seq string, err error)
+}
+
+// Bell rings the terminal bell.
+type Bell struct{}
+
+func (Bell) Name() string { return "bell" }
+
+func (Bell) Notify(context.Context, Notification) (string, error) {
+	return "\a", nil
+}
+
+// OSC9 posts a notification through the OSC 9 escape sequence (iTerm2,
+// Windows Terminal, WezTerm, kitty, foot, Ghostty).
+type OSC9 struct{}
+
+func (OSC9) Name() string { return "osc9" }
+
+func (OSC9) Notify(_ context.Context, n Notification) (string, error) {
+	return "\x1b]9;" + sanitize(n.Title) + ": " + sanitize(n.Body) + "\a", nil
+}
+
+// OSC777 posts a notification through the OSC 777 escape sequence
+// (urxvt, VTE-based terminals, WezTerm, foot, Ghostty), which carries the
+// title and body separately.
+type OSC777 struct{}
+
+func (OSC777) Name() string { return "osc777" }
+
+func (OSC777) Notify(_ context.Context, n Notification) (string, error) {
+	// The title ends at the first ';', so it can't contain one.
+	title := strings.ReplaceAll(sanitize(n.Title), ";", ",")
+	return "\x1b]777;notify;" + title + ";" + sanitize(n.Body) + "\a", nil
+}
+
+// sanitize drops the control characters from s, so text from the API
+// can't end the escape sequence early or start another.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// appName is what desktop notifications are posted as.
+const appName = "gh-observer"
+
+// Desktop posts a desktop notification on Linux with notify-send, or
+// straight over D-Bus with gdbus when notify-send isn't installed.
+type Desktop struct {
+	// lookPath and run are exec.LookPath and running a command, replaced
+	// in tests.
+	lookPath func(file string) (string, error)
+	run      func(ctx context.Context, name string, args ...string) error
+}
+
+// NewDesktop returns the desktop backend, or an error off Linux.
+func NewDesktop() (*Desktop, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("desktop notifications need Linux (notify-send or D-Bus), not %s", runtime.GOOS)
+	}
+	return &Desktop{
+		lookPath: exec.LookPath,
+		run: func(ctx context.Context, name string, args ...string) error {
+			out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+			if err != nil && len(out) > 0 {
+				return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+			}
+			return err
+		},
+	}, nil
+}
+
+func (*Desktop) Name() string { return "desktop" }
+
+// Notify runs notify-send, falling back to gdbus. Arguments are passed
+// straight to the command, never through a shell.
+func (d *Desktop) Notify(ctx context.Context, n Notification) (string, error) {
+	// Notification servers may render the body as markup.
+	body := markupEscaper.Replace(sanitize(n.Body))
+	title := sanitize(n.Title)
+
+	if _, err := d.lookPath("notify-send"); err == nil {
+		if err := d.run(ctx, "notify-send", "--app-name="+appName, "--", title, body); err != nil {
+			return "", fmt.Errorf("failed to run notify-send: %w", err)
+		}
+		return "", nil
+	}
+	if _, err := d.lookPath("gdbus"); err == nil {
+		args := []string{
+			"call", "--session",
+			"--dest", "org.freedesktop.Notifications",
+			"--object-path", "/org/freedesktop/Notifications",
+			"--method", "org.freedesktop.Notifications.Notify",
+			gvariantString(appName), "0", gvariantString(""),
+			gvariantString(title), gvariantString(body),
+			"[]", "{}", "-1",
+		}
+		if err := d.run(ctx, "gdbus", args...); err != nil {
+			return "", fmt.Errorf("failed to call the notification service over D-Bus: %w", err)
+		}
+		return "", nil
+	}
+	return "", errors.New("desktop notifications need notify-send or gdbus on PATH")
+}
+
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// gvariantString quotes s as a GVariant text-format string, the form gdbus
+// call parses its arguments in.
+func gvariantString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// ParseBackends turns backend names ("bell", "osc9", "osc777", "desktop")
+// into backends. "none" or no names at all turns notifications off.
+func ParseBackends(names []string) ([]Backend, error) {
+	var backends []Backend
+	var seen []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" || slices.Contains(seen, name) {
+			continue
+		}
+		seen = append(seen, name)
+		switch name {
+		case "bell":
+			backends = append(backends, Bell{})
+		case "osc9":
+			backends = append(backends, OSC9{})
+		case "osc777":
+			backends = append(backends, OSC777{})
+		case "desktop":
+			d, err := NewDesktop()
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, d)
+		default:
+			return nil, fmt.Errorf("unknown notification backend %q (want bell, osc9, osc777, desktop or none)", name)
+		}
+	}
+	return backends, nil
+}
+
+// ParseTriggers turns trigger names into triggers.
+func ParseTriggers(names []string) ([]Trigger, error) {
+	var triggers []Trigger
+	for _, name := range names {
+		t := Trigger(strings.ToLower(strings.TrimSpace(name)))
+		if !slices.Contains(Triggers, t) {
+			return nil, fmt.Errorf("unknown notification trigger %q (want pr_done, first_failure, copilot_review or main_failed)", name)
+		}
+		if !slices.Contains(triggers, t) {
+			triggers = append(triggers, t)
+		}
+	}
+	return triggers, nil
+}
+
+// Notifier sends notifications for its triggers through its backends. A
+// nil Notifier, or one without backends, sends nothing.
+type Notifier struct {
+	backends     []Backend
+	triggers     []Trigger
+	mainBranches []string
+}
+
+// New returns a notifier for triggers through backends. mainBranches are
+// the branches MainFailed watches; empty means DefaultMainBranches.
+func New(backends []Backend, triggers []Trigger, mainBranches []string) *Notifier {
+	if len(mainBranches) == 0 {
+		mainBranches = DefaultMainBranches
+	}
+	return &Notifier{backends: backends, triggers: triggers, mainBranches: mainBranches}
+}
+
+// Enabled reports whether t would be delivered anywhere.
+func (n *Notifier) Enabled(t Trigger) bool {
+	return n != nil && len(n.backends) > 0 && slices.Contains(n.triggers, t)
+}
+
+// MainBranch reports whether a failed run on branch is worth MainFailed.
+func (n *Notifier) MainBranch(branch string) bool {
+	return n != nil && slices.Contains(n.mainBranches, branch)
+}
+
+// Send delivers note through every backend, returning the escape sequences
+// the terminal backends produced, concatenated, and any backend errors
+// joined. A note whose trigger isn't enabled is dropped.
+func (n *Notifier) Send(ctx context.Context, note Notification) (string, error) {
+	if !n.Enabled(note.Trigger) {
+		return "", nil
+	}
+	var seqs strings.Builder
+	var errs []error
+	for _, b := range n.backends {
+		seq, err := b.Notify(ctx, note)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+		}
+		seqs.WriteString(seq)
+	}
+	return seqs.String(), errors.Join(errs...)
+}
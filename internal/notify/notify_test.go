@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerminalSequences(t *testing.T) {
+	note := Notification{Trigger: FirstFailure, Title: "octo/hello PR #12", Body: "CI / test failed"}
+	tests := []struct {
+		name    string
+		backend Backend
+		note    Notification
+		want    string
+	}{
+		{name: "bell", backend: Bell{}, note: note, want: "\a"},
+		{name: "osc9", backend: OSC9{}, note: note, want: "\x1b]9;octo/hello PR #12: CI / test failed\a"},
+		{name: "osc777", backend: OSC777{}, note: note, want: "\x1b]777;notify;octo/hello PR #12;CI / test failed\a"},
+		{
+			name:    "control characters can't end the sequence",
+			backend: OSC9{},
+			note:    Notification{Title: "evil\a\x1b]0;pwned", Body: "line\none\u009c"},
+			want:    "\x1b]9;evil]0;pwned: lineone\a",
+		},
+		{
+			name:    "osc777 title can't contain the field separator",
+			backend: OSC777{},
+			note:    Notification{Title: "a;b", Body: "c;d"},
+			want:    "\x1b]777;notify;a,b;c;d\a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.backend.Notify(context.Background(), tt.note)
+			if err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Notify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDesktop(t *testing.T) {
+	note := Notification{Title: "octo/hello main", Body: "Deploy <prod> failed & it's red"}
+	tests := []struct {
+		name      string
+		installed []string
+		wantName  string
+		wantArgs  []string
+		wantErr   bool
+	}{
+		{
+			name:      "notify-send",
+			installed: []string{"notify-send", "gdbus"},
+			wantName:  "notify-send",
+			wantArgs:  []string{"--app-name=gh-observer", "--", "octo/hello main", "Deploy &lt;prod&gt; failed &amp; it's red"},
+		},
+		{
+			name:      "gdbus when notify-send is missing",
+			installed: []string{"gdbus"},
+			wantName:  "gdbus",
+			wantArgs: []string{
+				"call", "--session",
+				"--dest", "org.freedesktop.Notifications",
+				"--object-path", "/org/freedesktop/Notifications",
+				"--method", "org.freedesktop.Notifications.Notify",
+				"'gh-observer'", "0", "''", "'octo/hello main'", `'Deploy &lt;prod&gt; failed &amp; it\'s red'`,
+				"[]", "{}", "-1",
+			},
+		},
+		{name: "neither installed", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			d := &Desktop{
+				lookPath: func(file string) (string, error) {
+					for _, name := range tt.installed {
+						if name == file {
+							return "/usr/bin/" + file, nil
+						}
+					}
+					return "", errors.New("not found")
+				},
+				run: func(_ context.Context, name string, args ...string) error {
+					gotName, gotArgs = name, args
+					return nil
+				},
+			}
+			seq, err := d.Notify(context.Background(), note)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if seq != "" {
+				t.Errorf("Notify() = %q, want no escape sequence", seq)
+			}
+			if gotName != tt.wantName || !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("ran %s %q, want %s %q", gotName, gotArgs, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "none configured", names: nil, want: nil},
+		{name: "none", names: []string{"none"}, want: nil},
+		{name: "several, duplicates dropped", names: []string{"bell", " OSC9 ", "bell", "osc777"}, want: []string{"bell", "osc9", "osc777"}},
+		{name: "unknown", names: []string{"bell", "pager"}, wantErr: `unknown notification backend "pager"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backends, err := ParseBackends(tt.names)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseBackends error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBackends: %v", err)
+			}
+			var got []string
+			for _, b := range backends {
+				got = append(got, b.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBackends(%q) = %q, want %q", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTriggers(t *testing.T) {
+	got, err := ParseTriggers([]string{"pr_done", "MAIN_FAILED", "pr_done"})
+	if err != nil {
+		t.Fatalf("ParseTriggers: %v", err)
+	}
+	if want := []Trigger{PRDone, MainFailed}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTriggers() = %q, want %q", got, want)
+	}
+	if _, err := ParseTriggers([]string{"pr_merged"}); err == nil {
+		t.Error("ParseTriggers accepted an unknown trigger")
+	}
+}
+
+// failing is a backend that always fails.
+type failing struct{}
+
+func (failing) Name() string { return "failing" }
+
+func (failing) Notify(context.Context, Notification) (string, error) {
+	return "", errors.New("no display")
+}
+
+func TestNotifierSend(t *testing.T) {
+	ctx := context.Background()
+	n := New([]Backend{Bell{}, failing{}, OSC9{}}, []Trigger{PRDone}, nil)
+
+	seq, err := n.Send(ctx, Notification{Trigger: PRDone, Title: "t", Body: "b"})
+	if want := "\a\x1b]9;t: b\a"; seq != want {
+		t.Errorf("Send() = %q, want %q", seq, want)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failing: no display") {
+		t.Errorf("Send error = %v, want the failing backend's error", err)
+	}
+
+	if seq, err := n.Send(ctx, Notification{Trigger: FirstFailure}); seq != "" || err != nil {
+		t.Errorf("Send(disabled trigger) = %q, %v, want nothing", seq, err)
+	}
+
+	var none *Notifier
+	if none.Enabled(PRDone) || none.MainBranch("main") {
+		t.Error("a nil Notifier should have nothing enabled")
+	}
+	if !n.MainBranch("master") || n.MainBranch("feature") {
+		t.Error("MainBranch should default to main and master")
+	}
+}
@@ -0,0 +1,260 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/timing"
+)
+
+// Output formats accepted by --format.
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+)
+
+// Write renders the report in the given format.
+func Write(w io.Writer, r Report, format string) error {
+	switch format {
+	case FormatTable, "":
+		return WriteTable(w, r)
+	case FormatCSV:
+		return WriteCSV(w, r)
+	case FormatJSON:
+		return WriteJSON(w, r)
+	default:
+		return fmt.Errorf("unknown format %q (use table, csv, or json)", format)
+	}
+}
+
+// sparkBlocks are the eight levels used by Sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders one block per value scaled between the smallest and
+// largest non-zero values. Zero values (weeks without samples) render as a
+// space so gaps stay visible instead of reading as "fast".
+func Sparkline(values []time.Duration) string {
+	var lo, hi time.Duration
+	for _, v := range values {
+		if v <= 0 {
+			continue
+		}
+		if lo == 0 || v < lo {
+			lo = v
+		}
+		hi = max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v <= 0:
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[len(sparkBlocks)/2])
+		default:
+			level := int(float64(v-lo) / float64(hi-lo) * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[level])
+		}
+	}
+	return b.String()
+}
+
+// formatTrend renders a Series.Trend as "+40%" / "-12%", or "-" when there
+// is not enough data.
+func formatTrend(s Series) string {
+	trend, ok := s.Trend()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", trend*100)
+}
+
+// formatStat renders a duration cell, or "-" for no samples.
+func formatStat(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return timing.FormatDuration(d)
+}
+
+// WriteTable renders the human-readable report: a header line, then one row
+// per series with overall percentiles, failure rate, streaks, the p50 trend
+// between the first and last weeks with data, and weekly sparklines for
+// duration and queue p50.
+func WriteTable(w io.Writer, r Report) error {
+	scope := "all workflows"
+	if r.Workflow != "" {
+		scope = r.Workflow
+	}
+	fmt.Fprintf(w, "%s/%s  •  %s  •  %s → %s  •  %d run%s\n\n", r.Owner, r.Repo, scope,
+		r.Since.UTC().Format(time.DateOnly), r.Until.UTC().Format(time.DateOnly),
+		r.RunCount, pluralS(r.RunCount))
+
+	if len(r.Series) == 0 {
+		fmt.Fprintln(w, "No completed runs in this window")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Workflow/Job\tRuns\tFail%\tp50\tp90\tQueue p50\tQueue p90\tStreak\tTrend\tWeekly p50\tWeekly queue")
+	for _, s := range r.Series {
+		name := s.Name
+		if s.Kind == KindJob {
+			name = "  " + name
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.0f%%\t%s\t%s\t%s\t%s\t%d (best %d)\t%s\t%s\t%s\n",
+			name, s.Runs, s.FailureRate()*100,
+			formatStat(s.DurationP50), formatStat(s.DurationP90),
+			formatStat(s.QueueP50), formatStat(s.QueueP90),
+			s.CurrentStreak, s.LongestStreak, formatTrend(s),
+			Sparkline(weekly(s, func(w WeekStats) time.Duration { return w.DurationP50 })),
+			Sparkline(weekly(s, func(w WeekStats) time.Duration { return w.QueueP50 })))
+	}
+	return tw.Flush()
+}
+
+// weekly extracts one duration per week from a series.
+func weekly(s Series, field func(WeekStats) time.Duration) []time.Duration {
+	out := make([]time.Duration, len(s.Weeks))
+	for i, w := range s.Weeks {
+		out[i] = field(w)
+	}
+	return out
+}
+
+// seconds renders a duration as whole seconds for machine-readable output.
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
+
+// WriteCSV writes one row per series per week, which is the shape
+// spreadsheets chart most easily. Durations are whole seconds.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	header := []string{"kind", "name", "week_start", "runs", "failures", "failure_rate",
+		"duration_p50_s", "duration_p90_s", "queue_p50_s", "queue_p90_s"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, s := range r.Series {
+		for _, wk := range s.Weeks {
+			row := []string{
+				s.Kind, s.Name, wk.Start.Format(time.DateOnly),
+				strconv.Itoa(wk.Runs), strconv.Itoa(wk.Failures),
+				strconv.FormatFloat(wk.FailureRate(), 'f', 4, 64),
+				strconv.FormatInt(seconds(wk.DurationP50), 10),
+				strconv.FormatInt(seconds(wk.DurationP90), 10),
+				strconv.FormatInt(seconds(wk.QueueP50), 10),
+				strconv.FormatInt(seconds(wk.QueueP90), 10),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonWeek and jsonSeries are the wire shapes for --format json: durations
+// as whole seconds and derived values (failure rate, trend) precomputed so
+// consumers don't have to re-derive them.
+type jsonWeek struct {
+	WeekStart    string  `json:"week_start"`
+	Runs         int     `json:"runs"`
+	Failures     int     `json:"failures"`
+	FailureRate  float64 `json:"failure_rate"`
+	DurationP50S int64   `json:"duration_p50_s"`
+	DurationP90S int64   `json:"duration_p90_s"`
+	QueueP50S    int64   `json:"queue_p50_s"`
+	QueueP90S    int64   `json:"queue_p90_s"`
+}
+
+type jsonSeries struct {
+	Kind          string     `json:"kind"`
+	Name          string     `json:"name"`
+	Runs          int        `json:"runs"`
+	Failures      int        `json:"failures"`
+	FailureRate   float64    `json:"failure_rate"`
+	DurationP50S  int64      `json:"duration_p50_s"`
+	DurationP90S  int64      `json:"duration_p90_s"`
+	QueueP50S     int64      `json:"queue_p50_s"`
+	QueueP90S     int64      `json:"queue_p90_s"`
+	CurrentStreak int        `json:"current_success_streak"`
+	LongestStreak int        `json:"longest_success_streak"`
+	Trend         *float64   `json:"p50_trend,omitempty"`
+	Weeks         []jsonWeek `json:"weeks"`
+}
+
+type jsonReport struct {
+	Repo     string       `json:"repo"`
+	Workflow string       `json:"workflow,omitempty"`
+	Since    time.Time    `json:"since"`
+	Until    time.Time    `json:"until"`
+	Runs     int          `json:"runs"`
+	Series   []jsonSeries `json:"series"`
+}
+
+// WriteJSON writes the report as a single indented JSON document.
+func WriteJSON(w io.Writer, r Report) error {
+	out := jsonReport{
+		Repo:     r.Owner + "/" + r.Repo,
+		Workflow: r.Workflow,
+		Since:    r.Since.UTC(),
+		Until:    r.Until.UTC(),
+		Runs:     r.RunCount,
+		Series:   make([]jsonSeries, 0, len(r.Series)),
+	}
+	for _, s := range r.Series {
+		js := jsonSeries{
+			Kind:          s.Kind,
+			Name:          s.Name,
+			Runs:          s.Runs,
+			Failures:      s.Failures,
+			FailureRate:   s.FailureRate(),
+			DurationP50S:  seconds(s.DurationP50),
+			DurationP90S:  seconds(s.DurationP90),
+			QueueP50S:     seconds(s.QueueP50),
+			QueueP90S:     seconds(s.QueueP90),
+			CurrentStreak: s.CurrentStreak,
+			LongestStreak: s.LongestStreak,
+			Weeks:         make([]jsonWeek, 0, len(s.Weeks)),
+		}
+		if trend, ok := s.Trend(); ok {
+			js.Trend = &trend
+		}
+		for _, wk := range s.Weeks {
+			js.Weeks = append(js.Weeks, jsonWeek{
+				WeekStart:    wk.Start.Format(time.DateOnly),
+				Runs:         wk.Runs,
+				Failures:     wk.Failures,
+				FailureRate:  wk.FailureRate(),
+				DurationP50S: seconds(wk.DurationP50),
+				DurationP90S: seconds(wk.DurationP90),
+				QueueP50S:    seconds(wk.QueueP50),
+				QueueP90S:    seconds(wk.QueueP90),
+			})
+		}
+		out.Series = append(out.Series, js)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// pluralS returns "s" when n != 1, else "".
+func pluralS(n int) string {
+	if n != 1 {
+		return "s"
+	}
+	return ""
+}
@@ -0,0 +1,268 @@
+// Package stats aggregates completed workflow runs into the weekly duration,
+// queue-latency, and reliability trends printed by `gh observer stats`.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+)
+
+// Series kinds. A workflow series measures whole runs (first job queued to
+// last job completed); a job series measures one job's runtime.
+const (
+	KindWorkflow = "workflow"
+	KindJob      = "job"
+)
+
+// WeekStats summarizes one calendar week (Monday 00:00 UTC) of a series.
+// Percentiles are zero when the week has no samples.
+type WeekStats struct {
+	Start       time.Time
+	Runs        int
+	Failures    int
+	DurationP50 time.Duration
+	DurationP90 time.Duration
+	QueueP50    time.Duration
+	QueueP90    time.Duration
+}
+
+// FailureRate returns Failures/Runs, or 0 for an empty week.
+func (w WeekStats) FailureRate() float64 {
+	if w.Runs == 0 {
+		return 0
+	}
+	return float64(w.Failures) / float64(w.Runs)
+}
+
+// Series is the trend for one workflow or one job across the whole window.
+// Runs counts decided outcomes only: cancelled and skipped runs say nothing
+// about reliability and are excluded from Runs, Failures, and streaks.
+type Series struct {
+	Kind          string
+	Name          string
+	Runs          int
+	Failures      int
+	DurationP50   time.Duration
+	DurationP90   time.Duration
+	QueueP50      time.Duration
+	QueueP90      time.Duration
+	CurrentStreak int
+	LongestStreak int
+	Weeks         []WeekStats
+}
+
+// FailureRate returns Failures/Runs, or 0 for a series with no decided runs.
+func (s Series) FailureRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Runs)
+}
+
+// Trend returns the relative change in weekly p50 duration between the
+// first and last weeks that have samples (0.4 means 40% slower). The
+// boolean is false when fewer than two weeks have samples.
+func (s Series) Trend() (float64, bool) {
+	first, last := -1, -1
+	for i, w := range s.Weeks {
+		if w.DurationP50 <= 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	if first < 0 || first == last {
+		return 0, false
+	}
+	return float64(s.Weeks[last].DurationP50)/float64(s.Weeks[first].DurationP50) - 1, true
+}
+
+// Report is the full stats output for one repo (optionally one workflow).
+type Report struct {
+	Owner    string
+	Repo     string
+	Workflow string
+	Since    time.Time
+	Until    time.Time
+	RunCount int
+	Series   []Series
+}
+
+// sample is one observation feeding a series: an outcome plus optional
+// duration and queue-wait measurements.
+type sample struct {
+	at         time.Time
+	conclusion string
+	duration   time.Duration
+	queue      []time.Duration
+}
+
+// Build aggregates runs into a Report covering [since, until]. Series are
+// ordered workflows first, then jobs, each alphabetically. Job series are
+// keyed "Workflow / Job" so same-named jobs in different workflows stay
+// separate.
+func Build(runs []ghclient.StatsRun, since, until time.Time) Report {
+	byKey := map[string][]sample{}
+	kinds := map[string]string{}
+
+	for _, run := range runs {
+		var runSample sample
+		runSample.at = run.CreatedAt
+		runSample.conclusion = run.Conclusion
+
+		var lastCompleted time.Time
+		for _, job := range run.Jobs {
+			js := sample{at: run.CreatedAt, conclusion: job.Conclusion}
+			if !job.StartedAt.IsZero() && job.CompletedAt.After(job.StartedAt) {
+				js.duration = job.CompletedAt.Sub(job.StartedAt)
+			}
+			if !job.QueuedAt.IsZero() && !job.StartedAt.IsZero() && !job.StartedAt.Before(job.QueuedAt) {
+				wait := job.StartedAt.Sub(job.QueuedAt)
+				js.queue = []time.Duration{wait}
+				runSample.queue = append(runSample.queue, wait)
+			}
+			if job.CompletedAt.After(lastCompleted) {
+				lastCompleted = job.CompletedAt
+			}
+			key := run.WorkflowName + " / " + job.Name
+			kinds[key] = KindJob
+			byKey[key] = append(byKey[key], js)
+		}
+		if !run.CreatedAt.IsZero() && lastCompleted.After(run.CreatedAt) {
+			runSample.duration = lastCompleted.Sub(run.CreatedAt)
+		}
+		kinds[run.WorkflowName] = KindWorkflow
+		byKey[run.WorkflowName] = append(byKey[run.WorkflowName], runSample)
+	}
+
+	report := Report{Since: since, Until: until, RunCount: len(runs)}
+	for key, samples := range byKey {
+		report.Series = append(report.Series, buildSeries(kinds[key], key, samples, since, until))
+	}
+	sort.Slice(report.Series, func(i, j int) bool {
+		a, b := report.Series[i], report.Series[j]
+		if a.Kind != b.Kind {
+			return a.Kind == KindWorkflow
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+// buildSeries computes the overall and weekly stats for one key.
+func buildSeries(kind, name string, samples []sample, since, until time.Time) Series {
+	// Oldest first so streaks read in the order things happened.
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].at.Before(samples[j].at) })
+
+	s := Series{Kind: kind, Name: name}
+	weeks := weekStarts(since, until)
+	weekIndex := make(map[time.Time]int, len(weeks))
+	for i, start := range weeks {
+		weekIndex[start] = i
+		s.Weeks = append(s.Weeks, WeekStats{Start: start})
+	}
+
+	var durations, queues []time.Duration
+	weekDurations := make([][]time.Duration, len(weeks))
+	weekQueues := make([][]time.Duration, len(weeks))
+	streak := 0
+
+	for _, smp := range samples {
+		wi, inWindow := weekIndex[weekStart(smp.at)]
+		if smp.duration > 0 {
+			durations = append(durations, smp.duration)
+			if inWindow {
+				weekDurations[wi] = append(weekDurations[wi], smp.duration)
+			}
+		}
+		queues = append(queues, smp.queue...)
+		if inWindow {
+			weekQueues[wi] = append(weekQueues[wi], smp.queue...)
+		}
+
+		switch {
+		case smp.conclusion == "success":
+			s.Runs++
+			streak++
+			s.LongestStreak = max(s.LongestStreak, streak)
+		case ghclient.FailureConclusion(smp.conclusion):
+			s.Runs++
+			s.Failures++
+			streak = 0
+		default:
+			// cancelled, skipped, neutral, stale: not a decided outcome.
+			continue
+		}
+		if inWindow {
+			s.Weeks[wi].Runs++
+			if ghclient.FailureConclusion(smp.conclusion) {
+				s.Weeks[wi].Failures++
+			}
+		}
+	}
+	s.CurrentStreak = streak
+
+	s.DurationP50 = timing.Percentile(durations, 50)
+	s.DurationP90 = timing.Percentile(durations, 90)
+	s.QueueP50 = timing.Percentile(queues, 50)
+	s.QueueP90 = timing.Percentile(queues, 90)
+	for i := range s.Weeks {
+		s.Weeks[i].DurationP50 = timing.Percentile(weekDurations[i], 50)
+		s.Weeks[i].DurationP90 = timing.Percentile(weekDurations[i], 90)
+		s.Weeks[i].QueueP50 = timing.Percentile(weekQueues[i], 50)
+		s.Weeks[i].QueueP90 = timing.Percentile(weekQueues[i], 90)
+	}
+	return s
+}
+
+// weekStart returns Monday 00:00 UTC of t's week.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// weekStarts lists every week start from since's week through until's week,
+// so weeks with no runs still appear (as gaps in the sparkline).
+func weekStarts(since, until time.Time) []time.Time {
+	var weeks []time.Time
+	for w := weekStart(since); !w.After(until); w = w.AddDate(0, 0, 7) {
+		weeks = append(weeks, w)
+	}
+	return weeks
+}
+
+// ParseSince converts a --since value into an absolute start time. It
+// accepts day and week shorthands ("30d", "12w"), Go durations ("72h"), and
+// calendar dates ("2026-07-01", interpreted as UTC midnight).
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty --since value")
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if unit := s[len(s)-1]; unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err == nil && n > 0 {
+			days := n
+			if unit == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q (use e.g. 30d, 12w, 72h, or 2026-07-01)", s)
+}
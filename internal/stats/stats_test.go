@@ -0,0 +1,224 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// statsRun builds a run created at created whose single "build" job waited
+// queue for a runner and then ran for dur.
+func statsRun(created time.Time, conclusion string, queue, dur time.Duration) ghclient.StatsRun {
+	started := created.Add(queue)
+	return ghclient.StatsRun{
+		WorkflowName: "CI",
+		Conclusion:   conclusion,
+		CreatedAt:    created,
+		Jobs: []ghclient.StatsJob{{
+			Name:        "build",
+			Conclusion:  conclusion,
+			QueuedAt:    created,
+			StartedAt:   started,
+			CompletedAt: started.Add(dur),
+		}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	// Monday 2026-06-01 00:00 UTC, so week boundaries are easy to reason about.
+	week1 := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	week3 := week1.AddDate(0, 0, 14)
+	since := week1.Add(-12 * time.Hour)
+	until := week3.Add(24 * time.Hour)
+
+	runs := []ghclient.StatsRun{
+		statsRun(week1, "success", 10*time.Second, 5*time.Minute),
+		statsRun(week1.Add(time.Hour), "failure", 20*time.Second, 5*time.Minute),
+		statsRun(week2, "cancelled", 0, time.Minute),
+		statsRun(week3, "success", 30*time.Second, 7*time.Minute),
+		statsRun(week3.Add(time.Hour), "success", 10*time.Second, 7*time.Minute),
+	}
+
+	report := Build(runs, since, until)
+	if report.RunCount != 5 {
+		t.Errorf("RunCount = %d, want 5", report.RunCount)
+	}
+	if len(report.Series) != 2 {
+		t.Fatalf("expected workflow + job series, got %d", len(report.Series))
+	}
+	if report.Series[0].Kind != KindWorkflow || report.Series[0].Name != "CI" {
+		t.Errorf("first series = %s %q, want workflow CI", report.Series[0].Kind, report.Series[0].Name)
+	}
+
+	job := report.Series[1]
+	if job.Name != "CI / build" {
+		t.Fatalf("job series name = %q, want %q", job.Name, "CI / build")
+	}
+	if job.Runs != 4 || job.Failures != 1 {
+		t.Errorf("runs/failures = %d/%d, want 4/1 (cancelled excluded)", job.Runs, job.Failures)
+	}
+	if job.CurrentStreak != 2 || job.LongestStreak != 2 {
+		t.Errorf("streak current/longest = %d/%d, want 2/2", job.CurrentStreak, job.LongestStreak)
+	}
+	if len(job.Weeks) != 3 {
+		t.Fatalf("weeks = %d, want 3", len(job.Weeks))
+	}
+	if job.Weeks[1].Runs != 0 {
+		t.Errorf("week 2 runs = %d, want 0 (only a cancelled run)", job.Weeks[1].Runs)
+	}
+	if job.Weeks[0].DurationP50 != 5*time.Minute || job.Weeks[2].DurationP50 != 7*time.Minute {
+		t.Errorf("weekly p50 = %v / %v, want 5m / 7m", job.Weeks[0].DurationP50, job.Weeks[2].DurationP50)
+	}
+	trend, ok := job.Trend()
+	if !ok || trend < 0.39 || trend > 0.41 {
+		t.Errorf("trend = %v (ok=%v), want +40%%", trend, ok)
+	}
+	if job.QueueP90 != 30*time.Second {
+		t.Errorf("queue p90 = %v, want 30s", job.QueueP90)
+	}
+}
+
+func TestTrendNeedsTwoWeeks(t *testing.T) {
+	s := Series{Weeks: []WeekStats{{DurationP50: time.Minute}, {}}}
+	if _, ok := s.Trend(); ok {
+		t.Error("expected no trend with a single populated week")
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		in   time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 6, 7, 23, 59, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := weekStart(tt.in); !got.Equal(tt.want) {
+			t.Errorf("weekStart(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 7, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "30d", want: now.AddDate(0, 0, -30)},
+		{in: "2w", want: now.AddDate(0, 0, -14)},
+		{in: "72h", want: now.Add(-72 * time.Hour)},
+		{in: "2026-07-01", want: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "", wantErr: true},
+		{in: "0d", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSince(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []time.Duration
+		want   string
+	}{
+		{name: "empty", values: nil, want: ""},
+		{name: "gaps render as spaces", values: []time.Duration{time.Minute, 0, 2 * time.Minute}, want: "▁ █"},
+		{name: "flat series", values: []time.Duration{time.Minute, time.Minute}, want: "▅▅"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	week1 := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := Build([]ghclient.StatsRun{
+		statsRun(week1, "success", 10*time.Second, 5*time.Minute),
+		statsRun(week1.AddDate(0, 0, 7), "success", 10*time.Second, 7*time.Minute),
+	}, week1, week1.AddDate(0, 0, 8))
+	report.Owner, report.Repo = "owner", "repo"
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, report, FormatTable); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		for _, want := range []string{"owner/repo", "all workflows", "CI / build", "+40%", "2 (best 2)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("table missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, report, FormatCSV); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// header + 2 series x 2 weeks
+		if len(rows) != 5 {
+			t.Fatalf("rows = %d, want 5", len(rows))
+		}
+		if rows[3][1] != "CI / build" || rows[3][6] != "300" {
+			t.Errorf("unexpected job row: %v", rows[3])
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, report, FormatJSON); err != nil {
+			t.Fatal(err)
+		}
+		var decoded jsonReport
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if decoded.Repo != "owner/repo" || len(decoded.Series) != 2 {
+			t.Errorf("decoded = %+v", decoded)
+		}
+		if decoded.Series[1].Trend == nil {
+			t.Error("expected p50_trend for job series")
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if err := Write(&bytes.Buffer{}, report, "xml"); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
@@ -2,6 +2,8 @@ package timing
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"time"
 
 	ghclient "github.com/fini-net/gh-observer/internal/github"
@@ -72,3 +74,16 @@ func FormatDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%ds", seconds)
 }
+
+// Percentile returns the nearest-rank p-th percentile (0 < p <= 100) of
+// durations, or 0 for none; durations isn't reordered.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
@@ -368,3 +368,44 @@ func TestRunJobDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{
+		50 * time.Second,
+		10 * time.Second,
+		30 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+	}
+
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		p       float64
+		want    time.Duration
+	}{
+		{name: "empty returns zero", samples: nil, p: 50, want: 0},
+		{name: "single sample", samples: []time.Duration{7 * time.Second}, p: 90, want: 7 * time.Second},
+		{name: "p50 of five", samples: samples, p: 50, want: 30 * time.Second},
+		{name: "p90 of five", samples: samples, p: 90, want: 50 * time.Second},
+		{name: "p100 is max", samples: samples, p: 100, want: 50 * time.Second},
+		{name: "tiny p clamps to min", samples: samples, p: 1, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Percentile(tt.samples, tt.p)
+			if got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.samples, tt.p, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("does not reorder input", func(t *testing.T) {
+		in := []time.Duration{3, 1, 2}
+		Percentile(in, 50)
+		if in[0] != 3 || in[1] != 1 || in[2] != 2 {
+			t.Errorf("Percentile mutated its input: %v", in)
+		}
+	})
+}
@@ -1,14 +1,24 @@
 package tui
 
-import "time"
+import (
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/watch"
+)
 
 const (
 	slowJobThreshold     = 2 * time.Minute
 	verySlowJobThreshold = 3 * time.Minute
 
-	rateBackoffThreshold = 10
+	// A check is marked slower than usual once its runtime passes
+	// slowerThanUsualFactor times its historical average, and by at least
+	// minSlowOverrun so short jobs' jitter doesn't trip it.
+	slowerThanUsualFactor = 1.5
+	minSlowOverrun        = time.Minute
+
+	rateBackoffThreshold = watch.RateBackoffThreshold
 	rateWarningThreshold = 500
-	minRateLimitForFetch = 100
+	minRateLimitForFetch = watch.MinRateLimitForFetch
 
 	historyFetchDelay = 10 * time.Second
 
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+	"github.com/mattn/go-runewidth"
+)
+
+// criticalPathMarker is appended to job rows on the critical path.
+const criticalPathMarker = "  ◀ critical path"
+
+// renderCriticalPathSection renders the critical path with each step's
+// share and wait, or why it's empty (graphPending, graphErr).
+func renderCriticalPathSection(b *strings.Builder, styles Styles, path *ghclient.CriticalPath, graphPending bool, graphErr error) {
+	if path == nil || len(path.Steps) == 0 {
+		b.WriteString(styles.Header.Render("Jobs blocking completion"))
+		b.WriteString("\n")
+		switch {
+		case graphPending:
+			b.WriteString(styles.Queued.Render("  Fetching workflow file..."))
+		case graphErr != nil:
+			b.WriteString(styles.Queued.Render(fmt.Sprintf("  Critical path unavailable: %v", graphErr)))
+		default:
+			b.WriteString(styles.Queued.Render("  No jobs with timing yet"))
+		}
+		b.WriteString("\n\n")
+		return
+	}
+
+	total := path.Total()
+	b.WriteString(styles.Header.Render("Jobs blocking completion"))
+	fmt.Fprintf(b, " (critical path %s)\n", timing.FormatDuration(total))
+
+	const minJobWidth = 12
+	jobWidth := minJobWidth
+	for _, s := range path.Steps {
+		jobWidth = max(jobWidth, runewidth.StringWidth(s.Instance))
+	}
+	jobWidth = min(jobWidth, maxCheckNameWidth)
+
+	bottleneck := path.Bottleneck()
+	for i, s := range path.Steps {
+		name := runewidth.Truncate(s.Instance, jobWidth, "…")
+		name += strings.Repeat(" ", max(jobWidth-runewidth.StringWidth(name), 0))
+
+		share := 0
+		if total > 0 {
+			share = int(100 * s.Duration() / total)
+		}
+		line := fmt.Sprintf("  %d. %s  %8s  %3d%%", i+1, name, timing.FormatDuration(s.Duration()), share)
+		if s.Wait > 0 {
+			line += fmt.Sprintf("  (waited %s)", timing.FormatDuration(s.Wait))
+		}
+		if s.Instances > 1 {
+			line += fmt.Sprintf("  slowest of %d", s.Instances)
+		}
+		if s.Estimated {
+			line += "  est"
+		}
+		b.WriteString(line)
+		if i == bottleneck && len(path.Steps) > 1 {
+			b.WriteString(styles.Running.Render("  ← optimize first"))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
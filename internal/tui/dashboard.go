@@ -0,0 +1,660 @@
+package tui
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/fini-net/gh-observer/internal/eventlog"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/notify"
+	"github.com/fini-net/gh-observer/internal/watch"
+	"github.com/fini-net/gh-observer/internal/webhook"
+)
+
+// DashboardChecksMsg carries the PR checks of every dashboard repo from one
+// batched GraphQL fetch.
+type DashboardChecksMsg struct {
+	Repos              map[ghclient.RepoRef]ghclient.RepoCheckRuns
+	RateLimitRemaining int
+	Err                error
+	// FilterGen is the DashboardModel.filterGen the fetch was issued
+	// under; results for an older filter are dropped.
+	FilterGen int
+}
+
+// dashSectionMsg carries a message for the dashboard section watching repo:
+// its standalone-runs fetches and queue history. Sections are addressed by
+// repo rather than position because --mine adds and drops them as PRs open
+// and close.
+type dashSectionMsg struct {
+	repo ghclient.RepoRef
+	msg  tea.Msg
+}
+
+// DashboardModel watches several repos (or, with --mine, the viewer's PRs)
+// in one collapsible RepoModel section each, fed by batched fetches.
+type DashboardModel struct {
+	ctx context.Context
+	api ghclient.API
+
+	sections  []RepoModel
+	collapsed []bool
+	// newSection builds the section for a repo, with the dashboard's
+	// display options.
+	newSection func(ghclient.RepoRef) RepoModel
+
+	// mine is set for --mine, and reviewRequested adds the PRs awaiting
+	// the viewer's review. checksReceived is whether any search has
+	// landed, since until then no sections doesn't mean no PRs.
+	mine            bool
+	reviewRequested bool
+	checksReceived  bool
+
+	// cursor is the selected section. Within it, the section's own
+	// selected field is the selected row, zero for the section header.
+	cursor int
+
+	// Filter shared by every section; see RepoModel.filter.
+	filter    ghclient.RepoFilter
+	filterGen int
+	prompt    filterPrompt
+
+	// Webhook deliveries, and the repos they concerned since the last
+	// webhook refresh (only those re-list their runs).
+	webhooks     webhookState
+	webhookDirty map[ghclient.RepoRef]bool
+
+	showQueueStats bool
+	// The runner pane, listing every section's repo and organization
+	// runners once per poll; the sections' own pools stay hidden.
+	runners runnerPool
+	// noAvg (--quick) skips every section's historical averages.
+	noAvg bool
+
+	// The event log every section records into, and the recent-events
+	// pane showing the sections' events together.
+	events       *eventlog.Log
+	showEvents   bool
+	eventsOffset int
+
+	// notifier is handed to every section, which notifies on its own.
+	notifier *notify.Notifier
+
+	// The batched checks fetch's error and rate-limit state. Runs errors
+	// stay with their section.
+	fetchErr         error
+	fetchErrAt       time.Time
+	rateLimitedUntil time.Time
+
+	spinner         spinner.Model
+	lastUpdate      time.Time
+	refreshInterval time.Duration
+	styles          Styles
+
+	exitCode int
+	quitting bool
+}
+
+// NewDashboardModel creates a dashboard over repos, in the order given.
+func NewDashboardModel(
+	ctx context.Context,
+	api ghclient.API,
+	repos []ghclient.RepoRef,
+	refreshInterval time.Duration,
+	styles Styles,
+	enableLinks bool,
+	fadeSuccess, fadeFailure time.Duration,
+) DashboardModel {
+	newSection := func(ref ghclient.RepoRef) RepoModel {
+		return NewRepoModel(ctx, api, ref.Owner, ref.Name, refreshInterval, styles, enableLinks, fadeSuccess, fadeFailure)
+	}
+	sections := make([]RepoModel, len(repos))
+	for i, ref := range repos {
+		sections[i] = newSection(ref)
+	}
+	return DashboardModel{
+		ctx:             ctx,
+		api:             api,
+		sections:        sections,
+		collapsed:       make([]bool, len(repos)),
+		newSection:      newSection,
+		webhookDirty:    make(map[ghclient.RepoRef]bool),
+		spinner:         spinner.New(spinner.WithSpinner(spinner.Dot)),
+		lastUpdate:      time.Now(),
+		refreshInterval: refreshInterval,
+		styles:          styles,
+	}
+}
+
+// NewMineDashboardModel creates a dashboard of the viewer's open PRs across
+// every repo (--mine), and of those awaiting their review with
+// reviewRequested. It starts with no sections; the first search adds them.
+func NewMineDashboardModel(
+	ctx context.Context,
+	api ghclient.API,
+	reviewRequested bool,
+	refreshInterval time.Duration,
+	styles Styles,
+	enableLinks bool,
+	fadeSuccess, fadeFailure time.Duration,
+) DashboardModel {
+	m := NewDashboardModel(ctx, api, nil, refreshInterval, styles, enableLinks, fadeSuccess, fadeFailure)
+	m.mine = true
+	m.reviewRequested = reviewRequested
+	m.prompt.authorFixed = true
+	return m
+}
+
+// WithFilter starts every section narrowed to filter.
+func (m DashboardModel) WithFilter(filter ghclient.RepoFilter) DashboardModel {
+	m.filter = filter
+	for i := range m.sections {
+		m.sections[i].feed.Filter = filter
+	}
+	return m
+}
+
+// WithoutAverages skips historical averages in every section; see
+// RepoModel.WithoutAverages.
+func (m DashboardModel) WithoutAverages() DashboardModel {
+	m.noAvg = true
+	for i := range m.sections {
+		m.sections[i].noAvg = true
+	}
+	return m
+}
+
+// WithEventLog makes every section record its completions in log; see
+// RepoModel.WithEventLog.
+func (m DashboardModel) WithEventLog(log *eventlog.Log) DashboardModel {
+	m.events = log
+	for i := range m.sections {
+		m.sections[i].events = log
+	}
+	return m
+}
+
+// WithWebhooks makes the dashboard refresh a repo when a delivery for it
+// arrives on events, polling only every reconcile.
+func (m DashboardModel) WithWebhooks(events <-chan webhook.Event, reconcile time.Duration) DashboardModel {
+	m.webhooks = webhookState{events: events, reconcile: reconcile}
+	return m
+}
+
+// ExitCode is always 0; see RepoModel.ExitCode.
+func (m DashboardModel) ExitCode() int {
+	return m.exitCode
+}
+
+// refs lists the sections' repos.
+func (m DashboardModel) refs() []ghclient.RepoRef {
+	refs := make([]ghclient.RepoRef, len(m.sections))
+	for i, s := range m.sections {
+		refs[i] = s.ref()
+	}
+	return refs
+}
+
+// sectionIndex is the position of repo's section, or -1 if it has none.
+func (m DashboardModel) sectionIndex(repo ghclient.RepoRef) int {
+	return slices.IndexFunc(m.sections, func(s RepoModel) bool { return s.ref() == repo })
+}
+
+// Init kicks off the spinner, the first checks and runs fetches, and the
+// poll tick.
+func (m DashboardModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		m.fetchChecksCmd(),
+		m.fetchRunsCmds(nil),
+		repoTick(m.refreshInterval),
+		waitForWebhook(m.webhooks.events),
+	)
+}
+
+// Update dispatches messages to the appropriate handlers.
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.reviewing() {
+			if msg.String() == "ctrl+c" {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m.updateCursorSection(msg)
+		}
+		if m.prompt.open {
+			if msg.String() == "ctrl+c" {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			filter, apply, cmd := m.prompt.handleKey(msg)
+			if apply {
+				return m, m.applyFilter(filter)
+			}
+			return m, cmd
+		}
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "f":
+			return m, m.prompt.show(m.filter)
+		case "up", "k":
+			m.moveCursor(-1)
+			return m, nil
+		case "down", "j":
+			m.moveCursor(1)
+			return m, nil
+		case "enter", "space":
+			if len(m.sections) == 0 {
+				return m, nil
+			}
+			if _, ok := m.sections[m.cursor].selectedItem(); !ok {
+				m.collapsed[m.cursor] = !m.collapsed[m.cursor]
+				m.sections[m.cursor].selected = repoItem{}
+			}
+			return m, nil
+		case "l":
+			m.showQueueStats = !m.showQueueStats
+			var cmds []tea.Cmd
+			for i := range m.sections {
+				m.sections[i].showQueueStats = m.showQueueStats
+				cmds = append(cmds, wrapSectionCmds(m.sections[i].ref(), m.sections[i].queueHistoryCmds())...)
+			}
+			return m, tea.Batch(cmds...)
+		case "r":
+			m.runners.show = !m.runners.show
+			return m, tea.Batch(m.runnersCmds()...)
+		case "e":
+			m.showEvents = !m.showEvents
+			m.eventsOffset = 0
+			return m, nil
+		case "pgup", "pgdown":
+			if m.showEvents {
+				m.eventsOffset = scrollEvents(m.eventsOffset, pageDelta(msg.String()), len(m.dashboardEvents()))
+			}
+			return m, nil
+		case "a", "x":
+			if len(m.sections) == 0 {
+				return m, nil
+			}
+			return m.updateCursorSection(msg)
+		}
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case RepoTickMsg:
+		if wait := m.rateLimitWait(time.Now()); wait > 0 {
+			debug.Log("rate limited, delaying poll (dashboard)", "wait", wait)
+			return m, repoTick(wait)
+		}
+		if q := m.quota(); q.Hold(m.api, m.refreshInterval, time.Now()) > 0 {
+			debug.Log("rate limit backoff (dashboard)", "remaining", q.Remaining, "threshold", rateBackoffThreshold)
+			return m, repoTick(m.refreshInterval * 3)
+		}
+		interval := m.webhooks.pollInterval(m.nextPollInterval(time.Now()))
+		if interval != m.refreshInterval {
+			debug.Log("adaptive poll interval (dashboard)", "interval", interval, "base", m.refreshInterval)
+		}
+		for i := range m.sections {
+			m.sections[i].feed.Quota.StartPoll()
+		}
+		cmds := []tea.Cmd{m.fetchChecksCmd(), m.fetchRunsCmds(nil), repoTick(interval)}
+		return m, tea.Batch(append(cmds, m.runnersCmds()...)...)
+
+	case WebhookMsg:
+		relevant := false
+		for _, s := range m.sections {
+			if sameRepo(msg.Event, s.owner, s.repo) {
+				m.webhookDirty[s.ref()] = true
+				relevant = true
+			}
+		}
+		return m, m.webhooks.receive(relevant)
+
+	case webhookRefreshMsg:
+		m.webhooks.refreshPending = false
+		dirty := m.webhookDirty
+		m.webhookDirty = make(map[ghclient.RepoRef]bool)
+		debug.Log("webhook refresh (dashboard)", "repos", len(dirty))
+		if m.rateLimitWait(time.Now()) > 0 {
+			return m, nil
+		}
+		return m, tea.Batch(m.fetchChecksCmd(), m.fetchRunsCmds(dirty))
+
+	case DashboardChecksMsg:
+		return m, m.handleChecks(msg)
+
+	case dashSectionMsg:
+		return m.updateSection(msg)
+
+	case RunnersMsg:
+		if until, ok := watch.ResumeAt(m.api, msg.Err); ok {
+			m.rateLimitedUntil = until
+		} else if msg.Err != nil {
+			debug.Log("runners fetch error (dashboard)", "owner", msg.Owner, "repo", msg.Repo, "err", msg.Err)
+		}
+		m.runners.handle(msg)
+		return m, nil
+	}
+
+	if m.prompt.open {
+		return m, m.prompt.update(msg)
+	}
+	return m, nil
+}
+
+// handleChecks fans a batched checks fetch out to the sections; a failure
+// keeps their last good state and is shown once.
+func (m *DashboardModel) handleChecks(msg DashboardChecksMsg) tea.Cmd {
+	if msg.FilterGen != m.filterGen {
+		return nil
+	}
+	if until, ok := watch.ResumeAt(m.api, msg.Err); ok {
+		m.rateLimitedUntil = until
+		return nil
+	}
+	if msg.Err != nil {
+		m.fetchErr = msg.Err
+		m.fetchErrAt = time.Now()
+		debug.Log("dashboard checks fetch error", "err", msg.Err)
+		return nil
+	}
+	m.fetchErr = nil
+	m.fetchErrAt = time.Time{}
+	m.lastUpdate = time.Now()
+	m.checksReceived = true
+	if m.mine {
+		m.syncSections(msg.Repos)
+	}
+	var cmds []tea.Cmd
+	for i := range m.sections {
+		s := &m.sections[i]
+		data := msg.Repos[s.ref()]
+		_, cmd := s.handleRepoChecksUpdate(RepoChecksUpdateMsg{
+			PRData:             data.PRs,
+			OmittedPRs:         data.Omitted,
+			RateLimitRemaining: msg.RateLimitRemaining,
+			FilterGen:          s.feed.FilterGen,
+		})
+		cmds = append(cmds, wrapSectionCmd(s.ref(), cmd))
+	}
+	return tea.Batch(cmds...)
+}
+
+// syncSections makes the sections follow the repos a --mine search found,
+// sorted by name: repos new to it get a section, and repos left without a
+// matching PR lose theirs. Surviving sections keep their state, fold and
+// selection, and the cursor stays on its repo if that is still listed.
+func (m *DashboardModel) syncSections(repos map[ghclient.RepoRef]ghclient.RepoCheckRuns) {
+	refs := slices.SortedFunc(maps.Keys(repos), func(a, b ghclient.RepoRef) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	var current ghclient.RepoRef
+	if len(m.sections) > 0 {
+		current = m.sections[m.cursor].ref()
+	}
+
+	sections := make([]RepoModel, len(refs))
+	collapsed := make([]bool, len(refs))
+	for i, ref := range refs {
+		if j := m.sectionIndex(ref); j >= 0 {
+			sections[i], collapsed[i] = m.sections[j], m.collapsed[j]
+			continue
+		}
+		debug.Log("dashboard repo added", "repo", ref.String())
+		s := m.newSection(ref)
+		s.feed.Filter = m.filter
+		s.showQueueStats = m.showQueueStats
+		s.noAvg = m.noAvg
+		s.events = m.events
+		s.notify = notifyState{notifier: m.notifier}
+		sections[i] = s
+	}
+	m.sections, m.collapsed = sections, collapsed
+
+	if i := m.sectionIndex(current); i >= 0 {
+		m.cursor = i
+	} else {
+		m.cursor = max(0, min(m.cursor, len(m.sections)-1))
+		if len(m.sections) > 0 {
+			m.sections[m.cursor].selected = repoItem{}
+		}
+	}
+}
+
+// updateSection delivers a wrapped message to its section, if the repo
+// still has one.
+func (m DashboardModel) updateSection(msg dashSectionMsg) (tea.Model, tea.Cmd) {
+	i := m.sectionIndex(msg.repo)
+	if i < 0 {
+		return m, nil
+	}
+	if _, ok := msg.msg.(RepoRunsUpdateMsg); ok {
+		m.lastUpdate = time.Now()
+	}
+	model, cmd := m.sections[i].Update(msg.msg)
+	m.sections[i] = repoModelOf(model)
+	return m, wrapSectionCmd(msg.repo, cmd)
+}
+
+// reviewing reports whether the selected section's review prompt is open.
+// It takes the keys until closed, so the cursor can't leave the section.
+func (m DashboardModel) reviewing() bool {
+	return len(m.sections) > 0 && m.sections[m.cursor].review.open
+}
+
+// updateCursorSection hands a key to the selected section: a/x to review
+// its selected run's deployments, and the keys typed into its prompt.
+func (m DashboardModel) updateCursorSection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.sections[m.cursor]
+	model, cmd := s.Update(msg)
+	m.sections[m.cursor] = repoModelOf(model)
+	return m, wrapSectionCmd(s.ref(), cmd)
+}
+
+// applyFilter switches every section to filter and refetches right away.
+func (m *DashboardModel) applyFilter(filter ghclient.RepoFilter) tea.Cmd {
+	if filter.Equal(m.filter) {
+		return nil
+	}
+	m.filter = filter
+	m.filterGen++
+	for i := range m.sections {
+		m.sections[i].setFilter(filter)
+	}
+	if m.rateLimitWait(time.Now()) > 0 {
+		return nil
+	}
+	return tea.Batch(m.fetchChecksCmd(), m.fetchRunsCmds(nil))
+}
+
+// fetchChecksCmd fetches every section's PR checks in one batch, or with
+// --mine searches for the PRs and their repos.
+func (m DashboardModel) fetchChecksCmd() tea.Cmd {
+	ctx, api, refs, filter, filterGen := m.ctx, m.api, m.refs(), m.filter, m.filterGen
+	mine, reviewRequested := m.mine, m.reviewRequested
+	return func() tea.Msg {
+		var repos map[ghclient.RepoRef]ghclient.RepoCheckRuns
+		var rateLimit int
+		var err error
+		if mine {
+			repos, rateLimit, err = api.FetchMyPRCheckRuns(ctx, reviewRequested, filter)
+		} else {
+			repos, rateLimit, err = api.FetchReposCheckRuns(ctx, refs, filter)
+		}
+		return DashboardChecksMsg{
+			Repos:              repos,
+			RateLimitRemaining: rateLimit,
+			Err:                err,
+			FilterGen:          filterGen,
+		}
+	}
+}
+
+// fetchRunsCmds fetches the standalone runs of the sections in only, or of
+// every section when only is nil. --mine watches PRs alone, so it lists
+// none.
+func (m DashboardModel) fetchRunsCmds(only map[ghclient.RepoRef]bool) tea.Cmd {
+	if m.mine {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, s := range m.sections {
+		if only == nil || only[s.ref()] {
+			cmds = append(cmds, wrapSectionCmd(s.ref(), s.fetchRunsCmd()))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// runnersCmds lists every section's runners while the runner pane is
+// shown, unless quota is below minRateLimitForFetch.
+func (m *DashboardModel) runnersCmds() []tea.Cmd {
+	if q := m.quota(); q.Received && q.Remaining < minRateLimitForFetch {
+		return nil
+	}
+	return m.runners.fetchCmds(m.ctx, m.api, m.refs())
+}
+
+// rateLimitWait is how long polling should stand down: the latest resume
+// time seen by the checks fetch, any section's runs fetch, or the session.
+func (m DashboardModel) rateLimitWait(now time.Time) time.Duration {
+	until := m.rateLimitedUntil
+	for _, s := range m.sections {
+		if s.feed.Quota.LimitedUntil.After(until) {
+			until = s.feed.Quota.LimitedUntil
+		}
+	}
+	return watch.Wait(m.api, until, now)
+}
+
+// quota returns the lowest remaining quota any section has seen; it isn't
+// Received until one has seen one at all.
+func (m DashboardModel) quota() watch.Quota {
+	var q watch.Quota
+	for _, s := range m.sections {
+		if s.feed.Quota.Received && (!q.Received || s.feed.Quota.Remaining < q.Remaining) {
+			q = s.feed.Quota
+		}
+	}
+	return q
+}
+
+// nextPollInterval is the adaptive interval for the dashboard, costing a
+// poll across every section.
+func (m DashboardModel) nextPollInterval(now time.Time) time.Duration {
+	active := false
+	nextFinish := time.Duration(-1)
+	fetched := 0
+	cost := (len(m.sections) + ghclient.ReposPerListQuery - 1) / ghclient.ReposPerListQuery
+	if m.mine {
+		cost = 1
+		if m.reviewRequested {
+			cost = 2
+		}
+	}
+	for _, s := range m.sections {
+		sectionActive, activeRuns := s.feed.Activity()
+		active = active || sectionActive
+		if left := s.feed.NextExpectedFinish(s.workflowAverages, now); left >= 0 && (nextFinish < 0 || left < nextFinish) {
+			nextFinish = left
+		}
+		fetched += s.feed.FetchedPRs
+		if !m.mine {
+			cost += 3 + activeRuns
+		}
+	}
+	cost += (fetched + ghclient.RepoPRsPerQuery - 1) / ghclient.RepoPRsPerQuery
+	cost += m.runners.costPerPoll(m.refs())
+	q := m.quota()
+	return watch.Interval(m.refreshInterval, watch.PollState{
+		Active:      active,
+		NextFinish:  nextFinish,
+		Remaining:   q.Remaining,
+		Received:    q.Received,
+		ResetAt:     q.ResetAt(m.api),
+		CostPerPoll: cost,
+	}, now)
+}
+
+// dashPos is a selectable dashboard row: a section header (zero item) or a
+// row within an expanded section.
+type dashPos struct {
+	section int
+	item    repoItem
+}
+
+// positions lists the selectable rows in render order.
+func (m DashboardModel) positions() []dashPos {
+	var positions []dashPos
+	for i, s := range m.sections {
+		positions = append(positions, dashPos{section: i})
+		if m.collapsed[i] {
+			continue
+		}
+		for _, item := range s.items() {
+			positions = append(positions, dashPos{section: i, item: item})
+		}
+	}
+	return positions
+}
+
+// moveCursor moves the selection delta rows, clamped to the list. A
+// selected row that has since faded out counts as its section's header.
+func (m *DashboardModel) moveCursor(delta int) {
+	if len(m.sections) == 0 {
+		return
+	}
+	positions := m.positions()
+	current, _ := m.sections[m.cursor].selectedItem()
+	i := slices.Index(positions, dashPos{section: m.cursor, item: current})
+	next := positions[max(0, min(i+delta, len(positions)-1))]
+	m.sections[m.cursor].selected = repoItem{}
+	m.cursor = next.section
+	m.sections[m.cursor].selected = next.item
+}
+
+// selectedTarget, capturingKeys, overviewName and overviewStyles make the
+// dashboard an Overview. A selected section header is not a target: enter
+// toggles it instead.
+func (m DashboardModel) selectedTarget() (navTarget, bool) {
+	if len(m.sections) == 0 {
+		return navTarget{}, false
+	}
+	return m.sections[m.cursor].selectedTarget()
+}
+
+func (m DashboardModel) capturingKeys() bool { return m.prompt.open || m.reviewing() }
+
+func (m DashboardModel) overviewName() string {
+	if m.mine {
+		return "my PRs"
+	}
+	return "dashboard"
+}
+
+func (m DashboardModel) overviewStyles() Styles { return m.styles }
+
+// wrapSectionCmd tags cmd's message for repo's section.
+func wrapSectionCmd(repo ghclient.RepoRef, cmd tea.Cmd) tea.Cmd {
+	return wrapCmd(cmd, func(msg tea.Msg) tea.Msg { return dashSectionMsg{repo: repo, msg: msg} })
+}
+
+func wrapSectionCmds(repo ghclient.RepoRef, cmds []tea.Cmd) []tea.Cmd {
+	wrapped := make([]tea.Cmd, 0, len(cmds))
+	for _, cmd := range cmds {
+		wrapped = append(wrapped, wrapSectionCmd(repo, cmd))
+	}
+	return wrapped
+}
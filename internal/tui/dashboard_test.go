@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+func newTestDashboard() DashboardModel {
+	return NewDashboardModel(
+		context.Background(), nil,
+		[]ghclient.RepoRef{{Owner: "o", Name: "api"}, {Owner: "o", Name: "web"}, {Owner: "o", Name: "docs"}},
+		30*time.Second, stylesForTest(), true,
+		15*time.Minute, 30*time.Minute,
+	)
+}
+
+func runningCheck(name string) ghclient.CheckRunInfo {
+	started := time.Now().Add(-time.Minute)
+	return ghclient.CheckRunInfo{Name: name, Status: "in_progress", StartedAt: &started}
+}
+
+func TestDashboardChecksFanOut(t *testing.T) {
+	m := newTestDashboard()
+	m.sections[1].setFilter(ghclient.RepoFilter{}) // sections keep their own filter generations
+
+	var model tea.Model = m
+	model, _ = model.Update(DashboardChecksMsg{
+		Repos: map[ghclient.RepoRef]ghclient.RepoCheckRuns{
+			{Owner: "o", Name: "api"}: {PRs: map[int]ghclient.PRCheckData{
+				4: {Number: 4, Title: "four", CheckRuns: []ghclient.CheckRunInfo{runningCheck("build")}},
+			}},
+			{Owner: "o", Name: "web"}: {PRs: map[int]ghclient.PRCheckData{
+				9: {Number: 9, Title: "nine", CheckRuns: []ghclient.CheckRunInfo{runningCheck("lint")}},
+			}, Omitted: 2},
+		},
+		RateLimitRemaining: 4000,
+	})
+	d := model.(DashboardModel)
+	if _, ok := d.sections[0].feed.PRs[4]; !ok || len(d.sections[0].feed.PRs) != 1 {
+		t.Errorf("api PRs = %v, want #4", d.sections[0].feed.PRNumbers())
+	}
+	if _, ok := d.sections[1].feed.PRs[9]; !ok || d.sections[1].feed.OmittedPRs != 2 {
+		t.Errorf("web PRs = %v (omitted %d), want #9 with 2 omitted", d.sections[1].feed.PRNumbers(), d.sections[1].feed.OmittedPRs)
+	}
+	if !d.sections[2].feed.Quota.Received || !d.sections[2].idle() {
+		t.Error("docs not marked fetched and idle")
+	}
+
+	view := d.View().Content
+	for _, want := range []string{"2 active PRs  •", "▸ [-]", "1 active PR  •  2 idle open PRs not fetched", "idle"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A failed fetch keeps every section's data and is shown once.
+	model, _ = model.Update(DashboardChecksMsg{Err: errors.New("boom")})
+	d = model.(DashboardModel)
+	if len(d.sections[0].feed.PRs) != 1 {
+		t.Error("failed fetch cleared a section")
+	}
+	if view := d.View().Content; strings.Count(view, "fetch error: boom") != 1 {
+		t.Errorf("view = %q, want the error once", view)
+	}
+
+	// A fetch issued under an older filter is dropped.
+	model, _ = pressKeys(t, model, "fauthor:x", tea.KeyEnter)
+	model, _ = model.Update(DashboardChecksMsg{Repos: map[ghclient.RepoRef]ghclient.RepoCheckRuns{
+		{Owner: "o", Name: "api"}: {PRs: map[int]ghclient.PRCheckData{
+			5: {Number: 5, Title: "five", CheckRuns: []ghclient.CheckRunInfo{runningCheck("build")}},
+		}},
+	}})
+	d = model.(DashboardModel)
+	if d.filterGen != 1 || len(d.sections[0].feed.PRs) != 0 {
+		t.Errorf("gen = %d, api PRs = %v; want the filter applied and the stale fetch dropped", d.filterGen, d.sections[0].feed.PRNumbers())
+	}
+	for i, s := range d.sections {
+		if s.feed.Filter.Author != "x" {
+			t.Errorf("section %d filter = %+v, want author:x", i, s.feed.Filter)
+		}
+	}
+}
+
+func TestDashboardCursorAndCollapse(t *testing.T) {
+	m := newTestDashboard()
+	m.sections[0].feed.PRs[4] = PRViewData{Title: "four"}
+	m.sections[0].feed.Runs = []ghclient.BranchRunData{{RunID: 40, HeadBranch: "main"}}
+	m.sections[1].feed.PRs[9] = PRViewData{Title: "nine"}
+
+	var model tea.Model = m
+	steps := []struct {
+		keys    string
+		section int
+		item    repoItem
+	}{
+		{"", 0, repoItem{}}, // starts on the first header
+		{"j", 0, repoItem{prNumber: 4}},
+		{"j", 0, repoItem{runID: 40}},
+		{"j", 1, repoItem{}},
+		{"j", 1, repoItem{prNumber: 9}},
+		{"jj", 2, repoItem{}}, // clamped at the last header
+		{"kk", 1, repoItem{}},
+	}
+	for i, step := range steps {
+		model, _ = pressKeys(t, model, step.keys)
+		d := model.(DashboardModel)
+		if d.cursor != step.section || d.sections[d.cursor].selected != step.item {
+			t.Fatalf("step %d: at section %d %+v, want %d %+v", i, d.cursor, d.sections[d.cursor].selected, step.section, step.item)
+		}
+		for j, s := range d.sections {
+			if j != d.cursor && s.selected != (repoItem{}) {
+				t.Fatalf("step %d: section %d also has %+v selected", i, j, s.selected)
+			}
+		}
+	}
+
+	// Enter on a header folds the section: its rows are skipped and it
+	// renders as one line.
+	model, _ = pressKeys(t, model, "kkk", tea.KeyEnter)
+	d := model.(DashboardModel)
+	if !d.collapsed[0] {
+		t.Fatal("enter on the header didn't collapse the section")
+	}
+	if view := d.View().Content; !strings.Contains(view, "▸ [+]") || strings.Contains(view, "Waiting for jobs") {
+		t.Errorf("view = %q, want o/api folded", view)
+	}
+	model, _ = pressKeys(t, model, "j")
+	if d := model.(DashboardModel); d.cursor != 1 || d.sections[1].selected != (repoItem{}) {
+		t.Errorf("after fold: at section %d %+v, want the o/web header", d.cursor, d.sections[1].selected)
+	}
+
+	// A selected row is a drill-down target in its own repo; a header is not.
+	model, _ = pressKeys(t, model, "j")
+	target, ok := model.(DashboardModel).selectedTarget()
+	if !ok || target != (navTarget{owner: "o", repo: "web", item: repoItem{prNumber: 9}}) {
+		t.Errorf("target = %+v (%v), want o/web PR #9", target, ok)
+	}
+	model, _ = pressKeys(t, model, "k")
+	if _, ok := model.(DashboardModel).selectedTarget(); ok {
+		t.Error("header selected as a drill-down target")
+	}
+}
+
+func TestDashboardMineSections(t *testing.T) {
+	m := NewMineDashboardModel(context.Background(), nil, false, 30*time.Second, stylesForTest(), true,
+		15*time.Minute, 30*time.Minute)
+	if view := m.View().Content; !strings.Contains(view, "Searching for your open PRs") {
+		t.Errorf("view before the first search = %q", view)
+	}
+
+	checks := func(names ...string) DashboardChecksMsg {
+		repos := make(map[ghclient.RepoRef]ghclient.RepoCheckRuns)
+		for _, name := range names {
+			repos[ghclient.RepoRef{Owner: "o", Name: name}] = ghclient.RepoCheckRuns{PRs: map[int]ghclient.PRCheckData{
+				1: {Number: 1, Title: name, CheckRuns: []ghclient.CheckRunInfo{runningCheck("build")}},
+			}}
+		}
+		return DashboardChecksMsg{Repos: repos, RateLimitRemaining: 4000}
+	}
+	sectionNames := func(d DashboardModel) []string {
+		var names []string
+		for _, s := range d.sections {
+			names = append(names, s.repo)
+		}
+		return names
+	}
+
+	var model tea.Model = m
+	model, _ = model.Update(checks("web", "api"))
+	model, _ = pressKeys(t, model, "jj") // o/web's header
+	d := model.(DashboardModel)
+	if got := sectionNames(d); !slices.Equal(got, []string{"api", "web"}) || d.cursor != 1 {
+		t.Fatalf("sections = %v, cursor %d; want [api web] on web", got, d.cursor)
+	}
+
+	// o/api's PR closed and one opened in o/cli: the cursor stays on o/web.
+	model, _ = model.Update(checks("web", "cli"))
+	d = model.(DashboardModel)
+	if got := sectionNames(d); !slices.Equal(got, []string{"cli", "web"}) || d.cursor != 1 {
+		t.Errorf("sections = %v, cursor %d; want [cli web] on web", got, d.cursor)
+	}
+	if _, ok := d.sections[0].feed.PRs[1]; !ok {
+		t.Error("new o/cli section didn't get its PR")
+	}
+
+	model, _ = model.Update(checks())
+	d = model.(DashboardModel)
+	if len(d.sections) != 0 || !strings.Contains(d.View().Content, "No open PRs") {
+		t.Errorf("sections = %v, want none and the empty state", sectionNames(d))
+	}
+	model, _ = pressKeys(t, model, "jk", tea.KeyEnter) // no sections to move through
+
+	// The search picks the author, so the prompt refuses one.
+	model, _ = pressKeys(t, model, "fauthor:x", tea.KeyEnter)
+	d = model.(DashboardModel)
+	if !d.prompt.open || d.prompt.err == nil || d.filterGen != 0 {
+		t.Errorf("prompt open %v err %v gen %d; want author:x rejected", d.prompt.open, d.prompt.err, d.filterGen)
+	}
+}
+
+func TestDashboardDeploymentReview(t *testing.T) {
+	m := newTestDashboard()
+	m.sections[0].feed.Runs = []ghclient.BranchRunData{{RunID: 40, HeadBranch: "main", Status: "waiting"}}
+	m.sections[0].deployments = map[int64][]ghclient.PendingDeployment{40: {{EnvironmentID: 1, Environment: "audit", Reviewers: []string{"@bob"}}}}
+	m.sections[1].feed.Runs = []ghclient.BranchRunData{{RunID: 50, HeadBranch: "main", Status: "waiting"}}
+	m.sections[1].deployments = map[int64][]ghclient.PendingDeployment{50: {{EnvironmentID: 2, Environment: "production", CanApprove: true}}}
+
+	// Someone else's approval gate can't be reviewed.
+	var model tea.Model = m
+	model, _ = pressKeys(t, model, "ja")
+	if model.(DashboardModel).capturingKeys() {
+		t.Fatal("review prompt opened for a deployment the user can't approve")
+	}
+	if view := model.View().Content; !strings.Contains(view, "needs approval from @bob") || !strings.Contains(view, "a/x to approve or reject") {
+		t.Errorf("view = %q, want both gates and the a/x hint", view)
+	}
+
+	// The prompt takes every key until closed, so the cursor stays put.
+	model, _ = pressKeys(t, model, "jja")
+	if d := model.(DashboardModel); !d.capturingKeys() || !strings.Contains(d.View().Content, "Approve production, comment:") {
+		t.Fatalf("no approval prompt for o/web's run:\n%s", d.View().Content)
+	}
+	model, _ = pressKeys(t, model, "jk")
+	d := model.(DashboardModel)
+	if d.cursor != 1 || d.sections[1].review.input.Value() != "jk" {
+		t.Errorf("cursor %d, comment %q; want o/web and the keys typed", d.cursor, d.sections[1].review.input.Value())
+	}
+	model, cmd := pressKeys(t, model, "", tea.KeyEnter)
+	if d := model.(DashboardModel); d.capturingKeys() || cmd == nil {
+		t.Errorf("enter left the prompt open (%v) or sent nothing (%v)", d.capturingKeys(), cmd)
+	}
+}
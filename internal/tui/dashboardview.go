@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+)
+
+// View renders the dashboard's header and summary, then a section per repo
+// (with --mine, a spinner until the first search lands).
+func (m DashboardModel) View() tea.View {
+	var b strings.Builder
+
+	utcTime := time.Now().UTC().Format("15:04:05 UTC")
+	prCount, runCount := 0, 0
+	for _, s := range m.sections {
+		prCount += len(s.feed.PRs)
+		runCount += len(s.feed.Runs)
+	}
+
+	title := fmt.Sprintf("%d repos", len(m.sections))
+	if m.mine {
+		who := "My open PRs"
+		if m.reviewRequested {
+			who = "My open PRs and review requests"
+		}
+		title = fmt.Sprintf("%s in %d repo%s", who, len(m.sections), pluralS(len(m.sections)))
+	}
+	header := m.styles.Header.Render(title)
+	summaryParts := []string{
+		fmt.Sprintf("%d active PR%s", prCount, pluralS(prCount)),
+	}
+	if runCount > 0 {
+		summaryParts = append(summaryParts, fmt.Sprintf("%d branch run%s", runCount, pluralS(runCount)))
+	}
+	if !m.filter.IsZero() {
+		summaryParts = append(summaryParts, "Filter: "+m.filter.String())
+	}
+	summaryParts = append(summaryParts, fmt.Sprintf("Updated %s ago", timing.FormatDuration(time.Since(m.lastUpdate))))
+
+	fmt.Fprintf(&b, "%s %s\n", header, utcTime)
+	fmt.Fprintf(&b, "%s\n", strings.Join(summaryParts, "  •  "))
+	b.WriteString("\n")
+
+	for i, s := range m.sections {
+		m.renderSection(&b, i, s)
+	}
+	if m.mine && len(m.sections) == 0 {
+		if m.checksReceived {
+			b.WriteString(m.styles.Queued.Render("  No open PRs"))
+		} else {
+			b.WriteString("  " + m.spinner.View() + " Searching for your open PRs")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.showQueueStats {
+		var jobs []ghclient.CheckRunInfo
+		var history map[string][]time.Duration
+		pending := false
+		for _, s := range m.sections {
+			jobs = append(jobs, s.queueJobs...)
+			history = mergeQueueSamples(history, s.queueHistory)
+			pending = pending || len(s.queueHistoryPending) > 0
+		}
+		current := ghclient.QueueSamplesByLabel(jobs, time.Now())
+		renderQueueStatsSection(&b, m.styles, "", summarizeQueueByLabel(current, history), pending)
+	}
+
+	if m.runners.show {
+		repos := make([]runnerRepo, len(m.sections))
+		for i, s := range m.sections {
+			repos[i] = runnerRepo{ref: s.ref(), jobs: s.queueJobs}
+		}
+		renderRunnersSection(&b, m.styles, m.runners, repos)
+	}
+
+	if m.showEvents {
+		renderEventsSection(&b, m.styles, m.dashboardEvents(), m.eventsOffset, true, time.Now())
+	}
+
+	q := m.quota()
+	renderRemainingQuota(&b, m.styles, q.Received, q.Remaining)
+
+	until := time.Now().Add(m.rateLimitWait(time.Now()))
+	if pause := renderRateLimitPause(m.styles, nil, until, time.Now()); pause != "" {
+		b.WriteString("  ")
+		b.WriteString(pause)
+		b.WriteString("\n")
+	}
+
+	if m.fetchErr != nil {
+		errText := truncateFetchError(m.fetchErr.Error(), 70)
+		age := timing.FormatDuration(time.Since(m.fetchErrAt))
+		b.WriteString(m.styles.Failure.Render(fmt.Sprintf("  [PR checks fetch error: %s — %s ago]", errText, age)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+
+	switch {
+	case m.prompt.open:
+		b.WriteString(m.prompt.view(m.styles))
+	case m.reviewing():
+		b.WriteString(m.sections[m.cursor].review.view())
+	case !m.quitting:
+		review := ""
+		for _, s := range m.sections {
+			if review = s.reviewHint(); review != "" {
+				break
+			}
+		}
+		fmt.Fprintf(&b, "Press ↑/↓ and enter to open a PR or run or fold a repo, f to filter, l for queue latency by runner label, r for runners, e for recent events, %sq to quit\n", review)
+	}
+
+	return tea.NewView(b.String())
+}
+
+// renderSection renders one repo's header line and, when expanded and
+// active, its groups and runs fetch error.
+func (m DashboardModel) renderSection(b *strings.Builder, i int, s RepoModel) {
+	indent := "  "
+	if i == m.cursor {
+		if _, ok := s.selectedItem(); !ok {
+			indent = selectionMarker
+		}
+	}
+	toggle := "[-]"
+	if m.collapsed[i] {
+		toggle = "[+]"
+	}
+	name := m.styles.Header.Render(s.owner + "/" + s.repo)
+
+	var summary string
+	switch {
+	case !s.feed.Quota.Received:
+		summary = m.spinner.View()
+	case s.idle():
+		summary = m.styles.Queued.Render("idle")
+	default:
+		summary = strings.Join(s.activityParts(), "  •  ")
+	}
+	fmt.Fprintf(b, "%s%s %s  %s\n", indent, toggle, name, summary)
+
+	if m.collapsed[i] {
+		return
+	}
+	if !s.idle() {
+		b.WriteString("\n")
+		s.renderGroups(b)
+	}
+	s.renderFetchError(b)
+}
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+)
+
+// PendingDeploymentsMsg carries the environments a waiting run is held at.
+type PendingDeploymentsMsg struct {
+	RunID              int64
+	Deployments        []ghclient.PendingDeployment
+	RateLimitRemaining int
+	Err                error
+}
+
+// DeploymentReviewMsg reports how approving (or rejecting) a run's
+// deployments went.
+type DeploymentReviewMsg struct {
+	RunID        int64
+	Approve      bool
+	Environments []string
+	Err          error
+}
+
+// fetchPendingDeployments lists the environments runID is waiting on.
+func fetchPendingDeployments(ctx context.Context, api ghclient.API, owner, repo string, runID int64) tea.Cmd {
+	return func() tea.Msg {
+		deployments, rateLimit, err := api.FetchPendingDeployments(ctx, owner, repo, runID)
+		return PendingDeploymentsMsg{RunID: runID, Deployments: deployments, RateLimitRemaining: rateLimit, Err: err}
+	}
+}
+
+// runWaiting reports whether any job is held at an environment.
+func runWaiting(jobs []ghclient.WorkflowJobInfo) bool {
+	return slices.ContainsFunc(jobs, func(j ghclient.WorkflowJobInfo) bool { return j.Status == "waiting" })
+}
+
+// approvable returns the deployments the user is a required reviewer of.
+func approvable(deployments []ghclient.PendingDeployment) []ghclient.PendingDeployment {
+	var mine []ghclient.PendingDeployment
+	for _, d := range deployments {
+		if d.CanApprove {
+			mine = append(mine, d)
+		}
+	}
+	return mine
+}
+
+// environmentNames joins deployments' environment names for a prompt or
+// status line.
+func environmentNames(deployments []ghclient.PendingDeployment) string {
+	names := make([]string, len(deployments))
+	for i, d := range deployments {
+		names[i] = d.Environment
+	}
+	return strings.Join(names, ", ")
+}
+
+// reviewPrompt is the a/x prompt for the comment approving or rejecting a
+// waiting run's deployments, shared by run mode, the repo overview and the
+// dashboard's sections. Every environment of the run the user can approve
+// is reviewed together, as GitHub's own review dialog does.
+type reviewPrompt struct {
+	open        bool
+	input       textinput.Model
+	runID       int64
+	approve     bool
+	deployments []ghclient.PendingDeployment
+}
+
+// show opens the prompt for reviewing runID's deployments, or does nothing
+// when the user can't approve any of them.
+func (p *reviewPrompt) show(runID int64, deployments []ghclient.PendingDeployment, approve bool) tea.Cmd {
+	mine := approvable(deployments)
+	if len(mine) == 0 {
+		return nil
+	}
+	verb := "Reject"
+	if approve {
+		verb = "Approve"
+	}
+	p.input = textinput.New()
+	p.input.Prompt = fmt.Sprintf("%s %s, comment: ", verb, environmentNames(mine))
+	p.input.Placeholder = "optional"
+	p.runID, p.approve, p.deployments = runID, approve, mine
+	p.open = true
+	return p.input.Focus()
+}
+
+// handleKey routes a key to the open prompt: enter submits, esc closes it
+// unchanged, and everything else edits the comment. Callers handle ctrl+c
+// first.
+func (p *reviewPrompt) handleKey(msg tea.KeyMsg) (submit bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.open = false
+		return false, nil
+	case "enter":
+		p.open = false
+		return true, nil
+	}
+	p.input, cmd = p.input.Update(msg)
+	return false, cmd
+}
+
+// update passes anything else (the cursor blink) to the open prompt.
+func (p *reviewPrompt) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+// submitCmd posts the review the prompt was opened for.
+func (p reviewPrompt) submitCmd(ctx context.Context, api ghclient.API, owner, repo string) tea.Cmd {
+	runID, approve, comment := p.runID, p.approve, p.input.Value()
+	ids := make([]int64, len(p.deployments))
+	names := make([]string, len(p.deployments))
+	for i, d := range p.deployments {
+		ids[i], names[i] = d.EnvironmentID, d.Environment
+	}
+	return func() tea.Msg {
+		err := api.ReviewPendingDeployments(ctx, owner, repo, runID, ids, approve, comment)
+		return DeploymentReviewMsg{RunID: runID, Approve: approve, Environments: names, Err: err}
+	}
+}
+
+// view renders the prompt and how to leave it.
+func (p reviewPrompt) view() string {
+	verb := "reject"
+	if p.approve {
+		verb = "approve"
+	}
+	return p.input.View() + "\n" + fmt.Sprintf("Press enter to %s, esc to cancel\n", verb)
+}
+
+// reviewResult is how the last review went, shown under the run it was
+// for until the run stops waiting.
+type reviewResult struct {
+	runID int64
+	text  string
+	err   bool
+}
+
+// newReviewResult describes msg's outcome.
+func newReviewResult(msg DeploymentReviewMsg) reviewResult {
+	verb, done := "reject", "Rejected"
+	if msg.Approve {
+		verb, done = "approve", "Approved"
+	}
+	envs := strings.Join(msg.Environments, ", ")
+	if msg.Err != nil {
+		return reviewResult{runID: msg.RunID, text: fmt.Sprintf("Couldn't %s %s: %v", verb, envs, msg.Err), err: true}
+	}
+	return reviewResult{runID: msg.RunID, text: done + " " + envs}
+}
+
+// renderPendingDeployments renders one line per environment a run is
+// waiting on, under indent: who has to approve it, how long its wait timer
+// has left, and whether the user is one of the reviewers. result is shown
+// beneath when it is for runID.
+func renderPendingDeployments(b *strings.Builder, styles Styles, runID int64, deployments []ghclient.PendingDeployment, result reviewResult, indent string, now time.Time) {
+	for _, d := range deployments {
+		var parts []string
+		if len(d.Reviewers) > 0 {
+			parts = append(parts, "needs approval from "+strings.Join(d.Reviewers, ", "))
+		}
+		if d.WaitTimer > 0 {
+			if left := d.WaitRemaining(now); left > 0 {
+				parts = append(parts, fmt.Sprintf("wait timer %s left", timing.FormatDuration(left)))
+			} else {
+				parts = append(parts, "wait timer done")
+			}
+		}
+		line := styles.Queued.Render(GetCheckIcon("waiting", "")) + " " + d.Environment
+		if len(parts) > 0 {
+			line += "  " + styles.Queued.Render(strings.Join(parts, "  •  "))
+		}
+		if d.CanApprove {
+			line += "  " + styles.Running.Render("you can approve")
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	if result.runID == runID && result.text != "" {
+		style := styles.Success
+		if result.err {
+			style = styles.Failure
+		}
+		fmt.Fprintf(b, "%s%s\n", indent, style.Render(result.text))
+	}
+}
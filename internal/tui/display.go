@@ -211,6 +211,37 @@ func FormatAvg(check ghclient.CheckRunInfo, jobAverages map[string]time.Duration
 	return timing.FormatDuration(avg)
 }
 
+// FormatSlowMarker returns "2.1× usual" for a check whose runtime so far
+// (or final runtime) is more than slowerThanUsualFactor times its
+// historical average and at least minSlowOverrun past it, or "" when it
+// isn't or has no average. Keyed by bare check name, like FormatAvg.
+func FormatSlowMarker(check ghclient.CheckRunInfo, jobAverages map[string]time.Duration, now time.Time) string {
+	avg, ok := jobAverages[check.Name]
+	if !ok || avg <= 0 || check.StartedAt == nil {
+		return ""
+	}
+	end := now
+	if check.CompletedAt != nil {
+		end = *check.CompletedAt
+	}
+	runtime := end.Sub(*check.StartedAt)
+	if float64(runtime) < slowerThanUsualFactor*float64(avg) || runtime-avg < minSlowOverrun {
+		return ""
+	}
+	return fmt.Sprintf("%.1f× usual", float64(runtime)/float64(avg))
+}
+
+// FormatFlakyMarker returns "flaky (3/20 recent runs failed then passed on
+// retry)" for a check whose history shows fail-then-pass on the same commit,
+// or "" when it has no such history. Keyed by bare check name, like FormatAvg.
+func FormatFlakyMarker(check ghclient.CheckRunInfo, flakyStats map[string]ghclient.FlakyStats) string {
+	stats, ok := flakyStats[check.Name]
+	if !ok || !stats.IsFlaky() {
+		return ""
+	}
+	return fmt.Sprintf("flaky (%d/%d recent runs failed then passed on retry)", stats.Retried, stats.Runs)
+}
+
 // CalculateColumnWidths scans all check runs and determines max width for each column
 func CalculateColumnWidths(checkRuns []ghclient.CheckRunInfo, headPushedTime time.Time, jobAverages map[string]time.Duration) ColumnWidths {
 	const (
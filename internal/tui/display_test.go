@@ -263,6 +263,68 @@ func TestFormatCheckNameWithTruncate(t *testing.T) {
 	}
 }
 
+func TestFormatFlakyMarker(t *testing.T) {
+	check := ghclient.CheckRunInfo{Name: "test"}
+	tests := []struct {
+		name  string
+		stats map[string]ghclient.FlakyStats
+		want  string
+	}{
+		{name: "nil map", stats: nil, want: ""},
+		{name: "missing key", stats: map[string]ghclient.FlakyStats{"lint": {Retried: 1, Runs: 10}}, want: ""},
+		{name: "never retried", stats: map[string]ghclient.FlakyStats{"test": {Retried: 0, Runs: 10}}, want: ""},
+		{
+			name:  "flaky",
+			stats: map[string]ghclient.FlakyStats{"test": {Retried: 3, Runs: 20}},
+			want:  "flaky (3/20 recent runs failed then passed on retry)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatFlakyMarker(check, tt.stats); got != tt.want {
+				t.Errorf("FormatFlakyMarker() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSlowMarker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	startedAgo := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	averages := map[string]time.Duration{"test": 2 * time.Minute, "lint": 10 * time.Second}
+	tests := []struct {
+		name     string
+		check    ghclient.CheckRunInfo
+		averages map[string]time.Duration
+		want     string
+	}{
+		{name: "no averages", check: ghclient.CheckRunInfo{Name: "test", StartedAt: startedAgo(10 * time.Minute)}, want: ""},
+		{name: "not started", check: ghclient.CheckRunInfo{Name: "test"}, averages: averages, want: ""},
+		{name: "within usual", check: ghclient.CheckRunInfo{Name: "test", StartedAt: startedAgo(150 * time.Second)}, averages: averages, want: ""},
+		{name: "running slow", check: ghclient.CheckRunInfo{Name: "test", StartedAt: startedAgo(5 * time.Minute)}, averages: averages, want: "2.5× usual"},
+		{
+			name: "finished slow",
+			check: ghclient.CheckRunInfo{
+				Name: "test", StartedAt: startedAgo(time.Hour), CompletedAt: startedAgo(time.Hour - 4*time.Minute),
+			},
+			averages: averages,
+			want:     "2.0× usual",
+		},
+		// 6× a 10s average, but under a minute over: jitter, not slow.
+		{name: "short job", check: ghclient.CheckRunInfo{Name: "lint", StartedAt: startedAgo(time.Minute)}, averages: averages, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSlowMarker(tt.check, tt.averages, now); got != tt.want {
+				t.Errorf("FormatSlowMarker() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatAvg(t *testing.T) {
 	check := ghclient.CheckRunInfo{Name: "my-job"}
 
@@ -0,0 +1,687 @@
+package tui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// drive runs a model the way the bubbletea runtime would, but
+// synchronously and against the fake: commands execute in order, batches
+// are flattened, and every poll tick advances the fake clock by step so
+// checks move through their scripted timeline. A sequence's commands run
+// before anything else queued, in order. Spinner ticks are dropped
+// (they'd animate forever). Stops when the model quits or done reports
+// true (nil means run until quit); fails after maxSteps messages.
+func drive(t *testing.T, model tea.Model, srv *fakegithub.Server, step time.Duration, maxSteps int, done func(tea.Model) bool) tea.Model {
+	t.Helper()
+	return driveFrom(t, model, model.Init(), srv, step, maxSteps, done)
+}
+
+// driveFrom is drive starting from cmd rather than the model's Init, to
+// carry on after keys pressed mid-way.
+func driveFrom(t *testing.T, model tea.Model, cmd tea.Cmd, srv *fakegithub.Server, step time.Duration, maxSteps int, done func(tea.Model) bool) tea.Model {
+	t.Helper()
+	queue := []tea.Cmd{cmd}
+	for steps := 0; len(queue) > 0; {
+		cmd := queue[0]
+		queue = queue[1:]
+		if cmd == nil {
+			continue
+		}
+		msg := cmd()
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			queue = append(queue, msg...)
+			continue
+		case tea.QuitMsg:
+			return model
+		case spinner.TickMsg:
+			continue
+		case TickMsg, RunTickMsg, RepoTickMsg:
+			srv.Advance(step)
+		default:
+			if seq, ok := sequenceCmds(msg); ok {
+				queue = append(seq, queue...)
+				continue
+			}
+		}
+		if steps++; steps > maxSteps {
+			t.Fatalf("model not done within %d messages", maxSteps)
+		}
+		var next tea.Cmd
+		model, next = model.Update(msg)
+		if done != nil && done(model) {
+			return model
+		}
+		queue = append(queue, next)
+	}
+	t.Fatal("model stopped issuing commands")
+	return nil
+}
+
+// runModelOf unwraps a RunModel returned by Update, by value or pointer.
+func runModelOf(m tea.Model) RunModel {
+	if p, ok := m.(*RunModel); ok {
+		return *p
+	}
+	return m.(RunModel)
+}
+
+// newFakeGitHub starts a fake with a quota that resets soon, so the poll
+// budget never stretches the (millisecond) test refresh interval.
+func newFakeGitHub(t *testing.T) (*fakegithub.Server, ghclient.API) {
+	t.Helper()
+	srv := fakegithub.New("octo", "hello")
+	t.Cleanup(srv.Close)
+	srv.SetRateLimit(5000, time.Now().Add(10*time.Second))
+	api, err := ghclient.NewSession("test-token", ghclient.WithEndpoints(srv.URL(), srv.GraphQLURL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, api
+}
+
+func TestPRLifecycleEndToEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		conclusion string
+		wantExit   int
+	}{
+		{name: "passing", conclusion: "success", wantExit: 0},
+		{name: "failing", conclusion: "failure", wantExit: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, api := newFakeGitHub(t)
+			now := srv.Now()
+			srv.AddRun(fakegithub.Run{
+				ID: 90, WorkflowID: 7, Name: "CI", HeadSHA: "old", CreatedAt: now.Add(-24 * time.Hour),
+				Jobs: []fakegithub.Job{{Name: "build", Duration: 2 * time.Minute}, {Name: "test", Duration: 4 * time.Minute}},
+			})
+			srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+			srv.AddRun(fakegithub.Run{
+				ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", CreatedAt: now.Add(20 * time.Second),
+				Jobs: []fakegithub.Job{
+					{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute},
+					{Name: "test", QueuedAfter: time.Minute, StartedAfter: 90 * time.Second, Duration: 2 * time.Minute, Conclusion: tt.conclusion},
+				},
+			})
+
+			model := NewModel(context.Background(), api, "octo", "hello", 12, time.Millisecond,
+				stylesForTest(), false, false, nil, false, 0, 0, 0)
+			final := drive(t, model, srv, 15*time.Second, 500, nil).(Model)
+
+			if final.ExitCode() != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", final.ExitCode(), tt.wantExit)
+			}
+			if final.feed.Title != "Add widgets" || !final.feed.HeadPushedTime.Equal(now) {
+				t.Errorf("PR title %q pushed %v, want %q %v", final.feed.Title, final.feed.HeadPushedTime, "Add widgets", now)
+			}
+			if len(final.feed.CheckRuns) != 2 || !allChecksComplete(final.feed.CheckRuns) {
+				t.Errorf("final checks = %+v, want 2 completed", final.feed.CheckRuns)
+			}
+			// History is fetched once the checks complete, so the PR's own
+			// run is the newest sample, weighted ahead of the older one.
+			if b := final.jobAverages["build"]; b <= time.Minute || b >= 2*time.Minute {
+				t.Errorf("build average = %v, want between this run (1m) and history (2m)", b)
+			}
+			if final.expectedCheckCount != 2 {
+				t.Errorf("expected check count = %d, want 2 from history", final.expectedCheckCount)
+			}
+			view := final.View().Content
+			for _, want := range []string{"build", "test"} {
+				if !strings.Contains(view, want) {
+					t.Errorf("final view missing %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunLifecycleEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	now := srv.Now()
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "Release", HeadSHA: "abc123", Event: "push",
+		Jobs: []fakegithub.Job{
+			{Name: "package", StartedAfter: 5 * time.Second, Duration: 40 * time.Second},
+			{Name: "publish", QueuedAfter: 45 * time.Second, StartedAfter: 50 * time.Second, Duration: 30 * time.Second},
+		},
+	})
+
+	model := NewRunModel(context.Background(), api, "octo", "hello", 100, time.Millisecond,
+		stylesForTest(), false, true, nil)
+	final := drive(t, model, srv, 10*time.Second, 500, nil).(RunModel)
+
+	if final.ExitCode() != 0 {
+		t.Errorf("exit code = %d, want 0", final.ExitCode())
+	}
+	if final.runInfo.HeadPushedTime == nil || !final.runInfo.HeadPushedTime.Time.Equal(now) {
+		t.Errorf("head pushed time = %v, want %v", final.runInfo.HeadPushedTime, now)
+	}
+	if len(final.jobs) != 2 || !ghclient.AllJobsComplete(final.jobs) {
+		t.Errorf("final jobs = %+v, want 2 completed", final.jobs)
+	}
+}
+
+func TestRunWithoutHistoryEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	// The workflow's first run: no completed runs to take history from.
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "Release", HeadSHA: "abc123", Event: "push",
+		Jobs: []fakegithub.Job{{Name: "package", StartedAfter: 5 * time.Second, Duration: 40 * time.Second}},
+	})
+
+	model := NewRunModel(context.Background(), api, "octo", "hello", 100, time.Millisecond,
+		stylesForTest(), false, false, nil)
+	final := runModelOf(drive(t, model, srv, 10*time.Second, 500, nil))
+
+	if final.ExitCode() != 0 {
+		t.Errorf("exit code = %d, want 0", final.ExitCode())
+	}
+	if !final.historyFetchCompleted || final.avgFetchErr != nil {
+		t.Errorf("history fetch completed %v err %v, want completed without error", final.historyFetchCompleted, final.avgFetchErr)
+	}
+	if len(final.jobAverages) != 0 {
+		t.Errorf("job averages = %v, want none", final.jobAverages)
+	}
+}
+
+func TestRunDeploymentApprovalEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "Deploy", HeadSHA: "abc123", HeadBranch: "main", Event: "push",
+		Jobs: []fakegithub.Job{
+			{Name: "build", Duration: 30 * time.Second},
+			{Name: "production", QueuedAfter: 30 * time.Second, Duration: 30 * time.Second, Gate: &fakegithub.Gate{
+				EnvironmentID: 1, Environment: "production", Reviewers: []string{"alice", "octo"}, CanApprove: true,
+			}},
+		},
+	})
+
+	model := NewRunModel(context.Background(), api, "octo", "hello", 100, time.Millisecond,
+		stylesForTest(), false, true, nil)
+	m := drive(t, model, srv, 10*time.Second, 500, func(m tea.Model) bool {
+		return len(runModelOf(m).deployments) == 1
+	})
+	view := m.View().Content
+	for _, want := range []string{"production", "needs approval from @alice, @octo", "you can approve", "a/x to approve or reject"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = pressKeys(t, m, "a")
+	if !strings.Contains(m.View().Content, "Approve production, comment:") {
+		t.Fatalf("no approval prompt:\n%s", m.View().Content)
+	}
+	m, cmd := pressKeys(t, m, "ship it", tea.KeyEnter)
+	final := runModelOf(driveFrom(t, m, tea.Batch(cmd, runTick(time.Millisecond)), srv, 10*time.Second, 500, nil))
+
+	if reviews := srv.DeploymentReviews(100); len(reviews) != 1 || reviews[0].State != "approved" || reviews[0].Comment != "ship it" {
+		t.Errorf("reviews = %+v, want production approved with the comment", reviews)
+	}
+	if final.ExitCode() != 0 || !ghclient.AllJobsComplete(final.jobs) {
+		t.Errorf("exit %d, jobs %+v; want the deploy through", final.ExitCode(), final.jobs)
+	}
+	if final.reviewResult.text != "Approved production" || len(final.deployments) != 0 {
+		t.Errorf("review result %+v, deployments %+v; want approved and none left", final.reviewResult, final.deployments)
+	}
+}
+
+func TestRepoWatchEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute}},
+	})
+	srv.AddRun(fakegithub.Run{
+		ID: 200, WorkflowID: 8, Name: "Nightly", HeadSHA: "def456", HeadBranch: "main", Event: "schedule",
+		Jobs: []fakegithub.Job{{Name: "soak", StartedAfter: 5 * time.Second, Duration: 10 * time.Minute}},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute)
+	// Repo mode never quits; stop once the PR's check has finished and the
+	// nightly run is visible.
+	m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		rm := repoModelOf(m)
+		pr, ok := rm.feed.PRs[12]
+		return ok && allChecksComplete(pr.CheckRuns) && len(rm.feed.Runs) == 1
+	})
+	final := repoModelOf(m)
+
+	if got := final.feed.PRs[12].CheckRuns; len(got) != 1 || got[0].Name != "build" || got[0].Conclusion != "success" {
+		t.Errorf("PR #12 checks = %+v, want build succeeded", got)
+	}
+	if run := final.feed.Runs[0]; run.RunID != 200 || len(run.Jobs) != 1 || run.Jobs[0].Status != "in_progress" {
+		t.Errorf("standalone run = %+v, want nightly with soak in progress", run)
+	}
+}
+
+func TestRepoWatchDeploymentRejectionEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	now := srv.Now()
+	// Waiting since long before the fade window: only the waiting-runs
+	// listing still finds it.
+	srv.AddRun(fakegithub.Run{
+		ID: 200, WorkflowID: 8, Name: "Deploy", HeadSHA: "def456", HeadBranch: "main", Event: "push", CreatedAt: now.Add(-3 * time.Hour),
+		Jobs: []fakegithub.Job{{Name: "production", Duration: time.Minute, Gate: &fakegithub.Gate{
+			EnvironmentID: 1, Environment: "production", Reviewers: []string{"octo/deployers"}, WaitTimer: 4 * time.Hour, CanApprove: true,
+		}}},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).WithoutAverages()
+	m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		return len(repoModelOf(m).deployments[200]) == 1
+	})
+	view := m.View().Content
+	for _, want := range []string{"production", "needs approval from @octo/deployers", "wait timer", "you can approve"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// a/x act on the selected run only.
+	m, _ = pressKeys(t, m, "x")
+	if repoModelOf(m).review.open {
+		t.Fatal("review prompt opened with no run selected")
+	}
+	m, _ = pressKeys(t, m, "jx")
+	if !strings.Contains(m.View().Content, "Reject production, comment:") {
+		t.Fatalf("no rejection prompt:\n%s", m.View().Content)
+	}
+	m, cmd := pressKeys(t, m, "not today", tea.KeyEnter)
+	// Rejected, the run fails, and having started hours ago it is past
+	// the fade window at once.
+	m = driveFrom(t, m, tea.Batch(cmd, repoTick(time.Millisecond)), srv, 15*time.Second, 500, func(m tea.Model) bool {
+		return repoModelOf(m).idle()
+	})
+	final := repoModelOf(m)
+
+	if reviews := srv.DeploymentReviews(200); len(reviews) != 1 || reviews[0].State != "rejected" || reviews[0].Comment != "not today" {
+		t.Errorf("reviews = %+v, want production rejected with the comment", reviews)
+	}
+	if len(final.deployments) != 0 {
+		t.Errorf("deployments = %+v, want none left", final.deployments)
+	}
+	if final.reviewResult.text != "Rejected production" {
+		t.Errorf("review result = %+v, want rejected", final.reviewResult)
+	}
+}
+
+func TestRepoWatchRunnersEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddRunner(fakegithub.Runner{ID: 1, Name: "build-1", OS: "Linux", Online: true, Busy: true, Labels: []string{"self-hosted", "linux"}})
+	srv.AddRunner(fakegithub.Runner{ID: 2, Name: "gpu-1", OS: "Linux", Labels: []string{"self-hosted", "gpu"}})
+	gpu := fakegithub.Job{Labels: []string{"self-hosted", "gpu"}, StartedAfter: 24 * time.Hour, Duration: time.Minute}
+	srv.AddRun(fakegithub.Run{
+		ID: 400, WorkflowID: 10, Name: "Train", HeadSHA: "aaa111", HeadBranch: "main", Event: "push",
+		Jobs: []fakegithub.Job{
+			{Name: "lint", Labels: []string{"self-hosted", "linux"}, Duration: time.Hour},
+			withName(gpu, "train-a"), withName(gpu, "train-b"), withName(gpu, "train-c"),
+		},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).WithoutAverages()
+	m, cmd := pressKeys(t, model, "r")
+	m = driveFrom(t, m, tea.Batch(model.Init(), cmd), srv, 15*time.Second, 500, func(m tea.Model) bool {
+		r := repoModelOf(m)
+		return len(r.queueJobs) == 4 && r.runners.listings["octo/hello"].fetched && r.runners.listings["octo"].hidden()
+	})
+	view := m.View().Content
+	for _, want := range []string{
+		"build-1", "gpu-1", "self-hosted, linux",
+		"octo: organization runners not visible to this token",
+		"3 jobs queued for [self-hosted, gpu] — 0 online runners with those labels (1 offline)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A refused organization listing isn't asked for again; the repo's
+	// runners are listed on every poll.
+	m = drive(t, m, srv, 15*time.Second, 500, func(tea.Model) bool {
+		return countRequests(srv, "GET /repos/octo/hello/actions/runners") >= 3
+	})
+	if orgLists, repoLists := countRequests(srv, "GET /orgs/octo/actions/runners"), countRequests(srv, "GET /repos/octo/hello/actions/runners"); orgLists != 1 {
+		t.Errorf("listed org runners %d times and repo runners %d times, want once and every poll", orgLists, repoLists)
+	}
+
+	// Hiding the pane stops the listings.
+	m, _ = pressKeys(t, m, "r")
+	if strings.Contains(m.View().Content, "build-1") {
+		t.Error("runner pane still shown after r")
+	}
+}
+
+// A PR's job stuck behind a missing runner for longer than the fade window
+// is still listed, and warned about.
+func TestRepoWatchRunnerShortfallLongQueuedEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddRunner(fakegithub.Runner{ID: 2, Name: "gpu-1", OS: "Linux", Labels: []string{"self-hosted", "gpu"}})
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Train bigger", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", Event: "pull_request",
+		CreatedAt: srv.Now().Add(-2 * time.Hour),
+		Jobs:      []fakegithub.Job{{Name: "train", Labels: []string{"self-hosted", "gpu"}, StartedAfter: 24 * time.Hour, Duration: time.Minute}},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).WithoutAverages()
+	m, cmd := pressKeys(t, model, "r")
+	m = driveFrom(t, m, tea.Batch(model.Init(), cmd), srv, 15*time.Second, 500, func(m tea.Model) bool {
+		r := repoModelOf(m)
+		return len(r.queueJobs) == 1 && r.runners.listings["octo/hello"].fetched
+	})
+	want := "1 job queued for [self-hosted, gpu] — 0 online runners with those labels (1 offline)"
+	if view := m.View().Content; !strings.Contains(view, want) {
+		t.Errorf("view missing %q:\n%s", want, view)
+	}
+}
+
+// Queue latency covers PR checks too: their REST copies carry the runner
+// labels the GraphQL rollup lacks.
+func TestRepoWatchQueueStatsPRChecksEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Train bigger", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", Event: "pull_request",
+		CreatedAt: srv.Now().Add(-2 * time.Hour),
+		Jobs:      []fakegithub.Job{{Name: "train", Labels: []string{"self-hosted", "gpu"}, StartedAfter: 24 * time.Hour, Duration: time.Minute}},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).WithoutAverages()
+	m, cmd := pressKeys(t, model, "l")
+	m = driveFrom(t, m, tea.Batch(model.Init(), cmd), srv, 15*time.Second, 500, func(m tea.Model) bool {
+		r := repoModelOf(m)
+		return len(r.queueJobs) == 1 && len(r.queueHistoryPending) == 0
+	})
+	if view := m.View().Content; !strings.Contains(view, "self-hosted, gpu") {
+		t.Errorf("queue stats missing the PR's gpu job:\n%s", view)
+	}
+}
+
+// countRequests counts how many times srv has served req.
+func countRequests(srv *fakegithub.Server, req string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r == req {
+			n++
+		}
+	}
+	return n
+}
+
+// withName returns job renamed to name.
+func withName(job fakegithub.Job, name string) fakegithub.Job {
+	job.Name = name
+	return job
+}
+
+func TestRepoWatchAveragesEndToEnd(t *testing.T) {
+	tests := []struct {
+		name  string
+		quick bool
+	}{
+		{name: "averages"},
+		{name: "quick", quick: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, api := newFakeGitHub(t)
+			now := srv.Now()
+			srv.AddRun(fakegithub.Run{
+				ID: 90, WorkflowID: 7, Name: "CI", HeadSHA: "old", CreatedAt: now.Add(-24 * time.Hour),
+				Jobs: []fakegithub.Job{{Name: "build", Duration: 2 * time.Minute}},
+			})
+			srv.AddRun(fakegithub.Run{
+				ID: 91, WorkflowID: 8, Name: "Nightly", HeadSHA: "older", HeadBranch: "main", Event: "schedule",
+				CreatedAt: now.Add(-24 * time.Hour), Jobs: []fakegithub.Job{{Name: "soak", Duration: 2 * time.Minute}},
+			})
+			srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+			srv.AddRun(fakegithub.Run{
+				ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+				Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute}},
+			})
+			srv.AddRun(fakegithub.Run{
+				ID: 200, WorkflowID: 8, Name: "Nightly", HeadSHA: "def456", HeadBranch: "main", Event: "schedule",
+				Jobs: []fakegithub.Job{{Name: "soak", StartedAfter: 5 * time.Second, Duration: 10 * time.Minute}},
+			})
+
+			model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+				stylesForTest(), false, 15*time.Minute, 30*time.Minute)
+			if tt.quick {
+				model = model.WithoutAverages()
+			}
+			// Stop once the nightly soak has run well past its 2m average.
+			m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+				rm := repoModelOf(m)
+				if len(rm.feed.Runs) != 1 || len(rm.feed.Runs[0].Jobs) != 1 {
+					return false
+				}
+				soak := rm.feed.Runs[0].Jobs[0]
+				return soak.StartedAt != nil && srv.Now().Sub(*soak.StartedAt) > 5*time.Minute
+			})
+			final := repoModelOf(m)
+
+			var graphQLFetches, restFetches int
+			for _, req := range srv.Requests() {
+				switch req {
+				case "POST /graphql workflowHistory":
+					graphQLFetches++
+				case "GET /repos/octo/hello/actions/workflows/8/runs":
+					restFetches++
+				}
+			}
+			view := final.View().Content
+			if tt.quick {
+				if graphQLFetches+restFetches != 0 || len(final.workflowAverages) != 0 {
+					t.Errorf("--quick fetched history: %d GraphQL, %d REST, averages %v", graphQLFetches, restFetches, final.workflowAverages)
+				}
+				if strings.Contains(view, "2m 0s") {
+					t.Errorf("--quick view shows averages:\n%s", view)
+				}
+				return
+			}
+
+			// The PR check's workflow is batched over GraphQL; the
+			// branch-run-only workflow falls back to REST. Each once.
+			if graphQLFetches != 1 || restFetches != 1 {
+				t.Errorf("history fetches = %d GraphQL, %d REST, want 1 each", graphQLFetches, restFetches)
+			}
+			if got := final.averagesFor(7)["build"]; got != 2*time.Minute {
+				t.Errorf("workflow 7 build average = %v, want 2m", got)
+			}
+			if got := final.averagesFor(8)["soak"]; got != 2*time.Minute {
+				t.Errorf("workflow 8 soak average = %v, want 2m", got)
+			}
+			if !strings.Contains(view, "2m 0s") {
+				t.Errorf("view missing the 2m averages:\n%s", view)
+			}
+			// The view measures runtime by the wall clock, so check the
+			// marker against the fake's.
+			soak := final.feed.Runs[0].Jobs[0]
+			if got := FormatSlowMarker(soak, final.averagesFor(8), srv.Now()); !strings.HasSuffix(got, "× usual") {
+				t.Errorf("soak slow marker = %q, want \"N× usual\"", got)
+			}
+		})
+	}
+}
+
+func TestRepoWatchFilteredEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Mine", Author: "octo", HeadSHA: "abc123"})
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 13, Title: "Theirs", Author: "hubot", HeadSHA: "bcd234"})
+	for i, sha := range []string{"abc123", "bcd234"} {
+		srv.AddRun(fakegithub.Run{
+			ID: int64(100 + i), WorkflowID: 7, Name: "CI", HeadSHA: sha,
+			Jobs: []fakegithub.Job{{Name: "build", Duration: time.Minute}},
+		})
+	}
+	srv.AddRun(fakegithub.Run{
+		ID: 200, WorkflowID: 8, Name: "Deploy", HeadSHA: "def456", HeadBranch: "main", Event: "push", Actor: "octo",
+		Jobs: []fakegithub.Job{{Name: "deploy", Duration: 10 * time.Minute}},
+	})
+	srv.AddRun(fakegithub.Run{
+		ID: 201, WorkflowID: 8, Name: "Deploy", HeadSHA: "efa567", HeadBranch: "main", Event: "push", Actor: "hubot",
+		Jobs: []fakegithub.Job{{Name: "deploy", Duration: 10 * time.Minute}},
+	})
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).
+		WithFilter(ghclient.RepoFilter{Author: ghclient.ViewerAlias, Base: "main"})
+	m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		rm := repoModelOf(m)
+		return len(rm.feed.PRs) > 0 && len(rm.feed.Runs) > 0
+	})
+	final := repoModelOf(m)
+
+	if _, ok := final.feed.PRs[12]; !ok || len(final.feed.PRs) != 1 {
+		t.Errorf("PRs = %v, want only #12 (authored by the viewer)", final.feed.PRNumbers())
+	}
+	if len(final.feed.Runs) != 1 || final.feed.Runs[0].RunID != 200 {
+		t.Errorf("standalone runs = %+v, want only the viewer's deploy", final.feed.Runs)
+	}
+}
+
+func TestDashboardEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute}},
+	})
+	apiRepo := srv.AddRepo("octo", "api")
+	apiRepo.AddRun(fakegithub.Run{
+		ID: 300, WorkflowID: 9, Name: "Deploy", HeadSHA: "fed987", HeadBranch: "main",
+		Jobs: []fakegithub.Job{{Name: "deploy", Duration: 10 * time.Minute}},
+	})
+	srv.AddRepo("octo", "docs")
+
+	repos := []ghclient.RepoRef{{Owner: "octo", Name: "hello"}, {Owner: "octo", Name: "api"}, {Owner: "octo", Name: "docs"}}
+	model := NewDashboardModel(context.Background(), api, repos, time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute)
+	m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		d := m.(DashboardModel)
+		pr, ok := d.sections[0].feed.PRs[12]
+		return ok && allChecksComplete(pr.CheckRuns) && len(d.sections[1].feed.Runs) == 1
+	})
+	final := m.(DashboardModel)
+
+	if got := final.sections[0].feed.PRs[12].CheckRuns; len(got) != 1 || got[0].Conclusion != "success" {
+		t.Errorf("octo/hello PR #12 checks = %+v, want build succeeded", got)
+	}
+	if run := final.sections[1].feed.Runs[0]; run.RunID != 300 {
+		t.Errorf("octo/api standalone run = %+v, want the deploy", run)
+	}
+	if !final.sections[2].idle() {
+		t.Error("octo/docs not idle")
+	}
+
+	// All three repos' PRs are listed by one query per poll, not one each.
+	// Every poll lists octo/docs's runs three times (active, waiting, then
+	// recent); the last poll's runs may not have been fetched when driving
+	// stopped.
+	listings, runLists := 0, 0
+	for _, req := range srv.Requests() {
+		switch req {
+		case "POST /graphql repoPullRequests":
+			listings++
+		case "GET /repos/octo/docs/actions/runs":
+			runLists++
+		}
+	}
+	if polls := runLists / 3; listings < polls || listings > polls+1 {
+		t.Errorf("%d listing queries over %d polls, want one per poll", listings, polls)
+	}
+}
+
+func TestDashboardRunnersEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddOrgRunners("octo",
+		fakegithub.Runner{ID: 1, Name: "org-linux", Online: true, Labels: []string{"self-hosted", "linux"}},
+		fakegithub.Runner{ID: 2, Name: "org-gpu", Labels: []string{"self-hosted", "gpu"}},
+	)
+	apiRepo := srv.AddRepo("octo", "api")
+	apiRepo.AddRun(fakegithub.Run{
+		ID: 300, WorkflowID: 9, Name: "Train", HeadSHA: "fed987", HeadBranch: "main", Event: "push",
+		Jobs: []fakegithub.Job{{Name: "train", Labels: []string{"gpu"}, StartedAfter: 24 * time.Hour, Duration: time.Minute}},
+	})
+
+	repos := []ghclient.RepoRef{{Owner: "octo", Name: "hello"}, {Owner: "octo", Name: "api"}}
+	model := NewDashboardModel(context.Background(), api, repos, time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute)
+	m, cmd := pressKeys(t, model, "r")
+	m = driveFrom(t, m, tea.Batch(model.Init(), cmd), srv, 15*time.Second, 500, func(m tea.Model) bool {
+		d := m.(DashboardModel)
+		return len(d.sections[1].queueJobs) == 1 && d.runners.listings["octo"].fetched && d.runners.listings["octo/api"].fetched
+	})
+	view := m.View().Content
+	for _, want := range []string{"org-linux", "octo (org)", "octo/api: 1 job queued for [gpu] — 0 online runners with that label (1 offline)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Both repos are listed on every poll, their organization once.
+	orgLists, helloLists := countRequests(srv, "GET /orgs/octo/actions/runners"), countRequests(srv, "GET /repos/octo/hello/actions/runners")
+	if orgLists != helloLists {
+		t.Errorf("listed org runners %d times over %d polls, want once per poll", orgLists, helloLists)
+	}
+}
+
+func TestMineDashboardEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", Author: "octo", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute}},
+	})
+	lib := srv.AddRepo("other", "lib")
+	lib.AddPullRequest(fakegithub.PullRequest{Number: 5, Title: "Bump deps", Author: "bot", HeadSHA: "fed987", ReviewRequested: []string{"octo"}})
+	lib.AddRun(fakegithub.Run{
+		ID: 300, WorkflowID: 9, Name: "CI", HeadSHA: "fed987",
+		Jobs: []fakegithub.Job{{Name: "test", Duration: 30 * time.Minute}},
+	})
+	lib.AddPullRequest(fakegithub.PullRequest{Number: 6, Title: "Not mine", Author: "someone", HeadSHA: "0ff1ce"})
+
+	model := NewMineDashboardModel(context.Background(), api, true, time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute)
+	m := drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		d := m.(DashboardModel)
+		if len(d.sections) != 2 {
+			return false
+		}
+		pr, ok := d.sections[0].feed.PRs[12]
+		return ok && allChecksComplete(pr.CheckRuns)
+	})
+	final := m.(DashboardModel)
+
+	if got := final.refs(); !slices.Equal(got, []ghclient.RepoRef{{Owner: "octo", Name: "hello"}, {Owner: "other", Name: "lib"}}) {
+		t.Fatalf("sections = %v, want octo/hello and other/lib", got)
+	}
+	if _, ok := final.sections[1].feed.PRs[5]; !ok || len(final.sections[1].feed.PRs) != 1 {
+		t.Errorf("other/lib PRs = %v, want only #5 (review requested)", final.sections[1].feed.PRNumbers())
+	}
+
+	// PRs come from the search alone: no repo listings, no runs lists.
+	for _, req := range srv.Requests() {
+		if req == "POST /graphql repoPullRequests" || strings.HasSuffix(req, "/actions/runs") {
+			t.Fatalf("unexpected request %q with --mine", req)
+		}
+	}
+}
@@ -4,33 +4,18 @@ import (
 	"time"
 
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // TickMsg is sent on each poll interval
 type TickMsg time.Time
 
-// PRInfoMsg contains PR metadata. HeadCommitTime was previously sourced from
-// the REST commit fetch in FetchPRInfo; that fetch has been removed (issue
-// #349) and the push time now arrives via ChecksUpdateMsg from the GraphQL
-// check-runs query (which selects pushedDate in the same round-trip).
-type PRInfoMsg struct {
-	Number    int
-	Title     string
-	HeadSHA   string
-	CreatedAt time.Time
-	Err       error
-}
+// PRInfoMsg contains PR metadata.
+type PRInfoMsg = watch.PRInfo
 
-// ChecksUpdateMsg contains updated check runs. HeadPushedTime carries the
-// PR head commit's push time (pushedDate, with committedDate fallback)
-// sourced from the same GraphQL query that produced CheckRuns. Callers
-// use it for the "Pushed Xs ago" header and the queue-latency column.
-type ChecksUpdateMsg struct {
-	CheckRuns          []ghclient.CheckRunInfo
-	HeadPushedTime     time.Time
-	RateLimitRemaining int
-	Err                error
-}
+// ChecksUpdateMsg contains updated check runs and the head commit's push
+// time, from the same GraphQL query.
+type ChecksUpdateMsg = watch.PRChecks
 
 // ErrorMsg contains error information
 type ErrorMsg struct {
@@ -48,9 +33,16 @@ type WorkflowsDiscoveredMsg struct {
 type JobAveragesPartialMsg struct {
 	WorkflowID int64
 	Averages   map[string]time.Duration
+	Flaky      map[string]ghclient.FlakyStats
 	Err        error
 }
 
+// WorkflowHistoriesMsg carries the results of one batched history fetch,
+// one JobAveragesPartialMsg per workflow, applied in order.
+type WorkflowHistoriesMsg struct {
+	Partials []JobAveragesPartialMsg
+}
+
 // CopilotReviewMsg carries the Copilot code review state for the PR's HEAD
 // commit. Copilot reviews live in PullRequest.reviews (not
 // StatusCheckRollup.Contexts), so they require a separate fetch path and
@@ -6,32 +6,25 @@ import (
 
 	"charm.land/bubbles/v2/spinner"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // Model holds the application state
 type Model struct {
 	ctx      context.Context
-	token    string
+	api      ghclient.API
 	owner    string
 	repo     string
 	prNumber int
 
-	// PR metadata
-	prTitle        string
-	headSHA        string
-	prCreatedAt    time.Time
-	headPushedTime time.Time
+	// The PR's metadata, checks and quota, kept current by its watch
+	feed watch.PR
 
-	// Check runs
-	checkRuns []ghclient.CheckRunInfo
+	// Webhook deliveries (--webhook-listen), when enabled
+	webhooks webhookState
 
-	// Rate limiting
-	rateLimitRemaining int
-	// fetchReceived is true after the first successful API response that
-	// populated rateLimitRemaining. Before that, rateLimitRemaining is the
-	// Go zero value (0) and the view must not render a misleading
-	// "[Rate limit: 0 remaining]" indicator or trigger backoff.
-	fetchReceived bool
+	// Notifications (--notify), when enabled
+	notify notifyState
 
 	// UI state
 	spinner         spinner.Model
@@ -39,6 +32,8 @@ type Model struct {
 	lastUpdate      time.Time
 	refreshInterval time.Duration
 	styles          Styles
+	// showTimeline swaps the check table for the Gantt timeline (t).
+	showTimeline bool
 
 	// Exit tracking
 	exitCode int
@@ -87,6 +82,11 @@ type Model struct {
 	// presumed_averages map.
 	presumedAverages map[string]time.Duration
 
+	// Flakiness scores per bare job name, merged from each workflow's
+	// history fetch alongside jobAverages. Drives the "flaky (...)" marker
+	// rendered next to failing checks.
+	flakyStats map[string]ghclient.FlakyStats
+
 	// Copilot code review detection (issue #409). PR mode only — run mode
 	// has no reviews object to query. copilotPending gates exit; copilotStale
 	// surfaces a warning. copilotWaitStartTime is set to PRInfoMsg time and
@@ -109,15 +109,16 @@ type Model struct {
 }
 
 // NewModel creates a new TUI model
-func NewModel(ctx context.Context, token, owner, repo string, prNumber int, refreshInterval time.Duration, styles Styles, enableLinks bool, noAvg bool, presumedAverages map[string]time.Duration, waitForCopilot bool, copilotMaxWait, copilotPollInterval, copilotInitialDelay time.Duration) Model {
+func NewModel(ctx context.Context, api ghclient.API, owner, repo string, prNumber int, refreshInterval time.Duration, styles Styles, enableLinks bool, noAvg bool, presumedAverages map[string]time.Duration, waitForCopilot bool, copilotMaxWait, copilotPollInterval, copilotInitialDelay time.Duration) Model {
 	s := spinner.New(spinner.WithSpinner(spinner.Dot))
 
 	return Model{
 		ctx:                     ctx,
-		token:                   token,
+		api:                     api,
 		owner:                   owner,
 		repo:                    repo,
 		prNumber:                prNumber,
+		feed:                    watch.NewPR(api, owner, repo, prNumber),
 		spinner:                 s,
 		startTime:               time.Now(),
 		lastUpdate:              time.Now(),
@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"context"
+	"reflect"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+)
+
+// Navigator is repo mode's top-level model: the overview (a RepoModel or
+// DashboardModel), which keeps polling, plus a stack of PR and run views
+// opened with enter and closed with esc. A view's messages are tagged with
+// its ID, so a popped view's late ones are dropped.
+type Navigator struct {
+	ctx      context.Context
+	overview Overview
+	openPR   func(ctx context.Context, owner, repo string, prNumber int) tea.Model
+	openRun  func(ctx context.Context, owner, repo string, runID int64) tea.Model
+
+	stack  []navView
+	nextID int
+}
+
+// Overview is a model the Navigator drills down from.
+type Overview interface {
+	tea.Model
+	ExitCode() int
+
+	// selectedTarget returns the selected PR or run, if any.
+	selectedTarget() (navTarget, bool)
+	// capturingKeys reports whether a prompt is open and owns enter.
+	capturingKeys() bool
+	// overviewName names the overview in the hint for getting back to it.
+	overviewName() string
+	overviewStyles() Styles
+}
+
+// navTarget is a PR or run to open, in the repo it belongs to.
+type navTarget struct {
+	owner, repo string
+	item        repoItem
+}
+
+// navView is one pushed view. finished is set once the view has quit on
+// its own (a PR whose checks all completed); it stays on screen, frozen,
+// until popped.
+type navView struct {
+	id       int
+	model    tea.Model
+	cancel   context.CancelFunc
+	finished bool
+}
+
+// navChildMsg carries a message for the pushed view with the given ID.
+type navChildMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// NewNavigator wraps overview for drill-down. openPR and openRun build the
+// views pushed for a PR group or a standalone run.
+func NewNavigator(ctx context.Context, overview Overview, openPR func(ctx context.Context, owner, repo string, prNumber int) tea.Model, openRun func(ctx context.Context, owner, repo string, runID int64) tea.Model) Navigator {
+	return Navigator{ctx: ctx, overview: overview, openPR: openPR, openRun: openRun}
+}
+
+// ExitCode implements the exit-code contract of the other models. Repo mode
+// is persistent, so this is the overview's (always 0).
+func (n Navigator) ExitCode() int {
+	return n.overview.ExitCode()
+}
+
+// Init starts the overview.
+func (n Navigator) Init() tea.Cmd {
+	return n.overview.Init()
+}
+
+// Update routes keys to the view on top, child messages to their view, and
+// everything else to the overview.
+func (n Navigator) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if len(n.stack) > 0 {
+			return n.updateTopKey(msg)
+		}
+		if msg.String() == "enter" && !n.overview.capturingKeys() {
+			if target, ok := n.overview.selectedTarget(); ok {
+				return n.push(target)
+			}
+		}
+
+	case navChildMsg:
+		return n.updateChild(msg)
+	}
+
+	model, cmd := n.overview.Update(msg)
+	n.overview = model.(Overview)
+	return n, cmd
+}
+
+// updateTopKey handles a key while a view is pushed: esc pops it, q and
+// ctrl+c quit the program (not just the view, which would otherwise quit
+// itself), and everything else goes to the view. While the view has a
+// prompt open, esc and q are the prompt's.
+func (n Navigator) updateTopKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	top := &n.stack[len(n.stack)-1]
+	prompt, ok := top.model.(interface{ capturingKeys() bool })
+	capturing := ok && !top.finished && prompt.capturingKeys()
+	switch msg.String() {
+	case "esc":
+		if !capturing {
+			return n.pop(), nil
+		}
+	case "q":
+		if !capturing {
+			return n, tea.Quit
+		}
+	case "ctrl+c":
+		return n, tea.Quit
+	}
+	if top.finished {
+		return n, nil
+	}
+	model, cmd := top.model.Update(msg)
+	top.model = model
+	return n, wrapChildCmd(top.id, cmd)
+}
+
+// updateChild delivers a wrapped message to its view, if still pushed. A
+// quit only marks the view finished.
+func (n Navigator) updateChild(msg navChildMsg) (tea.Model, tea.Cmd) {
+	i := n.indexOf(msg.id)
+	if i < 0 {
+		return n, nil
+	}
+	if _, ok := msg.msg.(tea.QuitMsg); ok {
+		n.stack[i].finished = true
+		return n, nil
+	}
+	if n.stack[i].finished {
+		return n, nil
+	}
+	model, cmd := n.stack[i].model.Update(msg.msg)
+	n.stack[i].model = model
+	return n, wrapChildCmd(msg.id, cmd)
+}
+
+// push opens target's full view on top of the stack.
+func (n Navigator) push(target navTarget) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(n.ctx)
+	item := target.item
+	var model tea.Model
+	switch {
+	case item.prNumber != 0:
+		model = n.openPR(ctx, target.owner, target.repo, item.prNumber)
+	default:
+		model = n.openRun(ctx, target.owner, target.repo, item.runID)
+	}
+	n.nextID++
+	view := navView{id: n.nextID, model: model, cancel: cancel}
+	n.stack = append(n.stack, view)
+	debug.Log("drill down", "repo", target.owner+"/"+target.repo, "pr", item.prNumber, "run", item.runID, "depth", len(n.stack))
+	return n, wrapChildCmd(view.id, model.Init())
+}
+
+// pop closes the top view, cancelling its in-flight fetches.
+func (n Navigator) pop() Navigator {
+	top := n.stack[len(n.stack)-1]
+	top.cancel()
+	n.stack = n.stack[:len(n.stack)-1]
+	debug.Log("drill up", "depth", len(n.stack))
+	return n
+}
+
+func (n Navigator) indexOf(id int) int {
+	for i, v := range n.stack {
+		if v.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// wrapChildCmd tags cmd's message for the pushed view id.
+func wrapChildCmd(id int, cmd tea.Cmd) tea.Cmd {
+	return wrapCmd(cmd, func(msg tea.Msg) tea.Msg { return navChildMsg{id: id, msg: msg} })
+}
+
+// wrapCmd tags cmd's message with tag. A batch or sequence is rebuilt with
+// each of its commands tagged, so the runtime still runs them (in order,
+// for a sequence); raw output passes through for the program to print.
+func wrapCmd(cmd tea.Cmd, tag func(tea.Msg) tea.Msg) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if raw, ok := msg.(tea.RawMsg); ok {
+			return raw
+		}
+		if cmds, ok := sequenceCmds(msg); ok {
+			wrapped := make([]tea.Cmd, 0, len(cmds))
+			for _, c := range cmds {
+				wrapped = append(wrapped, wrapCmd(c, tag))
+			}
+			// Same type as msg: a tea.BatchMsg or bubbletea's unexported
+			// sequence message.
+			return reflect.ValueOf(wrapped).Convert(reflect.TypeOf(msg)).Interface()
+		}
+		return tag(msg)
+	}
+}
+
+// sequenceCmds returns the commands of a tea.Batch or tea.Sequence's
+// message; bubbletea doesn't export the sequence's type.
+func sequenceCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeFor[tea.Cmd]() {
+		return nil, false
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
+
+// View renders the top view with a line explaining how to get back, or the
+// overview when nothing is pushed.
+func (n Navigator) View() tea.View {
+	if len(n.stack) == 0 {
+		return n.overview.View()
+	}
+	top := n.stack[len(n.stack)-1]
+	content := top.model.View().Content
+	hint := "Press esc to return to the " + n.overview.overviewName() + " overview, q to quit"
+	if top.finished {
+		hint = "Finished. " + hint
+	}
+	return tea.NewView(content + "\n" + n.overview.overviewStyles().Queued.Render(hint) + "\n")
+}
+
+// selectedTarget, capturingKeys, overviewName and overviewStyles make
+// RepoModel an Overview.
+func (m RepoModel) selectedTarget() (navTarget, bool) {
+	item, ok := m.selectedItem()
+	return navTarget{owner: m.owner, repo: m.repo, item: item}, ok
+}
+
+func (m RepoModel) capturingKeys() bool { return m.prompt.open || m.review.open }
+
+func (m RepoModel) overviewName() string { return m.owner + "/" + m.repo }
+
+func (m RepoModel) overviewStyles() Styles { return m.styles }
+
+// repoModelOf unwraps a repo model; its message handlers use pointer
+// receivers, so Update may hand back either form.
+func repoModelOf(m tea.Model) RepoModel {
+	if p, ok := m.(*RepoModel); ok {
+		return *p
+	}
+	return m.(RepoModel)
+}
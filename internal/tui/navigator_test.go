@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// stubView stands in for a pushed PR or run view, recording what reaches it.
+type stubView struct {
+	name string
+	got  *[]tea.Msg
+}
+
+type stubTickMsg struct{}
+
+func (s stubView) Init() tea.Cmd {
+	return func() tea.Msg { return stubTickMsg{} }
+}
+
+func (s stubView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	*s.got = append(*s.got, msg)
+	return s, nil
+}
+
+func (s stubView) View() tea.View {
+	return tea.NewView("stub " + s.name)
+}
+
+// promptView is a stubView with a prompt open.
+type promptView struct {
+	stubView
+}
+
+func (p promptView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	*p.got = append(*p.got, msg)
+	return p, nil
+}
+
+func (p promptView) capturingKeys() bool { return true }
+
+func newNavTestRepoModel() RepoModel {
+	m := NewRepoModel(
+		context.Background(), nil, "o", "r",
+		30*time.Second, NewStyles(10, 9, 11, 8), true,
+		15*time.Minute, 30*time.Minute,
+	)
+	m.feed.PRs[3] = PRViewData{Title: "three"}
+	m.feed.PRs[1] = PRViewData{Title: "one"}
+	m.feed.Runs = []ghclient.BranchRunData{{RunID: 200, HeadBranch: "main", WorkflowName: "Nightly"}}
+	return m
+}
+
+func TestRepoSelection(t *testing.T) {
+	m := newNavTestRepoModel()
+	if _, ok := m.selectedItem(); ok {
+		t.Fatal("selection before any movement")
+	}
+
+	steps := []struct {
+		delta int
+		want  repoItem
+	}{
+		{1, repoItem{prNumber: 1}}, // first movement starts at the top
+		{1, repoItem{prNumber: 3}},
+		{1, repoItem{runID: 200}},
+		{1, repoItem{runID: 200}}, // clamped at the bottom
+		{-1, repoItem{prNumber: 3}},
+	}
+	for i, step := range steps {
+		m.moveSelection(step.delta)
+		if got, ok := m.selectedItem(); !ok || got != step.want {
+			t.Fatalf("step %d: selected = %+v (%v), want %+v", i, got, ok, step.want)
+		}
+	}
+
+	// The selected PR fading out clears the selection; moving restarts at
+	// the top.
+	delete(m.feed.PRs, 3)
+	if _, ok := m.selectedItem(); ok {
+		t.Error("faded PR still selected")
+	}
+	m.moveSelection(1)
+	if got, _ := m.selectedItem(); got != (repoItem{prNumber: 1}) {
+		t.Errorf("after fade: selected = %+v, want PR #1", got)
+	}
+}
+
+func TestNavigatorDrillDown(t *testing.T) {
+	var got []tea.Msg
+	var viewCtx context.Context
+	var opened []string
+	nav := NewNavigator(context.Background(), newNavTestRepoModel(),
+		func(ctx context.Context, owner, repo string, prNumber int) tea.Model {
+			viewCtx = ctx
+			opened = append(opened, "pr")
+			return stubView{name: "pr", got: &got}
+		},
+		func(ctx context.Context, owner, repo string, runID int64) tea.Model {
+			viewCtx = ctx
+			opened = append(opened, "run")
+			return stubView{name: "run", got: &got}
+		},
+	)
+
+	// enter with nothing selected does nothing.
+	var model tea.Model = nav
+	model, _ = pressKeys(t, model, "", tea.KeyEnter)
+	if len(opened) != 0 {
+		t.Fatalf("opened %v with nothing selected", opened)
+	}
+
+	// Select the standalone run and open it.
+	model, _ = pressKeys(t, model, "jjj")
+	model, cmd := pressKeys(t, model, "", tea.KeyEnter)
+	if len(opened) != 1 || opened[0] != "run" || cmd == nil {
+		t.Fatalf("opened = %v, cmd = %v; want the run view started", opened, cmd)
+	}
+	initMsg := cmd()
+	model, _ = model.Update(initMsg)
+	if len(got) != 1 || got[0] != (stubTickMsg{}) {
+		t.Fatalf("view got %v, want its own init message", got)
+	}
+	view := model.View().Content
+	if !strings.Contains(view, "stub run") || !strings.Contains(view, "Press esc to return to the o/r overview") {
+		t.Errorf("view = %q, want the run view with a way back", view)
+	}
+
+	// Other keys reach the view; the overview still gets its own updates.
+	model, _ = pressKeys(t, model, "x")
+	if len(got) != 2 {
+		t.Errorf("view got %v, want the key too", got)
+	}
+	model, _ = model.Update(RepoChecksUpdateMsg{PRData: map[int]ghclient.PRCheckData{1: {Number: 1}}})
+	if nav := model.(Navigator); !repoModelOf(nav.overview).feed.Quota.Received {
+		t.Error("overview update not applied while drilled in")
+	}
+
+	// esc pops the view and cancels it; its late messages are dropped.
+	model, _ = pressKeys(t, model, "", tea.KeyEscape)
+	if nav := model.(Navigator); len(nav.stack) != 0 {
+		t.Fatalf("stack = %d after esc, want 0", len(nav.stack))
+	}
+	if viewCtx.Err() == nil {
+		t.Error("popped view's context not cancelled")
+	}
+	model, _ = model.Update(initMsg)
+	if len(got) != 2 {
+		t.Errorf("popped view got %v", got[2:])
+	}
+	if view := model.View().Content; strings.Contains(view, "stub") {
+		t.Errorf("view = %q, want the overview back", view)
+	}
+
+	// A view that quits on its own stays up, frozen, instead of ending the
+	// program.
+	model, _ = pressKeys(t, model, "", tea.KeyEnter)
+	model, _ = model.Update(navChildMsg{id: 2, msg: tea.QuitMsg{}})
+	if nav := model.(Navigator); len(nav.stack) != 1 || !nav.stack[0].finished {
+		t.Fatalf("stack = %+v, want the view kept and marked finished", nav.stack)
+	}
+	if !strings.Contains(model.View().Content, "Finished.") {
+		t.Error("finished view not marked")
+	}
+
+	// q quits the program from a pushed view.
+	_, cmd = pressKeys(t, model, "q")
+	if cmd == nil || cmd() != (tea.QuitMsg{}) {
+		t.Error("q in a pushed view didn't quit")
+	}
+}
+
+func TestNavigatorLeavesKeysToPrompt(t *testing.T) {
+	var got []tea.Msg
+	open := func(ctx context.Context, owner, repo string, runID int64) tea.Model {
+		return promptView{stubView{name: "run", got: &got}}
+	}
+	var model tea.Model = NewNavigator(context.Background(), newNavTestRepoModel(), nil, open)
+	model, _ = pressKeys(t, model, "jjj", tea.KeyEnter)
+
+	// esc and q are typed into the view's prompt rather than popping it
+	// or quitting; ctrl+c still quits.
+	model, cmd := pressKeys(t, model, "q", tea.KeyEscape)
+	if nav := model.(Navigator); len(nav.stack) != 1 || len(got) != 2 || cmd != nil {
+		t.Fatalf("stack %d, view got %v; want esc and q delivered to the view", len(nav.stack), got)
+	}
+	_, cmd = model.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl})
+	if cmd == nil || cmd() != (tea.QuitMsg{}) {
+		t.Error("ctrl+c with a prompt open didn't quit")
+	}
+}
+
+// seqView starts with a two-step sequence, as a view notifying ahead of
+// its next fetch does.
+type seqView struct {
+	stubView
+}
+
+type seqStepMsg int
+
+func (s seqView) Init() tea.Cmd {
+	return tea.Sequence(
+		func() tea.Msg { return seqStepMsg(1) },
+		func() tea.Msg { return seqStepMsg(2) },
+	)
+}
+
+func TestNavigatorKeepsChildSequence(t *testing.T) {
+	var got []tea.Msg
+	open := func(ctx context.Context, owner, repo string, runID int64) tea.Model {
+		return seqView{stubView{name: "run", got: &got}}
+	}
+	var model tea.Model = NewNavigator(context.Background(), newNavTestRepoModel(), nil, open)
+	model, _ = pressKeys(t, model, "jjj")
+	model, cmd := pressKeys(t, model, "", tea.KeyEnter)
+
+	// The runtime gets a sequence back, each step tagged for the view.
+	steps, ok := sequenceCmds(cmd())
+	if !ok || len(steps) != 2 {
+		t.Fatalf("init = %v, want a two-step sequence", steps)
+	}
+	for _, step := range steps {
+		model, _ = model.Update(step())
+	}
+	if len(got) != 2 || got[0] != seqStepMsg(1) || got[1] != seqStepMsg(2) {
+		t.Errorf("view got %v, want both steps in order", got)
+	}
+}
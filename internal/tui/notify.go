@@ -0,0 +1,310 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/fini-net/gh-observer/internal/eventlog"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/notify"
+)
+
+// notifyState is a model's part in notifications: the notifier, and what
+// it has seen so far, so each moment is announced once and nothing that
+// happened before the watch started is announced at all.
+type notifyState struct {
+	notifier *notify.Notifier
+	// primed lists the sources ("checks", "runs", "jobs", "copilot")
+	// whose first update has arrived. That update shows the state the
+	// watch started in, so it only fills seen.
+	primed map[string]bool
+	// seen holds the failed checks and finished runs already accounted
+	// for, and the subjects seen running (see markRunning).
+	seen map[string]bool
+}
+
+// live reports whether an update from source can notify: every one but
+// the first.
+func (s *notifyState) live(source string) bool {
+	if s.primed == nil {
+		s.primed = make(map[string]bool)
+	}
+	live := s.primed[source]
+	s.primed[source] = true
+	return live
+}
+
+// reprime makes the next update from every source count as the first
+// again, for when what the model watches changes (a new filter).
+func (s *notifyState) reprime() {
+	s.primed = nil
+}
+
+// once remembers key, reporting whether it is new on a live update.
+func (s *notifyState) once(key string, live bool) bool {
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	if s.seen[key] {
+		return false
+	}
+	s.seen[key] = true
+	return live
+}
+
+// markRunning records that subject's checks or jobs were seen unfinished.
+func (s *notifyState) markRunning(subject string) {
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	s.seen["running "+subject] = true
+}
+
+// finished reports whether subject was seen running, clearing the mark, so
+// a PR or run that was already done when the watch started (or that
+// finishes again) isn't announced twice.
+func (s *notifyState) finished(subject string) bool {
+	key := "running " + subject
+	if !s.seen[key] {
+		return false
+	}
+	delete(s.seen, key)
+	return true
+}
+
+// firstFailure returns the failed check to announce when checks has its
+// first failure: there are newly failed checks on a live update and none
+// failed before. Every failed check is remembered, keyed by subject.
+func (s *notifyState) firstFailure(subject string, checks []ghclient.CheckRunInfo, live bool) (ghclient.CheckRunInfo, bool) {
+	var first ghclient.CheckRunInfo
+	failed, fresh := 0, 0
+	for _, cr := range checks {
+		if cr.Status != "completed" || !ghclient.FailureConclusion(cr.Conclusion) {
+			continue
+		}
+		failed++
+		if s.once("failed "+subject+" "+checkKey(cr), true) {
+			if fresh == 0 {
+				first = cr
+			}
+			fresh++
+		}
+	}
+	return first, live && fresh > 0 && fresh == failed
+}
+
+// send delivers note in the background. Terminal backends' escape
+// sequences come back as a tea.RawMsg, which the program prints; errors
+// are only logged, since a missed notification shouldn't interrupt the
+// watch.
+func (s *notifyState) send(ctx context.Context, note notify.Notification) tea.Cmd {
+	if !s.notifier.Enabled(note.Trigger) {
+		return nil
+	}
+	notifier := s.notifier
+	return func() tea.Msg {
+		seq, err := notifier.Send(ctx, note)
+		if err != nil {
+			debug.Log("notification failed", "trigger", note.Trigger, "title", note.Title, "err", err)
+		}
+		if seq == "" {
+			return nil
+		}
+		return tea.RawMsg{Msg: seq}
+	}
+}
+
+// failureNote announces a check's failure: "CI / test failed".
+func failureNote(title string, cr ghclient.CheckRunInfo) notify.Notification {
+	return notify.Notification{
+		Trigger: notify.FirstFailure,
+		Title:   title,
+		Body:    FormatCheckName(cr) + " " + eventlog.OutcomeVerb(cr.Conclusion),
+	}
+}
+
+// doneNote announces every check (or job) finishing: "12 checks finished,
+// 2 failed".
+func doneNote(title, noun string, checks []ghclient.CheckRunInfo) notify.Notification {
+	failed := 0
+	for _, cr := range checks {
+		if ghclient.FailureConclusion(cr.Conclusion) {
+			failed++
+		}
+	}
+	outcome := "none failed"
+	if failed > 0 {
+		outcome = fmt.Sprintf("%d failed", failed)
+	}
+	return notify.Notification{
+		Trigger: notify.PRDone,
+		Title:   title,
+		Body:    fmt.Sprintf("%d %s%s finished, %s", len(checks), noun, pluralS(len(checks)), outcome),
+	}
+}
+
+// mainFailedNote announces a failed run on a main branch.
+func mainFailedNote(repo, branch, name, conclusion string) notify.Notification {
+	return notify.Notification{
+		Trigger: notify.MainFailed,
+		Title:   repo + " " + branch,
+		Body:    name + " " + eventlog.OutcomeVerb(conclusion),
+	}
+}
+
+// WithNotifier makes the PR watch notify through n (nil turns notifications
+// off).
+func (m Model) WithNotifier(n *notify.Notifier) Model {
+	m.notify = notifyState{notifier: n}
+	return m
+}
+
+// notifyTitle names the watched PR in notifications.
+func (m Model) notifyTitle() string {
+	return fmt.Sprintf("%s/%s PR #%d", m.owner, m.repo, m.prNumber)
+}
+
+// notifyChecks announces the first failure among the PR's checks, and
+// notes whether any is still running for notifyDone.
+func (m *Model) notifyChecks() tea.Cmd {
+	live := m.notify.live("checks")
+	if !allChecksComplete(m.feed.CheckRuns) {
+		m.notify.markRunning("pr")
+	}
+	if cr, ok := m.notify.firstFailure("pr", m.feed.CheckRuns, live); ok {
+		return m.notify.send(m.ctx, failureNote(m.notifyTitle(), cr))
+	}
+	return nil
+}
+
+// notifyDone announces the PR's checks finishing, once the watch is sure
+// they have. A PR already done when the watch started isn't announced.
+func (m *Model) notifyDone() tea.Cmd {
+	if !m.notify.finished("pr") {
+		return nil
+	}
+	return m.notify.send(m.ctx, doneNote(m.notifyTitle(), "check", m.feed.CheckRuns))
+}
+
+// notifyCopilotReview announces a Copilot review of the head commit,
+// unless it was already there when the watch started.
+func (m *Model) notifyCopilotReview(msg CopilotReviewMsg) tea.Cmd {
+	live := m.notify.live("copilot")
+	if msg.Pending || msg.Stale || msg.State == "" || !m.notify.once("copilot "+m.feed.HeadSHA, live) {
+		return nil
+	}
+	return m.notify.send(m.ctx, notify.Notification{
+		Trigger: notify.CopilotReview,
+		Title:   m.notifyTitle(),
+		Body:    "Copilot reviewed: " + strings.ReplaceAll(msg.State, "_", " "),
+	})
+}
+
+// WithNotifier makes the run watch notify through n (nil turns
+// notifications off).
+func (m RunModel) WithNotifier(n *notify.Notifier) RunModel {
+	m.notify = notifyState{notifier: n}
+	return m
+}
+
+// notifyJobs announces the run's first failed job and, once every job is
+// done, the run finishing and, on a main branch, failing, in that order. A
+// run already done when the watch started isn't announced.
+func (m *RunModel) notifyJobs() tea.Cmd {
+	live := m.notify.live("jobs")
+	title := fmt.Sprintf("%s/%s %s", m.owner, m.repo, m.runInfo.HeadBranch)
+	jobs := ghclient.WorkflowJobInfoToCheckRuns(m.jobs)
+
+	var cmds []tea.Cmd
+	if cr, ok := m.notify.firstFailure("run", jobs, live); ok {
+		cmds = append(cmds, m.notify.send(m.ctx, failureNote(title, cr)))
+	}
+	if !ghclient.AllJobsComplete(m.jobs) {
+		m.notify.markRunning("run")
+		return tea.Sequence(cmds...)
+	}
+	if m.notify.finished("run") {
+		note := doneNote(title, "job", jobs)
+		note.Body = m.runInfo.DisplayTitle + ": " + note.Body
+		cmds = append(cmds, m.notify.send(m.ctx, note))
+		if m.exitCode != 0 && m.notify.notifier.MainBranch(m.runInfo.HeadBranch) {
+			name := m.runInfo.DisplayTitle
+			cmds = append(cmds, m.notify.send(m.ctx, mainFailedNote(m.owner+"/"+m.repo, m.runInfo.HeadBranch, name, "failure")))
+		}
+	}
+	return tea.Sequence(cmds...)
+}
+
+// WithNotifier makes repo mode notify through n (nil turns notifications
+// off): a watched PR's first failure and all its checks finishing, and a
+// run failing on a main branch.
+func (m RepoModel) WithNotifier(n *notify.Notifier) RepoModel {
+	m.notify = notifyState{notifier: n}
+	return m
+}
+
+// notifyChecks announces each PR's first failed check, and each PR whose
+// checks were seen running finishing.
+func (m *RepoModel) notifyChecks(prs map[int]ghclient.PRCheckData) tea.Cmd {
+	live := m.notify.live("checks")
+	repo := m.ref().String()
+	var cmds []tea.Cmd
+	for _, prNum := range slices.Sorted(maps.Keys(prs)) {
+		checks := prs[prNum].CheckRuns
+		subject := fmt.Sprintf("#%d", prNum)
+		title := fmt.Sprintf("%s PR #%d", repo, prNum)
+		if cr, ok := m.notify.firstFailure(subject, checks, live); ok {
+			cmds = append(cmds, m.notify.send(m.ctx, failureNote(title, cr)))
+		}
+		if !allChecksComplete(checks) {
+			if len(checks) > 0 {
+				m.notify.markRunning(subject)
+			}
+			continue
+		}
+		if m.notify.finished(subject) {
+			cmds = append(cmds, m.notify.send(m.ctx, doneNote(title, "check", checks)))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// notifyRuns announces each run that fails on a main branch. Runs on a
+// PR's head commit are left to notifyChecks.
+func (m *RepoModel) notifyRuns(runs []ghclient.BranchRunData) tea.Cmd {
+	live := m.notify.live("runs")
+	if !m.notify.notifier.Enabled(notify.MainFailed) {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, run := range runs {
+		if run.Status != "completed" || !ghclient.FailureConclusion(run.Conclusion) ||
+			m.feed.PRHeadSHAs[run.HeadSHA] || !m.notify.notifier.MainBranch(run.HeadBranch) {
+			continue
+		}
+		if !m.notify.once(fmt.Sprintf("run %d/%d", run.RunID, run.RunAttempt), live) {
+			continue
+		}
+		name := run.WorkflowName
+		if name == "" {
+			name = run.DisplayTitle
+		}
+		cmds = append(cmds, m.notify.send(m.ctx, mainFailedNote(m.ref().String(), run.HeadBranch, name, run.Conclusion)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// WithNotifier makes every section notify through n; see
+// RepoModel.WithNotifier.
+func (m DashboardModel) WithNotifier(n *notify.Notifier) DashboardModel {
+	m.notifier = n
+	for i := range m.sections {
+		m.sections[i].notify = notifyState{notifier: n}
+	}
+	return m
+}
@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/fakegithub"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/notify"
+)
+
+// noteRecorder is a notification backend that keeps what it is sent.
+type noteRecorder struct {
+	notes []notify.Notification
+}
+
+func (r *noteRecorder) Name() string { return "recorder" }
+
+func (r *noteRecorder) Notify(_ context.Context, n notify.Notification) (string, error) {
+	r.notes = append(r.notes, n)
+	return "", nil
+}
+
+// newRecordingNotifier returns a notifier for every trigger, recording
+// into the returned recorder.
+func newRecordingNotifier() (*notify.Notifier, *noteRecorder) {
+	rec := &noteRecorder{}
+	return notify.New([]notify.Backend{rec}, notify.Triggers, nil), rec
+}
+
+func TestPRWatchNotifiesEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	now := srv.Now()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", CreatedAt: now.Add(20 * time.Second),
+		Jobs: []fakegithub.Job{
+			{Name: "lint", StartedAfter: 10 * time.Second, Duration: 30 * time.Second, Conclusion: "failure"},
+			{Name: "test", StartedAfter: 10 * time.Second, Duration: 2 * time.Minute, Conclusion: "failure"},
+		},
+	})
+	notifier, rec := newRecordingNotifier()
+
+	model := NewModel(context.Background(), api, "octo", "hello", 12, time.Millisecond,
+		stylesForTest(), false, true, nil, false, 0, 0, 0).WithNotifier(notifier)
+	drive(t, model, srv, 15*time.Second, 500, nil)
+
+	// lint failing first is announced; test failing later isn't.
+	want := []notify.Notification{
+		{Trigger: notify.FirstFailure, Title: "octo/hello PR #12", Body: "CI / lint failed"},
+		{Trigger: notify.PRDone, Title: "octo/hello PR #12", Body: "2 checks finished, 2 failed"},
+	}
+	if !reflect.DeepEqual(rec.notes, want) {
+		t.Errorf("notifications = %+v, want %+v", rec.notes, want)
+	}
+}
+
+func TestPRWatchAlreadyDoneDoesNotNotify(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	now := srv.Now()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123", CreatedAt: now.Add(-time.Hour),
+		Jobs: []fakegithub.Job{{Name: "test", Duration: time.Minute, Conclusion: "failure"}},
+	})
+	notifier, rec := newRecordingNotifier()
+
+	model := NewModel(context.Background(), api, "octo", "hello", 12, time.Millisecond,
+		stylesForTest(), false, true, nil, false, 0, 0, 0).WithNotifier(notifier)
+	drive(t, model, srv, 15*time.Second, 500, nil)
+
+	if len(rec.notes) != 0 {
+		t.Errorf("notifications = %+v, want none for a PR that was done before the watch", rec.notes)
+	}
+}
+
+func TestRunWatchNotifiesMainFailure(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "Release", HeadSHA: "abc123", HeadBranch: "main", Event: "push",
+		Jobs: []fakegithub.Job{{Name: "publish", StartedAfter: 5 * time.Second, Duration: 40 * time.Second, Conclusion: "failure"}},
+	})
+	notifier, rec := newRecordingNotifier()
+
+	model := NewRunModel(context.Background(), api, "octo", "hello", 100, time.Millisecond,
+		stylesForTest(), false, true, nil).WithNotifier(notifier)
+	drive(t, model, srv, 10*time.Second, 500, nil)
+
+	want := []notify.Notification{
+		{Trigger: notify.FirstFailure, Title: "octo/hello main", Body: "Release / publish failed"},
+		{Trigger: notify.PRDone, Title: "octo/hello main", Body: "Release: 1 job finished, 1 failed"},
+		{Trigger: notify.MainFailed, Title: "octo/hello main", Body: "Release failed"},
+	}
+	if !reflect.DeepEqual(rec.notes, want) {
+		t.Errorf("notifications = %+v, want %+v", rec.notes, want)
+	}
+}
+
+func TestRepoWatchNotifiesEndToEnd(t *testing.T) {
+	srv, api := newFakeGitHub(t)
+	now := srv.Now()
+	srv.AddPullRequest(fakegithub.PullRequest{Number: 12, Title: "Add widgets", HeadSHA: "abc123"})
+	srv.AddRun(fakegithub.Run{
+		ID: 100, WorkflowID: 7, Name: "CI", HeadSHA: "abc123",
+		Jobs: []fakegithub.Job{{Name: "build", StartedAfter: 10 * time.Second, Duration: time.Minute, Conclusion: "failure"}},
+	})
+	// Failed before the watch started: not news.
+	srv.AddRun(fakegithub.Run{
+		ID: 200, WorkflowID: 8, Name: "Deploy", HeadSHA: "old", HeadBranch: "main", Event: "push", CreatedAt: now.Add(-5 * time.Minute),
+		Jobs: []fakegithub.Job{{Name: "ship", Duration: time.Minute, Conclusion: "failure"}},
+	})
+	srv.AddRun(fakegithub.Run{
+		ID: 201, WorkflowID: 8, Name: "Deploy", HeadSHA: "def456", HeadBranch: "main", Event: "push", CreatedAt: now.Add(30 * time.Second),
+		Jobs: []fakegithub.Job{{Name: "ship", StartedAfter: 5 * time.Second, Duration: time.Minute, Conclusion: "failure"}},
+	})
+	// Not a main branch.
+	srv.AddRun(fakegithub.Run{
+		ID: 300, WorkflowID: 8, Name: "Deploy", HeadSHA: "fed789", HeadBranch: "staging", Event: "push", CreatedAt: now.Add(30 * time.Second),
+		Jobs: []fakegithub.Job{{Name: "ship", StartedAfter: 5 * time.Second, Duration: time.Minute, Conclusion: "failure"}},
+	})
+	notifier, rec := newRecordingNotifier()
+
+	model := NewRepoModel(context.Background(), api, "octo", "hello", time.Millisecond,
+		stylesForTest(), false, 15*time.Minute, 30*time.Minute).WithNotifier(notifier)
+	drive(t, model, srv, 15*time.Second, 500, func(m tea.Model) bool {
+		return len(rec.notes) == 3
+	})
+
+	// The two polls race, so the order between them isn't fixed.
+	want := map[notify.Trigger]notify.Notification{
+		notify.FirstFailure: {Trigger: notify.FirstFailure, Title: "octo/hello PR #12", Body: "CI / build failed"},
+		notify.PRDone:       {Trigger: notify.PRDone, Title: "octo/hello PR #12", Body: "1 check finished, 1 failed"},
+		notify.MainFailed:   {Trigger: notify.MainFailed, Title: "octo/hello main", Body: "Deploy failed"},
+	}
+	for _, note := range rec.notes {
+		if note != want[note.Trigger] {
+			t.Errorf("notification %+v, want %+v", note, want[note.Trigger])
+		}
+		delete(want, note.Trigger)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing notifications %+v", want)
+	}
+}
+
+func TestNotifyCopilotReview(t *testing.T) {
+	tests := []struct {
+		name string
+		msgs []CopilotReviewMsg
+		want int
+	}{
+		{name: "review posted during the watch", msgs: []CopilotReviewMsg{{NotRequested: true}, {Pending: true, State: "pending"}, {State: "commented"}}, want: 1},
+		{name: "review already there", msgs: []CopilotReviewMsg{{State: "approved"}, {State: "approved"}}, want: 0},
+		{name: "stale review", msgs: []CopilotReviewMsg{{Pending: true}, {State: "approved", Stale: true}}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, rec := newRecordingNotifier()
+			m := makeModel().WithNotifier(notifier)
+			m.waitForCopilot = true
+			m.copilotPending = true
+			m.feed.HeadSHA = "abc123"
+			for _, msg := range tt.msgs {
+				model, cmd := m.handleCopilotReview(msg)
+				m = *model.(*Model)
+				if cmd != nil {
+					cmd()
+				}
+			}
+			if len(rec.notes) != tt.want {
+				t.Fatalf("notifications = %+v, want %d", rec.notes, tt.want)
+			}
+			if tt.want > 0 && rec.notes[0].Body != "Copilot reviewed: commented" {
+				t.Errorf("body = %q, want %q", rec.notes[0].Body, "Copilot reviewed: commented")
+			}
+		})
+	}
+}
+
+func TestDashboardPassesNotificationsThrough(t *testing.T) {
+	raw := tea.RawMsg{Msg: "\a"}
+	cmd := wrapSectionCmd(ghclient.RepoRef{Owner: "octo", Name: "hello"}, func() tea.Msg { return raw })
+	if got := cmd(); got != raw {
+		t.Errorf("wrapped section command returned %#v, want the raw message unwrapped", got)
+	}
+}
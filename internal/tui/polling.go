@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
+)
+
+// nextPollInterval is the adaptive interval for PR mode.
+func (m Model) nextPollInterval(now time.Time) time.Duration {
+	return m.feed.NextPoll(m.refreshInterval, m.jobAverages, now)
+}
+
+// nextPollInterval is the adaptive interval for run mode. One poll is one
+// REST jobs list.
+func (m RunModel) nextPollInterval(now time.Time) time.Duration {
+	var pushed time.Time
+	if m.runInfo.HeadPushedTime != nil {
+		pushed = m.runInfo.HeadPushedTime.Time
+	}
+	checks := ghclient.WorkflowJobInfoToCheckRuns(m.jobs)
+	return watch.Interval(m.refreshInterval, watch.PollState{
+		Pushed:      pushed,
+		Active:      !m.fetchReceived || watch.AnyActive(checks),
+		NextFinish:  watch.NextExpectedFinish(checks, m.jobAverages, now),
+		Remaining:   m.rateLimitRemaining,
+		Received:    m.fetchReceived,
+		ResetAt:     watch.ResetAt(m.api, ghclient.ResourceCore),
+		CostPerPoll: 1,
+	}, now)
+}
+
+// nextPollInterval is the adaptive interval for repo mode, counting the
+// runner listings while that pane is shown.
+func (m *RepoModel) nextPollInterval(now time.Time) time.Duration {
+	extra := m.runners.costPerPoll([]ghclient.RepoRef{m.ref()})
+	return m.feed.NextPoll(m.refreshInterval, m.workflowAverages, extra, now)
+}
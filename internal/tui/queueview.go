@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/timing"
+	"github.com/mattn/go-runewidth"
+)
+
+// queueLabelStats is one runner label's row: Current* from the jobs on
+// screen, Hist* from recent runs. A zero count means no samples.
+type queueLabelStats struct {
+	Label        string
+	CurrentCount int
+	CurrentP50   time.Duration
+	CurrentP90   time.Duration
+	HistCount    int
+	HistP50      time.Duration
+	HistP90      time.Duration
+}
+
+// summarizeQueueByLabel merges current and historical samples into one row
+// per label, sorted by the worse of the two p90s descending so the slowest
+// runner pool is always the first row. Ties break alphabetically.
+func summarizeQueueByLabel(current, hist map[string][]time.Duration) []queueLabelStats {
+	labels := make(map[string]bool, len(current)+len(hist))
+	for label := range current {
+		labels[label] = true
+	}
+	for label := range hist {
+		labels[label] = true
+	}
+
+	stats := make([]queueLabelStats, 0, len(labels))
+	for label := range labels {
+		s := queueLabelStats{Label: label}
+		if samples := current[label]; len(samples) > 0 {
+			s.CurrentCount = len(samples)
+			s.CurrentP50 = timing.Percentile(samples, 50)
+			s.CurrentP90 = timing.Percentile(samples, 90)
+		}
+		if samples := hist[label]; len(samples) > 0 {
+			s.HistCount = len(samples)
+			s.HistP50 = timing.Percentile(samples, 50)
+			s.HistP90 = timing.Percentile(samples, 90)
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		wi := max(stats[i].CurrentP90, stats[i].HistP90)
+		wj := max(stats[j].CurrentP90, stats[j].HistP90)
+		if wi != wj {
+			return wi > wj
+		}
+		return stats[i].Label < stats[j].Label
+	})
+	return stats
+}
+
+// mergeQueueSamples appends src's samples into dst (allocating dst if nil)
+// and returns it. Used to fold per-workflow history into a model-wide map.
+func mergeQueueSamples(dst, src map[string][]time.Duration) map[string][]time.Duration {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string][]time.Duration, len(src))
+	}
+	for label, samples := range src {
+		dst[label] = append(dst[label], samples...)
+	}
+	return dst
+}
+
+// formatQueueStat renders a percentile cell, or "-" when the side has no
+// samples for the label.
+func formatQueueStat(count int, d time.Duration) string {
+	if count == 0 {
+		return "-"
+	}
+	return timing.FormatDuration(d)
+}
+
+// renderQueueStatsSection renders the queue latency table, flagging a
+// current p90 50% over the historical one.
+func renderQueueStatsSection(b *strings.Builder, styles Styles, indent string, stats []queueLabelStats, histPending bool) {
+	b.WriteString(indent)
+	b.WriteString(styles.Header.Render("Queue latency by runner label"))
+	b.WriteString("\n")
+
+	if len(stats) == 0 {
+		b.WriteString(indent)
+		if histPending {
+			b.WriteString(styles.Queued.Render("  Fetching runner queue history..."))
+		} else {
+			b.WriteString(styles.Queued.Render("  No labeled jobs yet"))
+		}
+		b.WriteString("\n\n")
+		return
+	}
+
+	const minLabelWidth = 12
+	labelWidth := minLabelWidth
+	for _, s := range stats {
+		labelWidth = max(labelWidth, runewidth.StringWidth(s.Label))
+	}
+	labelWidth = min(labelWidth, maxCheckNameWidth)
+
+	fmt.Fprintf(b, "%s  %s  %8s  %8s  %9s  %9s  %9s\n", indent,
+		"Label"+strings.Repeat(" ", labelWidth-5),
+		"Now p50", "Now p90", "Hist p50", "Hist p90", "Jobs")
+
+	for _, s := range stats {
+		label := runewidth.Truncate(s.Label, labelWidth, "…")
+		label += strings.Repeat(" ", max(labelWidth-runewidth.StringWidth(label), 0))
+
+		nowP90 := fmt.Sprintf("%8s", formatQueueStat(s.CurrentCount, s.CurrentP90))
+		if s.CurrentCount > 0 && s.HistCount > 0 && float64(s.CurrentP90) > 1.5*float64(s.HistP90) {
+			nowP90 = styles.Failure.Render(nowP90)
+		}
+
+		fmt.Fprintf(b, "%s  %s  %8s  %s  %9s  %9s  %9s\n", indent, label,
+			formatQueueStat(s.CurrentCount, s.CurrentP50),
+			nowP90,
+			formatQueueStat(s.HistCount, s.HistP50),
+			formatQueueStat(s.HistCount, s.HistP90),
+			fmt.Sprintf("%d/%d", s.CurrentCount, s.HistCount))
+	}
+	if histPending {
+		b.WriteString(indent)
+		b.WriteString(styles.Queued.Render("  Fetching runner queue history..."))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
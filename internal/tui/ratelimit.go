@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+	"github.com/fini-net/gh-observer/internal/watch"
+)
+
+// renderRateLimitPause renders the status line shown in place of an error
+// while GitHub has us rate limited, or "" when we aren't.
+func renderRateLimitPause(styles Styles, api ghclient.API, until, now time.Time) string {
+	wait := watch.Wait(api, until, now)
+	if wait <= 0 {
+		return ""
+	}
+	return styles.Running.Render("⏸ rate limited, resuming in " + timing.FormatDuration(wait))
+}
+
+// renderRemainingQuota renders the two-tier rate-limit indicator of the
+// repo overviews: red under minRateLimitForFetch, yellow under
+// rateWarningThreshold. Only rendered once a response has arrived — before
+// that, remaining is the Go zero value (0) and showing "[Rate limit: 0
+// remaining]" in red would be misleading.
+func renderRemainingQuota(b *strings.Builder, styles Styles, received bool, remaining int) {
+	if !received {
+		return
+	}
+	if remaining < minRateLimitForFetch {
+		b.WriteString(styles.Failure.Render(fmt.Sprintf("  [Rate limit: %d remaining]", remaining)))
+		b.WriteString("\n")
+	} else if remaining < rateWarningThreshold {
+		b.WriteString(styles.Running.Render(fmt.Sprintf("  [Rate limit: %d remaining]", remaining)))
+		b.WriteString("\n")
+	}
+}
+
+// retryAfterRateLimit runs cmd once wait has passed, for one-shot fetches
+// (PR or run metadata) that have no poll loop to pick them back up.
+func retryAfterRateLimit(wait time.Duration, cmd tea.Cmd) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return cmd()
+	})
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// RepoAveragesMsg carries the histories of the workflows one averagesCmd
+// asked for. Workflows whose fetch failed are in Errs; workflows with no
+// completed runs are in neither.
+type RepoAveragesMsg struct {
+	Histories map[int64]*ghclient.WorkflowHistory
+	Errs      map[int64]error
+}
+
+// WithoutAverages skips historical averages (--quick): the Avg column stays
+// empty and no history is fetched.
+func (m RepoModel) WithoutAverages() RepoModel {
+	m.noAvg = true
+	return m
+}
+
+// averagesCmd fetches, once each, the history of the workflows on screen
+// not yet asked for, unless the quota is held or below
+// minRateLimitForFetch.
+func (m *RepoModel) averagesCmd() tea.Cmd {
+	if m.noAvg || (m.feed.Quota.Received && m.feed.Quota.Remaining < minRateLimitForFetch) {
+		return nil
+	}
+	if m.feed.Quota.Hold(m.api, m.refreshInterval, time.Now()) > 0 {
+		return nil
+	}
+	var checks []ghclient.CheckRunInfo
+	for _, pr := range m.feed.PRs {
+		checks = append(checks, pr.CheckRuns...)
+		checks = append(checks, pr.ExtraCheckRuns...)
+	}
+	if m.avgRequested == nil {
+		m.avgRequested = make(map[int64]bool)
+	}
+	var ids []int64
+	request := func(id int64) {
+		if id > 0 && !m.avgRequested[id] {
+			m.avgRequested[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, check := range checks {
+		request(check.WorkflowID)
+	}
+	for _, run := range m.feed.Runs {
+		request(run.WorkflowID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	slices.Sort(ids)
+
+	ctx, api, owner, repo := m.ctx, m.api, m.owner, m.repo
+	refs := ghclient.WorkflowRefs(checks, ids)
+	debug.Log("repo averages fetch", "repo", owner+"/"+repo, "workflows", len(refs))
+	return func() tea.Msg {
+		histories, errs := api.FetchWorkflowHistories(ctx, owner, repo, refs)
+		return RepoAveragesMsg{Histories: histories, Errs: errs}
+	}
+}
+
+// handleRepoAverages stores the fetched averages. Errors are non-fatal:
+// they're logged, and the workflows asked for again on a later poll.
+func (m *RepoModel) handleRepoAverages(msg RepoAveragesMsg) (tea.Model, tea.Cmd) {
+	for id, err := range msg.Errs {
+		debug.Log("repo averages fetch error", "workflow_id", id, "err", err)
+		m.feed.Quota.Limited(m.api, err)
+		delete(m.avgRequested, id)
+	}
+	if m.workflowAverages == nil {
+		m.workflowAverages = make(map[int64]map[string]time.Duration)
+	}
+	for id, history := range msg.Histories {
+		if history != nil && len(history.Averages) > 0 {
+			m.workflowAverages[id] = history.Averages
+		}
+	}
+	return m, nil
+}
+
+// averagesFor returns the job averages of a workflow, or nil when they
+// aren't known (yet).
+func (m RepoModel) averagesFor(workflowID int64) map[string]time.Duration {
+	return m.workflowAverages[workflowID]
+}
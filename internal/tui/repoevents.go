@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/fini-net/gh-observer/internal/eventlog"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// eventPaneLines is how many events the recent-events pane shows at once;
+// pgup/pgdown scroll by a page.
+const eventPaneLines = 8
+
+// WithEventLog records the check and run completions the watcher sees in
+// log, and lets e show them. Without it the pane is empty.
+func (m RepoModel) WithEventLog(log *eventlog.Log) RepoModel {
+	m.events = log
+	return m
+}
+
+// recordCheckEvents logs every completed check of prs, before fade-out, so
+// a check that finished unwatched (the laptop asleep, the watcher not yet
+// started) still shows up. The log drops the ones it already has.
+func (m *RepoModel) recordCheckEvents(prs map[int]ghclient.PRCheckData) tea.Cmd {
+	if m.events == nil {
+		return nil
+	}
+	repo := m.ref().String()
+	var events []eventlog.Event
+	for prNum, pr := range prs {
+		for _, check := range pr.CheckRuns {
+			if check.Status != "completed" || check.CompletedAt == nil || eventlog.OutcomeVerb(check.Conclusion) == "" {
+				continue
+			}
+			name := FormatCheckName(check)
+			events = append(events, eventlog.Event{
+				At:      *check.CompletedAt,
+				Repo:    repo,
+				Subject: fmt.Sprintf("PR #%d", prNum),
+				Name:    name,
+				Outcome: check.Conclusion,
+				URL:     check.DetailsURL,
+				Key:     fmt.Sprintf("%s#%d %s %s", repo, prNum, name, check.CompletedAt.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+	return m.recordEvents(events)
+}
+
+// recordRunEvents logs every completed branch run, before fade-out and
+// dedup. Runs on a PR's head commit are left to recordCheckEvents, which
+// logs their jobs as that PR's checks.
+func (m *RepoModel) recordRunEvents(runs []ghclient.BranchRunData) tea.Cmd {
+	if m.events == nil {
+		return nil
+	}
+	repo := m.ref().String()
+	var events []eventlog.Event
+	for _, run := range runs {
+		if run.Status != "completed" || m.feed.PRHeadSHAs[run.HeadSHA] || eventlog.OutcomeVerb(run.Conclusion) == "" {
+			continue
+		}
+		name := run.WorkflowName
+		if name == "" {
+			name = run.DisplayTitle
+		}
+		events = append(events, eventlog.Event{
+			At:      run.UpdatedAt,
+			Repo:    repo,
+			Subject: run.HeadBranch,
+			Name:    name,
+			Outcome: run.Conclusion,
+			Key:     fmt.Sprintf("%s run %d/%d", repo, run.RunID, run.RunAttempt),
+		})
+	}
+	return m.recordEvents(events)
+}
+
+// recordEvents adds events to the log, saving it in the background when
+// any were new.
+func (m *RepoModel) recordEvents(events []eventlog.Event) tea.Cmd {
+	if !m.events.Record(time.Now(), events...) {
+		return nil
+	}
+	return saveEventsCmd(m.events)
+}
+
+// saveEventsCmd writes log to its state file. A failed save is only
+// logged: the events stay in memory and the next change retries.
+func saveEventsCmd(log *eventlog.Log) tea.Cmd {
+	return func() tea.Msg {
+		if err := log.Save(); err != nil {
+			debug.Log("event log save failed", "err", err)
+		}
+		return nil
+	}
+}
+
+// repoEvents is the events logged for this repo, newest first.
+func (m RepoModel) repoEvents() []eventlog.Event {
+	repo := m.ref().String()
+	return m.events.Events(func(e eventlog.Event) bool { return e.Repo == repo })
+}
+
+// dashboardEvents is the events logged for the dashboard's repos, newest
+// first. With --mine, whose repos come and go with the search, that's
+// every repo's.
+func (m DashboardModel) dashboardEvents() []eventlog.Event {
+	if m.mine {
+		return m.events.Events(nil)
+	}
+	repos := make(map[string]bool, len(m.sections))
+	for _, s := range m.sections {
+		repos[s.ref().String()] = true
+	}
+	return m.events.Events(func(e eventlog.Event) bool { return repos[e.Repo] })
+}
+
+// scrollEvents moves an events pane offset by delta, keeping a full page
+// on screen where there's one to show.
+func scrollEvents(offset, delta, total int) int {
+	return max(0, min(offset+delta, total-eventPaneLines))
+}
+
+// pageDelta is how far pgup or pgdown scrolls the events pane.
+func pageDelta(key string) int {
+	if key == "pgup" {
+		return -eventPaneLines
+	}
+	return eventPaneLines
+}
+
+// renderEventsSection renders a page of events starting at offset, newest
+// first. withRepo prefixes each with its repo, for the dashboard.
+func renderEventsSection(b *strings.Builder, styles Styles, events []eventlog.Event, offset int, withRepo bool, now time.Time) {
+	title := "Recent events"
+	offset = scrollEvents(offset, 0, len(events))
+	end := min(offset+eventPaneLines, len(events))
+	if len(events) > eventPaneLines {
+		title += fmt.Sprintf(" (%d–%d of %d)", offset+1, end, len(events))
+	}
+	b.WriteString(styles.Header.Render(title))
+	b.WriteString("\n")
+
+	if len(events) == 0 {
+		b.WriteString(styles.Queued.Render("  No finished checks or runs seen yet"))
+		b.WriteString("\n\n")
+		return
+	}
+	page := events[offset:end]
+	timeWidth := 0
+	for _, e := range page {
+		timeWidth = max(timeWidth, len(formatEventTime(e.At, now)))
+	}
+	for _, e := range page {
+		style := styleForCheck("completed", e.Outcome, styles)
+		text := e.Text()
+		if withRepo {
+			text = e.Repo + " " + text
+		}
+		fmt.Fprintf(b, "  %*s  %s %s\n", timeWidth, formatEventTime(e.At, now),
+			style.Render(GetCheckIcon("completed", e.Outcome)), text)
+	}
+	b.WriteString("\n")
+}
+
+// formatEventTime is an event's local time, with the date when it wasn't
+// today.
+func formatEventTime(at, now time.Time) string {
+	at, now = at.Local(), now.Local()
+	if y, m, d := at.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return at.Format("15:04")
+	}
+	return at.Format("Jan _2 15:04")
+}
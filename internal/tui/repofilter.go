@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// WithFilter starts repo mode narrowed to filter (--author, --label, --base,
+// --no-drafts). The f prompt can change it later.
+func (m RepoModel) WithFilter(filter ghclient.RepoFilter) RepoModel {
+	m.feed.Filter = filter
+	return m
+}
+
+// filterPrompt is the f prompt shared by the repo overview and the
+// dashboard. err is the last unparseable entry. authorFixed rejects
+// author: terms, for --mine where the search picks the author.
+type filterPrompt struct {
+	open        bool
+	input       textinput.Model
+	err         error
+	authorFixed bool
+}
+
+// show opens the prompt, pre-filled with the current filter so a small
+// change doesn't mean retyping it.
+func (p *filterPrompt) show(current ghclient.RepoFilter) tea.Cmd {
+	p.input = textinput.New()
+	p.input.Prompt = "Filter: "
+	p.input.Placeholder = "author:@me label:NAME base:BRANCH -draft"
+	if p.authorFixed {
+		p.input.Placeholder = "label:NAME base:BRANCH -draft"
+	}
+	p.input.SetValue(current.String())
+	p.input.CursorEnd()
+	p.err = nil
+	p.open = true
+	return p.input.Focus()
+}
+
+// handleKey routes a key to the open prompt: enter returns the typed
+// filter with apply set, esc closes the prompt unchanged, and everything
+// else edits the text. An unparseable filter (or, with authorFixed, one
+// naming an author) keeps the prompt open with the error shown beneath
+// it. Callers handle ctrl+c first.
+func (p *filterPrompt) handleKey(msg tea.KeyMsg) (filter ghclient.RepoFilter, apply bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.open = false
+		p.err = nil
+		return filter, false, nil
+	case "enter":
+		filter, err := ghclient.ParseRepoFilter(p.input.Value())
+		if err == nil && p.authorFixed && filter.Author != "" {
+			err = errors.New("author: doesn't apply with --mine, which already picks the author")
+		}
+		if err != nil {
+			p.err = err
+			return filter, false, nil
+		}
+		p.open = false
+		p.err = nil
+		return filter, true, nil
+	}
+	p.input, cmd = p.input.Update(msg)
+	return filter, false, cmd
+}
+
+// update passes anything else (the cursor blink) to the open prompt.
+func (p *filterPrompt) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+// view renders the prompt, its error if any, and how to leave it.
+func (p filterPrompt) view(styles Styles) string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+	if p.err != nil {
+		b.WriteString(styles.Failure.Render("  " + p.err.Error()))
+		b.WriteString("\n")
+	}
+	b.WriteString("Press enter to apply (empty clears), esc to cancel\n")
+	return b.String()
+}
+
+// handleFilterKey handles a key while the filter prompt is open; ctrl+c
+// still quits.
+func (m *RepoModel) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		m.quitting = true
+		return m, tea.Quit
+	}
+	filter, apply, cmd := m.prompt.handleKey(msg)
+	if apply {
+		return m, m.applyFilter(filter)
+	}
+	return m, cmd
+}
+
+// applyFilter switches to filter and refetches right away rather than on
+// the next tick.
+func (m *RepoModel) applyFilter(filter ghclient.RepoFilter) tea.Cmd {
+	if !m.setFilter(filter) {
+		return nil
+	}
+	return m.refreshCmd()
+}
+
+// setFilter switches to filter without fetching, reporting whether it
+// changed. The watch clears what the old filter selected and drops fetches
+// still in flight under it.
+func (m *RepoModel) setFilter(filter ghclient.RepoFilter) bool {
+	from := m.feed.Filter
+	if !m.feed.SetFilter(filter) {
+		return false
+	}
+	debug.Log("repo filter changed", "repo", m.owner+"/"+m.repo, "from", from.String(), "to", filter.String())
+	// PRs the new filter brings in aren't news.
+	m.notify.reprime()
+	return true
+}
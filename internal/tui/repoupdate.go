@@ -2,30 +2,32 @@ package tui
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"time"
 
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 	"github.com/fini-net/gh-observer/internal/debug"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
-	"github.com/google/go-github/v90/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // RepoTickMsg is sent on each repo-mode poll interval.
 type RepoTickMsg time.Time
 
 // RepoChecksUpdateMsg carries PR check data from the batched GraphQL query.
-type RepoChecksUpdateMsg struct {
-	PRData             map[int]ghclient.PRCheckData
-	RateLimitRemaining int
-	Err                error
-}
+type RepoChecksUpdateMsg = watch.RepoChecks
 
 // RepoRunsUpdateMsg carries standalone (non-PR) workflow runs from REST.
-type RepoRunsUpdateMsg struct {
-	Runs               []ghclient.BranchRunData
-	RateLimitRemaining int
-	Err                error
+type RepoRunsUpdateMsg = watch.RepoRuns
+
+// RepoWorkflowHistoryMsg carries the recent-history summary for one workflow
+// seen in repo mode, fetched lazily for the queue-by-label pane.
+type RepoWorkflowHistoryMsg struct {
+	WorkflowID int64
+	History    *ghclient.WorkflowHistory
+	Err        error
 }
 
 // Init kicks off the spinner, the first PR fetch, the first standalone-runs
@@ -33,9 +35,10 @@ type RepoRunsUpdateMsg struct {
 func (m RepoModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		fetchRepoCheckRuns(m.ctx, m.token, m.owner, m.repo),
-		fetchRepoRuns(m.ctx, m.token, m.owner, m.repo, m.fadeWindow()),
+		m.fetchChecksCmd(),
+		m.fetchRunsCmd(),
 		repoTick(m.refreshInterval),
+		waitForWebhook(m.webhooks.events),
 	)
 }
 
@@ -43,10 +46,45 @@ func (m RepoModel) Init() tea.Cmd {
 func (m RepoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.prompt.open {
+			return m.handleFilterKey(msg)
+		}
+		if m.review.open {
+			return m.handleReviewKey(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "f":
+			return m, m.prompt.show(m.feed.Filter)
+		case "up", "k":
+			m.moveSelection(-1)
+			return m, nil
+		case "down", "j":
+			m.moveSelection(1)
+			return m, nil
+		case "l":
+			m.showQueueStats = !m.showQueueStats
+			return m, tea.Batch(m.queueHistoryCmds()...)
+		case "r":
+			m.runners.show = !m.runners.show
+			return m, tea.Batch(m.runnersCmds()...)
+		case "e":
+			m.showEvents = !m.showEvents
+			m.eventsOffset = 0
+			return m, nil
+		case "pgup", "pgdown":
+			if m.showEvents {
+				m.eventsOffset = scrollEvents(m.eventsOffset, pageDelta(msg.String()), len(m.repoEvents()))
+			}
+			return m, nil
+		case "a", "x":
+			item, ok := m.selectedItem()
+			if !ok || item.runID == 0 {
+				return m, nil
+			}
+			return m, m.review.show(item.runID, m.deployments[item.runID], msg.String() == "a")
 		}
 
 	case spinner.TickMsg:
@@ -55,43 +93,108 @@ func (m RepoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case RepoTickMsg:
-		// Rate-limit backoff: triple the interval when quota is critically low.
-		// Gate on fetchReceived so the zero-value rateLimitRemaining (0) before
-		// the first successful response doesn't pin us in backoff forever and
-		// suppress the fetch commands that would clear that initial state.
-		if m.fetchReceived && m.rateLimitRemaining < rateBackoffThreshold {
-			debug.Log("rate limit backoff (repo)", "remaining", m.rateLimitRemaining, "threshold", rateBackoffThreshold)
-			return m, repoTick(m.refreshInterval * 3)
+		if hold := m.feed.Quota.Hold(m.api, m.refreshInterval, time.Now()); hold > 0 {
+			debug.Log("rate limited, delaying poll (repo)", "wait", hold, "remaining", m.feed.Quota.Remaining)
+			return m, repoTick(hold)
+		}
+		interval := m.webhooks.pollInterval(m.nextPollInterval(time.Now()))
+		if interval != m.refreshInterval {
+			debug.Log("adaptive poll interval (repo)", "interval", interval, "base", m.refreshInterval)
 		}
+		m.feed.Quota.StartPoll()
 		cmds := []tea.Cmd{
-			fetchRepoCheckRuns(m.ctx, m.token, m.owner, m.repo),
-			fetchRepoRuns(m.ctx, m.token, m.owner, m.repo, m.fadeWindow()),
-			repoTick(m.refreshInterval),
+			m.fetchChecksCmd(),
+			m.fetchRunsCmd(),
+			repoTick(interval),
 		}
-		return m, tea.Batch(cmds...)
+		return m, tea.Batch(append(cmds, m.runnersCmds()...)...)
+
+	case WebhookMsg:
+		return m, m.webhooks.receive(sameRepo(msg.Event, m.owner, m.repo))
+
+	case webhookRefreshMsg:
+		m.webhooks.refreshPending = false
+		debug.Log("webhook refresh (repo)")
+		return m, m.refreshCmd()
 
 	case RepoChecksUpdateMsg:
 		return m.handleRepoChecksUpdate(msg)
 
 	case RepoRunsUpdateMsg:
 		return m.handleRepoRunsUpdate(msg)
+
+	case RepoWorkflowHistoryMsg:
+		return m.handleRepoWorkflowHistory(msg)
+
+	case RepoAveragesMsg:
+		return m.handleRepoAverages(msg)
+
+	case PendingDeploymentsMsg:
+		return m.handlePendingDeployments(msg)
+
+	case RunnersMsg:
+		return m.handleRunners(msg)
+
+	case DeploymentReviewMsg:
+		m.reviewResult = newReviewResult(msg)
+		if msg.Err != nil || m.feed.Quota.Wait(m.api, time.Now()) > 0 {
+			return m, nil
+		}
+		return m, m.fetchRunsCmd()
 	}
 
+	// Anything else (the prompt's cursor blink) belongs to whichever
+	// prompt is open.
+	switch {
+	case m.prompt.open:
+		return m, m.prompt.update(msg)
+	case m.review.open:
+		return m, m.review.update(msg)
+	}
 	return m, nil
 }
 
-// handleRepoChecksUpdate applies fade-out filtering to PR checks and stores
-// the surviving PRs in m.prs. A PR stays visible while it has at least one
-// active (in_progress/queued) check, or a completed check whose CompletedAt
-// is within the configured fade window (fadeSuccess or fadeFailure).
+// refreshCmd fetches both sources now, outside the tick schedule, unless
+// GitHub is rate limiting us (the pending tick resumes polling then).
+func (m RepoModel) refreshCmd() tea.Cmd {
+	if m.feed.Quota.Wait(m.api, time.Now()) > 0 {
+		return nil
+	}
+	return tea.Batch(m.fetchChecksCmd(), m.fetchRunsCmd())
+}
+
+// fetchChecksCmd and fetchRunsCmd fetch the two sources under the current
+// filter.
+func (m RepoModel) fetchChecksCmd() tea.Cmd {
+	ctx, feed := m.ctx, m.feed
+	return func() tea.Msg {
+		return feed.FetchChecks(ctx)
+	}
+}
+
+func (m RepoModel) fetchRunsCmd() tea.Cmd {
+	ctx, feed := m.ctx, m.feed
+	return func() tea.Msg {
+		return feed.FetchRuns(ctx)
+	}
+}
+
+// handleRepoChecksUpdate hands the PRs' checks to the watch, which keeps
+// the PRs with an active or recently completed check.
 //
 // Transient fetch errors (e.g. 504 Gateway Timeout) are non-fatal: the last
-// good m.prs is preserved on screen, the error is surfaced via
-// m.fetchErrChecks for the view to render as a status line, and polling
-// continues. Only the PR-checks error is touched here; the standalone-runs
-// error (m.fetchErrRuns) is managed by handleRepoRunsUpdate so a success
-// from one source cannot mask an ongoing error from the other.
+// good PRs stay on screen, the error is surfaced via m.fetchErrChecks for
+// the view to render as a status line, and polling continues. Only the
+// PR-checks error is touched here; the standalone-runs error
+// (m.fetchErrRuns) is managed by handleRepoRunsUpdate so a success from
+// one source cannot mask an ongoing error from the other.
 func (m *RepoModel) handleRepoChecksUpdate(msg RepoChecksUpdateMsg) (tea.Model, tea.Cmd) {
+	if msg.FilterGen != m.feed.FilterGen {
+		return m, nil
+	}
+	if m.feed.Quota.Limited(m.api, msg.Err) {
+		return m, nil
+	}
 	if msg.Err != nil {
 		m.fetchErrChecks = msg.Err
 		m.fetchErrChecksAt = time.Now()
@@ -99,87 +202,32 @@ func (m *RepoModel) handleRepoChecksUpdate(msg RepoChecksUpdateMsg) (tea.Model,
 		return m, nil
 	}
 
-	// Take the minimum across sources, but accept the first observed value
-	// so the zero default doesn't pin rateLimitRemaining at 0 forever (which
-	// would trigger permanent rate-limit backoff and show "0 remaining").
-	// Mirrors handleRepoRunsUpdate so neither source can raise the value
-	// past what the other already observed.
-	if !m.fetchReceived || msg.RateLimitRemaining < m.rateLimitRemaining {
-		m.rateLimitRemaining = msg.RateLimitRemaining
-	}
 	m.lastUpdate = time.Now()
 	m.fetchErrChecks = nil
 	m.fetchErrChecksAt = time.Time{}
-	m.fetchReceived = true
-
-	now := time.Now()
-	activePRs := make(map[int]PRViewData)
-
-	for prNum, prData := range msg.PRData {
-		checkRuns := prData.CheckRuns
-		SortCheckRuns(checkRuns)
-
-		var visible []ghclient.CheckRunInfo
-		for _, cr := range checkRuns {
-			if cr.Status == "in_progress" || cr.Status == "queued" || cr.Status == "waiting" {
-				visible = append(visible, cr)
-				continue
-			}
-			if cr.Status != "completed" {
-				continue
-			}
-			fadeTimeout := m.fadeSuccess
-			if ghclient.FailureConclusion(cr.Conclusion) {
-				fadeTimeout = m.fadeFailure
-			}
-			if cr.CompletedAt != nil && now.Sub(*cr.CompletedAt) < fadeTimeout {
-				visible = append(visible, cr)
-			}
-		}
-
-		if len(visible) == 0 {
-			continue
-		}
-		view := PRViewData{
-			Title:          prData.Title,
-			CheckRuns:      visible,
-			HeadPushedTime: prData.HeadPushedTime,
-			HeadSHA:        prData.HeadSHA,
-		}
-		// Preserve extras carried over from the last runs-poll so they
-		// don't flicker out between runs ticks. dedupeAndAttachExtraJobs
-		// will refresh them on the next RepoRunsUpdateMsg.
-		if prev, ok := m.prs[prNum]; ok {
-			view.ExtraCheckRuns = prev.ExtraCheckRuns
-		}
-		activePRs[prNum] = view
+	for _, pr := range msg.PRData {
+		SortCheckRuns(pr.CheckRuns)
 	}
+	m.feed.ApplyChecks(msg, time.Now())
+	recordCmd := m.recordCheckEvents(msg.PRData)
 
-	m.prs = activePRs
-
-	debug.Log("repo checks update", "total_prs", len(msg.PRData), "active_prs", len(activePRs),
-		"rate_limit_remaining", msg.RateLimitRemaining)
-
-	return m, nil
+	return m, tea.Batch(m.averagesCmd(), recordCmd, m.notifyChecks(msg.PRData))
 }
 
-// handleRepoRunsUpdate applies the same fade-out logic to standalone runs and
-// stores the survivors in m.standaloneRuns. Active runs are always kept;
-// completed runs are kept if RunStartedAt is within the fade window.
-//
-// After fade-out, dedupeAndAttachExtraJobs reconciles the runs against the PR
-// section (issue #331): jobs whose (HeadSHA, WorkflowName, Name) already appear
-// in a tracked PR's CheckRuns are dropped from the standalone view, and any
-// leftover "extra" jobs (e.g. Copilot) the PR GraphQL query missed are attached
-// under their matching PR so they render in the PR group instead of a separate
-// branch section. Runs whose commit matches no tracked PR stay standalone.
+// handleRepoRunsUpdate hands the standalone runs to the watch, which keeps
+// the active and recently completed ones and reconciles them against the
+// PRs (issue #331).
 //
-// Transient fetch errors are non-fatal: the last good m.standaloneRuns is
-// preserved, the error is surfaced via m.fetchErrRuns, and polling continues.
-// Only the standalone-runs error is touched here; the PR-checks error
-// (m.fetchErrChecks) is managed by handleRepoChecksUpdate so a success from
-// one source cannot mask an ongoing error from the other.
+// Transient fetch errors are non-fatal: the last good runs stay, the error
+// is surfaced via m.fetchErrRuns, and polling continues. Only the
+// standalone-runs error is touched here; see handleRepoChecksUpdate.
 func (m *RepoModel) handleRepoRunsUpdate(msg RepoRunsUpdateMsg) (tea.Model, tea.Cmd) {
+	if msg.FilterGen != m.feed.FilterGen {
+		return m, nil
+	}
+	if m.feed.Quota.Limited(m.api, msg.Err) {
+		return m, nil
+	}
 	if msg.Err != nil {
 		m.fetchErrRuns = msg.Err
 		m.fetchErrRunsAt = time.Now()
@@ -187,99 +235,178 @@ func (m *RepoModel) handleRepoRunsUpdate(msg RepoRunsUpdateMsg) (tea.Model, tea.
 		return m, nil
 	}
 
-	// Take the minimum across sources, but accept the first observed value
-	// so the zero default doesn't pin rateLimitRemaining at 0 forever (which
-	// would trigger permanent rate-limit backoff and show "0 remaining").
-	if !m.fetchReceived || msg.RateLimitRemaining < m.rateLimitRemaining {
-		m.rateLimitRemaining = msg.RateLimitRemaining
-	}
 	m.lastUpdate = time.Now()
 	m.fetchErrRuns = nil
 	m.fetchErrRunsAt = time.Time{}
-	m.fetchReceived = true
+	recordCmd := m.recordRunEvents(msg.Runs)
+	visible := m.feed.ApplyRuns(msg, time.Now())
+
+	// Capture labeled jobs before dedup: jobs that dedup drops in favor of
+	// the PR's GraphQL checks are the same jobs, but only the REST copy
+	// knows which runner pool they waited on.
+	m.queueJobs = nil
+	m.queueWorkflowIDs = nil
+	seenWorkflows := make(map[int64]bool)
+	for _, run := range visible {
+		m.queueJobs = append(m.queueJobs, run.Jobs...)
+		if run.WorkflowID != 0 && !seenWorkflows[run.WorkflowID] {
+			seenWorkflows[run.WorkflowID] = true
+			m.queueWorkflowIDs = append(m.queueWorkflowIDs, run.WorkflowID)
+		}
+	}
 
-	now := time.Now()
-	var visible []ghclient.BranchRunData
-	for _, run := range msg.Runs {
-		if isActiveBranchRun(run.Status) {
-			visible = append(visible, run)
-			continue
+	cmds := append(m.queueHistoryCmds(), m.deploymentsCmds()...)
+	return m, tea.Batch(append(cmds, m.averagesCmd(), recordCmd, m.notifyRuns(msg.Runs))...)
+}
+
+// deploymentsCmds fetches the pending deployments of every waiting
+// standalone run, and forgets those of runs no longer waiting. Like the
+// other follow-up fetches, nothing is fetched below minRateLimitForFetch.
+func (m *RepoModel) deploymentsCmds() []tea.Cmd {
+	waiting := make(map[int64]bool)
+	for _, run := range m.feed.Runs {
+		if run.Status == "waiting" {
+			waiting[run.RunID] = true
 		}
-		if run.Status != "completed" {
+	}
+	maps.DeleteFunc(m.deployments, func(runID int64, _ []ghclient.PendingDeployment) bool { return !waiting[runID] })
+	if m.feed.Quota.Received && m.feed.Quota.Remaining < minRateLimitForFetch {
+		return nil
+	}
+	if m.deploymentsPending == nil {
+		m.deploymentsPending = make(map[int64]bool)
+	}
+	var cmds []tea.Cmd
+	for _, run := range m.feed.Runs {
+		if !waiting[run.RunID] || m.deploymentsPending[run.RunID] {
 			continue
 		}
-		fadeTimeout := m.fadeSuccess
-		if ghclient.FailureConclusion(run.Conclusion) {
-			fadeTimeout = m.fadeFailure
-		}
-		if !run.RunStartedAt.IsZero() && now.Sub(run.RunStartedAt) < fadeTimeout {
-			visible = append(visible, run)
-		}
+		m.deploymentsPending[run.RunID] = true
+		cmds = append(cmds, fetchPendingDeployments(m.ctx, m.api, m.owner, m.repo, run.RunID))
 	}
+	return cmds
+}
 
-	m.standaloneRuns = m.dedupeAndAttachExtraJobs(visible)
-
-	debug.Log("repo runs update", "total", len(msg.Runs), "visible", len(visible),
-		"standalone", len(m.standaloneRuns),
-		"rate_limit_remaining", msg.RateLimitRemaining)
-
+// handlePendingDeployments stores a waiting run's pending deployments, if
+// it is still waiting. Errors are non-fatal and only logged; the next runs
+// poll retries.
+func (m *RepoModel) handlePendingDeployments(msg PendingDeploymentsMsg) (tea.Model, tea.Cmd) {
+	delete(m.deploymentsPending, msg.RunID)
+	if msg.Err != nil {
+		debug.Log("pending deployments fetch error", "run_id", msg.RunID, "err", msg.Err)
+		return m, nil
+	}
+	if !slices.ContainsFunc(m.feed.Runs, func(run ghclient.BranchRunData) bool {
+		return run.RunID == msg.RunID && run.Status == "waiting"
+	}) {
+		return m, nil
+	}
+	m.feed.Quota.Lower(msg.RateLimitRemaining, ghclient.ResourceCore, watch.ResetAt(m.api, ghclient.ResourceCore))
+	if m.deployments == nil {
+		m.deployments = make(map[int64][]ghclient.PendingDeployment)
+	}
+	m.deployments[msg.RunID] = msg.Deployments
 	return m, nil
 }
 
-// isActiveBranchRun returns true for statuses that should always be shown.
-func isActiveBranchRun(status string) bool {
-	switch status {
-	case "in_progress", "queued", "waiting", "pending":
-		return true
+// runnersCmds lists the runners while the runner pane is shown, unless
+// quota is below minRateLimitForFetch.
+func (m *RepoModel) runnersCmds() []tea.Cmd {
+	if m.feed.Quota.Received && m.feed.Quota.Remaining < minRateLimitForFetch {
+		return nil
 	}
-	return false
+	return m.runners.fetchCmds(m.ctx, m.api, []ghclient.RepoRef{m.ref()})
 }
 
-// repoTick schedules the next RepoTickMsg after d.
-func repoTick(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg {
-		return RepoTickMsg(t)
-	})
+// handleRunners records a runners listing. Errors are non-fatal and only
+// logged; a refused listing is shown as such in the pane.
+func (m *RepoModel) handleRunners(msg RunnersMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.feed.Quota.Limited(m.api, msg.Err):
+	case msg.Err != nil:
+		debug.Log("runners fetch error", "owner", msg.Owner, "repo", msg.Repo, "err", msg.Err)
+	default:
+		m.feed.Quota.Lower(msg.RateLimitRemaining, ghclient.ResourceCore, watch.ResetAt(m.api, ghclient.ResourceCore))
+	}
+	m.runners.handle(msg)
+	return m, nil
 }
 
-// fetchRepoCheckRuns issues the batched GraphQL query for all open PRs.
-func fetchRepoCheckRuns(ctx context.Context, token, owner, repo string) tea.Cmd {
-	return func() tea.Msg {
-		prData, rateLimit, err := ghclient.FetchRepoCheckRunsGraphQL(ctx, token, owner, repo)
-		return RepoChecksUpdateMsg{
-			PRData:             prData,
-			RateLimitRemaining: rateLimit,
-			Err:                err,
-		}
+// handleReviewKey handles a key while the review prompt is open; ctrl+c
+// still quits.
+func (m *RepoModel) handleReviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		m.quitting = true
+		return m, tea.Quit
+	}
+	submit, cmd := m.review.handleKey(msg)
+	if submit {
+		return m, m.review.submitCmd(m.ctx, m.api, m.owner, m.repo)
 	}
+	return m, cmd
 }
 
-// fetchRepoRuns fetches standalone (non-PR) workflow runs and enriches them
-// with per-run jobs in a single command. Job enrichment failure is non-fatal:
-// runs are still returned with empty Jobs so their headers can render.
-func fetchRepoRuns(ctx context.Context, token, owner, repo string, fadeWindow time.Duration) tea.Cmd {
-	return func() tea.Msg {
-		client, err := github.NewClient(github.WithAuthToken(token))
-		if err != nil {
-			return RepoRunsUpdateMsg{Err: err}
+// handleRepoWorkflowHistory merges one workflow's queue samples and
+// averages. A failed fetch is only logged and not retried.
+func (m *RepoModel) handleRepoWorkflowHistory(msg RepoWorkflowHistoryMsg) (tea.Model, tea.Cmd) {
+	delete(m.queueHistoryPending, msg.WorkflowID)
+	if msg.Err != nil {
+		debug.Log("repo workflow history fetch error", "workflow_id", msg.WorkflowID, "err", msg.Err)
+		return m, nil
+	}
+	if msg.History != nil {
+		m.queueHistory = mergeQueueSamples(m.queueHistory, msg.History.QueueByLabel)
+		if _, ok := m.workflowAverages[msg.WorkflowID]; !ok && len(msg.History.Averages) > 0 {
+			if m.workflowAverages == nil {
+				m.workflowAverages = make(map[int64]map[string]time.Duration)
+			}
+			m.workflowAverages[msg.WorkflowID] = msg.History.Averages
 		}
+	}
+	return m, nil
+}
 
-		runs, rateLimit, err := ghclient.FetchRepoWorkflowRuns(ctx, client, owner, repo, fadeWindow)
-		if err != nil {
-			return RepoRunsUpdateMsg{Err: err}
+// queueHistoryCmds dispatches history fetches for workflows seen in the
+// visible runs that have not been fetched yet. Nothing is fetched while the
+// queue pane is hidden, or when quota is below minRateLimitForFetch — the
+// pane is a diagnostic and must never starve the main poll loop.
+func (m *RepoModel) queueHistoryCmds() []tea.Cmd {
+	if !m.showQueueStats {
+		return nil
+	}
+	if m.feed.Quota.Received && m.feed.Quota.Remaining < minRateLimitForFetch {
+		return nil
+	}
+	if m.queueHistoryFetched == nil {
+		m.queueHistoryFetched = make(map[int64]bool)
+	}
+	if m.queueHistoryPending == nil {
+		m.queueHistoryPending = make(map[int64]bool)
+	}
+	var cmds []tea.Cmd
+	for _, wfID := range m.queueWorkflowIDs {
+		if m.queueHistoryFetched[wfID] {
+			continue
 		}
+		m.queueHistoryFetched[wfID] = true
+		m.queueHistoryPending[wfID] = true
+		cmds = append(cmds, fetchRepoWorkflowHistory(m.ctx, m.api, m.owner, m.repo, wfID))
+	}
+	return cmds
+}
 
-		enriched, rl2, err := ghclient.EnrichRepoRunsWithJobs(ctx, client, owner, repo, runs)
-		if err != nil {
-			debug.Log("enrich repo runs error", "err", err)
-		}
-		if rl2 < rateLimit {
-			rateLimit = rl2
-		}
+// repoTick schedules the next RepoTickMsg after d.
+func repoTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return RepoTickMsg(t)
+	})
+}
 
-		return RepoRunsUpdateMsg{
-			Runs:               enriched,
-			RateLimitRemaining: rateLimit,
-		}
+// fetchRepoWorkflowHistory fetches the recent-history summary for a single
+// workflow.
+func fetchRepoWorkflowHistory(ctx context.Context, api ghclient.API, owner, repo string, workflowID int64) tea.Cmd {
+	return func() tea.Msg {
+		history, err := api.FetchWorkflowHistoryDetail(ctx, owner, repo, workflowID)
+		return RepoWorkflowHistoryMsg{WorkflowID: workflowID, History: history, Err: err}
 	}
 }
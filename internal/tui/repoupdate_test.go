@@ -2,12 +2,19 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/eventlog"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 	"github.com/mattn/go-runewidth"
 )
 
@@ -167,9 +174,11 @@ func TestRepoChecksUpdateFadeOut(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := RepoModel{
-				prs:         make(map[int]PRViewData),
-				fadeSuccess: fadeSuccess,
-				fadeFailure: fadeFailure,
+				feed: watch.Repo{
+					PRs:         make(map[int]PRViewData),
+					FadeSuccess: fadeSuccess,
+					FadeFailure: fadeFailure,
+				},
 			}
 
 			msg := RepoChecksUpdateMsg{
@@ -180,12 +189,12 @@ func TestRepoChecksUpdateFadeOut(t *testing.T) {
 			newModel, _ := m.Update(msg)
 			rm := newModel.(*RepoModel)
 
-			if len(rm.prs) != tt.wantPRCount {
-				t.Errorf("visible PRs = %d, want %d", len(rm.prs), tt.wantPRCount)
+			if len(rm.feed.PRs) != tt.wantPRCount {
+				t.Errorf("visible PRs = %d, want %d", len(rm.feed.PRs), tt.wantPRCount)
 			}
 
 			visibleChecks := 0
-			for _, pr := range rm.prs {
+			for _, pr := range rm.feed.PRs {
 				visibleChecks += len(pr.CheckRuns)
 			}
 			if visibleChecks != tt.wantVisibleChecks {
@@ -261,9 +270,11 @@ func TestRepoRunsUpdateFadeOut(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := RepoModel{
-				prs:         make(map[int]PRViewData),
-				fadeSuccess: fadeSuccess,
-				fadeFailure: fadeFailure,
+				feed: watch.Repo{
+					PRs:         make(map[int]PRViewData),
+					FadeSuccess: fadeSuccess,
+					FadeFailure: fadeFailure,
+				},
 			}
 
 			msg := RepoRunsUpdateMsg{
@@ -274,72 +285,8 @@ func TestRepoRunsUpdateFadeOut(t *testing.T) {
 			newModel, _ := m.Update(msg)
 			rm := newModel.(*RepoModel)
 
-			if len(rm.standaloneRuns) != tt.wantVisible {
-				t.Errorf("visible branch runs = %d, want %d", len(rm.standaloneRuns), tt.wantVisible)
-			}
-		})
-	}
-}
-
-func TestIsActiveBranchRun(t *testing.T) {
-	tests := []struct {
-		status string
-		want   bool
-	}{
-		{"in_progress", true},
-		{"queued", true},
-		{"waiting", true},
-		{"pending", true},
-		{"completed", false},
-		{"", false},
-		{"unknown", false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.status, func(t *testing.T) {
-			if got := isActiveBranchRun(tt.status); got != tt.want {
-				t.Errorf("isActiveBranchRun(%q) = %v, want %v", tt.status, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestRepoModelSortedPRNumbers(t *testing.T) {
-	m := RepoModel{
-		prs: map[int]PRViewData{
-			3: {Title: "c"},
-			1: {Title: "a"},
-			2: {Title: "b"},
-		},
-	}
-	got := m.sortedPRNumbers()
-	want := []int{1, 2, 3}
-	if len(got) != len(want) {
-		t.Fatalf("len = %d, want %d", len(got), len(want))
-	}
-	for i := range want {
-		if got[i] != want[i] {
-			t.Errorf("sortedPRNumbers[%d] = %d, want %d", i, got[i], want[i])
-		}
-	}
-}
-
-func TestRepoModelFadeWindow(t *testing.T) {
-	tests := []struct {
-		name        string
-		fadeSuccess time.Duration
-		fadeFailure time.Duration
-		want        time.Duration
-	}{
-		{"failure larger", 15 * time.Minute, 30 * time.Minute, 30 * time.Minute},
-		{"success larger", 30 * time.Minute, 15 * time.Minute, 30 * time.Minute},
-		{"equal", 15 * time.Minute, 15 * time.Minute, 15 * time.Minute},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			m := RepoModel{fadeSuccess: tt.fadeSuccess, fadeFailure: tt.fadeFailure}
-			if got := m.fadeWindow(); got != tt.want {
-				t.Errorf("fadeWindow() = %v, want %v", got, tt.want)
+			if len(rm.feed.Runs) != tt.wantVisible {
+				t.Errorf("visible branch runs = %d, want %d", len(rm.feed.Runs), tt.wantVisible)
 			}
 		})
 	}
@@ -371,20 +318,21 @@ func TestRepoChecksUpdateErrorNonFatal(t *testing.T) {
 	now := time.Now()
 	goodCompletedAt := now.Add(-1 * time.Minute)
 	seedPR := RepoModel{
-		prs: map[int]PRViewData{
-			42: {
-				Title: "Pre-existing PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					{Status: "in_progress", Name: "build"},
-					{Status: "completed", Conclusion: "success", Name: "lint", CompletedAt: &goodCompletedAt},
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				42: {
+					Title: "Pre-existing PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						{Status: "in_progress", Name: "build"},
+						{Status: "completed", Conclusion: "success", Name: "lint", CompletedAt: &goodCompletedAt},
+					},
+					HeadPushedTime: now.Add(-2 * time.Minute),
 				},
-				HeadPushedTime: now.Add(-2 * time.Minute),
 			},
+			FadeSuccess: fadeSuccess,
+			FadeFailure: fadeFailure,
+			Quota:       watch.Quota{Received: true, Remaining: 4000},
 		},
-		fadeSuccess:        fadeSuccess,
-		fadeFailure:        fadeFailure,
-		fetchReceived:      true,
-		rateLimitRemaining: 4000,
 	}
 
 	t.Run("error preserves last good state and sets fetchErrChecks", func(t *testing.T) {
@@ -406,19 +354,19 @@ func TestRepoChecksUpdateErrorNonFatal(t *testing.T) {
 		if rm.fetchErrRuns != nil {
 			t.Error("fetchErrRuns should not be touched by a checks-source error")
 		}
-		if rm.fetchReceived != true {
+		if rm.feed.Quota.Received != true {
 			t.Error("fetchReceived should remain true after error (already received before)")
 		}
 		// Last good PRs preserved.
-		if len(rm.prs) != 1 {
-			t.Errorf("prs = %d, want 1 (last good state preserved)", len(rm.prs))
+		if len(rm.feed.PRs) != 1 {
+			t.Errorf("prs = %d, want 1 (last good state preserved)", len(rm.feed.PRs))
 		}
-		if _, ok := rm.prs[42]; !ok {
+		if _, ok := rm.feed.PRs[42]; !ok {
 			t.Error("PR #42 should still be present after error")
 		}
 		// Rate limit should be unchanged from before (not reset to 5000-default-on-error).
-		if rm.rateLimitRemaining != 4000 {
-			t.Errorf("rateLimitRemaining = %d, want 4000 (unchanged on error)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4000 {
+			t.Errorf("rateLimitRemaining = %d, want 4000 (unchanged on error)", rm.feed.Quota.Remaining)
 		}
 	})
 
@@ -461,22 +409,22 @@ func TestRepoChecksUpdateErrorNonFatal(t *testing.T) {
 		if rm.fetchErrRunsAt.IsZero() {
 			t.Error("fetchErrRunsAt should survive a checks-source success")
 		}
-		if rm.fetchReceived != true {
+		if rm.feed.Quota.Received != true {
 			t.Error("fetchReceived should remain true")
 		}
 		// Fix #4: handleRepoChecksUpdate now applies the min-across-sources
 		// guard, so a 4900 message against a seeded 4000 leaves 4000 in
 		// place rather than overwriting with 4900.
-		if rm.rateLimitRemaining != 4000 {
-			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4000 {
+			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.feed.Quota.Remaining)
 		}
 		// The PR map is replaced wholesale with the new message's PRs (fade-out
 		// only filters within the new message — it does not carry over PRs
 		// from the previous render). So only PR #7 should be present.
-		if len(rm.prs) != 1 {
-			t.Errorf("prs = %d, want 1 (new message replaces map)", len(rm.prs))
+		if len(rm.feed.PRs) != 1 {
+			t.Errorf("prs = %d, want 1 (new message replaces map)", len(rm.feed.PRs))
 		}
-		if _, ok := rm.prs[7]; !ok {
+		if _, ok := rm.feed.PRs[7]; !ok {
 			t.Error("PR #7 should be present after success")
 		}
 	})
@@ -513,11 +461,12 @@ func TestRepoRunsUpdateErrorNonFatal(t *testing.T) {
 		{RunID: 1, Status: "in_progress", Event: "push", HeadBranch: "main"},
 	}
 	m := RepoModel{
-		standaloneRuns:     seedRuns,
-		fadeSuccess:        fadeSuccess,
-		fadeFailure:        fadeFailure,
-		fetchReceived:      true,
-		rateLimitRemaining: 4000,
+		feed: watch.Repo{
+			Runs:        seedRuns,
+			FadeSuccess: fadeSuccess,
+			FadeFailure: fadeFailure,
+			Quota:       watch.Quota{Received: true, Remaining: 4000},
+		},
 	}
 
 	t.Run("error preserves last good runs and sets fetchErrRuns", func(t *testing.T) {
@@ -538,8 +487,8 @@ func TestRepoRunsUpdateErrorNonFatal(t *testing.T) {
 		if rm.fetchErrChecks != nil {
 			t.Error("fetchErrChecks should not be touched by a runs-source error")
 		}
-		if len(rm.standaloneRuns) != 1 {
-			t.Errorf("standaloneRuns = %d, want 1 (last good state preserved)", len(rm.standaloneRuns))
+		if len(rm.feed.Runs) != 1 {
+			t.Errorf("standaloneRuns = %d, want 1 (last good state preserved)", len(rm.feed.Runs))
 		}
 	})
 
@@ -572,11 +521,11 @@ func TestRepoRunsUpdateErrorNonFatal(t *testing.T) {
 		if rm.fetchErrChecksAt.IsZero() {
 			t.Error("fetchErrChecksAt should survive a runs-source success")
 		}
-		if len(rm.standaloneRuns) != 1 {
-			t.Errorf("standaloneRuns = %d, want 1 (new active run)", len(rm.standaloneRuns))
+		if len(rm.feed.Runs) != 1 {
+			t.Errorf("standaloneRuns = %d, want 1 (new active run)", len(rm.feed.Runs))
 		}
-		if rm.standaloneRuns[0].RunID != 2 {
-			t.Errorf("standaloneRuns[0].RunID = %d, want 2", rm.standaloneRuns[0].RunID)
+		if rm.feed.Runs[0].RunID != 2 {
+			t.Errorf("standaloneRuns[0].RunID = %d, want 2", rm.feed.Runs[0].RunID)
 		}
 	})
 
@@ -613,11 +562,12 @@ func TestRepoRunsUpdateErrorNonFatal(t *testing.T) {
 func TestRepoChecksUpdateRateLimitMinAcrossSources(t *testing.T) {
 	t.Run("min wins when checks quota is higher", func(t *testing.T) {
 		m := RepoModel{
-			prs:                make(map[int]PRViewData),
-			fadeSuccess:        15 * time.Minute,
-			fadeFailure:        30 * time.Minute,
-			fetchReceived:      true,
-			rateLimitRemaining: 4000,
+			feed: watch.Repo{
+				PRs:         make(map[int]PRViewData),
+				FadeSuccess: 15 * time.Minute,
+				FadeFailure: 30 * time.Minute,
+				Quota:       watch.Quota{Received: true, Remaining: 4000},
+			},
 		}
 		msg := RepoChecksUpdateMsg{
 			PRData:             map[int]ghclient.PRCheckData{},
@@ -625,17 +575,19 @@ func TestRepoChecksUpdateRateLimitMinAcrossSources(t *testing.T) {
 		}
 		newModel, _ := m.Update(msg)
 		rm := newModel.(*RepoModel)
-		if rm.rateLimitRemaining != 4000 {
-			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4000 {
+			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.feed.Quota.Remaining)
 		}
 	})
 
 	t.Run("first observed wins on a fresh model", func(t *testing.T) {
 		m := RepoModel{
-			prs:           make(map[int]PRViewData),
-			fadeSuccess:   15 * time.Minute,
-			fadeFailure:   30 * time.Minute,
-			fetchReceived: false,
+			feed: watch.Repo{
+				PRs:         make(map[int]PRViewData),
+				FadeSuccess: 15 * time.Minute,
+				FadeFailure: 30 * time.Minute,
+				Quota:       watch.Quota{Received: false},
+			},
 		}
 		msg := RepoChecksUpdateMsg{
 			PRData:             map[int]ghclient.PRCheckData{},
@@ -643,10 +595,10 @@ func TestRepoChecksUpdateRateLimitMinAcrossSources(t *testing.T) {
 		}
 		newModel, _ := m.Update(msg)
 		rm := newModel.(*RepoModel)
-		if rm.rateLimitRemaining != 4500 {
-			t.Errorf("rateLimitRemaining = %d, want 4500 (first observed)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4500 {
+			t.Errorf("rateLimitRemaining = %d, want 4500 (first observed)", rm.feed.Quota.Remaining)
 		}
-		if !rm.fetchReceived {
+		if !rm.feed.Quota.Received {
 			t.Error("fetchReceived should be true after first successful checks update")
 		}
 	})
@@ -658,11 +610,12 @@ func TestRepoChecksUpdateRateLimitMinAcrossSources(t *testing.T) {
 func TestRepoRunsUpdateRateLimitMinAcrossSources(t *testing.T) {
 	t.Run("min wins when runs quota is higher", func(t *testing.T) {
 		m := RepoModel{
-			standaloneRuns:     []ghclient.BranchRunData{},
-			fadeSuccess:        15 * time.Minute,
-			fadeFailure:        30 * time.Minute,
-			fetchReceived:      true,
-			rateLimitRemaining: 4000,
+			feed: watch.Repo{
+				Runs:        []ghclient.BranchRunData{},
+				FadeSuccess: 15 * time.Minute,
+				FadeFailure: 30 * time.Minute,
+				Quota:       watch.Quota{Received: true, Remaining: 4000},
+			},
 		}
 		msg := RepoRunsUpdateMsg{
 			Runs:               []ghclient.BranchRunData{},
@@ -670,17 +623,19 @@ func TestRepoRunsUpdateRateLimitMinAcrossSources(t *testing.T) {
 		}
 		newModel, _ := m.Update(msg)
 		rm := newModel.(*RepoModel)
-		if rm.rateLimitRemaining != 4000 {
-			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4000 {
+			t.Errorf("rateLimitRemaining = %d, want 4000 (min across sources)", rm.feed.Quota.Remaining)
 		}
 	})
 
 	t.Run("first observed wins on a fresh model", func(t *testing.T) {
 		m := RepoModel{
-			standaloneRuns: []ghclient.BranchRunData{},
-			fadeSuccess:    15 * time.Minute,
-			fadeFailure:    30 * time.Minute,
-			fetchReceived:  false,
+			feed: watch.Repo{
+				Runs:        []ghclient.BranchRunData{},
+				FadeSuccess: 15 * time.Minute,
+				FadeFailure: 30 * time.Minute,
+				Quota:       watch.Quota{Received: false},
+			},
 		}
 		msg := RepoRunsUpdateMsg{
 			Runs:               []ghclient.BranchRunData{},
@@ -688,10 +643,10 @@ func TestRepoRunsUpdateRateLimitMinAcrossSources(t *testing.T) {
 		}
 		newModel, _ := m.Update(msg)
 		rm := newModel.(*RepoModel)
-		if rm.rateLimitRemaining != 4500 {
-			t.Errorf("rateLimitRemaining = %d, want 4500 (first observed)", rm.rateLimitRemaining)
+		if rm.feed.Quota.Remaining != 4500 {
+			t.Errorf("rateLimitRemaining = %d, want 4500 (first observed)", rm.feed.Quota.Remaining)
 		}
-		if !rm.fetchReceived {
+		if !rm.feed.Quota.Received {
 			t.Error("fetchReceived should be true after first successful runs update")
 		}
 	})
@@ -700,11 +655,11 @@ func TestRepoRunsUpdateRateLimitMinAcrossSources(t *testing.T) {
 func TestRepoModelFetchReceivedGatesRateLimit(t *testing.T) {
 	// Verify the fetchReceived flag starts false on a fresh model.
 	m := NewRepoModel(
-		context.Background(), "tok", "o", "r",
+		context.Background(), nil, "o", "r",
 		30*time.Second, NewStyles(10, 9, 11, 8), true,
 		15*time.Minute, 30*time.Minute,
 	)
-	if m.fetchReceived {
+	if m.feed.Quota.Received {
 		t.Error("fetchReceived should be false on a fresh model")
 	}
 
@@ -715,7 +670,7 @@ func TestRepoModelFetchReceivedGatesRateLimit(t *testing.T) {
 	}
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
-	if !rm.fetchReceived {
+	if !rm.feed.Quota.Received {
 		t.Error("fetchReceived should be true after a successful checks update")
 	}
 }
@@ -787,19 +742,21 @@ var makeDedupBranchJob = makeDedupPRCheck
 func TestRepoRunsDedupAgainstPRs(t *testing.T) {
 	const sharedSHA = "abc123"
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			7: {
-				Title: "Add feature",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
-					makeDedupPRCheck("CI", "lint"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				7: {
+					Title: "Add feature",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+						makeDedupPRCheck("CI", "lint"),
+					},
+					HeadSHA: sharedSHA,
 				},
-				HeadSHA: sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -825,9 +782,9 @@ func TestRepoRunsDedupAgainstPRs(t *testing.T) {
 
 	// The run shares a commit with PR #7 and should be removed from the
 	// standalone section entirely (its extras attach under the PR).
-	if len(rm.standaloneRuns) != 0 {
-		t.Errorf("standaloneRuns = %d, want 0 (run's commit matches PR #7)", len(rm.standaloneRuns))
-		for _, r := range rm.standaloneRuns {
+	if len(rm.feed.Runs) != 0 {
+		t.Errorf("standaloneRuns = %d, want 0 (run's commit matches PR #7)", len(rm.feed.Runs))
+		for _, r := range rm.feed.Runs {
 			t.Logf("  leftover standalone: RunID=%d HeadSHA=%q jobs=%d", r.RunID, r.HeadSHA, len(r.Jobs))
 		}
 	}
@@ -835,7 +792,7 @@ func TestRepoRunsDedupAgainstPRs(t *testing.T) {
 	// PR #7 should now carry the Copilot extra in addition to its own checks.
 	// Extras land in ExtraCheckRuns (separate from CheckRuns) so the next
 	// PR-checks poll can replace CheckRuns without wiping the extras.
-	pr := rm.prs[7]
+	pr := rm.feed.PRs[7]
 	if len(pr.CheckRuns) != 2 {
 		t.Fatalf("PR #7 CheckRuns = %d, want 2 (original GraphQL checks untouched)", len(pr.CheckRuns))
 	}
@@ -864,18 +821,20 @@ func TestRepoRunsDedupAgainstPRs(t *testing.T) {
 // matches no tracked PR stays in the standalone section with all its jobs.
 func TestRepoRunsDedupStandaloneNoMatchingPR(t *testing.T) {
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			7: {
-				Title: "Add feature",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				7: {
+					Title: "Add feature",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					HeadSHA: "pr-sha-aaa",
 				},
-				HeadSHA: "pr-sha-aaa",
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -898,10 +857,10 @@ func TestRepoRunsDedupStandaloneNoMatchingPR(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 1 {
-		t.Fatalf("standaloneRuns = %d, want 1 (no matching PR)", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 1 {
+		t.Fatalf("standaloneRuns = %d, want 1 (no matching PR)", len(rm.feed.Runs))
 	}
-	run := rm.standaloneRuns[0]
+	run := rm.feed.Runs[0]
 	if run.RunID != 200 {
 		t.Errorf("standaloneRuns[0].RunID = %d, want 200", run.RunID)
 	}
@@ -909,21 +868,23 @@ func TestRepoRunsDedupStandaloneNoMatchingPR(t *testing.T) {
 		t.Errorf("standaloneRuns[0].Jobs = %d, want 2 (no dedup against unmatched PR)", len(run.Jobs))
 	}
 	// PR #7 must be unchanged: no extras attached.
-	if len(rm.prs[7].CheckRuns) != 1 {
-		t.Errorf("PR #7 CheckRuns = %d, want 1 (unchanged)", len(rm.prs[7].CheckRuns))
+	if len(rm.feed.PRs[7].CheckRuns) != 1 {
+		t.Errorf("PR #7 CheckRuns = %d, want 1 (unchanged)", len(rm.feed.PRs[7].CheckRuns))
 	}
 }
 
-// TestRepoRunsDedupNoPRsSkipsDedup verifies that when m.prs is empty (PR data
+// TestRepoRunsDedupNoPRsSkipsDedup verifies that when the PRs are empty (PR data
 // not yet arrived), dedupeAndAttachExtraJobs is a no-op and all runs stay
 // standalone. This covers the ordering case where RepoRunsUpdateMsg arrives
 // before RepoChecksUpdateMsg.
 func TestRepoRunsDedupNoPRsSkipsDedup(t *testing.T) {
 	m := RepoModel{
-		prs:           make(map[int]PRViewData),
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
+		feed: watch.Repo{
+			PRs:         make(map[int]PRViewData),
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
+		},
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -945,11 +906,11 @@ func TestRepoRunsDedupNoPRsSkipsDedup(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 1 {
-		t.Fatalf("standaloneRuns = %d, want 1 (no PRs to dedup against)", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 1 {
+		t.Fatalf("standaloneRuns = %d, want 1 (no PRs to dedup against)", len(rm.feed.Runs))
 	}
-	if len(rm.standaloneRuns[0].Jobs) != 1 {
-		t.Errorf("Jobs = %d, want 1 (unchanged)", len(rm.standaloneRuns[0].Jobs))
+	if len(rm.feed.Runs[0].Jobs) != 1 {
+		t.Errorf("Jobs = %d, want 1 (unchanged)", len(rm.feed.Runs[0].Jobs))
 	}
 }
 
@@ -964,18 +925,20 @@ func TestRepoRunsDedupMatchingPRRunAllJobsDuped(t *testing.T) {
 	recentStart := now.Add(-5 * time.Minute)
 
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			1: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				1: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					HeadSHA: sharedSHA,
 				},
-				HeadSHA: sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -999,15 +962,15 @@ func TestRepoRunsDedupMatchingPRRunAllJobsDuped(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 0 {
-		t.Errorf("standaloneRuns = %d, want 0 (run matches PR #1, dropped)", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 0 {
+		t.Errorf("standaloneRuns = %d, want 0 (run matches PR #1, dropped)", len(rm.feed.Runs))
 	}
 	// PR unchanged — no extras to attach.
-	if len(rm.prs[1].CheckRuns) != 1 {
-		t.Errorf("PR #1 CheckRuns = %d, want 1", len(rm.prs[1].CheckRuns))
+	if len(rm.feed.PRs[1].CheckRuns) != 1 {
+		t.Errorf("PR #1 CheckRuns = %d, want 1", len(rm.feed.PRs[1].CheckRuns))
 	}
-	if len(rm.prs[1].ExtraCheckRuns) != 0 {
-		t.Errorf("PR #1 ExtraCheckRuns = %d, want 0 (all jobs were dupes)", len(rm.prs[1].ExtraCheckRuns))
+	if len(rm.feed.PRs[1].ExtraCheckRuns) != 0 {
+		t.Errorf("PR #1 ExtraCheckRuns = %d, want 0 (all jobs were dupes)", len(rm.feed.PRs[1].ExtraCheckRuns))
 	}
 }
 
@@ -1016,27 +979,29 @@ func TestRepoRunsDedupMatchingPRRunAllJobsDuped(t *testing.T) {
 // completed run whose SHA matches NO tracked PR but whose only job duplicates
 // a job already present in some PR's CheckRuns is dropped from the standalone
 // section (rather than rendered as an empty header). This exercises the
-// `len(leftover) == 0 && !isActiveBranchRun(run.Status)` path, distinct from
-// the matching-PR path tested above.
+// `len(leftover) == 0 && !watch.IsActiveRun(run.Status)` path that
+// Repo.ApplyRuns reaches, distinct from the matching-PR path tested above.
 func TestRepoRunsDedupStandaloneCompletedRunAllJobsDupedDropped(t *testing.T) {
 	const prSHA = "sha-pr1"
 	const runSHA = "sha-run-other" // does NOT match the PR
 
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			1: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					// PR carries a build job on its own SHA, so the dedup key
-					// (prSHA, "CI", "build") is in the seen set.
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				1: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						// PR carries a build job on its own SHA, so the dedup key
+						// (prSHA, "CI", "build") is in the seen set.
+						makeDedupPRCheck("CI", "build"),
+					},
+					HeadSHA: prSHA,
 				},
-				HeadSHA: prSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -1063,11 +1028,11 @@ func TestRepoRunsDedupStandaloneCompletedRunAllJobsDupedDropped(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 0 {
-		t.Errorf("standaloneRuns = %d, want 0 (completed run with zero jobs dropped)", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 0 {
+		t.Errorf("standaloneRuns = %d, want 0 (completed run with zero jobs dropped)", len(rm.feed.Runs))
 	}
-	if len(rm.prs[1].ExtraCheckRuns) != 0 {
-		t.Errorf("PR #1 ExtraCheckRuns = %d, want 0 (SHA mismatch, no attach)", len(rm.prs[1].ExtraCheckRuns))
+	if len(rm.feed.PRs[1].ExtraCheckRuns) != 0 {
+		t.Errorf("PR #1 ExtraCheckRuns = %d, want 0 (SHA mismatch, no attach)", len(rm.feed.PRs[1].ExtraCheckRuns))
 	}
 }
 
@@ -1078,18 +1043,20 @@ func TestRepoRunsDedupStandaloneCompletedRunAllJobsDupedDropped(t *testing.T) {
 func TestRepoRunsDedupActiveRunMatchesPRKeepsExtrasAttached(t *testing.T) {
 	const sharedSHA = "sha-eee"
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			5: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				5: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					HeadSHA: sharedSHA,
 				},
-				HeadSHA: sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -1112,14 +1079,14 @@ func TestRepoRunsDedupActiveRunMatchesPRKeepsExtrasAttached(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 0 {
-		t.Errorf("standaloneRuns = %d, want 0 (run's commit matches PR #5)", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 0 {
+		t.Errorf("standaloneRuns = %d, want 0 (run's commit matches PR #5)", len(rm.feed.Runs))
 	}
-	if len(rm.prs[5].CheckRuns) != 1 {
-		t.Fatalf("PR #5 CheckRuns = %d, want 1 (original untouched)", len(rm.prs[5].CheckRuns))
+	if len(rm.feed.PRs[5].CheckRuns) != 1 {
+		t.Fatalf("PR #5 CheckRuns = %d, want 1 (original untouched)", len(rm.feed.PRs[5].CheckRuns))
 	}
-	if len(rm.prs[5].ExtraCheckRuns) != 1 {
-		t.Errorf("PR #5 ExtraCheckRuns = %d, want 1 (Copilot extra)", len(rm.prs[5].ExtraCheckRuns))
+	if len(rm.feed.PRs[5].ExtraCheckRuns) != 1 {
+		t.Errorf("PR #5 ExtraCheckRuns = %d, want 1 (Copilot extra)", len(rm.feed.PRs[5].ExtraCheckRuns))
 	}
 }
 
@@ -1131,21 +1098,23 @@ func TestRepoRunsDedupActiveRunMatchesPRKeepsExtrasAttached(t *testing.T) {
 func TestRepoRunsDedupDropsStaleExtras(t *testing.T) {
 	const sharedSHA = "sha-stale"
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			5: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				5: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					// Previously-attached extra from a Copilot run that has now
+					// faded out of the visible runs window.
+					ExtraCheckRuns: []ghclient.CheckRunInfo{makeDedupBranchJob("Copilot", "coding-agent")},
+					HeadSHA:        sharedSHA,
 				},
-				// Previously-attached extra from a Copilot run that has now
-				// faded out of the visible runs window.
-				ExtraCheckRuns: []ghclient.CheckRunInfo{makeDedupBranchJob("Copilot", "coding-agent")},
-				HeadSHA:        sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	// New runs poll reports no runs at all for this SHA — the Copilot run
@@ -1158,12 +1127,12 @@ func TestRepoRunsDedupDropsStaleExtras(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.prs[5].CheckRuns) != 1 {
-		t.Fatalf("PR #5 CheckRuns = %d, want 1 (untouched)", len(rm.prs[5].CheckRuns))
+	if len(rm.feed.PRs[5].CheckRuns) != 1 {
+		t.Fatalf("PR #5 CheckRuns = %d, want 1 (untouched)", len(rm.feed.PRs[5].CheckRuns))
 	}
-	if len(rm.prs[5].ExtraCheckRuns) != 0 {
+	if len(rm.feed.PRs[5].ExtraCheckRuns) != 0 {
 		t.Errorf("PR #5 ExtraCheckRuns = %d, want 0 (stale Copilot extra must be dropped when its run fades)",
-			len(rm.prs[5].ExtraCheckRuns))
+			len(rm.feed.PRs[5].ExtraCheckRuns))
 	}
 }
 
@@ -1175,19 +1144,21 @@ func TestRepoRunsDedupReattachesStillVisibleExtra(t *testing.T) {
 	const sharedSHA = "sha-reattach"
 	copilot := makeDedupBranchJob("Copilot", "coding-agent")
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			5: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				5: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					ExtraCheckRuns: []ghclient.CheckRunInfo{copilot},
+					HeadSHA:        sharedSHA,
 				},
-				ExtraCheckRuns: []ghclient.CheckRunInfo{copilot},
-				HeadSHA:        sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -1208,12 +1179,12 @@ func TestRepoRunsDedupReattachesStillVisibleExtra(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.prs[5].ExtraCheckRuns) != 1 {
+	if len(rm.feed.PRs[5].ExtraCheckRuns) != 1 {
 		t.Fatalf("PR #5 ExtraCheckRuns = %d, want 1 (still-visible extra must re-attach)",
-			len(rm.prs[5].ExtraCheckRuns))
+			len(rm.feed.PRs[5].ExtraCheckRuns))
 	}
-	if rm.prs[5].ExtraCheckRuns[0].Name != "coding-agent" {
-		t.Errorf("ExtraCheckRuns[0].Name = %q, want %q", rm.prs[5].ExtraCheckRuns[0].Name, "coding-agent")
+	if rm.feed.PRs[5].ExtraCheckRuns[0].Name != "coding-agent" {
+		t.Errorf("ExtraCheckRuns[0].Name = %q, want %q", rm.feed.PRs[5].ExtraCheckRuns[0].Name, "coding-agent")
 	}
 }
 
@@ -1222,18 +1193,20 @@ func TestRepoRunsDedupReattachesStillVisibleExtra(t *testing.T) {
 func TestRepoRunsDedupMultipleRunsSameSHA(t *testing.T) {
 	const sharedSHA = "sha-fff"
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			9: {
-				Title: "PR",
-				CheckRuns: []ghclient.CheckRunInfo{
-					makeDedupPRCheck("CI", "build"),
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				9: {
+					Title: "PR",
+					CheckRuns: []ghclient.CheckRunInfo{
+						makeDedupPRCheck("CI", "build"),
+					},
+					HeadSHA: sharedSHA,
 				},
-				HeadSHA: sharedSHA,
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	msg := RepoRunsUpdateMsg{
@@ -1263,83 +1236,22 @@ func TestRepoRunsDedupMultipleRunsSameSHA(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if len(rm.standaloneRuns) != 0 {
-		t.Errorf("standaloneRuns = %d, want 0", len(rm.standaloneRuns))
+	if len(rm.feed.Runs) != 0 {
+		t.Errorf("standaloneRuns = %d, want 0", len(rm.feed.Runs))
 	}
-	if len(rm.prs[9].CheckRuns) != 1 {
-		t.Fatalf("PR #9 CheckRuns = %d, want 1 (original untouched)", len(rm.prs[9].CheckRuns))
+	if len(rm.feed.PRs[9].CheckRuns) != 1 {
+		t.Fatalf("PR #9 CheckRuns = %d, want 1 (original untouched)", len(rm.feed.PRs[9].CheckRuns))
 	}
-	if len(rm.prs[9].ExtraCheckRuns) != 1 {
-		t.Fatalf("PR #9 ExtraCheckRuns = %d, want 1 (one Copilot extra, not duplicated)", len(rm.prs[9].ExtraCheckRuns))
+	if len(rm.feed.PRs[9].ExtraCheckRuns) != 1 {
+		t.Fatalf("PR #9 ExtraCheckRuns = %d, want 1 (one Copilot extra, not duplicated)", len(rm.feed.PRs[9].ExtraCheckRuns))
 	}
 }
 
 // TestJobDedupKey verifies the canonical key format (NUL-separated) and
 // lowercasing. NUL is used as the field delimiter so a workflow or job name
 // containing "|" can't collide with another key split across fields.
-func TestJobDedupKey(t *testing.T) {
-	const sep = "\x00"
-	tests := []struct {
-		headSHA, workflow, name string
-		want                    string
-	}{
-		{"abc", "CI", "Build", "abc" + sep + "ci" + sep + "build"},
-		{"", "", "lint", sep + sep + "lint"},
-		{"DEF", "CI", "Build", "def" + sep + "ci" + sep + "build"},
-		// A workflow name containing "|" must not collide with a name-only split.
-		{"abc", "CI|extra", "build", "abc" + sep + "ci|extra" + sep + "build"},
-		{"abc", "CI", "extra|build", "abc" + sep + "ci" + sep + "extra|build"},
-	}
-	for _, tt := range tests {
-		if got := jobDedupKey(tt.headSHA, tt.workflow, tt.name); got != tt.want {
-			t.Errorf("jobDedupKey(%q,%q,%q) = %q, want %q", tt.headSHA, tt.workflow, tt.name, got, tt.want)
-		}
-	}
-}
-
-// TestPRByHeadSHA verifies PR lookup by head SHA.
-func TestPRByHeadSHA(t *testing.T) {
-	m := RepoModel{
-		prs: map[int]PRViewData{
-			1: {HeadSHA: "aaa"},
-			2: {HeadSHA: "bbb"},
-		},
-	}
-	if got := m.prByHeadSHA("bbb"); got != 2 {
-		t.Errorf("prByHeadSHA(bbb) = %d, want 2", got)
-	}
-	if got := m.prByHeadSHA("zzz"); got != 0 {
-		t.Errorf("prByHeadSHA(zzz) = %d, want 0", got)
-	}
-	if got := m.prByHeadSHA(""); got != 0 {
-		t.Errorf("prByHeadSHA(empty) = %d, want 0 (empty SHA never matches)", got)
-	}
-}
-
-// TestPRByHeadSHADeterministic verifies that when multiple PRs share the same
-// HeadSHA, prByHeadSHA returns the lowest PR number deterministically rather
-// than whichever entry Go's map iteration happens to yield first.
-func TestPRByHeadSHADeterministic(t *testing.T) {
-	const sharedSHA = "deadbeef"
-	// Insert in reverse order to ensure the result isn't just "first inserted".
-	// Use enough entries to make map-iteration nondeterminism likely to surface
-	// across runs, and run the lookup many times to catch any ordering drift.
-	m := RepoModel{
-		prs: map[int]PRViewData{
-			42: {HeadSHA: sharedSHA},
-			7:  {HeadSHA: sharedSHA},
-			99: {HeadSHA: sharedSHA},
-		},
-	}
-	for i := 0; i < 50; i++ {
-		if got := m.prByHeadSHA(sharedSHA); got != 7 {
-			t.Fatalf("iteration %d: prByHeadSHA(%q) = %d, want 7 (lowest matching PR number)", i, sharedSHA, got)
-		}
-	}
-}
-
 // TestRepoChecksUpdatePreservesExtras verifies the flicker fix: a
-// RepoChecksUpdateMsg replaces m.prs wholesale, but extras attached by the
+// RepoChecksUpdateMsg replaces the PRs wholesale, but extras attached by the
 // last runs-poll must survive so Copilot-style jobs don't vanish between runs
 // ticks. The PR-checks poll must carry forward ExtraCheckRuns from the prior
 // PRViewData for the same PR number.
@@ -1349,17 +1261,19 @@ func TestRepoChecksUpdatePreservesExtras(t *testing.T) {
 
 	// Seed: PR #7 with GraphQL checks and a previously-attached Copilot extra.
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			7: {
-				Title:          "Add feature",
-				CheckRuns:      []ghclient.CheckRunInfo{makeDedupPRCheck("CI", "build")},
-				ExtraCheckRuns: []ghclient.CheckRunInfo{copilot},
-				HeadSHA:        sharedSHA,
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				7: {
+					Title:          "Add feature",
+					CheckRuns:      []ghclient.CheckRunInfo{makeDedupPRCheck("CI", "build")},
+					ExtraCheckRuns: []ghclient.CheckRunInfo{copilot},
+					HeadSHA:        sharedSHA,
+				},
 			},
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   15 * time.Minute,
-		fadeFailure:   30 * time.Minute,
-		fetchReceived: true,
 	}
 
 	// A new PR-checks poll arrives with the same PR (still active) but the
@@ -1382,7 +1296,7 @@ func TestRepoChecksUpdatePreservesExtras(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	pr := rm.prs[7]
+	pr := rm.feed.PRs[7]
 	if len(pr.CheckRuns) != 2 {
 		t.Errorf("CheckRuns = %d, want 2 (fresh GraphQL checks)", len(pr.CheckRuns))
 	}
@@ -1405,17 +1319,19 @@ func TestRepoChecksUpdateDropsExtrasWhenPRFades(t *testing.T) {
 	fadeFailure := 30 * time.Minute
 
 	m := RepoModel{
-		prs: map[int]PRViewData{
-			7: {
-				Title:          "Add feature",
-				CheckRuns:      []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success", Name: "build", CompletedAt: &fadedCompletedAt}},
-				ExtraCheckRuns: []ghclient.CheckRunInfo{makeDedupBranchJob("Copilot", "coding-agent")},
-				HeadSHA:        "sha-fade",
+		feed: watch.Repo{
+			PRs: map[int]PRViewData{
+				7: {
+					Title:          "Add feature",
+					CheckRuns:      []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success", Name: "build", CompletedAt: &fadedCompletedAt}},
+					ExtraCheckRuns: []ghclient.CheckRunInfo{makeDedupBranchJob("Copilot", "coding-agent")},
+					HeadSHA:        "sha-fade",
+				},
 			},
+			FadeSuccess: fadeSuccess,
+			FadeFailure: fadeFailure,
+			Quota:       watch.Quota{Received: true},
 		},
-		fadeSuccess:   fadeSuccess,
-		fadeFailure:   fadeFailure,
-		fetchReceived: true,
 	}
 
 	// PR-checks poll reports only the already-faded build check -> PR drops.
@@ -1436,7 +1352,360 @@ func TestRepoChecksUpdateDropsExtrasWhenPRFades(t *testing.T) {
 	newModel, _ := m.Update(msg)
 	rm := newModel.(*RepoModel)
 
-	if _, ok := rm.prs[7]; ok {
+	if _, ok := rm.feed.PRs[7]; ok {
 		t.Error("PR #7 should have been dropped (all checks faded)")
 	}
 }
+
+func TestRepoRunsUpdateCapturesQueueJobsBeforeDedup(t *testing.T) {
+	queuedAt := time.Now().Add(-2 * time.Minute)
+	startedAt := queuedAt.Add(40 * time.Second)
+
+	m := &RepoModel{
+		feed: watch.Repo{
+			FadeSuccess: 15 * time.Minute,
+			FadeFailure: 30 * time.Minute,
+			PRs: map[int]PRViewData{
+				7: {
+					Title:     "PR",
+					HeadSHA:   "sha1",
+					CheckRuns: []ghclient.CheckRunInfo{makeDedupPRCheck("CI", "build")},
+				},
+			},
+		},
+		noAvg: true,
+	}
+
+	runs := []ghclient.BranchRunData{
+		{
+			RunID: 1, HeadSHA: "sha1", WorkflowID: 11, Status: "in_progress",
+			Jobs: []ghclient.CheckRunInfo{{
+				Name: "build", WorkflowName: "CI", Status: "in_progress",
+				Labels: []string{"ubuntu-latest"}, QueuedAt: &queuedAt, StartedAt: &startedAt,
+			}},
+		},
+		{
+			RunID: 2, HeadSHA: "sha2", WorkflowID: 11, Status: "queued",
+			Jobs: []ghclient.CheckRunInfo{{
+				Name: "gpu-test", WorkflowName: "CI", Status: "queued",
+				Labels: []string{"self-hosted", "gpu"}, QueuedAt: &queuedAt,
+			}},
+		},
+	}
+
+	_, cmd := m.handleRepoRunsUpdate(RepoRunsUpdateMsg{Runs: runs, RateLimitRemaining: 4000})
+
+	if len(m.queueJobs) != 2 {
+		t.Fatalf("expected 2 queue jobs (including the one deduped against the PR), got %d", len(m.queueJobs))
+	}
+	if len(m.queueWorkflowIDs) != 1 || m.queueWorkflowIDs[0] != 11 {
+		t.Errorf("queueWorkflowIDs = %v, want [11]", m.queueWorkflowIDs)
+	}
+	if cmd != nil {
+		t.Error("no history fetch should be dispatched while the queue pane is hidden")
+	}
+
+	samples := ghclient.QueueSamplesByLabel(m.queueJobs, time.Now())
+	if got := samples["ubuntu-latest"]; len(got) != 1 || got[0] != 40*time.Second {
+		t.Errorf("ubuntu-latest samples = %v, want [40s]", got)
+	}
+	if _, ok := samples["self-hosted, gpu"]; !ok {
+		t.Errorf("expected waiting gpu job to contribute a sample, got %v", samples)
+	}
+}
+
+func TestRepoQueueHistoryCmds(t *testing.T) {
+	t.Run("dispatches once per workflow while shown", func(t *testing.T) {
+		m := &RepoModel{
+			ctx:              context.Background(),
+			showQueueStats:   true,
+			queueWorkflowIDs: []int64{11, 12},
+		}
+		if got := len(m.queueHistoryCmds()); got != 2 {
+			t.Fatalf("first call dispatched %d fetches, want 2", got)
+		}
+		if got := len(m.queueHistoryPending); got != 2 {
+			t.Errorf("pending = %d, want 2", got)
+		}
+		if got := len(m.queueHistoryCmds()); got != 0 {
+			t.Errorf("second call dispatched %d fetches, want 0", got)
+		}
+	})
+
+	t.Run("hidden pane fetches nothing", func(t *testing.T) {
+		m := &RepoModel{queueWorkflowIDs: []int64{11}}
+		if got := len(m.queueHistoryCmds()); got != 0 {
+			t.Errorf("dispatched %d fetches, want 0", got)
+		}
+	})
+
+	t.Run("low quota fetches nothing", func(t *testing.T) {
+		m := &RepoModel{
+			showQueueStats:   true,
+			queueWorkflowIDs: []int64{11},
+			feed: watch.Repo{
+				Quota: watch.Quota{Received: true, Remaining: minRateLimitForFetch - 1},
+			},
+		}
+		if got := len(m.queueHistoryCmds()); got != 0 {
+			t.Errorf("dispatched %d fetches, want 0", got)
+		}
+		if m.queueHistoryFetched[11] {
+			t.Error("workflow must stay unfetched so it is retried once quota recovers")
+		}
+	})
+}
+
+func TestRepoWorkflowHistoryMerge(t *testing.T) {
+	m := &RepoModel{queueHistoryPending: map[int64]bool{11: true, 12: true}}
+
+	m.handleRepoWorkflowHistory(RepoWorkflowHistoryMsg{
+		WorkflowID: 11,
+		History: &ghclient.WorkflowHistory{
+			QueueByLabel: map[string][]time.Duration{"ubuntu-latest": {10 * time.Second}},
+		},
+	})
+	m.handleRepoWorkflowHistory(RepoWorkflowHistoryMsg{WorkflowID: 12, Err: fmt.Errorf("boom")})
+
+	if len(m.queueHistoryPending) != 0 {
+		t.Errorf("pending = %v, want empty", m.queueHistoryPending)
+	}
+	if got := m.queueHistory["ubuntu-latest"]; len(got) != 1 {
+		t.Errorf("queueHistory = %v, want one ubuntu-latest sample", m.queueHistory)
+	}
+}
+
+func TestRepoRateLimitedFetchShowsCountdown(t *testing.T) {
+	until := time.Now().Add(2 * time.Minute)
+	limited := &ghclient.RateLimitedError{Until: until, Reason: "Retry-After"}
+
+	m := RepoModel{styles: stylesForTest(), feed: watch.Repo{PRs: map[int]PRViewData{}, Quota: watch.Quota{Received: true, Remaining: 4000}}}
+	newModel, _ := m.Update(RepoChecksUpdateMsg{Err: limited})
+	rm := newModel.(*RepoModel)
+	newModel, _ = rm.Update(RepoRunsUpdateMsg{Err: limited})
+	rm = newModel.(*RepoModel)
+
+	if rm.fetchErrChecks != nil || rm.fetchErrRuns != nil {
+		t.Errorf("rate limit recorded as fetch error: %v / %v", rm.fetchErrChecks, rm.fetchErrRuns)
+	}
+	out := rm.View().Content
+	if !strings.Contains(out, "rate limited, resuming in") {
+		t.Errorf("missing countdown:\n%s", out)
+	}
+	if strings.Contains(out, "fetch error") {
+		t.Errorf("unexpected error line:\n%s", out)
+	}
+}
+
+// pressKeys sends each rune of s, then any named keys, as key presses.
+func pressKeys(t *testing.T, m tea.Model, s string, named ...rune) (tea.Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, r := range s {
+		m, cmd = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	for _, code := range named {
+		m, cmd = m.Update(tea.KeyPressMsg{Code: code})
+	}
+	return m, cmd
+}
+
+func TestRepoFilterPrompt(t *testing.T) {
+	m := NewRepoModel(
+		context.Background(), nil, "o", "r",
+		30*time.Second, NewStyles(10, 9, 11, 8), true,
+		15*time.Minute, 30*time.Minute,
+	).WithFilter(ghclient.RepoFilter{Author: "@me"})
+	m.feed.PRs[1] = PRViewData{Title: "old"}
+
+	// q types into the open prompt instead of quitting.
+	var model tea.Model = m
+	model, _ = pressKeys(t, model, "f q")
+	rm := repoModelOf(model)
+	if !rm.prompt.open || rm.quitting {
+		t.Fatalf("filtering = %v, quitting = %v; want prompt open", rm.prompt.open, rm.quitting)
+	}
+	if got := rm.prompt.input.Value(); got != "author:@me q" {
+		t.Errorf("prompt = %q, want the current filter pre-filled", got)
+	}
+
+	// An invalid filter keeps the prompt open with the error.
+	model, _ = pressKeys(t, model, "", tea.KeyEnter)
+	rm = repoModelOf(model)
+	if !rm.prompt.open || rm.prompt.err == nil {
+		t.Fatalf("filtering = %v, filterErr = %v; want prompt kept open with an error", rm.prompt.open, rm.prompt.err)
+	}
+	if !strings.Contains(rm.View().Content, "invalid filter term") {
+		t.Error("view doesn't show the filter error")
+	}
+
+	// Fix it up and apply: the old data is cleared and both sources are
+	// refetched under the new filter.
+	model, _ = pressKeys(t, model, "", tea.KeyBackspace, tea.KeyBackspace)
+	model, cmd := pressKeys(t, model, " label:bug", tea.KeyEnter)
+	rm = repoModelOf(model)
+	want := ghclient.RepoFilter{Author: "@me", Labels: []string{"bug"}}
+	if rm.prompt.open || !rm.feed.Filter.Equal(want) || rm.feed.FilterGen != 1 {
+		t.Fatalf("filtering = %v, filter = %+v, gen = %d; want %+v applied at gen 1", rm.prompt.open, rm.feed.Filter, rm.feed.FilterGen, want)
+	}
+	if len(rm.feed.PRs) != 0 || cmd == nil {
+		t.Errorf("prs = %v, cmd = %v; want data cleared and a refetch", rm.feed.PRs, cmd)
+	}
+	if !strings.Contains(rm.View().Content, "Filter: author:@me label:bug") {
+		t.Error("summary line doesn't show the active filter")
+	}
+
+	// A fetch issued under the old filter is dropped when it lands.
+	model, _ = model.Update(RepoChecksUpdateMsg{PRData: map[int]ghclient.PRCheckData{2: {Number: 2}}, FilterGen: 0})
+	if rm = repoModelOf(model); rm.feed.Quota.Received {
+		t.Error("stale checks update was applied")
+	}
+
+	// Esc closes the prompt without changing anything.
+	model, _ = pressKeys(t, model, "fxyz", tea.KeyEscape)
+	rm = repoModelOf(model)
+	if rm.prompt.open || !rm.feed.Filter.Equal(want) || rm.feed.FilterGen != 1 {
+		t.Errorf("after esc: filtering = %v, filter = %+v, gen = %d", rm.prompt.open, rm.feed.Filter, rm.feed.FilterGen)
+	}
+}
+
+func TestRepoAveragesCmd(t *testing.T) {
+	prs := map[int]PRViewData{
+		7: {CheckRuns: []ghclient.CheckRunInfo{{Name: "build", WorkflowID: 11}, {Name: "test", WorkflowID: 11}}},
+		8: {ExtraCheckRuns: []ghclient.CheckRunInfo{{Name: "lint", WorkflowID: 12}}},
+	}
+	runs := []ghclient.BranchRunData{{RunID: 1, WorkflowID: 13}, {RunID: 2, WorkflowID: 11}}
+
+	t.Run("requests each workflow once", func(t *testing.T) {
+		m := &RepoModel{ctx: context.Background(), feed: watch.Repo{PRs: prs, Runs: runs, Quota: watch.Quota{Received: true, Remaining: 4000}}}
+		if m.averagesCmd() == nil {
+			t.Fatal("first call dispatched no fetch")
+		}
+		if got := slices.Sorted(maps.Keys(m.avgRequested)); !slices.Equal(got, []int64{11, 12, 13}) {
+			t.Errorf("requested = %v, want [11 12 13]", got)
+		}
+		if m.averagesCmd() != nil {
+			t.Error("second call re-fetched already requested workflows")
+		}
+	})
+
+	t.Run("waits while quota is low", func(t *testing.T) {
+		m := &RepoModel{ctx: context.Background(), feed: watch.Repo{PRs: prs, Quota: watch.Quota{Received: true, Remaining: minRateLimitForFetch - 1}}}
+		if m.averagesCmd() != nil || len(m.avgRequested) != 0 {
+			t.Fatal("fetched below minRateLimitForFetch")
+		}
+		m.feed.Quota.Remaining = 4000
+		if m.averagesCmd() == nil {
+			t.Error("no fetch once quota recovered")
+		}
+	})
+
+	t.Run("retries a failed fetch", func(t *testing.T) {
+		m := &RepoModel{ctx: context.Background(), feed: watch.Repo{PRs: prs, Runs: runs, Quota: watch.Quota{Received: true, Remaining: 4000}}}
+		m.averagesCmd()
+		m.handleRepoAverages(RepoAveragesMsg{Errs: map[int64]error{12: errors.New("502 Bad Gateway")}})
+		if m.averagesCmd() == nil {
+			t.Fatal("failed workflow not requested again")
+		}
+		if got := slices.Sorted(maps.Keys(m.avgRequested)); !slices.Equal(got, []int64{11, 12, 13}) {
+			t.Errorf("requested = %v, want [11 12 13]", got)
+		}
+	})
+
+	t.Run("waits while rate limited", func(t *testing.T) {
+		m := &RepoModel{ctx: context.Background(), feed: watch.Repo{PRs: prs, Quota: watch.Quota{Received: true, Remaining: 4000, LimitedUntil: time.Now().Add(time.Minute)}}}
+		if m.averagesCmd() != nil || len(m.avgRequested) != 0 {
+			t.Error("fetched while rate limited")
+		}
+	})
+
+	t.Run("quick", func(t *testing.T) {
+		m := &RepoModel{ctx: context.Background(), feed: watch.Repo{PRs: prs}, noAvg: true}
+		if m.averagesCmd() != nil {
+			t.Error("fetched with noAvg")
+		}
+	})
+}
+
+func TestRepoEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	log, err := eventlog.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &RepoModel{owner: "octo", repo: "hello", feed: watch.Repo{FadeSuccess: 15 * time.Minute, FadeFailure: 30 * time.Minute}, styles: stylesForTest()}
+	*m = m.WithEventLog(log)
+
+	// Both finished well past the fade window, as if while asleep.
+	failedAt := time.Now().Add(-2 * time.Hour)
+	deployedAt := failedAt.Add(7 * time.Minute)
+	checks := RepoChecksUpdateMsg{PRData: map[int]ghclient.PRCheckData{
+		142: {Number: 142, HeadSHA: "pr-head", CheckRuns: []ghclient.CheckRunInfo{
+			{Name: "test", WorkflowName: "CI", Status: "completed", Conclusion: "failure", CompletedAt: &failedAt},
+			{Name: "lint", WorkflowName: "CI", Status: "completed", Conclusion: "skipped", CompletedAt: &failedAt},
+			{Name: "build", WorkflowName: "CI", Status: "in_progress"},
+		}},
+	}}
+	runs := RepoRunsUpdateMsg{Runs: []ghclient.BranchRunData{
+		{RunID: 1, HeadBranch: "main", HeadSHA: "main-head", WorkflowName: "Deploy", Status: "completed", Conclusion: "success", UpdatedAt: deployedAt, RunAttempt: 1},
+		{RunID: 2, HeadBranch: "feature", HeadSHA: "pr-head", WorkflowName: "CI", Status: "completed", Conclusion: "failure", UpdatedAt: failedAt, RunAttempt: 1},
+	}}
+
+	_, cmd := m.handleRepoChecksUpdate(checks)
+	if cmd == nil {
+		t.Fatal("no save for new check events")
+	}
+	m.handleRepoRunsUpdate(runs)
+
+	got := m.repoEvents()
+	want := []string{"main Deploy succeeded", "PR #142 CI / test failed"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %+v, want %q", got, want)
+	}
+	for i, e := range got {
+		if e.Text() != want[i] {
+			t.Errorf("event %d = %q, want %q", i, e.Text(), want[i])
+		}
+	}
+
+	// Seeing them again records nothing new.
+	if _, cmd := m.handleRepoChecksUpdate(checks); cmd != nil {
+		t.Error("re-recorded known check events")
+	}
+
+	m.showEvents = true
+	view := m.View().Content
+	for _, want := range []string{"main Deploy succeeded", "PR #142 CI / test failed", failedAt.Local().Format("15:04")} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A restarted watcher picks them up from the state file.
+	if err := log.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := eventlog.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reopened.Events(nil)); n != 2 {
+		t.Errorf("reopened log has %d events, want 2", n)
+	}
+}
+
+func TestScrollEvents(t *testing.T) {
+	tests := []struct {
+		offset, delta, total, want int
+	}{
+		{offset: 0, delta: eventPaneLines, total: 20, want: eventPaneLines},
+		{offset: eventPaneLines, delta: eventPaneLines, total: 20, want: 20 - eventPaneLines},
+		{offset: 4, delta: -eventPaneLines, total: 20, want: 0},
+		{offset: 0, delta: eventPaneLines, total: 3, want: 0},
+	}
+	for _, tt := range tests {
+		if got := scrollEvents(tt.offset, tt.delta, tt.total); got != tt.want {
+			t.Errorf("scrollEvents(%d, %d, %d) = %d, want %d", tt.offset, tt.delta, tt.total, got, tt.want)
+		}
+	}
+}
@@ -28,14 +28,9 @@ func (m RepoModel) View() tea.View {
 	timeSinceUpdate := time.Since(m.lastUpdate)
 
 	repoHeader := m.styles.Header.Render(fmt.Sprintf("%s/%s", m.owner, m.repo))
-	prCount := len(m.prs)
-	branchRunCount := len(m.standaloneRuns)
-
-	summaryParts := []string{
-		fmt.Sprintf("%d active PR%s", prCount, pluralS(prCount)),
-	}
-	if branchRunCount > 0 {
-		summaryParts = append(summaryParts, fmt.Sprintf("%d branch run%s", branchRunCount, pluralS(branchRunCount)))
+	summaryParts := m.activityParts()
+	if !m.feed.Filter.IsZero() {
+		summaryParts = append(summaryParts, "Filter: "+m.feed.Filter.String())
 	}
 	summaryParts = append(summaryParts, fmt.Sprintf("Updated %s ago", timing.FormatDuration(timeSinceUpdate)))
 	summaryLine := strings.Join(summaryParts, "  •  ")
@@ -44,39 +39,102 @@ func (m RepoModel) View() tea.View {
 	fmt.Fprintf(&b, "%s\n", summaryLine)
 	b.WriteString("\n")
 
-	if prCount == 0 && branchRunCount == 0 {
+	if m.idle() {
 		fmt.Fprintf(&b, "%s ", m.spinner.View())
 		b.WriteString(m.styles.Running.Render("No active PRs or branch runs..."))
 		b.WriteString("\n\n")
 	} else {
-		for _, prNum := range m.sortedPRNumbers() {
-			m.renderPRGroup(&b, prNum, m.prs[prNum])
-		}
-		if branchRunCount > 0 {
-			m.renderStandaloneRunsSection(&b)
-		}
+		m.renderGroups(&b)
+	}
+
+	if m.showQueueStats {
+		current := ghclient.QueueSamplesByLabel(m.queueJobs, time.Now())
+		renderQueueStatsSection(&b, m.styles, "", summarizeQueueByLabel(current, m.queueHistory), len(m.queueHistoryPending) > 0)
 	}
 
-	// Two-tier rate-limit indicator: red under minRateLimitForFetch, yellow
-	// under rateWarningThreshold. Only render once we've actually received a
-	// response — before that, rateLimitRemaining is the Go zero value (0) and
-	// showing "[Rate limit: 0 remaining]" in red would be misleading.
-	if m.fetchReceived {
-		if m.rateLimitRemaining < minRateLimitForFetch {
-			b.WriteString(m.styles.Failure.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.rateLimitRemaining)))
-			b.WriteString("\n")
-		} else if m.rateLimitRemaining < rateWarningThreshold {
-			b.WriteString(m.styles.Running.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.rateLimitRemaining)))
-			b.WriteString("\n")
+	if m.runners.show {
+		renderRunnersSection(&b, m.styles, m.runners, []runnerRepo{{ref: m.ref(), jobs: m.queueJobs}})
+	}
+
+	if m.showEvents {
+		renderEventsSection(&b, m.styles, m.repoEvents(), m.eventsOffset, false, time.Now())
+	}
+
+	renderRemainingQuota(&b, m.styles, m.feed.Quota.Received, m.feed.Quota.Remaining)
+
+	if pause := renderRateLimitPause(m.styles, m.api, m.feed.Quota.LimitedUntil, time.Now()); pause != "" {
+		b.WriteString("  ")
+		b.WriteString(pause)
+		b.WriteString("\n")
+	}
+
+	m.renderFetchError(&b)
+
+	b.WriteString("\n")
+
+	switch {
+	case m.prompt.open:
+		b.WriteString(m.prompt.view(m.styles))
+	case m.review.open:
+		b.WriteString(m.review.view())
+	case !m.quitting:
+		fmt.Fprintf(&b, "Press ↑/↓ and enter to open a PR or run, f to filter, l for queue latency by runner label, r for runners, e for recent events, %sq to quit\n", m.reviewHint())
+	}
+
+	return tea.NewView(b.String())
+}
+
+// reviewHint is the hint for a/x while any run on screen has a deployment
+// the user can approve.
+func (m RepoModel) reviewHint() string {
+	for _, deployments := range m.deployments {
+		if len(approvable(deployments)) > 0 {
+			return "a/x to approve or reject the selected run's deployment, "
 		}
 	}
+	return ""
+}
 
-	// Non-fatal fetch error status line. The last good state remains on
-	// screen above this line; polling continues and the relevant source's
-	// line clears on its next success. The two fetch sources (GraphQL PR
-	// checks and REST standalone runs) track errors independently so a
-	// success from one cannot mask an ongoing error from the other. Render
-	// whichever source's error is most recent, prefixed by the source.
+// activityParts returns the summary line's counts: active PRs, branch runs
+// and idle PRs left out.
+func (m RepoModel) activityParts() []string {
+	prCount := len(m.feed.PRs)
+	branchRunCount := len(m.feed.Runs)
+	parts := []string{
+		fmt.Sprintf("%d active PR%s", prCount, pluralS(prCount)),
+	}
+	if branchRunCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d branch run%s", branchRunCount, pluralS(branchRunCount)))
+	}
+	if m.feed.OmittedPRs > 0 {
+		parts = append(parts, fmt.Sprintf("%d idle open PR%s not fetched", m.feed.OmittedPRs, pluralS(m.feed.OmittedPRs)))
+	}
+	return parts
+}
+
+// idle reports whether there is nothing to show: no active PR and no
+// standalone run.
+func (m RepoModel) idle() bool {
+	return len(m.feed.PRs) == 0 && len(m.feed.Runs) == 0
+}
+
+// renderGroups renders the PR groups followed by the standalone runs.
+func (m RepoModel) renderGroups(b *strings.Builder) {
+	for _, prNum := range m.feed.PRNumbers() {
+		m.renderPRGroup(b, prNum, m.feed.PRs[prNum])
+	}
+	if len(m.feed.Runs) > 0 {
+		m.renderStandaloneRunsSection(b)
+	}
+}
+
+// renderFetchError renders the non-fatal fetch error status line. The last
+// good state remains on screen above this line; polling continues and the
+// relevant source's line clears on its next success. The two fetch sources
+// (GraphQL PR checks and REST standalone runs) track errors independently
+// so a success from one cannot mask an ongoing error from the other. Render
+// whichever source's error is most recent, prefixed by the source.
+func (m RepoModel) renderFetchError(b *strings.Builder) {
 	var err error
 	var errAt time.Time
 	var source string
@@ -91,16 +149,12 @@ func (m RepoModel) View() tea.View {
 		b.WriteString(m.styles.Failure.Render(fmt.Sprintf("  [%s fetch error: %s — %s ago]", source, errText, age)))
 		b.WriteString("\n")
 	}
-
-	b.WriteString("\n")
-
-	if !m.quitting {
-		b.WriteString("Press q to quit\n")
-	}
-
-	return tea.NewView(b.String())
 }
 
+// selectionMarker marks the selected overview row, in place of the
+// two-space indent run rows otherwise start with.
+const selectionMarker = "▸ "
+
 // truncateFetchError shortens an error message to fit within maxWidth terminal
 // display cells, appending an ellipsis if truncation occurs. GitHub 504 errors
 // embed a large HTML body that would otherwise span many terminal lines.
@@ -117,6 +171,9 @@ func truncateFetchError(s string, maxWidth int) string {
 // are fade-filtered at the runs-poll, so no second fade pass is needed here.
 func (m RepoModel) renderPRGroup(b *strings.Builder, prNum int, prData PRViewData) {
 	prHeader := m.styles.Header.Render(fmt.Sprintf("PR #%d: %s", prNum, prData.Title))
+	if m.selected == (repoItem{prNumber: prNum}) {
+		b.WriteString(selectionMarker)
+	}
 	b.WriteString(prHeader)
 	b.WriteString("\n")
 
@@ -129,7 +186,12 @@ func (m RepoModel) renderPRGroup(b *strings.Builder, prNum int, prData PRViewDat
 	}
 
 	SortCheckRuns(merged)
+	// A PR's checks can come from several workflows, each with its own
+	// averages, so the Avg column is sized check by check.
 	widths := CalculateColumnWidths(merged, prData.HeadPushedTime, nil)
+	for _, check := range merged {
+		widths.AvgWidth = max(widths.AvgWidth, runewidth.StringWidth(FormatAvg(check, m.averagesFor(check.WorkflowID))))
+	}
 
 	for _, check := range merged {
 		line := m.renderRepoCheckRun(check, prData.HeadPushedTime, widths)
@@ -140,12 +202,13 @@ func (m RepoModel) renderPRGroup(b *strings.Builder, prNum int, prData PRViewDat
 }
 
 // renderRepoCheckRun renders one PR check row, indented two spaces under the PR header.
-// Reuses display.go helpers (no HistAvg column — jobAverages is nil in repo mode).
+// Reuses display.go helpers, with the averages of the check's own workflow.
 func (m RepoModel) renderRepoCheckRun(check ghclient.CheckRunInfo, headPushedTime time.Time, widths ColumnWidths) string {
+	averages := m.averagesFor(check.WorkflowID)
 	nameCol := BuildNameColumn(check, widths, m.enableLinks)
 	queueText := FormatQueueLatency(check, headPushedTime)
 	durationText := FormatDuration(check)
-	avgText := FormatAvg(check, nil)
+	avgText := FormatAvg(check, averages)
 
 	icon := GetCheckIcon(check.Status, check.Conclusion)
 	style := styleForCheck(check.Status, check.Conclusion, m.styles)
@@ -161,7 +224,17 @@ func (m RepoModel) renderRepoCheckRun(check ghclient.CheckRunInfo, headPushedTim
 		styledName = style.Render(nameCol)
 	}
 
-	return "  " + queueCol + " " + styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg + "\n"
+	return "  " + queueCol + " " + styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg + m.slowMarker(check, averages) + "\n"
+}
+
+// slowMarker is the "  2.1× usual" suffix for a check running (or having
+// run) well past its historical average, or "" otherwise.
+func (m RepoModel) slowMarker(check ghclient.CheckRunInfo, averages map[string]time.Duration) string {
+	marker := FormatSlowMarker(check, averages, time.Now())
+	if marker == "" {
+		return ""
+	}
+	return "  " + m.styles.Running.Render(marker)
 }
 
 // renderStandaloneRunsSection renders standalone (non-PR) workflow runs grouped by branch.
@@ -179,11 +252,15 @@ func (m RepoModel) renderBranchGroup(b *strings.Builder, branch string, runs []g
 
 	for _, run := range runs {
 		m.renderBranchRunHeader(b, run)
+		renderPendingDeployments(b, m.styles, run.RunID, m.deployments[run.RunID], m.reviewResult, "    ", time.Now())
 
 		if len(run.Jobs) > 0 {
-			widths := calculateBranchRunColumnWidths(run.Jobs)
+			// The REST jobs list doesn't name the workflow, so jobs go by
+			// their run's.
+			averages := m.averagesFor(run.WorkflowID)
+			widths := calculateBranchRunColumnWidths(run.Jobs, averages)
 			for _, job := range run.Jobs {
-				line := m.renderBranchRunJob(job, widths)
+				line := m.renderBranchRunJob(job, averages, widths)
 				b.WriteString(line)
 			}
 		} else if run.Status != "completed" {
@@ -215,11 +292,16 @@ func (m RepoModel) renderBranchRunHeader(b *strings.Builder, run ghclient.Branch
 	styledTitle := style.Render(fmt.Sprintf("%s%s", title, eventAnnotation))
 	styledDuration := style.Render(durationText)
 
-	fmt.Fprintf(b, "  %s %s  %s\n", styledIcon, styledTitle, styledDuration)
+	indent := "  "
+	if m.selected == (repoItem{runID: run.RunID}) {
+		indent = selectionMarker
+	}
+	fmt.Fprintf(b, "%s%s %s  %s\n", indent, styledIcon, styledTitle, styledDuration)
 }
 
-// renderBranchRunJob renders a single job row under a branch run header.
-func (m RepoModel) renderBranchRunJob(job ghclient.CheckRunInfo, widths branchRunColumnWidths) string {
+// renderBranchRunJob renders a single job row under a branch run header,
+// with an Avg column once its workflow's averages are known.
+func (m RepoModel) renderBranchRunJob(job ghclient.CheckRunInfo, averages map[string]time.Duration, widths branchRunColumnWidths) string {
 	icon := GetCheckIcon(job.Status, job.Conclusion)
 	style := styleForCheck(job.Status, job.Conclusion, m.styles)
 
@@ -239,7 +321,13 @@ func (m RepoModel) renderBranchRunJob(job ghclient.CheckRunInfo, widths branchRu
 		styledName = style.Render(nameCol)
 	}
 
-	return "    " + styledIcon + " " + styledName + "  " + styledDuration + "\n"
+	avgCol := ""
+	if widths.avgWidth > 0 {
+		avgText := FormatAvg(job, averages)
+		avgCol = "  " + style.Render(strings.Repeat(" ", max(widths.avgWidth-runewidth.StringWidth(avgText), 0))+avgText)
+	}
+
+	return "    " + styledIcon + " " + styledName + "  " + styledDuration + avgCol + m.slowMarker(job, averages) + "\n"
 }
 
 // styleForCheck returns the lipgloss style for a status/conclusion pair.
@@ -320,14 +408,17 @@ func formatBranchJobDuration(job ghclient.CheckRunInfo) string {
 	}
 }
 
-// branchRunColumnWidths holds widths for the two-column branch-run job layout.
+// branchRunColumnWidths holds widths for the branch-run job layout: name,
+// duration and, when the workflow's averages are known, avg (0 otherwise).
 type branchRunColumnWidths struct {
 	nameWidth     int
 	durationWidth int
+	avgWidth      int
 }
 
-// calculateBranchRunColumnWidths scans jobs to size the name and duration columns.
-func calculateBranchRunColumnWidths(jobs []ghclient.CheckRunInfo) branchRunColumnWidths {
+// calculateBranchRunColumnWidths scans jobs to size the name, duration and
+// avg columns. averages is nil until the workflow's history is in.
+func calculateBranchRunColumnWidths(jobs []ghclient.CheckRunInfo, averages map[string]time.Duration) branchRunColumnWidths {
 	const (
 		minNameWidth     = 20
 		maxNameWidth     = 60
@@ -352,6 +443,10 @@ func calculateBranchRunColumnWidths(jobs []ghclient.CheckRunInfo) branchRunColum
 		if runewidth.StringWidth(durationText) > widths.durationWidth {
 			widths.durationWidth = runewidth.StringWidth(durationText)
 		}
+
+		if averages != nil {
+			widths.avgWidth = max(widths.avgWidth, minDurationWidth, runewidth.StringWidth(FormatAvg(job, averages)))
+		}
 	}
 
 	return widths
@@ -360,7 +455,7 @@ func calculateBranchRunColumnWidths(jobs []ghclient.CheckRunInfo) branchRunColum
 // groupBranchRunsByBranch groups standalone runs by their HeadBranch.
 func (m RepoModel) groupBranchRunsByBranch() map[string][]ghclient.BranchRunData {
 	groups := make(map[string][]ghclient.BranchRunData)
-	for _, run := range m.standaloneRuns {
+	for _, run := range m.feed.Runs {
 		branch := run.HeadBranch
 		if branch == "" {
 			branch = "(unknown)"
@@ -6,14 +6,12 @@ import (
 
 	"charm.land/bubbles/v2/spinner"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
-	"github.com/google/go-github/v90/github"
 )
 
 // RunModel holds the application state for watching a workflow run.
 type RunModel struct {
 	ctx   context.Context
-	token string
-	client *github.Client
+	api   ghclient.API
 	owner string
 	repo  string
 	runID int64
@@ -25,6 +23,12 @@ type RunModel struct {
 	// Jobs in the run
 	jobs []ghclient.WorkflowJobInfo
 
+	// Webhook deliveries (--webhook-listen), when enabled
+	webhooks webhookState
+
+	// Notifications (--notify), when enabled
+	notify notifyState
+
 	// Rate limiting
 	rateLimitRemaining int
 	// fetchReceived is true after the first successful API response that
@@ -32,6 +36,9 @@ type RunModel struct {
 	// Go zero value (0) and the view must not render a misleading
 	// "[Rate limit: 0 remaining]" indicator or trigger backoff.
 	fetchReceived bool
+	// rateLimitedUntil is when GitHub will accept requests again; see
+	// watch.Quota.LimitedUntil.
+	rateLimitedUntil time.Time
 
 	// UI state
 	spinner         spinner.Model
@@ -39,11 +46,13 @@ type RunModel struct {
 	lastUpdate      time.Time
 	refreshInterval time.Duration
 	styles          Styles
+	// showTimeline swaps the job table for the Gantt timeline (t).
+	showTimeline bool
 
 	// Exit tracking
-	exitCode      int
-	quitting      bool
-	jobsComplete  bool
+	exitCode     int
+	quitting     bool
+	jobsComplete bool
 
 	// Error state
 	err error
@@ -75,18 +84,36 @@ type RunModel struct {
 	// See Model.presumedAverages for the full rationale. Run mode rarely has
 	// external app checks, but we apply the same logic for consistency.
 	presumedAverages map[string]time.Duration
+
+	// Queue latency by runner label: historical samples merged across all
+	// fetched workflows, and whether the stats pane (toggled with l) is shown.
+	queueHistory   map[string][]time.Duration
+	showQueueStats bool
+
+	// Job dependency graph from the workflow file at the run's head SHA,
+	// used for the critical path (toggled with c). graphPending is true
+	// while the file is being fetched; graphErr records why it couldn't be.
+	workflowGraph    *ghclient.WorkflowGraph
+	graphPending     bool
+	graphErr         error
+	showCriticalPath bool
+
+	// Environments the run is held at, refreshed on every jobs poll while
+	// a job is waiting, the a/x prompt approving or rejecting them, and
+	// how the last review went.
+	deployments        []ghclient.PendingDeployment
+	deploymentsPending bool
+	review             reviewPrompt
+	reviewResult       reviewResult
 }
 
 // NewRunModel creates a new TUI model for watching a workflow run.
-func NewRunModel(ctx context.Context, token, owner, repo string, runID int64, refreshInterval time.Duration, styles Styles, enableLinks bool, noAvg bool, presumedAverages map[string]time.Duration) RunModel {
+func NewRunModel(ctx context.Context, api ghclient.API, owner, repo string, runID int64, refreshInterval time.Duration, styles Styles, enableLinks bool, noAvg bool, presumedAverages map[string]time.Duration) RunModel {
 	s := spinner.New(spinner.WithSpinner(spinner.Dot))
 
-	client, _ := ghclient.NewClientFromToken(token)
-
 	return RunModel{
 		ctx:                     ctx,
-		token:                   token,
-		client:                  client,
+		api:                     api,
 		owner:                   owner,
 		repo:                    repo,
 		runID:                   runID,
@@ -111,4 +138,8 @@ func NewRunModel(ctx context.Context, token, owner, repo string, runID int64, re
 // ExitCode returns the exit code for the program
 func (m RunModel) ExitCode() int {
 	return m.exitCode
-}
\ No newline at end of file
+}
+
+// capturingKeys reports whether the review prompt is open, so a Navigator
+// leaves esc and q to it.
+func (m RunModel) capturingKeys() bool { return m.review.open }
@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/mattn/go-runewidth"
+)
+
+// RunnersMsg carries one self-hosted runners listing: owner/repo's, or
+// organization Owner's when Repo is empty.
+type RunnersMsg struct {
+	Owner              string
+	Repo               string
+	Runners            []ghclient.Runner
+	RateLimitRemaining int
+	Err                error
+}
+
+// fetchRepoRunners lists the runners registered on repo.
+func fetchRepoRunners(ctx context.Context, api ghclient.API, repo ghclient.RepoRef) tea.Cmd {
+	return func() tea.Msg {
+		runners, rateLimit, err := api.FetchRepoRunners(ctx, repo.Owner, repo.Name)
+		return RunnersMsg{Owner: repo.Owner, Repo: repo.Name, Runners: runners, RateLimitRemaining: rateLimit, Err: err}
+	}
+}
+
+// fetchOrgRunners lists the runners registered on organization org.
+func fetchOrgRunners(ctx context.Context, api ghclient.API, org string) tea.Cmd {
+	return func() tea.Msg {
+		runners, rateLimit, err := api.FetchOrgRunners(ctx, org)
+		return RunnersMsg{Owner: org, Runners: runners, RateLimitRemaining: rateLimit, Err: err}
+	}
+}
+
+// runnerListing is the last result of one runners listing. A failed
+// listing keeps the runners it last returned.
+type runnerListing struct {
+	runners []ghclient.Runner
+	err     error
+	fetched bool
+}
+
+// hidden reports whether GitHub refused the listing outright, in which
+// case it isn't asked again.
+func (l runnerListing) hidden() bool {
+	return errors.Is(l.err, ghclient.ErrRunnersNotVisible)
+}
+
+// runnerPool is the runner pane (r) of repo mode and the dashboard: runner
+// listings keyed by "owner/repo" or organization, refreshed each poll.
+type runnerPool struct {
+	show     bool
+	listings map[string]runnerListing
+	pending  map[string]bool
+}
+
+// runnerScopes lists the repos' and their owners' listing keys, repos
+// first.
+func runnerScopes(repos []ghclient.RepoRef) []string {
+	var scopes, owners []string
+	for _, ref := range repos {
+		scopes = append(scopes, ref.String())
+		if !slices.Contains(owners, ref.Owner) {
+			owners = append(owners, ref.Owner)
+		}
+	}
+	return append(scopes, owners...)
+}
+
+// fetchCmds lists the runners of repos and their organizations, skipping
+// listings still in flight and those GitHub has refused. Nothing is
+// fetched while the pane is hidden.
+func (p *runnerPool) fetchCmds(ctx context.Context, api ghclient.API, repos []ghclient.RepoRef) []tea.Cmd {
+	if !p.show {
+		return nil
+	}
+	if p.pending == nil {
+		p.pending = make(map[string]bool)
+	}
+	var cmds []tea.Cmd
+	for _, ref := range repos {
+		if scope := ref.String(); p.wants(scope) {
+			p.pending[scope] = true
+			cmds = append(cmds, fetchRepoRunners(ctx, api, ref))
+		}
+	}
+	for _, scope := range runnerScopes(repos)[len(repos):] {
+		if p.wants(scope) {
+			p.pending[scope] = true
+			cmds = append(cmds, fetchOrgRunners(ctx, api, scope))
+		}
+	}
+	return cmds
+}
+
+// wants reports whether scope should be listed now.
+func (p runnerPool) wants(scope string) bool {
+	return !p.pending[scope] && !p.listings[scope].hidden()
+}
+
+// costPerPoll is how many listings each poll issues for repos.
+func (p runnerPool) costPerPoll(repos []ghclient.RepoRef) int {
+	if !p.show {
+		return 0
+	}
+	cost := 0
+	for _, scope := range runnerScopes(repos) {
+		if !p.listings[scope].hidden() {
+			cost++
+		}
+	}
+	return cost
+}
+
+// handle records a listing. Errors other than a refusal are only logged by
+// the caller and retried next poll.
+func (p *runnerPool) handle(msg RunnersMsg) {
+	scope := msg.Owner
+	if msg.Repo != "" {
+		scope = msg.Owner + "/" + msg.Repo
+	}
+	delete(p.pending, scope)
+	if p.listings == nil {
+		p.listings = make(map[string]runnerListing)
+	}
+	listing := p.listings[scope]
+	listing.err = msg.Err
+	if msg.Err == nil {
+		listing.runners = msg.Runners
+		listing.fetched = true
+	}
+	p.listings[scope] = listing
+}
+
+// runners returns the runners a job in repo can be routed to: the repo's
+// own and its organization's. ok is false until one of the two has been
+// listed.
+func (p runnerPool) runners(repo ghclient.RepoRef) (runners []ghclient.Runner, ok bool) {
+	for _, scope := range []string{repo.String(), repo.Owner} {
+		listing := p.listings[scope]
+		runners = append(runners, listing.runners...)
+		ok = ok || listing.fetched
+	}
+	return runners, ok
+}
+
+// runnerRepo is one watched repo's jobs, for cross-referencing queued jobs
+// against the runners they could use.
+type runnerRepo struct {
+	ref  ghclient.RepoRef
+	jobs []ghclient.CheckRunInfo
+}
+
+// renderRunnersSection renders a row per runner, a note per refused
+// listing, and a warning per label set no online runner carries.
+func renderRunnersSection(b *strings.Builder, styles Styles, pool runnerPool, repos []runnerRepo) {
+	b.WriteString(styles.Header.Render("Self-hosted runners"))
+	b.WriteString("\n")
+
+	refs := make([]ghclient.RepoRef, len(repos))
+	for i, r := range repos {
+		refs[i] = r.ref
+	}
+
+	type row struct {
+		scope  string
+		runner ghclient.Runner
+	}
+	var rows []row
+	var notes []string
+	fetched := false
+	for _, scope := range runnerScopes(refs) {
+		listing := pool.listings[scope]
+		fetched = fetched || listing.fetched
+		label := scope
+		if !strings.Contains(scope, "/") {
+			label = scope + " (org)"
+		}
+		if listing.hidden() {
+			what := "runners"
+			if !strings.Contains(scope, "/") {
+				what = "organization runners"
+			}
+			notes = append(notes, fmt.Sprintf("%s: %s not visible to this token", scope, what))
+			continue
+		}
+		runners := slices.Clone(listing.runners)
+		slices.SortFunc(runners, func(a, b ghclient.Runner) int { return strings.Compare(a.Name, b.Name) })
+		for _, r := range runners {
+			rows = append(rows, row{scope: label, runner: r})
+		}
+	}
+
+	switch {
+	case len(rows) == 0 && !fetched && len(notes) < len(runnerScopes(refs)):
+		b.WriteString(styles.Queued.Render("  Fetching runners..."))
+		b.WriteString("\n")
+	case len(rows) == 0 && fetched:
+		b.WriteString(styles.Queued.Render("  No self-hosted runners"))
+		b.WriteString("\n")
+	}
+
+	nameWidth, scopeWidth := 0, 0
+	for _, r := range rows {
+		nameWidth = max(nameWidth, runewidth.StringWidth(r.runner.Name))
+		scopeWidth = max(scopeWidth, runewidth.StringWidth(r.scope))
+	}
+	nameWidth = min(nameWidth, maxCheckNameWidth)
+	for _, r := range rows {
+		name := runewidth.Truncate(r.runner.Name, nameWidth, "…")
+		name += strings.Repeat(" ", max(nameWidth-runewidth.StringWidth(name), 0))
+		scope := r.scope + strings.Repeat(" ", max(scopeWidth-runewidth.StringWidth(r.scope), 0))
+		status := styles.Failure.Render("offline")
+		busy := "    "
+		if r.runner.Online() {
+			status = styles.Success.Render("online ")
+			busy = styles.Queued.Render("idle")
+			if r.runner.Busy {
+				busy = styles.Running.Render("busy")
+			}
+		}
+		fmt.Fprintf(b, "  %s  %s  %s  %s  %s\n", name, scope, status, busy, strings.Join(r.runner.Labels, ", "))
+	}
+
+	for _, note := range notes {
+		b.WriteString(styles.Queued.Render("  " + note))
+		b.WriteString("\n")
+	}
+
+	for _, repo := range repos {
+		runners, ok := pool.runners(repo.ref)
+		if !ok {
+			continue
+		}
+		for _, s := range ghclient.UnmatchedQueuedJobs(repo.jobs, runners) {
+			warning := formatRunnerShortfall(s)
+			if len(repos) > 1 {
+				warning = repo.ref.String() + ": " + warning
+			}
+			b.WriteString(styles.Failure.Render("  ⚠ " + warning))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+}
+
+// formatRunnerShortfall describes a shortfall, e.g. "3 jobs queued for
+// [gpu] — 0 online runners with that label".
+func formatRunnerShortfall(s ghclient.RunnerShortfall) string {
+	labels := "that label"
+	if len(s.Labels) > 1 {
+		labels = "those labels"
+	}
+	text := fmt.Sprintf("%d job%s queued for [%s] — 0 online runners with %s",
+		s.Jobs, pluralS(s.Jobs), strings.Join(s.Labels, ", "), labels)
+	if s.Offline > 0 {
+		text += fmt.Sprintf(" (%d offline)", s.Offline)
+	}
+	return text
+}
@@ -10,7 +10,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/fini-net/gh-observer/internal/debug"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
-	"github.com/google/go-github/v90/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // RunTickMsg is sent on each poll interval for run-watching mode.
@@ -39,9 +39,17 @@ type RunWorkflowsDiscoveredMsg struct {
 
 // RunJobAveragesPartialMsg is sent for each workflow that finishes history fetch in run mode.
 type RunJobAveragesPartialMsg struct {
-	WorkflowID int64
-	Averages   map[string]time.Duration
-	Err        error
+	WorkflowID   int64
+	Averages     map[string]time.Duration
+	QueueByLabel map[string][]time.Duration
+	Err          error
+}
+
+// RunWorkflowGraphMsg contains the job dependency graph parsed from the
+// run's workflow file.
+type RunWorkflowGraphMsg struct {
+	Graph *ghclient.WorkflowGraph
+	Err   error
 }
 
 // RunErrorMsg contains error information for run mode.
@@ -53,8 +61,9 @@ type RunErrorMsg struct {
 func (m RunModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		fetchRunInfo(m.ctx, m.client, m.token, m.owner, m.repo, m.runID),
+		fetchRunInfo(m.ctx, m.api, m.owner, m.repo, m.runID),
 		runTick(m.refreshInterval),
+		waitForWebhook(m.webhooks.events),
 	)
 }
 
@@ -62,10 +71,24 @@ func (m RunModel) Init() tea.Cmd {
 func (m RunModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.review.open {
+			return m.handleReviewKey(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "l":
+			m.showQueueStats = !m.showQueueStats
+			return m, nil
+		case "c":
+			m.showCriticalPath = !m.showCriticalPath
+			return m, nil
+		case "t":
+			m.showTimeline = !m.showTimeline
+			return m, nil
+		case "a", "x":
+			return m, m.review.show(m.runID, m.deployments, msg.String() == "a")
 		}
 
 	case spinner.TickMsg:
@@ -74,18 +97,41 @@ func (m RunModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case RunTickMsg:
+		if wait := watch.Wait(m.api, m.rateLimitedUntil, time.Now()); wait > 0 {
+			debug.Log("rate limited, delaying poll (run)", "wait", wait)
+			return m, runTick(wait)
+		}
 		// Gate backoff on fetchReceived so the zero-value rateLimitRemaining
 		// (0) before the first successful response doesn't suppress fetches.
 		if m.fetchReceived && m.rateLimitRemaining < rateBackoffThreshold {
 			debug.Log("rate limit backoff (run)", "remaining", m.rateLimitRemaining, "threshold", rateBackoffThreshold)
 			return m, runTick(m.refreshInterval * 3)
 		}
+		interval := m.webhooks.pollInterval(m.nextPollInterval(time.Now()))
+		if interval != m.refreshInterval {
+			debug.Log("adaptive poll interval (run)", "interval", interval, "base", m.refreshInterval)
+		}
 		return m, tea.Batch(
-			fetchRunJobs(m.ctx, m.client, m.owner, m.repo, m.runID),
-			runTick(m.refreshInterval),
+			fetchRunJobs(m.ctx, m.api, m.owner, m.repo, m.runID),
+			runTick(interval),
 		)
 
+	case WebhookMsg:
+		return m, m.webhooks.receive(m.webhookRelevant(msg.Event))
+
+	case webhookRefreshMsg:
+		m.webhooks.refreshPending = false
+		if m.quitting || watch.Wait(m.api, m.rateLimitedUntil, time.Now()) > 0 {
+			return m, nil
+		}
+		debug.Log("webhook refresh (run)")
+		return m, fetchRunJobs(m.ctx, m.api, m.owner, m.repo, m.runID)
+
 	case RunInfoMsg:
+		if until, ok := watch.ResumeAt(m.api, msg.Err); ok {
+			m.rateLimitedUntil = until
+			return m, retryAfterRateLimit(watch.Wait(m.api, until, time.Now()), fetchRunInfo(m.ctx, m.api, m.owner, m.repo, m.runID))
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, tea.Quit
@@ -100,7 +146,11 @@ func (m RunModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.rateLimitRemaining = msg.RateLimitRemaining
 			m.fetchReceived = true
 		}
-		return m, fetchRunJobs(m.ctx, m.client, m.owner, m.repo, m.runID)
+		m.graphPending = true
+		return m, tea.Batch(
+			fetchRunJobs(m.ctx, m.api, m.owner, m.repo, m.runID),
+			fetchRunWorkflowGraph(m.ctx, m.api, m.owner, m.repo, m.runInfo.WorkflowPath, m.runInfo.HeadSHA),
+		)
 
 	case RunJobsUpdateMsg:
 		return m.handleRunJobsUpdate(msg)
@@ -111,16 +161,66 @@ func (m RunModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case RunJobAveragesPartialMsg:
 		return m.handleRunJobAveragesPartial(msg)
 
+	case RunWorkflowGraphMsg:
+		m.graphPending = false
+		m.workflowGraph = msg.Graph
+		m.graphErr = msg.Err
+		if msg.Err != nil {
+			debug.Log("workflow graph unavailable", "path", m.runInfo.WorkflowPath, "err", msg.Err)
+		}
+		return m, nil
+
+	case PendingDeploymentsMsg:
+		m.deploymentsPending = false
+		if msg.Err != nil {
+			debug.Log("pending deployments fetch error", "run_id", msg.RunID, "err", msg.Err)
+			return m, nil
+		}
+		if runWaiting(m.jobs) {
+			m.deployments = msg.Deployments
+		}
+		return m, nil
+
+	case DeploymentReviewMsg:
+		m.reviewResult = newReviewResult(msg)
+		if msg.Err != nil {
+			return m, nil
+		}
+		return m, fetchRunJobs(m.ctx, m.api, m.owner, m.repo, m.runID)
+
 	case RunErrorMsg:
 		m.err = msg.Err
 		return m, nil
 	}
 
+	// Anything else (the prompt's cursor blink) belongs to the review
+	// prompt while it is open.
+	if m.review.open {
+		return m, m.review.update(msg)
+	}
 	return m, nil
 }
 
+// handleReviewKey handles a key while the review prompt is open; ctrl+c
+// still quits.
+func (m *RunModel) handleReviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		m.quitting = true
+		return m, tea.Quit
+	}
+	submit, cmd := m.review.handleKey(msg)
+	if submit {
+		return m, m.review.submitCmd(m.ctx, m.api, m.owner, m.repo)
+	}
+	return m, cmd
+}
+
 // handleRunJobsUpdate processes job status updates.
 func (m *RunModel) handleRunJobsUpdate(msg RunJobsUpdateMsg) (tea.Model, tea.Cmd) {
+	if until, ok := watch.ResumeAt(m.api, msg.Err); ok {
+		m.rateLimitedUntil = until
+		return m, nil
+	}
 	if msg.Err != nil {
 		m.err = msg.Err
 		return m, nil
@@ -145,6 +245,16 @@ func (m *RunModel) handleRunJobsUpdate(msg RunJobsUpdateMsg) (tea.Model, tea.Cmd
 
 	var cmds []tea.Cmd
 
+	// A job held at an environment's protection rules is "waiting" with no
+	// explanation in the jobs list; the pending deployments say who or
+	// what it waits on.
+	if !runWaiting(m.jobs) {
+		m.deployments = nil
+	} else if !m.deploymentsPending {
+		m.deploymentsPending = true
+		cmds = append(cmds, fetchPendingDeployments(m.ctx, m.api, m.owner, m.repo, m.runID))
+	}
+
 	allComplete := ghclient.AllJobsComplete(msg.Jobs)
 
 	// Trigger history discovery if we have new jobs and haven't fetched yet
@@ -153,7 +263,7 @@ func (m *RunModel) handleRunJobsUpdate(msg RunJobsUpdateMsg) (tea.Model, tea.Cmd
 		if len(checkRuns) > 0 {
 			m.avgFetchPending = true
 			m.avgFetchStartTime = time.Now()
-			cmds = append(cmds, discoverRunWorkflows(m.ctx, m.client, m.owner, m.repo, checkRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
+			cmds = append(cmds, discoverRunWorkflows(m.ctx, m.api, m.owner, m.repo, checkRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
 		}
 	}
 
@@ -166,7 +276,8 @@ func (m *RunModel) handleRunJobsUpdate(msg RunJobsUpdateMsg) (tea.Model, tea.Cmd
 		}
 	}
 
-	return m, tea.Batch(cmds...)
+	// In sequence, so notifications are out before the program quits.
+	return m, tea.Sequence(m.notifyJobs(), tea.Batch(cmds...))
 }
 
 // handleRunWorkflowsDiscovered processes workflow discovery results for run mode.
@@ -188,7 +299,7 @@ func (m *RunModel) handleRunWorkflowsDiscovered(msg RunWorkflowsDiscoveredMsg) (
 		if !m.dispatchedWorkflowFetch[wfID] {
 			m.pendingWorkflowFetch[wfID] = true
 			m.dispatchedWorkflowFetch[wfID] = true
-			workflowCmds = append(workflowCmds, fetchRunWorkflowHistory(m.ctx, m.client, m.owner, m.repo, wfID))
+			workflowCmds = append(workflowCmds, fetchRunWorkflowHistory(m.ctx, m.api, m.owner, m.repo, wfID))
 		}
 	}
 
@@ -217,6 +328,9 @@ func (m *RunModel) handleRunJobAveragesPartial(msg RunJobAveragesPartialMsg) (te
 		maps.Copy(m.jobAverages, msg.Averages)
 		m.workflowAverages[msg.WorkflowID] = msg.Averages
 	}
+	if msg.Err == nil {
+		m.queueHistory = mergeQueueSamples(m.queueHistory, msg.QueueByLabel)
+	}
 
 	if len(m.pendingWorkflowFetch) == 0 {
 		m.avgFetchPending = false
@@ -242,9 +356,9 @@ func runTick(d time.Duration) tea.Cmd {
 }
 
 // fetchRunInfo fetches workflow run metadata.
-func fetchRunInfo(ctx context.Context, client *github.Client, token, owner, repo string, runID int64) tea.Cmd {
+func fetchRunInfo(ctx context.Context, api ghclient.API, owner, repo string, runID int64) tea.Cmd {
 	return func() tea.Msg {
-		runInfo, rateLimit, err := ghclient.FetchRunInfo(ctx, client, token, owner, repo, runID)
+		runInfo, rateLimit, err := api.FetchRunInfo(ctx, owner, repo, runID)
 		if err != nil {
 			return RunInfoMsg{Err: err}
 		}
@@ -257,9 +371,9 @@ func fetchRunInfo(ctx context.Context, client *github.Client, token, owner, repo
 }
 
 // fetchRunJobs fetches the jobs for a workflow run.
-func fetchRunJobs(ctx context.Context, client *github.Client, owner, repo string, runID int64) tea.Cmd {
+func fetchRunJobs(ctx context.Context, api ghclient.API, owner, repo string, runID int64) tea.Cmd {
 	return func() tea.Msg {
-		jobs, rateLimit, err := ghclient.FetchRunJobs(ctx, client, owner, repo, runID)
+		jobs, rateLimit, err := api.FetchRunJobs(ctx, owner, repo, runID)
 		if err != nil {
 			return RunJobsUpdateMsg{Err: err}
 		}
@@ -272,9 +386,9 @@ func fetchRunJobs(ctx context.Context, client *github.Client, owner, repo string
 }
 
 // discoverRunWorkflows resolves workflow IDs from job data for history fetching.
-func discoverRunWorkflows(ctx context.Context, client *github.Client, owner, repo string, checkRuns []ghclient.CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) tea.Cmd {
+func discoverRunWorkflows(ctx context.Context, api ghclient.API, owner, repo string, checkRuns []ghclient.CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) tea.Cmd {
 	return func() tea.Msg {
-		newRunIDToWorkflowID, workflowIDsToFetch, err := ghclient.DiscoverWorkflows(ctx, client, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
+		newRunIDToWorkflowID, workflowIDsToFetch, err := api.DiscoverWorkflows(ctx, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
 		if err != nil {
 			return RunWorkflowsDiscoveredMsg{Err: err}
 		}
@@ -285,20 +399,34 @@ func discoverRunWorkflows(ctx context.Context, client *github.Client, owner, rep
 	}
 }
 
-// fetchRunWorkflowHistory fetches historical job durations for a single workflow.
-func fetchRunWorkflowHistory(ctx context.Context, client *github.Client, owner, repo string, workflowID int64) tea.Cmd {
+// fetchRunWorkflowHistory fetches historical job durations and runner queue
+// latencies for a single workflow.
+func fetchRunWorkflowHistory(ctx context.Context, api ghclient.API, owner, repo string, workflowID int64) tea.Cmd {
 	return func() tea.Msg {
-		averages, err := ghclient.FetchWorkflowHistory(ctx, client, owner, repo, workflowID)
+		history, err := api.FetchWorkflowHistoryDetail(ctx, owner, repo, workflowID)
 		if err != nil {
 			return RunJobAveragesPartialMsg{WorkflowID: workflowID, Err: err}
 		}
+		if history == nil {
+			return RunJobAveragesPartialMsg{WorkflowID: workflowID}
+		}
 		return RunJobAveragesPartialMsg{
-			WorkflowID: workflowID,
-			Averages:   averages,
+			WorkflowID:   workflowID,
+			Averages:     history.Averages,
+			QueueByLabel: history.QueueByLabel,
 		}
 	}
 }
 
+// fetchRunWorkflowGraph fetches and parses the run's workflow file at its
+// head SHA for critical path analysis.
+func fetchRunWorkflowGraph(ctx context.Context, api ghclient.API, owner, repo, path, sha string) tea.Cmd {
+	return func() tea.Msg {
+		graph, err := api.FetchWorkflowGraph(ctx, owner, repo, path, sha)
+		return RunWorkflowGraphMsg{Graph: graph, Err: err}
+	}
+}
+
 // hasNewRunJobs returns true if any jobs in the update are new.
 func hasNewRunJobs(jobs []ghclient.WorkflowJobInfo, seen map[string]bool) bool {
 	for _, job := range jobs {
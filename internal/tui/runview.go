@@ -59,22 +59,56 @@ func (m RunModel) View() tea.View {
 		b.WriteString("\n")
 	}
 
+	if pause := renderRateLimitPause(m.styles, m.api, m.rateLimitedUntil, time.Now()); pause != "" {
+		b.WriteString(pause)
+		b.WriteString("\n\n")
+	}
+
 	if len(m.jobs) == 0 {
 		return tea.NewView(b.String() + m.renderRunStartupPhase())
 	}
 
 	widths := CalculateRunColumnWidths(m.jobs, m.jobAverages)
+	path := ghclient.ComputeCriticalPath(m.workflowGraph, m.jobs, m.jobAverages, time.Now())
+
+	if m.showTimeline {
+		var pushed time.Time
+		if m.runInfo.HeadPushedTime != nil {
+			pushed = m.runInfo.HeadPushedTime.Time
+		} else if m.runInfo.CreatedAt != nil {
+			pushed = m.runInfo.CreatedAt.Time
+		}
+		checks := ghclient.WorkflowJobInfoToCheckRuns(m.jobs)
+		renderTimeline(&b, m.styles, checks, timelineOrigin(pushed, checks), time.Now())
+	} else {
+		headerName, headerDuration, headerAvg := FormatRunHeaderColumns(widths)
+		b.WriteString(m.styles.Header.Render(fmt.Sprintf("  %s  %s  %s\n", headerName, headerDuration, headerAvg)))
+		b.WriteString("\n")
+
+		for _, job := range m.jobs {
+			jobLine := m.renderRunJob(job, widths, path.Contains(job.Name))
+			b.WriteString(jobLine)
+		}
+	}
 
-	headerName, headerDuration, headerAvg := FormatRunHeaderColumns(widths)
-	b.WriteString(m.styles.Header.Render(fmt.Sprintf("  %s  %s  %s\n", headerName, headerDuration, headerAvg)))
 	b.WriteString("\n")
 
-	for _, job := range m.jobs {
-		jobLine := m.renderRunJob(job, widths)
-		b.WriteString(jobLine)
+	if len(m.deployments) > 0 {
+		b.WriteString(m.styles.Header.Render("Waiting for approval"))
+		b.WriteString("\n")
+		renderPendingDeployments(&b, m.styles, m.runID, m.deployments, m.reviewResult, "  ", time.Now())
+		b.WriteString("\n")
 	}
 
-	b.WriteString("\n")
+	if m.showCriticalPath {
+		renderCriticalPathSection(&b, m.styles, path, m.graphPending, m.graphErr)
+	}
+
+	if m.showQueueStats {
+		current := ghclient.QueueSamplesByLabel(ghclient.WorkflowJobInfoToCheckRuns(m.jobs), time.Now())
+		histPending := !m.noAvg && (m.avgFetchPending || len(m.pendingWorkflowFetch) > 0)
+		renderQueueStatsSection(&b, m.styles, "", summarizeQueueByLabel(current, m.queueHistory), histPending)
+	}
 
 	// Two-tier rate-limit indicator: red under minRateLimitForFetch, yellow
 	// under rateWarningThreshold. Only render once we've actually received a
@@ -90,8 +124,16 @@ func (m RunModel) View() tea.View {
 
 	b.WriteString("\n")
 
-	if !m.quitting {
-		b.WriteString("\nPress q to quit\n")
+	switch {
+	case m.review.open:
+		b.WriteString("\n")
+		b.WriteString(m.review.view())
+	case !m.quitting:
+		review := ""
+		if len(approvable(m.deployments)) > 0 {
+			review = "a/x to approve or reject the deployment, "
+		}
+		fmt.Fprintf(&b, "\nPress t for timeline, l for queue latency by runner label, c for critical path, %sq to quit\n", review)
 	}
 
 	return tea.NewView(b.String())
@@ -114,8 +156,9 @@ func (m RunModel) renderRunStartupPhase() string {
 	return b.String()
 }
 
-// renderRunJob displays a single job with aligned columns.
-func (m RunModel) renderRunJob(job ghclient.WorkflowJobInfo, widths RunColumnWidths) string {
+// renderRunJob displays a single job with aligned columns. Jobs on the
+// critical path get a trailing marker.
+func (m RunModel) renderRunJob(job ghclient.WorkflowJobInfo, widths RunColumnWidths, critical bool) string {
 	status := job.Status
 	conclusion := job.Conclusion
 
@@ -156,7 +199,11 @@ func (m RunModel) renderRunJob(job ghclient.WorkflowJobInfo, widths RunColumnWid
 		styledName = style.Render(nameCol)
 	}
 
-	return styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg + "\n"
+	line := styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg
+	if critical {
+		line += m.styles.Running.Render(criticalPathMarker)
+	}
+	return line + "\n"
 }
 
 // RunColumnWidths holds pre-calculated column widths for run mode rendering.
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/timing"
+	"github.com/mattn/go-runewidth"
+)
+
+// timelineWidth is the number of terminal columns the timeline bars span.
+const timelineWidth = 60
+
+const (
+	timelineQueueCell = "░"
+	timelineRunCell   = "█"
+	timelineNowCell   = "│"
+)
+
+// timelineOrigin picks the zero point for the timeline: the head push time
+// when known, otherwise the earliest queue or start time among the checks
+// (so a failed pushedDate lookup still yields a usable chart).
+func timelineOrigin(pushed time.Time, checks []ghclient.CheckRunInfo) time.Time {
+	if !pushed.IsZero() {
+		return pushed
+	}
+	var origin time.Time
+	for _, check := range checks {
+		for _, t := range []*time.Time{check.QueuedAt, check.StartedAt} {
+			if t != nil && !t.IsZero() && (origin.IsZero() || t.Before(origin)) {
+				origin = *t
+			}
+		}
+	}
+	return origin
+}
+
+// timelineColumn maps t onto [0, timelineWidth) given the chart's origin
+// and span, clamping times outside the window to the edges. The last column
+// is reserved for the now marker, so only a time at or past now lands there.
+func timelineColumn(t, origin time.Time, span time.Duration) int {
+	if span <= 0 {
+		return 0
+	}
+	col := int(float64(t.Sub(origin)) / float64(span) * (timelineWidth - 1))
+	return min(max(col, 0), timelineWidth-1)
+}
+
+// sortTimelineChecks returns the checks ordered by when they started (queued
+// checks last), so the chart reads top-to-bottom in execution order rather
+// than the table's duration order.
+func sortTimelineChecks(checks []ghclient.CheckRunInfo) []ghclient.CheckRunInfo {
+	sorted := append([]ghclient.CheckRunInfo(nil), checks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := sorted[i].StartedAt, sorted[j].StartedAt
+		switch {
+		case si == nil && sj == nil:
+			return FormatCheckName(sorted[i]) < FormatCheckName(sorted[j])
+		case si == nil:
+			return false
+		case sj == nil:
+			return true
+		case !si.Equal(*sj):
+			return si.Before(*sj)
+		}
+		return FormatCheckName(sorted[i]) < FormatCheckName(sorted[j])
+	})
+	return sorted
+}
+
+// renderTimeline draws each check as a bar from origin: shaded for queue
+// time, solid (by outcome) for run time, with a marker at now.
+func renderTimeline(b *strings.Builder, styles Styles, checks []ghclient.CheckRunInfo, origin, now time.Time) {
+	if origin.IsZero() {
+		b.WriteString(styles.Queued.Render("  No timing data yet"))
+		b.WriteString("\n")
+		return
+	}
+
+	// The chart ends at now, so the last column is the now marker; times
+	// past it (clock skew) clamp onto it.
+	span := now.Sub(origin)
+
+	const minNameWidth = 12
+	nameWidth := minNameWidth
+	for _, check := range checks {
+		nameWidth = max(nameWidth, runewidth.StringWidth(FormatCheckName(check)))
+	}
+	nameWidth = min(nameWidth, maxCheckNameWidth)
+
+	for _, check := range sortTimelineChecks(checks) {
+		name := runewidth.Truncate(FormatCheckName(check), nameWidth, "…")
+		name += strings.Repeat(" ", max(nameWidth-runewidth.StringWidth(name), 0))
+		fmt.Fprintf(b, "  %s  %s\n", name, renderTimelineBar(styles, check, origin, now, span))
+	}
+
+	right := "now (" + timing.FormatDuration(span) + ")"
+	fmt.Fprintf(b, "  %s  0s%s%s\n", strings.Repeat(" ", nameWidth),
+		strings.Repeat(" ", max(timelineWidth-2-len(right), 1)), right)
+	fmt.Fprintf(b, "  %s  %s queued  %s running  %s now\n\n", strings.Repeat(" ", nameWidth),
+		styles.Queued.Render(timelineQueueCell), timelineRunCell, timelineNowCell)
+}
+
+// renderTimelineBar renders one check's row of timelineWidth cells.
+func renderTimelineBar(styles Styles, check ghclient.CheckRunInfo, origin, now time.Time, span time.Duration) string {
+	queueStart := origin
+	if check.QueuedAt != nil && !check.QueuedAt.IsZero() {
+		queueStart = *check.QueuedAt
+	}
+	queueEnd, runEnd := now, now
+	if check.StartedAt != nil {
+		queueEnd = *check.StartedAt
+		if check.CompletedAt != nil {
+			runEnd = *check.CompletedAt
+		}
+	}
+
+	// Cell ranges are half-open. A started check gets at least one solid
+	// cell and a waiting one at least one shaded cell, so short jobs don't
+	// vanish at coarse scales.
+	started := check.StartedAt != nil
+	qs := timelineColumn(queueStart, origin, span)
+	qe := timelineColumn(queueEnd, origin, span)
+	if !started {
+		qe = max(qe, qs+1)
+	}
+	rs, re := qe, qe
+	if started {
+		re = max(timelineColumn(runEnd, origin, span), rs+1)
+	}
+
+	runStyle := styles.Running
+	switch {
+	case check.Conclusion == "success":
+		runStyle = styles.Success
+	case check.Conclusion == "failure" || check.Conclusion == "timed_out":
+		runStyle = styles.Failure
+	case check.Status == "completed":
+		runStyle = styles.Queued
+	}
+
+	// Render runs of identical cells in one style call so the row doesn't
+	// carry an escape sequence per cell.
+	cellAt := func(col int) (string, bool) {
+		switch {
+		case col >= rs && col < re:
+			return timelineRunCell, true
+		case col >= qs && col < qe:
+			return timelineQueueCell, true
+		case col == timelineWidth-1:
+			return timelineNowCell, false
+		}
+		return " ", false
+	}
+	var bar strings.Builder
+	for col := 0; col < timelineWidth; {
+		cell, styled := cellAt(col)
+		n := 1
+		for col+n < timelineWidth {
+			if next, _ := cellAt(col + n); next != cell {
+				break
+			}
+			n++
+		}
+		segment := strings.Repeat(cell, n)
+		switch {
+		case !styled:
+			bar.WriteString(segment)
+		case cell == timelineRunCell:
+			bar.WriteString(runStyle.Render(segment))
+		default:
+			bar.WriteString(styles.Queued.Render(segment))
+		}
+		col += n
+	}
+	return bar.String()
+}
@@ -10,6 +10,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/fini-net/gh-observer/internal/debug"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // canTrustCompletion returns true when we can trust that all checks have truly
@@ -20,7 +21,7 @@ func canTrustCompletion(m *Model) bool {
 		return false
 	}
 
-	checkCount := len(m.checkRuns)
+	checkCount := len(m.feed.CheckRuns)
 
 	if m.noAvg {
 		debug.Log("can trust completion: quick mode",
@@ -64,8 +65,9 @@ func canTrustCompletion(m *Model) bool {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		fetchPRInfo(m.ctx, m.token, m.owner, m.repo, m.prNumber),
+		fetchPRInfo(m.ctx, m.feed),
 		tick(m.refreshInterval),
+		waitForWebhook(m.webhooks.events),
 	)
 }
 
@@ -77,6 +79,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "t":
+			m.showTimeline = !m.showTimeline
+			return m, nil
 		}
 
 	case spinner.TickMsg:
@@ -85,18 +90,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case TickMsg:
-		// Check rate limit before polling. Gate on fetchReceived so the
-		// zero-value rateLimitRemaining (0) before the first successful
-		// response doesn't suppress the fetch that would clear that state.
-		if m.fetchReceived && m.rateLimitRemaining < rateBackoffThreshold {
-			debug.Log("rate limit backoff", "remaining", m.rateLimitRemaining, "threshold", rateBackoffThreshold)
-			// Back off if rate limited
-			return m, tick(m.refreshInterval * 3)
+		// Stand down until GitHub's rate limit resets rather than polling
+		// into more 403/429s, and back off while quota is nearly spent.
+		if hold := m.feed.Quota.Hold(m.api, m.refreshInterval, time.Now()); hold > 0 {
+			debug.Log("rate limited, delaying poll", "wait", hold, "remaining", m.feed.Quota.Remaining)
+			return m, tick(hold)
 		}
 
+		interval := m.webhooks.pollInterval(m.nextPollInterval(time.Now()))
+		if interval != m.refreshInterval {
+			debug.Log("adaptive poll interval", "interval", interval, "base", m.refreshInterval)
+		}
 		cmds := []tea.Cmd{
-			fetchCheckRuns(m.ctx, m.token, m.owner, m.repo, m.prNumber),
-			tick(m.refreshInterval),
+			fetchCheckRuns(m.ctx, m.feed),
+			tick(interval),
 		}
 
 		// Poll Copilot review on its own cadence, gated on rate limit and
@@ -104,29 +111,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// PRInfoMsg-time + copilotInitialDelay; the first poll may fire only
 		// after that instant so GitHub has time to create the review request.
 		if m.waitForCopilot && m.copilotPending && !m.quitting &&
-			m.rateLimitRemaining >= minRateLimitForFetch &&
+			m.feed.Quota.Remaining >= minRateLimitForFetch &&
 			!m.copilotPollStartTime.IsZero() && time.Now().After(m.copilotPollStartTime) &&
 			(m.copilotLastPoll.IsZero() || time.Since(m.copilotLastPoll) >= m.copilotPollInterval) {
-			cmds = append(cmds, fetchCopilotReview(m.ctx, m.token, m.owner, m.repo, m.prNumber, m.headSHA))
+			cmds = append(cmds, fetchCopilotReview(m.ctx, m.api, m.owner, m.repo, m.prNumber, m.feed.HeadSHA))
 		}
 
 		return m, tea.Batch(cmds...)
 
+	case WebhookMsg:
+		return m, m.webhooks.receive(m.webhookRelevant(msg.Event))
+
+	case webhookRefreshMsg:
+		m.webhooks.refreshPending = false
+		if m.quitting || m.feed.Quota.Wait(m.api, time.Now()) > 0 {
+			return m, nil
+		}
+		debug.Log("webhook refresh")
+		cmds := []tea.Cmd{fetchCheckRuns(m.ctx, m.feed)}
+		if m.waitForCopilot && m.copilotPending && m.feed.HeadSHA != "" {
+			cmds = append(cmds, fetchCopilotReview(m.ctx, m.api, m.owner, m.repo, m.prNumber, m.feed.HeadSHA))
+		}
+		return m, tea.Batch(cmds...)
+
 	case PRInfoMsg:
+		if m.feed.Quota.Limited(m.api, msg.Err) {
+			return m, retryAfterRateLimit(m.feed.Quota.Wait(m.api, time.Now()), fetchPRInfo(m.ctx, m.feed))
+		}
 		if msg.Err != nil {
 			m.err = msg.Err
 			return m, tea.Quit
 		}
 
-		oldSHA := m.headSHA
-		shaChanged := m.headSHA != "" && m.headSHA != msg.HeadSHA
+		oldSHA := m.feed.HeadSHA
+		shaChanged := m.feed.HeadSHA != "" && m.feed.HeadSHA != msg.HeadSHA
 
-		m.prTitle = msg.Title
-		m.headSHA = msg.HeadSHA
-		m.prCreatedAt = msg.CreatedAt
+		m.feed.ApplyInfo(msg)
 
 		cmds := []tea.Cmd{
-			fetchCheckRuns(m.ctx, m.token, m.owner, m.repo, m.prNumber),
+			fetchCheckRuns(m.ctx, m.feed),
 		}
 
 		// Start Copilot review polling once headSHA is known (issue #409).
@@ -185,16 +208,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Add new run→workflow mappings to cache
 			maps.Copy(m.runIDToWorkflowID, msg.NewRunIDToWorkflowID)
 			// Track pending workflow fetches and dispatch them immediately
-			var workflowCmds []tea.Cmd
+			var toFetch []int64
 			for _, wfID := range msg.WorkflowIDsToFetch {
 				if !m.dispatchedWorkflowFetch[wfID] {
 					m.pendingWorkflowFetch[wfID] = true
 					m.dispatchedWorkflowFetch[wfID] = true
-					workflowCmds = append(workflowCmds, fetchWorkflowHistory(m.ctx, m.owner, m.repo, wfID))
+					toFetch = append(toFetch, wfID)
 				}
 			}
 			// Also discover AdvSec workflows by name matching
-			advSecMatches, advSecWFIDs := ghclient.DiscoverAdvSecWorkflows(m.checkRuns, m.fetchedWorkflowIDs)
+			advSecMatches, advSecWFIDs := ghclient.DiscoverAdvSecWorkflows(m.feed.CheckRuns, m.fetchedWorkflowIDs)
 			for name, wfID := range advSecMatches {
 				m.advSecMatchWorkflow[name] = wfID
 				if averages, ok := m.workflowAverages[wfID]; ok {
@@ -210,11 +233,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if !m.dispatchedWorkflowFetch[wfID] {
 					m.pendingWorkflowFetch[wfID] = true
 					m.dispatchedWorkflowFetch[wfID] = true
-					workflowCmds = append(workflowCmds, fetchWorkflowHistory(m.ctx, m.owner, m.repo, wfID))
+					toFetch = append(toFetch, wfID)
 				}
 			}
 			// If no new fetches, discovery phase is complete
-			if len(workflowCmds) == 0 {
+			if len(toFetch) == 0 {
 				m.avgFetchPending = false
 				m.historyFetchCompleted = true
 				if len(m.pendingWorkflowFetch) == 0 {
@@ -226,7 +249,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.quitting = true
 				return m, tea.Quit
 			}
-			return m, tea.Batch(workflowCmds...)
+			if len(toFetch) == 0 {
+				return m, nil
+			}
+			return m, fetchWorkflowHistories(m.ctx, m.api, m.owner, m.repo, ghclient.WorkflowRefs(m.feed.CheckRuns, toFetch))
 		}
 
 		// Error case: check if we should quit
@@ -236,6 +262,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case WorkflowHistoriesMsg:
+		var model tea.Model = m
+		var cmds []tea.Cmd
+		for _, partial := range msg.Partials {
+			var cmd tea.Cmd
+			model, cmd = model.Update(partial)
+			cmds = append(cmds, cmd)
+		}
+		return model, tea.Batch(cmds...)
+
 	case JobAveragesPartialMsg:
 		// Remove from pending set
 		delete(m.pendingWorkflowFetch, msg.WorkflowID)
@@ -260,6 +296,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.expectedCheckCount = len(m.jobAverages)
 		}
+		if msg.Err == nil && len(msg.Flaky) > 0 {
+			if m.flakyStats == nil {
+				m.flakyStats = make(map[string]ghclient.FlakyStats, len(msg.Flaky))
+			}
+			maps.Copy(m.flakyStats, msg.Flaky)
+		}
 
 		// Check if all workflow fetches are done
 		if len(m.pendingWorkflowFetch) == 0 {
@@ -317,23 +359,16 @@ func markChecksSeen(checkRuns []ghclient.CheckRunInfo, seen map[string]bool) {
 
 // handleChecksUpdate processes check run updates and returns the updated model.
 func (m *Model) handleChecksUpdate(msg ChecksUpdateMsg) (tea.Model, tea.Cmd) {
+	if m.feed.Quota.Limited(m.api, msg.Err) {
+		return m, nil
+	}
 	if msg.Err != nil {
 		m.err = msg.Err
 		return m, nil
 	}
 
-	m.checkRuns = msg.CheckRuns
-	SortCheckRuns(m.checkRuns)
-	// Adopt the GraphQL-sourced push time on the first successful poll
-	// where it is non-zero. Subsequent polls overwrite with the same
-	// value; if a later poll returns zero (e.g. transient query shape
-	// change), preserve the last known value rather than dropping the
-	// user-facing "Pushed Xs ago" header.
-	if !msg.HeadPushedTime.IsZero() {
-		m.headPushedTime = msg.HeadPushedTime
-	}
-	m.rateLimitRemaining = msg.RateLimitRemaining
-	m.fetchReceived = true
+	SortCheckRuns(msg.CheckRuns)
+	m.feed.ApplyChecks(msg)
 	m.lastUpdate = time.Now()
 	m.err = nil
 
@@ -341,7 +376,7 @@ func (m *Model) handleChecksUpdate(msg ChecksUpdateMsg) (tea.Model, tea.Cmd) {
 	// (e.g. DCO) that have no Actions workflow run to fetch history for. This
 	// is idempotent — it only writes when the job name is absent from
 	// m.jobAverages, so real history fetched later always wins.
-	ghclient.ApplyPresumedAverages(m.jobAverages, m.checkRuns, m.presumedAverages)
+	ghclient.ApplyPresumedAverages(m.jobAverages, m.feed.CheckRuns, m.presumedAverages)
 
 	if len(msg.CheckRuns) > m.peakCheckCount {
 		m.peakCheckCount = len(msg.CheckRuns)
@@ -356,20 +391,20 @@ func (m *Model) handleChecksUpdate(msg ChecksUpdateMsg) (tea.Model, tea.Cmd) {
 	newChecks := hasNewChecks(msg.CheckRuns, m.seenCheckKeys)
 	markChecksSeen(msg.CheckRuns, m.seenCheckKeys)
 
-	var cmds []tea.Cmd
+	cmds := []tea.Cmd{m.notifyChecks()}
 
 	allComplete := allChecksComplete(msg.CheckRuns)
 	elapsed := time.Since(m.firstCheckSeenAt)
 	readyForHistory := !m.noAvg && !m.firstCheckSeenAt.IsZero() && (allComplete || elapsed >= historyFetchDelay)
 
-	reDiscover := newChecks && m.historyFetchCompleted && !m.avgFetchPending && m.rateLimitRemaining >= minRateLimitForFetch
+	reDiscover := newChecks && m.historyFetchCompleted && !m.avgFetchPending && m.feed.Quota.Remaining >= minRateLimitForFetch
 	if reDiscover {
 		m.avgFetchPending = true
 		m.avgFetchStartTime = time.Now()
-		cmds = append(cmds, discoverWorkflows(m.ctx, m.owner, m.repo, msg.CheckRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
+		cmds = append(cmds, discoverWorkflows(m.ctx, m.api, m.owner, m.repo, msg.CheckRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
 	}
 
-	if readyForHistory && !m.avgFetchPending && m.rateLimitRemaining >= minRateLimitForFetch {
+	if readyForHistory && !m.avgFetchPending && m.feed.Quota.Remaining >= minRateLimitForFetch {
 		needsDiscovery := false
 		for _, cr := range msg.CheckRuns {
 			if cr.WorkflowID > 0 {
@@ -408,30 +443,35 @@ func (m *Model) handleChecksUpdate(msg ChecksUpdateMsg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+			var toFetch []int64
 			for _, wfID := range advSecWFIDs {
 				if !m.dispatchedWorkflowFetch[wfID] {
 					m.pendingWorkflowFetch[wfID] = true
 					m.dispatchedWorkflowFetch[wfID] = true
-					cmds = append(cmds, fetchWorkflowHistory(m.ctx, m.owner, m.repo, wfID))
+					toFetch = append(toFetch, wfID)
 				}
 			}
+			if len(toFetch) > 0 {
+				cmds = append(cmds, fetchWorkflowHistories(m.ctx, m.api, m.owner, m.repo, ghclient.WorkflowRefs(msg.CheckRuns, toFetch)))
+			}
 		}
 
 		if needsDiscovery {
 			m.avgFetchPending = true
 			m.avgFetchStartTime = time.Now()
-			cmds = append(cmds, discoverWorkflows(m.ctx, m.owner, m.repo, msg.CheckRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
+			cmds = append(cmds, discoverWorkflows(m.ctx, m.api, m.owner, m.repo, msg.CheckRuns, m.runIDToWorkflowID, m.fetchedWorkflowIDs))
 		}
 	}
 
-	if allChecksComplete(m.checkRuns) && canTrustCompletion(m) && copilotGateSatisfied(m) {
-		m.exitCode = determineExitCode(m.checkRuns, m.copilotState, m.waitForCopilot)
+	if allChecksComplete(m.feed.CheckRuns) && canTrustCompletion(m) && copilotGateSatisfied(m) {
+		m.exitCode = determineExitCode(m.feed.CheckRuns, m.copilotState, m.waitForCopilot)
 		m.checksComplete = true
 		if !m.avgFetchPending && len(m.pendingWorkflowFetch) == 0 {
 			m.quitting = true
 			cmds = append(cmds, tea.Quit)
 		}
-		return m, tea.Batch(cmds...)
+		// In sequence, so the notification is out before the program quits.
+		return m, tea.Sequence(m.notifyDone(), tea.Batch(cmds...))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -466,16 +506,23 @@ func copilotGateSatisfied(m *Model) bool {
 func (m *Model) handleCopilotReview(msg CopilotReviewMsg) (tea.Model, tea.Cmd) {
 	m.copilotLastPoll = time.Now()
 
+	if m.feed.Quota.Limited(m.api, msg.Err) {
+		return m, nil
+	}
 	if msg.Err != nil {
 		debug.Log("copilot review fetch error", "err", msg.Err)
 		m.err = msg.Err
 		return m, nil
 	}
 
-	if msg.RateLimitRemaining > 0 && msg.RateLimitRemaining < m.rateLimitRemaining {
-		m.rateLimitRemaining = msg.RateLimitRemaining
+	if msg.RateLimitRemaining > 0 {
+		m.feed.Quota.Lower(msg.RateLimitRemaining, ghclient.ResourceGraphQL, watch.ResetAt(m.api, ghclient.ResourceGraphQL))
 	}
 
+	// Every poll counts toward notifications, a not-requested one too, so
+	// a review that lands after the watch started is announced.
+	notifyCmd := m.notifyCopilotReview(msg)
+
 	// Two-consecutive-not-found rule: require two consecutive "not requested
 	// and no HEAD review" polls before declaring Copilot not requested. This
 	// absorbs GraphQL read lag after a REST POST that requested the review.
@@ -501,7 +548,7 @@ func (m *Model) handleCopilotReview(msg CopilotReviewMsg) (tea.Model, tea.Cmd) {
 		m.copilotPending = true
 		m.copilotReviewComplete = false
 		debug.Log("copilot review in progress")
-		return m, nil
+		return m, notifyCmd
 	}
 
 	// Review complete (or stale with no pending) — stop blocking.
@@ -511,12 +558,12 @@ func (m *Model) handleCopilotReview(msg CopilotReviewMsg) (tea.Model, tea.Cmd) {
 
 	// If checks are already done and averages fetched, quit now.
 	if m.checksComplete && !m.avgFetchPending && len(m.pendingWorkflowFetch) == 0 {
-		m.exitCode = determineExitCode(m.checkRuns, m.copilotState, m.waitForCopilot)
+		m.exitCode = determineExitCode(m.feed.CheckRuns, m.copilotState, m.waitForCopilot)
 		m.quitting = true
-		return m, tea.Quit
+		return m, tea.Sequence(notifyCmd, tea.Quit)
 	}
 
-	return m, nil
+	return m, notifyCmd
 }
 
 // tick creates a command that sends a TickMsg after duration d
@@ -527,44 +574,16 @@ func tick(d time.Duration) tea.Cmd {
 }
 
 // fetchPRInfo fetches PR metadata
-func fetchPRInfo(ctx context.Context, token, owner, repo string, prNumber int) tea.Cmd {
+func fetchPRInfo(ctx context.Context, feed watch.PR) tea.Cmd {
 	return func() tea.Msg {
-		// Create temporary client for PR info (REST API)
-		client, err := ghclient.NewClient(ctx)
-		if err != nil {
-			return PRInfoMsg{Err: err}
-		}
-
-		prInfo, err := ghclient.FetchPRInfo(ctx, client, owner, repo, prNumber)
-		if err != nil {
-			return PRInfoMsg{Err: err}
-		}
-
-		createdAt, err := ghclient.ParseTimestamp(prInfo.CreatedAt)
-		if err != nil {
-			debug.Log("timestamp parse error", "field", "CreatedAt", "value", prInfo.CreatedAt, "err", err)
-		}
-
-		// The head push time arrives via ChecksUpdateMsg from the GraphQL
-		// check-runs query (FetchCheckRunsGraphQL), not from PRInfo (issue #349).
-
-		return PRInfoMsg{
-			Number:    prInfo.Number,
-			Title:     prInfo.Title,
-			HeadSHA:   prInfo.HeadSHA,
-			CreatedAt: createdAt,
-		}
+		return feed.FetchInfo(ctx)
 	}
 }
 
 // discoverWorkflows resolves run IDs to workflow IDs and returns which workflows need history fetches.
-func discoverWorkflows(ctx context.Context, owner, repo string, checkRuns []ghclient.CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) tea.Cmd {
+func discoverWorkflows(ctx context.Context, api ghclient.API, owner, repo string, checkRuns []ghclient.CheckRunInfo, knownRunIDToWorkflowID map[int64]int64, knownFetchedWorkflowIDs map[int64]bool) tea.Cmd {
 	return func() tea.Msg {
-		client, err := ghclient.NewClient(ctx)
-		if err != nil {
-			return WorkflowsDiscoveredMsg{Err: err}
-		}
-		newRunIDToWorkflowID, workflowIDsToFetch, err := ghclient.DiscoverWorkflows(ctx, client, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
+		newRunIDToWorkflowID, workflowIDsToFetch, err := api.DiscoverWorkflows(ctx, owner, repo, checkRuns, knownRunIDToWorkflowID, knownFetchedWorkflowIDs)
 		if err != nil {
 			return WorkflowsDiscoveredMsg{Err: err}
 		}
@@ -575,46 +594,38 @@ func discoverWorkflows(ctx context.Context, owner, repo string, checkRuns []ghcl
 	}
 }
 
-// fetchWorkflowHistory fetches historical job durations for a single workflow.
-func fetchWorkflowHistory(ctx context.Context, owner, repo string, workflowID int64) tea.Cmd {
+// fetchWorkflowHistories fetches historical job durations and flakiness
+// scores for a set of workflows in one batched call, reporting each
+// workflow as its own JobAveragesPartialMsg.
+func fetchWorkflowHistories(ctx context.Context, api ghclient.API, owner, repo string, refs []ghclient.WorkflowRef) tea.Cmd {
 	return func() tea.Msg {
-		client, err := ghclient.NewClient(ctx)
-		if err != nil {
-			return JobAveragesPartialMsg{WorkflowID: workflowID, Err: err}
-		}
-		averages, err := ghclient.FetchWorkflowHistory(ctx, client, owner, repo, workflowID)
-		if err != nil {
-			return JobAveragesPartialMsg{WorkflowID: workflowID, Err: err}
-		}
-		return JobAveragesPartialMsg{
-			WorkflowID: workflowID,
-			Averages:   averages,
+		histories, errs := api.FetchWorkflowHistories(ctx, owner, repo, refs)
+		partials := make([]JobAveragesPartialMsg, 0, len(refs))
+		for _, ref := range refs {
+			partial := JobAveragesPartialMsg{WorkflowID: ref.ID, Err: errs[ref.ID]}
+			if history := histories[ref.ID]; history != nil {
+				partial.Averages = history.Averages
+				partial.Flaky = history.Flaky
+			}
+			partials = append(partials, partial)
 		}
+		return WorkflowHistoriesMsg{Partials: partials}
 	}
 }
 
 // fetchCheckRuns fetches check runs using GraphQL
-func fetchCheckRuns(ctx context.Context, token, owner, repo string, prNumber int) tea.Cmd {
+func fetchCheckRuns(ctx context.Context, feed watch.PR) tea.Cmd {
 	return func() tea.Msg {
-		checkRuns, headPushedTime, rateLimit, err := ghclient.FetchCheckRunsGraphQL(ctx, token, owner, repo, prNumber)
-		if err != nil {
-			return ChecksUpdateMsg{Err: err}
-		}
-
-		return ChecksUpdateMsg{
-			CheckRuns:          checkRuns,
-			HeadPushedTime:     headPushedTime,
-			RateLimitRemaining: rateLimit,
-		}
+		return feed.FetchChecks(ctx)
 	}
 }
 
 // fetchCopilotReview fetches the Copilot code review state via GraphQL
 // (issue #409). This is a second query path alongside fetchCheckRuns, hitting
 // PullRequest.reviews instead of StatusCheckRollup.Contexts.
-func fetchCopilotReview(ctx context.Context, token, owner, repo string, prNumber int, headSHA string) tea.Cmd {
+func fetchCopilotReview(ctx context.Context, api ghclient.API, owner, repo string, prNumber int, headSHA string) tea.Cmd {
 	return func() tea.Msg {
-		review, rateLimit, err := ghclient.FetchCopilotReview(ctx, token, owner, repo, prNumber, headSHA)
+		review, rateLimit, err := api.FetchCopilotReview(ctx, owner, repo, prNumber, headSHA)
 		if err != nil {
 			return CopilotReviewMsg{Err: err, RateLimitRemaining: rateLimit}
 		}
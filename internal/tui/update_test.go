@@ -2,20 +2,23 @@ package tui
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 func makeModel() *Model {
 	return &Model{
-		ctx:                     context.Background(),
-		token:                   "test-token",
-		owner:                   "test-owner",
-		repo:                    "test-repo",
-		rateLimitRemaining:      5000,
+		ctx:   context.Background(),
+		owner: "test-owner",
+		repo:  "test-repo",
+		feed: watch.PR{
+			Quota: watch.Quota{Remaining: 5000},
+		},
 		jobAverages:             make(map[string]time.Duration),
 		workflowAverages:        make(map[int64]map[string]time.Duration),
 		advSecMatchWorkflow:     make(map[string]int64),
@@ -260,7 +263,7 @@ func TestHandleChecksUpdate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := makeModel()
-			m.rateLimitRemaining = tt.rateLimit
+			m.feed.Quota.Remaining = tt.rateLimit
 			m.expectedCheckCount = tt.expectedCheckCount
 			m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 
@@ -274,8 +277,8 @@ func TestHandleChecksUpdate(t *testing.T) {
 				t.Errorf("unexpected error: %v", result.err)
 			}
 
-			if tt.wantChecksStored && len(result.checkRuns) != len(tt.msg.CheckRuns) {
-				t.Errorf("checkRuns not stored, got %d, want %d", len(result.checkRuns), len(tt.msg.CheckRuns))
+			if tt.wantChecksStored && len(result.feed.CheckRuns) != len(tt.msg.CheckRuns) {
+				t.Errorf("checkRuns not stored, got %d, want %d", len(result.feed.CheckRuns), len(tt.msg.CheckRuns))
 			}
 
 			if tt.wantExitCode != result.exitCode {
@@ -292,7 +295,7 @@ func TestHandleChecksUpdate(t *testing.T) {
 func TestFirstCheckSeenAt(t *testing.T) {
 	t.Run("sets firstCheckSeenAt on first check run", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		if !m.firstCheckSeenAt.IsZero() {
 			t.Error("firstCheckSeenAt should start as zero")
 		}
@@ -312,7 +315,7 @@ func TestFirstCheckSeenAt(t *testing.T) {
 
 	t.Run("does not overwrite firstCheckSeenAt on subsequent updates", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		originalTime := time.Now().Add(-5 * time.Second)
 		m.firstCheckSeenAt = originalTime
 
@@ -331,7 +334,7 @@ func TestFirstCheckSeenAt(t *testing.T) {
 
 	t.Run("empty check runs do not set firstCheckSeenAt", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 
 		msg := ChecksUpdateMsg{
 			CheckRuns:          []ghclient.CheckRunInfo{},
@@ -350,7 +353,7 @@ func TestFirstCheckSeenAt(t *testing.T) {
 func TestHistoryFetchDelay(t *testing.T) {
 	t.Run("history fetch blocked when hold-off not elapsed", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-5 * time.Second)
 		m.runIDToWorkflowID = make(map[int64]int64)
 
@@ -371,7 +374,7 @@ func TestHistoryFetchDelay(t *testing.T) {
 
 	t.Run("history fetch proceeds when hold-off elapsed", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.runIDToWorkflowID = make(map[int64]int64)
 
@@ -392,7 +395,7 @@ func TestHistoryFetchDelay(t *testing.T) {
 
 	t.Run("history fetch blocked on first update despite firstCheckSeenAt being set", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.runIDToWorkflowID = make(map[int64]int64)
 
 		msg := ChecksUpdateMsg{
@@ -415,7 +418,7 @@ func TestHistoryFetchDelay(t *testing.T) {
 
 	t.Run("old PR with checks already present fetches after delay", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-11 * time.Second)
 		m.runIDToWorkflowID = make(map[int64]int64)
 
@@ -436,7 +439,7 @@ func TestHistoryFetchDelay(t *testing.T) {
 
 	t.Run("fetches immediately when all checks complete on first update", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.runIDToWorkflowID = make(map[int64]int64)
 
 		msg := ChecksUpdateMsg{
@@ -459,7 +462,7 @@ func TestHistoryFetchDelay(t *testing.T) {
 
 	t.Run("fetches immediately on first update if all checks already complete", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.runIDToWorkflowID = make(map[int64]int64)
 
 		msg := ChecksUpdateMsg{
@@ -582,6 +585,30 @@ func TestWorkflowsDiscoveredMsg(t *testing.T) {
 	})
 }
 
+func TestWorkflowHistoriesMsg(t *testing.T) {
+	m := makeModel()
+	m.pendingWorkflowFetch = map[int64]bool{456: true, 789: true}
+	m.fetchedWorkflowIDs = make(map[int64]bool)
+	m.jobAverages = make(map[string]time.Duration)
+	m.checksComplete = true
+
+	model, cmd := m.Update(WorkflowHistoriesMsg{Partials: []JobAveragesPartialMsg{
+		{WorkflowID: 456, Averages: map[string]time.Duration{"build": time.Minute}},
+		{WorkflowID: 789, Averages: map[string]time.Duration{"lint": 30 * time.Second}},
+	}})
+	result := model.(Model)
+
+	if len(result.pendingWorkflowFetch) != 0 {
+		t.Errorf("pending = %v, want every workflow in the batch resolved", result.pendingWorkflowFetch)
+	}
+	if result.jobAverages["build"] != time.Minute || result.jobAverages["lint"] != 30*time.Second {
+		t.Errorf("jobAverages = %v, want both workflows merged", result.jobAverages)
+	}
+	if !result.quitting || cmd == nil {
+		t.Error("should quit once the batch resolves the last pending fetch after checks complete")
+	}
+}
+
 func TestJobAveragesPartialMsg(t *testing.T) {
 	t.Run("merges averages and removes from pending", func(t *testing.T) {
 		m := makeModel()
@@ -614,6 +641,21 @@ func TestJobAveragesPartialMsg(t *testing.T) {
 		}
 	})
 
+	t.Run("merges flakiness scores", func(t *testing.T) {
+		m := makeModel()
+		m.pendingWorkflowFetch = map[int64]bool{456: true, 789: true}
+
+		model, _ := m.Update(JobAveragesPartialMsg{
+			WorkflowID: 456,
+			Flaky:      map[string]ghclient.FlakyStats{"test": {Retried: 3, Runs: 10}},
+		})
+		result := model.(Model)
+
+		if got := result.flakyStats["test"]; got.Retried != 3 || got.Runs != 10 {
+			t.Errorf("flakyStats[test] = %+v, want {3 10}", got)
+		}
+	})
+
 	t.Run("sets avgFetchLastDuration when all fetches complete", func(t *testing.T) {
 		m := makeModel()
 		m.pendingWorkflowFetch = map[int64]bool{456: true}
@@ -745,7 +787,7 @@ func TestCanTrustCompletion(t *testing.T) {
 	t.Run("returns true after grace period elapsed even with no expected count", func(t *testing.T) {
 		m := makeModel()
 		m.firstCheckSeenAt = now.Add(-3 * time.Minute)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 1
 		if !canTrustCompletion(m) {
 			t.Error("should trust completion after grace period")
@@ -755,7 +797,7 @@ func TestCanTrustCompletion(t *testing.T) {
 	t.Run("returns false when expected count not met within ratio", func(t *testing.T) {
 		m := makeModel()
 		m.firstCheckSeenAt = now.Add(-30 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 1
 		m.expectedCheckCount = 10
 		if canTrustCompletion(m) {
@@ -770,7 +812,7 @@ func TestCanTrustCompletion(t *testing.T) {
 		for i := range checks {
 			checks[i] = ghclient.CheckRunInfo{Status: "completed", Conclusion: "success"}
 		}
-		m.checkRuns = checks
+		m.feed.CheckRuns = checks
 		m.peakCheckCount = 4
 		m.expectedCheckCount = 10
 		if !canTrustCompletion(m) {
@@ -781,7 +823,7 @@ func TestCanTrustCompletion(t *testing.T) {
 	t.Run("returns true when expected count equals check count", func(t *testing.T) {
 		m := makeModel()
 		m.firstCheckSeenAt = now.Add(-30 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{
 			{Status: "completed", Conclusion: "success"},
 			{Status: "completed", Conclusion: "success"},
 		}
@@ -795,7 +837,7 @@ func TestCanTrustCompletion(t *testing.T) {
 	t.Run("returns false when peak count exceeds current count", func(t *testing.T) {
 		m := makeModel()
 		m.firstCheckSeenAt = now.Add(-30 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 5
 		m.expectedCheckCount = 10
 		if canTrustCompletion(m) {
@@ -806,7 +848,7 @@ func TestCanTrustCompletion(t *testing.T) {
 	t.Run("returns false with no expected count before grace period", func(t *testing.T) {
 		m := makeModel()
 		m.firstCheckSeenAt = now.Add(-30 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 1
 		m.expectedCheckCount = 0
 		if canTrustCompletion(m) {
@@ -818,7 +860,7 @@ func TestCanTrustCompletion(t *testing.T) {
 		m := makeModel()
 		m.noAvg = true
 		m.firstCheckSeenAt = now.Add(-5 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 1
 		m.expectedCheckCount = 0
 		if !canTrustCompletion(m) {
@@ -830,7 +872,7 @@ func TestCanTrustCompletion(t *testing.T) {
 		m := makeModel()
 		m.noAvg = true
 		m.firstCheckSeenAt = now.Add(-5 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{{Status: "completed", Conclusion: "success"}}
 		m.peakCheckCount = 3
 		if canTrustCompletion(m) {
 			t.Error("should not trust completion in quick mode when checks disappeared")
@@ -850,7 +892,7 @@ func TestCanTrustCompletion(t *testing.T) {
 		m := makeModel()
 		m.noAvg = true
 		m.firstCheckSeenAt = now.Add(-5 * time.Second)
-		m.checkRuns = []ghclient.CheckRunInfo{
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{
 			{Status: "completed", Conclusion: "success"},
 			{Status: "completed", Conclusion: "success"},
 		}
@@ -865,7 +907,7 @@ func TestCanTrustCompletion(t *testing.T) {
 func TestPeakCheckCountTracking(t *testing.T) {
 	t.Run("trackCheckCount updates peakCheckCount", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.expectedCheckCount = 3
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 
@@ -890,7 +932,7 @@ func TestPeakCheckCountTracking(t *testing.T) {
 func TestRediscoveryOnNewJobs(t *testing.T) {
 	t.Run("new check runs trigger re-discovery after initial discovery completed", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.runIDToWorkflowID = map[int64]int64{100: 200}
 		m.fetchedWorkflowIDs = map[int64]bool{200: true}
@@ -933,7 +975,7 @@ func TestRediscoveryOnNewJobs(t *testing.T) {
 
 	t.Run("no re-discovery for already-seen check runs", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.runIDToWorkflowID = map[int64]int64{100: 200}
 		m.fetchedWorkflowIDs = map[int64]bool{200: true}
@@ -1030,7 +1072,7 @@ func TestHasNewChecks(t *testing.T) {
 func TestAdvSecAliasOnRediscovery(t *testing.T) {
 	t.Run("AdvSec alias created from cached workflowAverages when re-discovery delivers WorkflowsDiscoveredMsg", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.avgFetchPending = true
 		m.historyFetchCompleted = true
@@ -1057,7 +1099,7 @@ func TestAdvSecAliasOnRediscovery(t *testing.T) {
 			AppName:    "GitHub",
 			DetailsURL: "https://github.com/test/test/runs/73263098935",
 		}
-		m.checkRuns = []ghclient.CheckRunInfo{ciCheck, advSecCheck}
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{ciCheck, advSecCheck}
 
 		msg := WorkflowsDiscoveredMsg{
 			NewRunIDToWorkflowID: map[int64]int64{},
@@ -1088,7 +1130,7 @@ func TestAdvSecAliasOnRediscovery(t *testing.T) {
 			789: {"Analyze (go)": 2 * time.Minute},
 		}
 		m.jobAverages = map[string]time.Duration{"Analyze (go)": 2 * time.Minute}
-		m.checkRuns = []ghclient.CheckRunInfo{
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{
 			{Name: "Analyze (go)", WorkflowRunID: 100, WorkflowID: 789, WorkflowName: "CodeQL", DetailsURL: "https://github.com/test/test/actions/runs/100/job/1"},
 			{Name: "CodeQL", AppName: "GitHub", DetailsURL: "https://github.com/test/test/runs/73263098935"},
 		}
@@ -1122,7 +1164,7 @@ func TestAdvSecAliasOnRediscovery(t *testing.T) {
 			"Analyze (go)": 2 * time.Minute,
 			"CodeQL":       3 * time.Minute,
 		}
-		m.checkRuns = []ghclient.CheckRunInfo{
+		m.feed.CheckRuns = []ghclient.CheckRunInfo{
 			{Name: "Analyze (go)", WorkflowRunID: 100, WorkflowID: 789, WorkflowName: "CodeQL", DetailsURL: "https://github.com/test/test/actions/runs/100/job/1"},
 			{Name: "CodeQL", AppName: "GitHub", DetailsURL: "https://github.com/test/test/runs/73263098935"},
 		}
@@ -1158,7 +1200,7 @@ func TestPresumedAverages(t *testing.T) {
 
 	t.Run("handleChecksUpdate injects presumed DCO average", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.presumedAverages = map[string]time.Duration{"DCO": 1 * time.Second}
 
@@ -1179,7 +1221,7 @@ func TestPresumedAverages(t *testing.T) {
 
 	t.Run("handleChecksUpdate does not overwrite real history", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 		m.presumedAverages = map[string]time.Duration{"DCO": 1 * time.Second}
 		m.jobAverages = map[string]time.Duration{"DCO": 5 * time.Second}
@@ -1198,7 +1240,7 @@ func TestPresumedAverages(t *testing.T) {
 
 	t.Run("handleChecksUpdate no-op when presumedAverages is nil", func(t *testing.T) {
 		m := makeModel()
-		m.rateLimitRemaining = 5000
+		m.feed.Quota.Remaining = 5000
 		m.firstCheckSeenAt = time.Now().Add(-15 * time.Second)
 
 		msg := ChecksUpdateMsg{
@@ -1258,7 +1300,7 @@ func TestHandleCopilotReview(t *testing.T) {
 		m.waitForCopilot = true
 		m.copilotPending = true
 		m.checksComplete = true
-		m.checkRuns = completedChecks
+		m.feed.CheckRuns = completedChecks
 
 		model, _ := m.handleCopilotReview(CopilotReviewMsg{
 			State: "approved",
@@ -1283,7 +1325,7 @@ func TestHandleCopilotReview(t *testing.T) {
 		m.waitForCopilot = true
 		m.copilotPending = true
 		m.checksComplete = true
-		m.checkRuns = completedChecks
+		m.feed.CheckRuns = completedChecks
 
 		model, _ := m.handleCopilotReview(CopilotReviewMsg{
 			State: "changes_requested",
@@ -1320,7 +1362,7 @@ func TestHandleCopilotReview(t *testing.T) {
 		m.waitForCopilot = true
 		m.copilotPending = true
 		m.checksComplete = true
-		m.checkRuns = completedChecks
+		m.feed.CheckRuns = completedChecks
 
 		model, _ := m.handleCopilotReview(CopilotReviewMsg{
 			Stale:        true,
@@ -1389,7 +1431,7 @@ func TestHandleCopilotReview(t *testing.T) {
 		m.copilotPollInterval = 10 * time.Millisecond
 		m.copilotMaxWait = 5 * time.Second
 		m.prNumber = 42
-		m.headSHA = "abc123"
+		m.feed.HeadSHA = "abc123"
 
 		// PRInfoMsg should arm the gate (copilotPending=true), set
 		// copilotWaitStartTime to now (max-wait measured from PR-info time),
@@ -1436,7 +1478,7 @@ func TestHandleCopilotReview(t *testing.T) {
 		m.copilotPollInterval = 10 * time.Millisecond
 		m.copilotMaxWait = 5 * time.Second
 		m.prNumber = 42
-		m.headSHA = "abc123"
+		m.feed.HeadSHA = "abc123"
 
 		model, _ := m.Update(PRInfoMsg{
 			Number:  42,
@@ -1517,3 +1559,101 @@ func TestDetermineExitCode_Copilot(t *testing.T) {
 		})
 	}
 }
+
+func TestRunJobAveragesPartialMergesQueueHistory(t *testing.T) {
+	m := &RunModel{
+		jobAverages:          make(map[string]time.Duration),
+		workflowAverages:     make(map[int64]map[string]time.Duration),
+		fetchedWorkflowIDs:   make(map[int64]bool),
+		pendingWorkflowFetch: map[int64]bool{1: true, 2: true},
+	}
+
+	m.handleRunJobAveragesPartial(RunJobAveragesPartialMsg{
+		WorkflowID:   1,
+		Averages:     map[string]time.Duration{"CI / build": time.Minute},
+		QueueByLabel: map[string][]time.Duration{"ubuntu-latest": {5 * time.Second}},
+	})
+	m.handleRunJobAveragesPartial(RunJobAveragesPartialMsg{
+		WorkflowID:   2,
+		QueueByLabel: map[string][]time.Duration{"ubuntu-latest": {9 * time.Second}, "macos-14": {time.Minute}},
+	})
+
+	if got := m.queueHistory["ubuntu-latest"]; len(got) != 2 {
+		t.Errorf("ubuntu-latest samples = %v, want 2 merged across workflows", got)
+	}
+	if got := m.queueHistory["macos-14"]; len(got) != 1 {
+		t.Errorf("macos-14 samples = %v, want 1", got)
+	}
+}
+
+func TestFetchRunWorkflowHistoryWithoutRuns(t *testing.T) {
+	// The workflow's first run: no completed runs to take history from.
+	_, api := newFakeGitHub(t)
+
+	msg, ok := fetchRunWorkflowHistory(context.Background(), api, "octo", "hello", 7)().(RunJobAveragesPartialMsg)
+	if !ok || msg.WorkflowID != 7 || msg.Err != nil || msg.Averages != nil || msg.QueueByLabel != nil {
+		t.Errorf("msg = %+v, want an empty history for workflow 7", msg)
+	}
+}
+
+func TestRunWorkflowGraphMsg(t *testing.T) {
+	graph, err := ghclient.ParseWorkflowGraph([]byte("jobs:\n  build: {}\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := RunModel{graphPending: true}
+	updated, cmd := m.Update(RunWorkflowGraphMsg{Graph: graph})
+	rm := updated.(RunModel)
+	if rm.graphPending || rm.workflowGraph != graph || rm.graphErr != nil {
+		t.Errorf("graph not stored: pending=%v graph=%v err=%v", rm.graphPending, rm.workflowGraph, rm.graphErr)
+	}
+	if cmd != nil {
+		t.Error("graph fetch result should not schedule further commands")
+	}
+
+	updated, _ = m.Update(RunWorkflowGraphMsg{Err: context.DeadlineExceeded})
+	rm = updated.(RunModel)
+	if rm.graphPending || rm.graphErr == nil || rm.err != nil {
+		t.Errorf("graph error should be recorded without failing the run view: graphErr=%v err=%v", rm.graphErr, rm.err)
+	}
+}
+
+func TestRateLimitedFetchPausesInsteadOfFailing(t *testing.T) {
+	until := time.Now().Add(2*time.Minute + 14*time.Second)
+	limited := &ghclient.RateLimitedError{Until: until, Reason: "Retry-After"}
+
+	t.Run("pr mode", func(t *testing.T) {
+		m := makeModel()
+		m.handleChecksUpdate(ChecksUpdateMsg{Err: limited})
+		if m.err != nil {
+			t.Errorf("rate limit surfaced as error: %v", m.err)
+		}
+		if !m.feed.Quota.LimitedUntil.Equal(until) {
+			t.Errorf("rateLimitedUntil = %v, want %v", m.feed.Quota.LimitedUntil, until)
+		}
+
+		updated, cmd := m.Update(TickMsg(time.Now()))
+		if cmd == nil || updated.(Model).feed.Quota.LimitedUntil != until {
+			t.Error("tick while rate limited should only reschedule itself")
+		}
+	})
+
+	t.Run("run mode", func(t *testing.T) {
+		m := &RunModel{}
+		m.handleRunJobsUpdate(RunJobsUpdateMsg{Err: limited})
+		if m.err != nil || !m.rateLimitedUntil.Equal(until) {
+			t.Errorf("err = %v, rateLimitedUntil = %v", m.err, m.rateLimitedUntil)
+		}
+	})
+
+	t.Run("status line", func(t *testing.T) {
+		line := renderRateLimitPause(stylesForTest(), nil, until, until.Add(-134*time.Second))
+		if !strings.Contains(line, "rate limited, resuming in 2m 14s") {
+			t.Errorf("line = %q", line)
+		}
+		if got := renderRateLimitPause(stylesForTest(), nil, until, until.Add(time.Second)); got != "" {
+			t.Errorf("expired pause rendered %q", got)
+		}
+	})
+}
@@ -19,14 +19,14 @@ func (m Model) View() tea.View {
 
 	var b strings.Builder
 
-	if m.prTitle != "" {
-		prInfo := m.styles.Header.Render(fmt.Sprintf("PR #%d: %s", m.prNumber, m.prTitle))
+	if m.feed.Title != "" {
+		prInfo := m.styles.Header.Render(fmt.Sprintf("PR #%d: %s", m.prNumber, m.feed.Title))
 		utcTime := time.Now().UTC().Format("15:04:05 UTC")
 		timeSinceUpdate := time.Since(m.lastUpdate)
 
 		var updatedLine string
-		if !m.headPushedTime.IsZero() {
-			timeSincePush := time.Since(m.headPushedTime)
+		if !m.feed.HeadPushedTime.IsZero() {
+			timeSincePush := time.Since(m.feed.HeadPushedTime)
 			updatedLine = fmt.Sprintf("Updated %s ago  •  Pushed %s ago",
 				timing.FormatDuration(timeSinceUpdate),
 				timing.FormatDuration(timeSincePush))
@@ -57,11 +57,16 @@ func (m Model) View() tea.View {
 		b.WriteString("\n")
 	}
 
-	if len(m.checkRuns) == 0 {
+	if pause := renderRateLimitPause(m.styles, m.api, m.feed.Quota.LimitedUntil, time.Now()); pause != "" {
+		b.WriteString(pause)
+		b.WriteString("\n\n")
+	}
+
+	if len(m.feed.CheckRuns) == 0 {
 		return tea.NewView(b.String() + m.renderStartupPhase())
 	}
 
-	widths := CalculateColumnWidths(m.checkRuns, m.headPushedTime, m.jobAverages)
+	widths := CalculateColumnWidths(m.feed.CheckRuns, m.feed.HeadPushedTime, m.jobAverages)
 
 	// Compute the synthetic Copilot review row once and reuse it for both
 	// column-width widening and the row render. Calling buildCopilotCheckRun
@@ -71,7 +76,7 @@ func (m Model) View() tea.View {
 	// zero mid-render — leading the widths calc and the rendered row to
 	// disagree on status.
 	//
-	// This call site is intentionally below the len(m.checkRuns)==0 early
+	// This call site is intentionally below the len(m.feed.CheckRuns)==0 early
 	// return above: during the Actions startup phase the Copilot row is not
 	// rendered (see the comment by renderCopilotStatusLine below for why
 	// that tradeoff is acceptable). Hoisting it above the early return would
@@ -86,32 +91,37 @@ func (m Model) View() tea.View {
 		widths = widenForCopilotRow(widths, *copilotRow, m.copilotPollStartTime)
 	}
 
-	headerQueue, headerName, headerDuration, headerAvg := FormatHeaderColumns(widths)
-	b.WriteString(m.styles.Header.Render(fmt.Sprintf("%s   %s  %s  %s\n", headerQueue, headerName, headerDuration, headerAvg)))
-	b.WriteString("\n")
+	if m.showTimeline {
+		renderTimeline(&b, m.styles, m.feed.CheckRuns, timelineOrigin(m.feed.HeadPushedTime, m.feed.CheckRuns), time.Now())
+	} else {
+		headerQueue, headerName, headerDuration, headerAvg := FormatHeaderColumns(widths)
+		b.WriteString(m.styles.Header.Render(fmt.Sprintf("%s   %s  %s  %s\n", headerQueue, headerName, headerDuration, headerAvg)))
+		b.WriteString("\n")
 
-	for _, check := range m.checkRuns {
-		checkLine := m.renderCheckRun(check, widths)
-		b.WriteString(checkLine)
+		for _, check := range m.feed.CheckRuns {
+			checkLine := m.renderCheckRun(check, widths)
+			b.WriteString(checkLine)
 
-		// Render the summary line for failed checks. (Synthetic Copilot
-		// review rows never appear in m.checkRuns — they are rendered at the
-		// separate copilotRow site below — so the summary path for them is
-		// also reached there, not here.)
-		if check.Summary != "" && (check.Conclusion == "failure" || check.Conclusion == "timed_out") {
-			b.WriteString(m.renderSummary(check, widths))
-		}
+			// Render the summary line for failed checks. (Synthetic Copilot
+			// review rows never appear in m.feed.CheckRuns — they are rendered at the
+			// separate copilotRow site below — so the summary path for them is
+			// also reached there, not here.)
+			if check.Summary != "" && (check.Conclusion == "failure" || check.Conclusion == "timed_out") {
+				b.WriteString(m.renderSummary(check, widths))
+			}
 
-		if (check.Conclusion == "failure" || check.Conclusion == "timed_out") && len(check.Annotations) > 0 {
-			b.WriteString(m.renderErrorBox(check, widths))
+			if (check.Conclusion == "failure" || check.Conclusion == "timed_out") && len(check.Annotations) > 0 {
+				b.WriteString(m.renderErrorBox(check, widths))
+			}
 		}
 	}
 
 	// Copilot review row (issue #409). The row is display-only: it never
-	// enters m.checkRuns, so allChecksComplete, SortCheckRuns, and
+	// enters m.feed.CheckRuns, so allChecksComplete, SortCheckRuns, and
 	// determineExitCode are unaffected. It is always rendered last,
-	// regardless of state, to keep its position predictable.
-	if copilotRow != nil {
+	// regardless of state, to keep its position predictable. The timeline
+	// leaves it out since its times are synthetic.
+	if copilotRow != nil && !m.showTimeline {
 		b.WriteString(m.renderCheckRun(*copilotRow, widths))
 		// Surface the stale-review Summary below the synthetic row,
 		// mirroring the failed-check summary render inside the loop
@@ -128,7 +138,7 @@ func (m Model) View() tea.View {
 	// visually next to the row it describes.
 	//
 	// Startup-phase tradeoff: this call site is below the
-	// len(m.checkRuns)==0 early return above, so during the Actions startup
+	// len(m.feed.CheckRuns)==0 early return above, so during the Actions startup
 	// phase (before any check appears, typically 30-90s after PR creation)
 	// NEITHER the synthetic Copilot row NOR this status line is rendered.
 	// That reopens a narrow window that an earlier revision (commit dd23bdd)
@@ -157,13 +167,13 @@ func (m Model) View() tea.View {
 
 	b.WriteString("\n")
 
-	if allChecksComplete(m.checkRuns) && !canTrustCompletion(&m) {
+	if allChecksComplete(m.feed.CheckRuns) && !canTrustCompletion(&m) {
 		b.WriteString(m.styles.Queued.Render("  ⏳ Waiting for more checks to appear...\n"))
 		if m.expectedCheckCount > 0 {
 			fmt.Fprintf(&b, m.styles.Queued.Render("  Seen %d of ~%d expected checks (%d%% threshold: %d%%)\n"),
-				len(m.checkRuns), m.expectedCheckCount,
+				len(m.feed.CheckRuns), m.expectedCheckCount,
 				int(minCheckAppearanceRatio*100),
-				int(float64(len(m.checkRuns))/float64(m.expectedCheckCount)*100))
+				int(float64(len(m.feed.CheckRuns))/float64(m.expectedCheckCount)*100))
 		} else if m.noAvg {
 			b.WriteString(m.styles.Queued.Render("  Waiting for all seen checks to finish...\n"))
 		} else {
@@ -181,18 +191,18 @@ func (m Model) View() tea.View {
 	// under rateWarningThreshold. Only render once we've actually received a
 	// response — before that, rateLimitRemaining is the Go zero value (0) and
 	// showing "[Rate limit: 0 remaining]" in red would be misleading.
-	if m.fetchReceived {
-		if m.rateLimitRemaining < minRateLimitForFetch {
-			b.WriteString(m.styles.Failure.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.rateLimitRemaining)))
-		} else if m.rateLimitRemaining < rateWarningThreshold {
-			b.WriteString(m.styles.Running.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.rateLimitRemaining)))
+	if m.feed.Quota.Received {
+		if m.feed.Quota.Remaining < minRateLimitForFetch {
+			b.WriteString(m.styles.Failure.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.feed.Quota.Remaining)))
+		} else if m.feed.Quota.Remaining < rateWarningThreshold {
+			b.WriteString(m.styles.Running.Render(fmt.Sprintf("  [Rate limit: %d remaining]", m.feed.Quota.Remaining)))
 		}
 	}
 
 	b.WriteString("\n")
 
 	if !m.quitting {
-		b.WriteString("\nPress q to quit\n")
+		b.WriteString("\nPress t for timeline, q to quit\n")
 	}
 
 	return tea.NewView(b.String())
@@ -262,7 +272,7 @@ func (m Model) renderCheckRun(check ghclient.CheckRunInfo, widths ColumnWidths)
 	nameCol := BuildNameColumn(check, widths, m.enableLinks)
 
 	// Get column data (plain text)
-	queueText := FormatQueueLatency(check, m.headPushedTime)
+	queueText := FormatQueueLatency(check, m.feed.HeadPushedTime)
 	durationText := FormatDuration(check)
 	avgText := FormatAvg(check, m.jobAverages)
 
@@ -305,8 +315,24 @@ func (m Model) renderCheckRun(check ghclient.CheckRunInfo, widths ColumnWidths)
 		styledName = style.Render(nameCol)
 	}
 
-	// Assemble line: [queue][1 space][icon][1 space][name][2 spaces][duration][2 spaces][avg][newline]
-	return queueCol + " " + styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg + "\n"
+	// Known-flaky marker for failing checks: tells the user a retry is
+	// likely to pass before they go digging through logs.
+	flakyCol := ""
+	if conclusion == "failure" || conclusion == "timed_out" {
+		if marker := FormatFlakyMarker(check, m.flakyStats); marker != "" {
+			flakyCol = "  " + m.styles.Running.Render(marker)
+		}
+	}
+
+	// Slower-than-usual marker: the check is well past its historical
+	// average, whether it's still running or already done.
+	slowCol := ""
+	if marker := FormatSlowMarker(check, m.jobAverages, time.Now()); marker != "" {
+		slowCol = "  " + m.styles.Running.Render(marker)
+	}
+
+	// Assemble line: [queue][1 space][icon][1 space][name][2 spaces][duration][2 spaces][avg][slow][flaky][newline]
+	return queueCol + " " + styledIcon + " " + styledName + "  " + styledDuration + "  " + styledAvg + slowCol + flakyCol + "\n"
 }
 
 // renderCopilotReviewCheckRun renders a synthetic Copilot review row using
@@ -405,7 +431,7 @@ func shortHeadSHA(sha string) string {
 //   - review never armed (copilotWaitStartTime is zero)
 //   - not-requested with no stale review to surface
 //
-// The returned row never enters m.checkRuns; it is rendered separately as
+// The returned row never enters m.feed.CheckRuns; it is rendered separately as
 // the last row of the table and is excluded from SortCheckRuns,
 // allChecksComplete, and determineExitCode. Timing fields are populated
 // only to drive the duration-column display:
@@ -444,7 +470,7 @@ func (m Model) buildCopilotCheckRun() *ghclient.CheckRunInfo {
 		// "stale" its own "⚠" icon, but the short SHA + actionable hint
 		// are too long for the name column, so they live here — this
 		// restores the information the pre-refactor status line carried.
-		row.Summary = fmt.Sprintf("Copilot review is stale (HEAD is %s) — refresh or re-request", shortHeadSHA(m.headSHA))
+		row.Summary = fmt.Sprintf("Copilot review is stale (HEAD is %s) — refresh or re-request", shortHeadSHA(m.feed.HeadSHA))
 	case m.copilotPending && !m.copilotReviewComplete:
 		// Pending: either still inside the initial-delay window (queued,
 		// countdown shown in duration column) or actively polling
@@ -506,7 +532,7 @@ func copilotDurationText(check ghclient.CheckRunInfo, copilotPollStartTime time.
 // touching the queue column (which is blank for reviews) and capping the
 // name column at maxCheckNameWidth. This keeps the header row and check rows
 // aligned when the Copilot row would otherwise exceed the geometry derived
-// from m.checkRuns alone.
+// from m.feed.CheckRuns alone.
 func widenForCopilotRow(widths ColumnWidths, row ghclient.CheckRunInfo, copilotPollStartTime time.Time) ColumnWidths {
 	name := FormatCheckName(row)
 	nameLen := runewidth.StringWidth(name)
@@ -1,11 +1,13 @@
 package tui
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/watch"
 )
 
 // stylesForTest returns a Styles instance suitable for render assertions.
@@ -320,7 +322,9 @@ func TestBuildCopilotCheckRun_StaleSummary(t *testing.T) {
 		waitForCopilot:       true,
 		copilotStale:         true,
 		copilotWaitStartTime: now,
-		headSHA:              "abcd1234ef567890",
+		feed: watch.PR{
+			HeadSHA: "abcd1234ef567890",
+		},
 	}
 	got := m.buildCopilotCheckRun()
 	if got == nil {
@@ -370,7 +374,9 @@ func TestRenderCopilotStatusLine_StaleReturnsEmpty(t *testing.T) {
 		waitForCopilot:       true,
 		copilotStale:         true,
 		copilotWaitStartTime: time.Now(),
-		headSHA:              "abcd1234ef567890",
+		feed: watch.PR{
+			HeadSHA: "abcd1234ef567890",
+		},
 	}
 	if got := m.renderCopilotStatusLine(); got != "" {
 		t.Errorf("stale status line should be empty (Summary carries it), got %q", got)
@@ -426,6 +432,27 @@ func TestRenderCheckRun_RegularRowUnchanged(t *testing.T) {
 	}
 }
 
+func TestRenderCheckRun_FlakyMarker(t *testing.T) {
+	m := &Model{
+		styles:     stylesForTest(),
+		flakyStats: map[string]ghclient.FlakyStats{"test": {Retried: 3, Runs: 20}},
+	}
+	startedAt := time.Now().Add(-5 * time.Minute)
+	completedAt := startedAt.Add(90 * time.Second)
+	widths := ColumnWidths{QueueWidth: 5, NameWidth: 20, DurationWidth: 7, AvgWidth: 7}
+
+	failing := ghclient.CheckRunInfo{Name: "test", WorkflowName: "CI", Status: "completed", Conclusion: "failure", StartedAt: &startedAt, CompletedAt: &completedAt}
+	if row := m.renderCheckRun(failing, widths); !strings.Contains(row, "flaky (3/20 recent runs failed then passed on retry)") {
+		t.Errorf("failing flaky check missing marker: %q", row)
+	}
+
+	passing := failing
+	passing.Conclusion = "success"
+	if row := m.renderCheckRun(passing, widths); strings.Contains(row, "flaky") {
+		t.Errorf("passing check should not carry the marker: %q", row)
+	}
+}
+
 // TestWidenForCopilotRow verifies that widenForCopilotRow grows the
 // duration and name columns to fit the synthetic Copilot row, including
 // the "in <remaining>" countdown text that FormatDuration alone does not
@@ -573,18 +600,19 @@ func TestView_StaleCopilotSummaryRendered(t *testing.T) {
 	}}
 
 	m := &Model{
-		styles:               stylesForTest(),
-		prTitle:              "Test PR",
+		styles: stylesForTest(),
+		feed: watch.PR{
+			Title:     "Test PR",
+			HeadSHA:   "abcd1234ef567890",
+			CheckRuns: checks,
+			Quota:     watch.Quota{Received: true, Remaining: 5000},
+		},
 		prNumber:             1,
-		headSHA:              "abcd1234ef567890",
-		checkRuns:            checks,
 		lastUpdate:           time.Now(),
 		startTime:            time.Now().Add(-10 * time.Minute),
 		waitForCopilot:       true,
 		copilotStale:         true,
 		copilotWaitStartTime: time.Now(),
-		fetchReceived:        true,
-		rateLimitRemaining:   5000,
 	}
 
 	out := m.View().Content
@@ -630,3 +658,189 @@ func TestRenderCopilotReviewCheckRun_LongCountdown(t *testing.T) {
 		t.Errorf("row should contain the countdown text verbatim, got %q", rendered)
 	}
 }
+
+func TestSummarizeQueueByLabel(t *testing.T) {
+	current := map[string][]time.Duration{
+		"ubuntu-latest":    {10 * time.Second, 20 * time.Second},
+		"self-hosted, gpu": {5 * time.Minute},
+	}
+	hist := map[string][]time.Duration{
+		"ubuntu-latest": {5 * time.Second, 15 * time.Second, 25 * time.Second},
+		"macos-14":      {2 * time.Minute},
+	}
+
+	got := summarizeQueueByLabel(current, hist)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 labels, got %d: %+v", len(got), got)
+	}
+
+	wantOrder := []string{"self-hosted, gpu", "macos-14", "ubuntu-latest"}
+	for i, label := range wantOrder {
+		if got[i].Label != label {
+			t.Errorf("row %d: got label %q, want %q", i, got[i].Label, label)
+		}
+	}
+
+	ubuntu := got[2]
+	if ubuntu.CurrentCount != 2 || ubuntu.HistCount != 3 {
+		t.Errorf("ubuntu counts = %d/%d, want 2/3", ubuntu.CurrentCount, ubuntu.HistCount)
+	}
+	if ubuntu.CurrentP50 != 10*time.Second || ubuntu.CurrentP90 != 20*time.Second {
+		t.Errorf("ubuntu current p50/p90 = %v/%v, want 10s/20s", ubuntu.CurrentP50, ubuntu.CurrentP90)
+	}
+	if ubuntu.HistP50 != 15*time.Second || ubuntu.HistP90 != 25*time.Second {
+		t.Errorf("ubuntu hist p50/p90 = %v/%v, want 15s/25s", ubuntu.HistP50, ubuntu.HistP90)
+	}
+	if got[1].CurrentCount != 0 {
+		t.Errorf("macos-14 has no current samples, got count %d", got[1].CurrentCount)
+	}
+}
+
+func TestRenderQueueStatsSection(t *testing.T) {
+	styles := stylesForTest()
+
+	t.Run("empty while history pending", func(t *testing.T) {
+		var b strings.Builder
+		renderQueueStatsSection(&b, styles, "", nil, true)
+		out := b.String()
+		if !strings.Contains(out, "Fetching runner queue history") {
+			t.Errorf("missing pending hint:\n%s", out)
+		}
+	})
+
+	t.Run("empty with no history pending", func(t *testing.T) {
+		var b strings.Builder
+		renderQueueStatsSection(&b, styles, "", nil, false)
+		if !strings.Contains(b.String(), "No labeled jobs yet") {
+			t.Errorf("missing empty hint:\n%s", b.String())
+		}
+	})
+
+	t.Run("rows render percentiles and placeholders", func(t *testing.T) {
+		stats := summarizeQueueByLabel(
+			map[string][]time.Duration{"ubuntu-latest": {30 * time.Second}},
+			map[string][]time.Duration{"macos-14": {2 * time.Minute}},
+		)
+		var b strings.Builder
+		renderQueueStatsSection(&b, styles, "", stats, false)
+		out := b.String()
+		for _, want := range []string{"ubuntu-latest", "macos-14", "30s", "2m", "1/0", "0/1", "Hist p90"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+}
+
+func TestRenderCriticalPathSection(t *testing.T) {
+	start := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := &ghclient.CriticalPath{Steps: []ghclient.CriticalPathStep{
+		{JobID: "lint", Instance: "lint", Instances: 1, Start: start, End: start.Add(2 * time.Minute)},
+		{JobID: "test", Instance: "Test (1.26)", Instances: 2, Start: start.Add(3 * time.Minute), End: start.Add(11 * time.Minute), Wait: time.Minute, Estimated: true},
+	}}
+
+	t.Run("steps", func(t *testing.T) {
+		var b strings.Builder
+		renderCriticalPathSection(&b, stylesForTest(), path, false, nil)
+		out := b.String()
+		for _, want := range []string{"(critical path 11m 0s)", "1. lint", "2. Test (1.26)", "72%", "(waited 1m 0s)", "slowest of 2", "est", "optimize first"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		pending bool
+		err     error
+		want    string
+	}{
+		{name: "pending", pending: true, want: "Fetching workflow file..."},
+		{name: "error", err: errors.New("not found"), want: "Critical path unavailable: not found"},
+		{name: "no timing", want: "No jobs with timing yet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			renderCriticalPathSection(&b, stylesForTest(), nil, tt.pending, tt.err)
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("missing %q:\n%s", tt.want, b.String())
+			}
+		})
+	}
+}
+
+func TestTimelineOrigin(t *testing.T) {
+	pushed := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	early := pushed.Add(-time.Minute)
+	late := pushed.Add(time.Minute)
+	checks := []ghclient.CheckRunInfo{{StartedAt: &late}, {QueuedAt: &early, StartedAt: &late}}
+
+	if got := timelineOrigin(pushed, checks); !got.Equal(pushed) {
+		t.Errorf("origin = %v, want push time", got)
+	}
+	if got := timelineOrigin(time.Time{}, checks); !got.Equal(early) {
+		t.Errorf("origin = %v, want earliest queue time", got)
+	}
+	if got := timelineOrigin(time.Time{}, nil); !got.IsZero() {
+		t.Errorf("origin = %v, want zero without timing", got)
+	}
+}
+
+func TestRenderTimeline(t *testing.T) {
+	origin := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) *time.Time {
+		ts := origin.Add(time.Duration(min) * time.Minute)
+		return &ts
+	}
+	// 60 minutes over 59 columns: each column is just over a minute.
+	now := *at(60)
+	checks := []ghclient.CheckRunInfo{
+		{Name: "test", Status: "in_progress", QueuedAt: at(30), StartedAt: at(40)},
+		{Name: "lint", Status: "completed", Conclusion: "success", StartedAt: at(10), CompletedAt: at(30)},
+		{Name: "deploy", Status: "queued", QueuedAt: at(50)},
+	}
+
+	var b strings.Builder
+	renderTimeline(&b, stylesForTest(), checks, origin, now)
+	lines := strings.Split(b.String(), "\n")
+
+	// Rows are ordered by start time, not table order; queued checks last.
+	for i, name := range []string{"lint", "test", "deploy"} {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), name) {
+			t.Errorf("row %d = %q, want %s", i, lines[i], name)
+		}
+	}
+
+	tests := []struct {
+		row         int
+		queue, runs int
+	}{
+		{row: 0, queue: 9, runs: 20},  // no QueuedAt: queue counted from push
+		{row: 1, queue: 10, runs: 20}, // running up to the now column
+		{row: 2, queue: 10},           // still waiting
+	}
+	for _, tt := range tests {
+		if got := strings.Count(lines[tt.row], timelineQueueCell); got != tt.queue {
+			t.Errorf("row %d queue cells = %d, want %d: %q", tt.row, got, tt.queue, lines[tt.row])
+		}
+		if got := strings.Count(lines[tt.row], timelineRunCell); got != tt.runs {
+			t.Errorf("row %d run cells = %d, want %d: %q", tt.row, got, tt.runs, lines[tt.row])
+		}
+		if !strings.HasSuffix(lines[tt.row], timelineNowCell) {
+			t.Errorf("row %d missing now marker: %q", tt.row, lines[tt.row])
+		}
+	}
+	if !strings.Contains(b.String(), "now (1h 0m 0s)") {
+		t.Errorf("missing axis label:\n%s", b.String())
+	}
+
+	t.Run("no origin", func(t *testing.T) {
+		var b strings.Builder
+		renderTimeline(&b, stylesForTest(), checks, time.Time{}, now)
+		if !strings.Contains(b.String(), "No timing data yet") {
+			t.Errorf("unexpected output: %q", b.String())
+		}
+	})
+}
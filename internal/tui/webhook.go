@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/fini-net/gh-observer/internal/webhook"
+)
+
+// webhookDebounce coalesces a burst of deliveries (a workflow starting
+// sends one per job) into a single refresh.
+const webhookDebounce = time.Second
+
+// WebhookMsg carries one verified webhook delivery.
+type WebhookMsg struct {
+	Event webhook.Event
+}
+
+// webhookRefreshMsg fires webhookDebounce after the first relevant delivery
+// of a burst.
+type webhookRefreshMsg struct{}
+
+// waitForWebhook delivers the next event from events, or nothing when
+// webhooks are off.
+func waitForWebhook(events <-chan webhook.Event) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
+		}
+		return WebhookMsg{Event: ev}
+	}
+}
+
+// webhookState is the models' webhook plumbing: a relevant delivery
+// triggers a fetch, and polling slows to a reconciliation interval.
+type webhookState struct {
+	events         <-chan webhook.Event
+	reconcile      time.Duration
+	refreshPending bool
+}
+
+// pollInterval stretches a poll interval to the reconciliation interval
+// while deliveries are arriving.
+func (w webhookState) pollInterval(interval time.Duration) time.Duration {
+	if w.events == nil {
+		return interval
+	}
+	return max(interval, w.reconcile)
+}
+
+// receive handles a delivery: schedules a refresh if it is relevant and
+// none is pending, and waits for the next delivery.
+func (w *webhookState) receive(relevant bool) tea.Cmd {
+	cmds := []tea.Cmd{waitForWebhook(w.events)}
+	if relevant && !w.refreshPending {
+		w.refreshPending = true
+		cmds = append(cmds, tea.Tick(webhookDebounce, func(time.Time) tea.Msg {
+			return webhookRefreshMsg{}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// sameRepo reports whether ev is from owner/repo.
+func sameRepo(ev webhook.Event, owner, repo string) bool {
+	return strings.EqualFold(ev.Repo, owner+"/"+repo)
+}
+
+// WithWebhooks makes the model refresh when a delivery about its PR
+// arrives on events, polling only every reconcile.
+func (m Model) WithWebhooks(events <-chan webhook.Event, reconcile time.Duration) Model {
+	m.webhooks = webhookState{events: events, reconcile: reconcile}
+	return m
+}
+
+// webhookRelevant reports whether ev concerns the watched PR: it names the
+// PR, or is about its head commit. Before the PR info arrives the head
+// isn't known, so any delivery for the repo counts.
+func (m Model) webhookRelevant(ev webhook.Event) bool {
+	if !sameRepo(ev, m.owner, m.repo) {
+		return false
+	}
+	return m.feed.HeadSHA == "" || ev.HeadSHA == m.feed.HeadSHA || slices.Contains(ev.PRNumbers, m.prNumber)
+}
+
+// WithWebhooks makes the model refresh when a delivery about its run
+// arrives on events, polling only every reconcile.
+func (m RunModel) WithWebhooks(events <-chan webhook.Event, reconcile time.Duration) RunModel {
+	m.webhooks = webhookState{events: events, reconcile: reconcile}
+	return m
+}
+
+// webhookRelevant reports whether ev concerns the watched run. Events that
+// name a run must name this one; the rest (check suites) match on the head
+// commit.
+func (m RunModel) webhookRelevant(ev webhook.Event) bool {
+	if !sameRepo(ev, m.owner, m.repo) {
+		return false
+	}
+	if ev.RunID != 0 {
+		return ev.RunID == m.runID
+	}
+	return m.runInfo.HeadSHA != "" && ev.HeadSHA == m.runInfo.HeadSHA
+}
+
+// WithWebhooks makes the model refresh when any delivery for its repo
+// arrives on events, polling only every reconcile.
+func (m RepoModel) WithWebhooks(events <-chan webhook.Event, reconcile time.Duration) RepoModel {
+	m.webhooks = webhookState{events: events, reconcile: reconcile}
+	return m
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/webhook"
+)
+
+func TestWebhookRelevant(t *testing.T) {
+	pr := makeModel()
+	pr.prNumber = 7
+	pr.feed.HeadSHA = "abc"
+	run := RunModel{owner: "test-owner", repo: "test-repo", runID: 42, runInfo: ghclient.RunInfo{HeadSHA: "abc"}}
+
+	tests := []struct {
+		name    string
+		ev      webhook.Event
+		wantPR  bool
+		wantRun bool
+	}{
+		{name: "other repo", ev: webhook.Event{Repo: "someone/else", HeadSHA: "abc", RunID: 42}},
+		{name: "repo name case differs", ev: webhook.Event{Repo: "Test-Owner/Test-Repo", HeadSHA: "abc", RunID: 42}, wantPR: true, wantRun: true},
+		{name: "names the PR", ev: webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "old", PRNumbers: []int{3, 7}}, wantPR: true},
+		{name: "other commit", ev: webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "def"}},
+		{name: "check suite on head", ev: webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "abc"}, wantPR: true, wantRun: true},
+		{name: "other run on head", ev: webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "abc", RunID: 41}, wantPR: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pr.webhookRelevant(tt.ev); got != tt.wantPR {
+				t.Errorf("PR webhookRelevant = %v, want %v", got, tt.wantPR)
+			}
+			if got := run.webhookRelevant(tt.ev); got != tt.wantRun {
+				t.Errorf("run webhookRelevant = %v, want %v", got, tt.wantRun)
+			}
+		})
+	}
+
+	pr.feed.HeadSHA = ""
+	if !pr.webhookRelevant(webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "def"}) {
+		t.Error("before the head is known, any delivery for the repo should count")
+	}
+}
+
+func TestWebhookDebounce(t *testing.T) {
+	// Closed, so counting the cmds (which runs them) doesn't block on the
+	// re-armed wait.
+	events := make(chan webhook.Event)
+	close(events)
+	m := *makeModel()
+	m.prNumber = 7
+	m.feed.HeadSHA = "abc"
+	m = m.WithWebhooks(events, 2*time.Minute)
+
+	if got := m.webhooks.pollInterval(5 * time.Second); got != 2*time.Minute {
+		t.Errorf("pollInterval = %v, want the 2m reconcile interval", got)
+	}
+	if got := (webhookState{}).pollInterval(5 * time.Second); got != 5*time.Second {
+		t.Errorf("pollInterval without webhooks = %v, want 5s", got)
+	}
+
+	relevant := WebhookMsg{Event: webhook.Event{Repo: "test-owner/test-repo", HeadSHA: "abc"}}
+	updated, cmd := m.Update(relevant)
+	m = updated.(Model)
+	if !m.webhooks.refreshPending || countBatchedCmds(cmd) != 2 {
+		t.Fatalf("first delivery: pending = %v, cmds = %d; want a refresh scheduled and the wait re-armed", m.webhooks.refreshPending, countBatchedCmds(cmd))
+	}
+
+	updated, cmd = m.Update(relevant)
+	m = updated.(Model)
+	if countBatchedCmds(cmd) != 1 {
+		t.Errorf("delivery while a refresh is pending should only re-arm the wait, got %d cmds", countBatchedCmds(cmd))
+	}
+
+	updated, cmd = m.Update(webhookRefreshMsg{})
+	m = updated.(Model)
+	if m.webhooks.refreshPending || cmd == nil {
+		t.Errorf("refresh: pending = %v, cmd = %v; want a fetch and the pending flag cleared", m.webhooks.refreshPending, cmd)
+	}
+
+	m.feed.Quota.LimitedUntil = time.Now().Add(time.Minute)
+	m.webhooks.refreshPending = true
+	updated, cmd = m.Update(webhookRefreshMsg{})
+	if updated.(Model).webhooks.refreshPending || cmd != nil {
+		t.Error("refresh while rate limited should be dropped, leaving the paused tick to resume")
+	}
+}
@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+const (
+	// RateBackoffThreshold is the remaining quota below which polling
+	// backs off to three times its interval.
+	RateBackoffThreshold = 10
+
+	// MinRateLimitForFetch is the remaining quota below which optional
+	// fetches (history, deployments, runners) are skipped, and which the
+	// poll budget keeps in reserve.
+	MinRateLimitForFetch = 100
+
+	// fastPollWindow is how long after a push polling stays fast: checks
+	// are being created and picked up by runners.
+	fastPollWindow = 2 * time.Minute
+
+	// nearFinishWindow is how close a running check must be to its
+	// historical average before polling speeds up to catch the result.
+	nearFinishWindow = 30 * time.Second
+
+	// longWaitThreshold is how far off the soonest expected finish must be
+	// before polling slows down: everything is queued behind a long job.
+	longWaitThreshold = 5 * time.Minute
+
+	// MaxPollInterval caps the slow interval however large the base is.
+	MaxPollInterval = 2 * time.Minute
+
+	// assumedResetWindow budgets polls when no response has reported the
+	// quota reset time yet (GitHub's primary window is an hour).
+	assumedResetWindow = time.Hour
+)
+
+// PollState is what Interval needs to know about the watched checks and
+// the API quota.
+type PollState struct {
+	// Pushed is the head push time; zero when unknown (repo mode).
+	Pushed time.Time
+	// Active is whether any check or run is queued or in progress.
+	Active bool
+	// NextFinish is the expected time until the soonest running check
+	// reaches its historical average (0 once overdue), or -1 when no
+	// running check has an average.
+	NextFinish time.Duration
+	// Remaining is the API quota left; only trusted when Received is set.
+	Remaining int
+	Received  bool
+	// ResetAt is when the quota window resets (or last reset, once
+	// passed); zero when unknown.
+	ResetAt time.Time
+	// CostPerPoll is roughly how many requests one poll spends.
+	CostPerPoll int
+}
+
+// Interval picks the next poll interval around base: fast right after a
+// push or when a check is about to finish, slow when nothing is active or
+// everything waits behind a long job, and never faster than the remaining
+// quota can sustain until it resets.
+func Interval(base time.Duration, s PollState, now time.Time) time.Duration {
+	fast := max(base/2, min(base, 2*time.Second))
+	slow := max(min(4*base, MaxPollInterval), base)
+
+	interval := base
+	switch {
+	case !s.Pushed.IsZero() && now.Sub(s.Pushed) < fastPollWindow:
+		interval = fast
+	case !s.Active:
+		interval = slow
+	case s.NextFinish >= 0 && s.NextFinish <= nearFinishWindow:
+		interval = fast
+	case s.NextFinish > longWaitThreshold:
+		interval = slow
+	}
+
+	return max(interval, budgetFloor(s, now))
+}
+
+// budgetFloor is the shortest interval that spreads the quota left above
+// MinRateLimitForFetch over the time until it resets. Zero before the
+// first response reports the quota, and once the reset has passed.
+func budgetFloor(s PollState, now time.Time) time.Duration {
+	if !s.Received {
+		return 0
+	}
+	resetIn := assumedResetWindow
+	if !s.ResetAt.IsZero() {
+		resetIn = s.ResetAt.Sub(now)
+		if resetIn <= 0 {
+			// The quota Remaining counts has since been refilled.
+			return 0
+		}
+	}
+	spare := s.Remaining - MinRateLimitForFetch
+	if spare <= 0 {
+		return resetIn
+	}
+	return min(resetIn*time.Duration(max(s.CostPerPoll, 1))/time.Duration(spare), resetIn)
+}
+
+// NextExpectedFinish returns how long until the soonest running check
+// reaches its historical average: 0 when one is already overdue, -1 when
+// no running check has an average to go by.
+func NextExpectedFinish(checks []ghclient.CheckRunInfo, averages map[string]time.Duration, now time.Time) time.Duration {
+	next := time.Duration(-1)
+	for _, check := range checks {
+		if check.Status == "completed" || check.StartedAt == nil {
+			continue
+		}
+		avg, ok := averages[check.Name]
+		if !ok || avg <= 0 {
+			continue
+		}
+		left := max(avg-now.Sub(*check.StartedAt), 0)
+		if next < 0 || left < next {
+			next = left
+		}
+	}
+	return next
+}
+
+// AnyActive reports whether any check is queued or in progress.
+func AnyActive(checks []ghclient.CheckRunInfo) bool {
+	for _, check := range checks {
+		if check.Status != "completed" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+func TestAdaptiveInterval(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	base := 5 * time.Second
+	plenty := PollState{Remaining: 5000, Received: true, ResetAt: now.Add(time.Hour), CostPerPoll: 1}
+
+	with := func(f func(*PollState)) PollState {
+		s := plenty
+		f(&s)
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		state PollState
+		want  time.Duration
+	}{
+		{name: "just pushed", state: with(func(s *PollState) { s.Pushed = now.Add(-30 * time.Second); s.Active = true; s.NextFinish = -1 }), want: 2500 * time.Millisecond},
+		{name: "running without history", state: with(func(s *PollState) { s.Active = true; s.NextFinish = -1 }), want: base},
+		{name: "job about to finish", state: with(func(s *PollState) { s.Active = true; s.NextFinish = 10 * time.Second }), want: 2500 * time.Millisecond},
+		{name: "job overdue", state: with(func(s *PollState) { s.Active = true; s.NextFinish = 0 }), want: 2500 * time.Millisecond},
+		{name: "mid-run", state: with(func(s *PollState) { s.Active = true; s.NextFinish = 2 * time.Minute }), want: base},
+		{name: "queued behind a long job", state: with(func(s *PollState) { s.Active = true; s.NextFinish = 20 * time.Minute }), want: 20 * time.Second},
+		{name: "idle", state: with(func(s *PollState) { s.NextFinish = -1 }), want: 20 * time.Second},
+		{name: "quota nearly spent", state: with(func(s *PollState) {
+			s.Active = true
+			s.NextFinish = -1
+			s.Remaining = MinRateLimitForFetch + 60
+			s.ResetAt = now.Add(30 * time.Minute)
+		}), want: 30 * time.Second},
+		{name: "quota budget scales with cost", state: with(func(s *PollState) {
+			s.Active = true
+			s.NextFinish = -1
+			s.Remaining = MinRateLimitForFetch + 600
+			s.ResetAt = now.Add(10 * time.Minute)
+			s.CostPerPoll = 5
+		}), want: 5 * time.Second},
+		{name: "quota spent waits for reset", state: with(func(s *PollState) {
+			s.Pushed = now
+			s.Remaining = MinRateLimitForFetch
+			s.ResetAt = now.Add(3 * time.Minute)
+		}), want: 3 * time.Minute},
+		{name: "unknown reset assumes an hour", state: with(func(s *PollState) {
+			s.Active = true
+			s.NextFinish = -1
+			s.Remaining = MinRateLimitForFetch + 360
+			s.ResetAt = time.Time{}
+		}), want: 10 * time.Second},
+		{name: "quota not yet reported", state: PollState{Active: true, NextFinish: -1}, want: base},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interval(base, tt.state, now); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveIntervalSlowIsCapped(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	if got := Interval(time.Minute, PollState{NextFinish: -1}, now); got != MaxPollInterval {
+		t.Errorf("idle interval = %v, want %v", got, MaxPollInterval)
+	}
+	if got := Interval(5*time.Minute, PollState{NextFinish: -1}, now); got != 5*time.Minute {
+		t.Errorf("idle interval below base: %v", got)
+	}
+}
+
+func TestNextExpectedFinish(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	startedAgo := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+	averages := map[string]time.Duration{
+		"build": 5 * time.Minute,
+		"test":  2 * time.Minute,
+		"lint":  time.Minute,
+	}
+
+	tests := []struct {
+		name   string
+		checks []ghclient.CheckRunInfo
+		want   time.Duration
+	}{
+		{name: "no checks", want: -1},
+		{name: "no history", checks: []ghclient.CheckRunInfo{
+			{Name: "deploy", Status: "in_progress", StartedAt: startedAgo(time.Minute)},
+		}, want: -1},
+		{name: "soonest of several", checks: []ghclient.CheckRunInfo{
+			{Name: "build", Status: "in_progress", StartedAt: startedAgo(time.Minute)},
+			{Name: "test", Status: "in_progress", StartedAt: startedAgo(90 * time.Second)},
+		}, want: 30 * time.Second},
+		{name: "overdue", checks: []ghclient.CheckRunInfo{
+			{Name: "test", Status: "in_progress", StartedAt: startedAgo(3 * time.Minute)},
+		}, want: 0},
+		{name: "completed and queued ignored", checks: []ghclient.CheckRunInfo{
+			{Name: "lint", Status: "completed", StartedAt: startedAgo(time.Minute)},
+			{Name: "test", Status: "queued"},
+			{Name: "build", Status: "in_progress", StartedAt: startedAgo(time.Minute)},
+		}, want: 4 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextExpectedFinish(tt.checks, averages, now); got != tt.want {
+				t.Errorf("NextExpectedFinish() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package watch keeps what a PR or repo watch shows current: it fetches
+// the checks and runs, reduces them to what is worth showing, and picks
+// when to poll next within the API quota. The TUI models and the serve
+// daemon both watch through it.
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// PR is one pull request's checks.
+type PR struct {
+	API    ghclient.API
+	Owner  string
+	Repo   string
+	Number int
+	// RefreshInfo makes Poll re-fetch the title and head commit every
+	// time, for watches that outlive a push.
+	RefreshInfo bool
+
+	Title     string
+	HeadSHA   string
+	CreatedAt time.Time
+	// HeadPushedTime is the head commit's push time, from the checks
+	// query; a poll without one keeps the last known value.
+	HeadPushedTime time.Time
+	CheckRuns      []ghclient.CheckRunInfo
+
+	Quota Quota
+}
+
+// NewPR returns a watch of owner/repo#number with nothing fetched yet.
+func NewPR(api ghclient.API, owner, repo string, number int) PR {
+	return PR{API: api, Owner: owner, Repo: repo, Number: number}
+}
+
+// PRInfo is the PR's metadata, as FetchInfo returns it.
+type PRInfo struct {
+	Number    int
+	Title     string
+	HeadSHA   string
+	CreatedAt time.Time
+	Err       error
+}
+
+// FetchInfo fetches the PR's metadata. The head push time isn't part of
+// it: it arrives with the checks (issue #349).
+func (p PR) FetchInfo(ctx context.Context) PRInfo {
+	info, err := p.API.FetchPRInfo(ctx, p.Owner, p.Repo, p.Number)
+	if err != nil {
+		return PRInfo{Err: err}
+	}
+	createdAt, err := ghclient.ParseTimestamp(info.CreatedAt)
+	if err != nil {
+		debug.Log("timestamp parse error", "field", "CreatedAt", "value", info.CreatedAt, "err", err)
+	}
+	return PRInfo{
+		Number:    info.Number,
+		Title:     info.Title,
+		HeadSHA:   info.HeadSHA,
+		CreatedAt: createdAt,
+	}
+}
+
+// ApplyInfo adopts a successful FetchInfo result.
+func (p *PR) ApplyInfo(info PRInfo) {
+	p.Title = info.Title
+	p.HeadSHA = info.HeadSHA
+	p.CreatedAt = info.CreatedAt
+}
+
+// PRChecks is the PR's checks, as FetchChecks returns them.
+type PRChecks struct {
+	CheckRuns []ghclient.CheckRunInfo
+	// HeadPushedTime is the head commit's push time (pushedDate, with
+	// committedDate fallback), from the same GraphQL query.
+	HeadPushedTime     time.Time
+	RateLimitRemaining int
+	Err                error
+}
+
+// FetchChecks fetches the checks of the PR's head commit.
+func (p PR) FetchChecks(ctx context.Context) PRChecks {
+	checkRuns, pushed, rateLimit, err := p.API.FetchCheckRuns(ctx, p.Owner, p.Repo, p.Number)
+	if err != nil {
+		return PRChecks{Err: err}
+	}
+	return PRChecks{CheckRuns: checkRuns, HeadPushedTime: pushed, RateLimitRemaining: rateLimit}
+}
+
+// ApplyChecks adopts a successful FetchChecks result.
+func (p *PR) ApplyChecks(c PRChecks) {
+	p.CheckRuns = c.CheckRuns
+	if !c.HeadPushedTime.IsZero() {
+		p.HeadPushedTime = c.HeadPushedTime
+	}
+	p.Quota.Observe(c.RateLimitRemaining, ghclient.ResourceGraphQL)
+}
+
+// Poll fetches the PR's metadata (the first time, or every time with
+// RefreshInfo) and its checks. On error the last good state stays, and a
+// rate limit is recorded in Quota.
+func (p *PR) Poll(ctx context.Context) error {
+	if p.RefreshInfo || p.HeadSHA == "" {
+		info := p.FetchInfo(ctx)
+		if info.Err != nil {
+			p.Quota.Limited(p.API, info.Err)
+			return info.Err
+		}
+		p.ApplyInfo(info)
+	}
+	checks := p.FetchChecks(ctx)
+	if checks.Err != nil {
+		p.Quota.Limited(p.API, checks.Err)
+		return checks.Err
+	}
+	p.ApplyChecks(checks)
+	return nil
+}
+
+// NextPoll is the adaptive interval around base; averages are the checks'
+// historical durations by name, nil when unknown. One poll is one GraphQL
+// checks query, plus the PR itself with RefreshInfo.
+func (p PR) NextPoll(base time.Duration, averages map[string]time.Duration, now time.Time) time.Duration {
+	cost := 1
+	if p.RefreshInfo {
+		cost = 2
+	}
+	return Interval(base, PollState{
+		Pushed:      p.HeadPushedTime,
+		Active:      !p.Quota.Received || AnyActive(p.CheckRuns),
+		NextFinish:  NextExpectedFinish(p.CheckRuns, averages, now),
+		Remaining:   p.Quota.Remaining,
+		Received:    p.Quota.Received,
+		ResetAt:     p.Quota.ResetAt(p.API),
+		CostPerPoll: cost,
+	}, now)
+}
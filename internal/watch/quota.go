@@ -0,0 +1,121 @@
+package watch
+
+import (
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// Quota is what a watch knows of the API quota from its own responses.
+type Quota struct {
+	// Remaining is the quota left as of the last response. Until Received
+	// is set it is the zero value, which must neither be shown as
+	// "0 remaining" nor trigger backoff.
+	Remaining int
+	Received  bool
+	// Resource is the quota Remaining counts, whose reset paces polling.
+	Resource string
+	// reset is Resource's reset time as of the report Lower kept, and
+	// stale is set between StartPoll and the poll's first report.
+	reset time.Time
+	stale bool
+	// LimitedUntil is when GitHub will accept requests again after a
+	// rate-limited fetch.
+	LimitedUntil time.Time
+}
+
+// Observe records the remaining resource quota a response reported.
+func (q *Quota) Observe(remaining int, resource string) {
+	q.Remaining = remaining
+	q.Received = true
+	q.Resource = resource
+	q.stale = false
+}
+
+// StartPoll begins a poll cycle: its first report replaces Remaining, and
+// Lower keeps the minimum only within the cycle.
+func (q *Quota) StartPoll() {
+	q.stale = true
+}
+
+// Lower records remaining (with its resource's reset time) if it is the
+// cycle's first report, below the last, or from a later window of the same
+// resource, for watches whose sources report independently: neither may
+// raise the value past what the other saw in the same window.
+func (q *Quota) Lower(remaining int, resource string, reset time.Time) {
+	newWindow := resource == q.Resource && reset.After(q.reset)
+	if !q.Received || q.stale || remaining < q.Remaining || newWindow {
+		q.Observe(remaining, resource)
+		q.reset = reset
+	}
+}
+
+// ResetAt is when the quota Remaining counts resets; see ResetAt.
+func (q Quota) ResetAt(api ghclient.API) time.Time {
+	return ResetAt(api, q.Resource)
+}
+
+// Limited reports whether err means GitHub is rate limiting us, recording
+// when to resume if so.
+func (q *Quota) Limited(api ghclient.API, err error) bool {
+	until, ok := ResumeAt(api, err)
+	if ok {
+		q.LimitedUntil = until
+	}
+	return ok
+}
+
+// Wait returns how long polling must stand down for a rate limit; see
+// Wait.
+func (q Quota) Wait(api ghclient.API, now time.Time) time.Duration {
+	return Wait(api, q.LimitedUntil, now)
+}
+
+// Hold returns how long to put off the next poll: until a rate limit
+// lifts, or three base intervals while the quota is nearly spent and
+// hasn't reset since. Zero means poll now.
+func (q Quota) Hold(api ghclient.API, base time.Duration, now time.Time) time.Duration {
+	if wait := q.Wait(api, now); wait > 0 {
+		return wait
+	}
+	reset := q.ResetAt(api)
+	if q.Received && q.Remaining < RateBackoffThreshold && (reset.IsZero() || now.Before(reset)) {
+		return 3 * base
+	}
+	return 0
+}
+
+// PausedUntil, ResetAt and ResumeAt read the session's rate-limit state.
+// A watch without a session has no governor to consult; only the error
+// itself can say it was rate limited. Watches spend the core and GraphQL
+// quotas, so a pause on either holds them.
+func PausedUntil(api ghclient.API) time.Time {
+	if api == nil {
+		return time.Time{}
+	}
+	return api.RateLimitPausedUntil(ghclient.ResourceCore, ghclient.ResourceGraphQL)
+}
+
+func ResetAt(api ghclient.API, resource string) time.Time {
+	if api == nil {
+		return time.Time{}
+	}
+	return api.RateLimitResetAt(resource)
+}
+
+func ResumeAt(api ghclient.API, err error) (time.Time, bool) {
+	if api == nil {
+		return ghclient.RateLimitResumeAt(err)
+	}
+	return api.RateLimitResumeAt(err)
+}
+
+// Wait returns how long polling should stand down: until the later of
+// until (from a rate-limited fetch error) and the session governor's
+// pause, or 0 when neither is in the future.
+func Wait(api ghclient.API, until, now time.Time) time.Duration {
+	if governed := PausedUntil(api); governed.After(until) {
+		until = governed
+	}
+	return max(until.Sub(now), 0)
+}
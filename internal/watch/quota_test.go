@@ -0,0 +1,35 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+func TestQuotaLower(t *testing.T) {
+	reset := time.Date(2026, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	var q Quota
+	q.Lower(4000, ghclient.ResourceGraphQL, reset)
+	q.Lower(4500, ghclient.ResourceCore, reset)
+	if q.Remaining != 4000 || q.Resource != ghclient.ResourceGraphQL {
+		t.Errorf("quota = %d %s, want 4000 graphql kept", q.Remaining, q.Resource)
+	}
+	q.Lower(300, ghclient.ResourceCore, reset)
+	if q.Remaining != 300 || q.Resource != ghclient.ResourceCore {
+		t.Errorf("quota = %d %s, want 300 core: the lower one paces polling", q.Remaining, q.Resource)
+	}
+
+	q.Lower(4900, ghclient.ResourceCore, reset.Add(time.Hour))
+	if q.Remaining != 4900 {
+		t.Errorf("quota = %d, want 4900 from core's next window", q.Remaining)
+	}
+
+	q.StartPoll()
+	q.Lower(4800, ghclient.ResourceGraphQL, reset)
+	q.Lower(4850, ghclient.ResourceCore, reset.Add(time.Hour))
+	if q.Remaining != 4800 || q.Resource != ghclient.ResourceGraphQL {
+		t.Errorf("quota = %d %s, want 4800 graphql: the minimum of this poll", q.Remaining, q.Resource)
+	}
+}
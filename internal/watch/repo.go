@@ -0,0 +1,451 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// PRView is an open PR as a repo watch shows it. HeadSHA carries the PR
+// head commit OID so standalone runs can be matched to the PR and their
+// jobs deduped or attached. CheckRuns holds the GraphQL-sourced checks
+// still within their fade window, replaced wholesale by every ApplyChecks.
+// ExtraCheckRuns holds "extra" jobs (e.g. Copilot) that the PR GraphQL
+// query misses but the REST branch-runs path surfaces; it is rebuilt from
+// the current visible runs by every ApplyRuns (not appended to) and
+// carried across ApplyChecks so extras don't flicker out between runs
+// polls.
+type PRView struct {
+	Title          string
+	CheckRuns      []ghclient.CheckRunInfo
+	ExtraCheckRuns []ghclient.CheckRunInfo
+	HeadPushedTime time.Time
+	HeadSHA        string
+}
+
+// Repo is a repo's open PRs and standalone (non-PR) workflow runs, with
+// completed checks and runs faded out so what's left is what's active.
+type Repo struct {
+	API   ghclient.API
+	Owner string
+	Repo  string
+
+	// Filter narrows the PRs and runs. FilterGen counts its changes, so
+	// results fetched under an older filter are dropped.
+	Filter    ghclient.RepoFilter
+	FilterGen int
+
+	// How long completed checks and runs stay listed.
+	FadeSuccess time.Duration
+	FadeFailure time.Duration
+
+	// PRs are the open PRs with a visible check, by number.
+	PRs map[int]PRView
+	// Runs are the visible standalone runs, on no open PR's head commit.
+	Runs []ghclient.BranchRunData
+	// PRHeadSHAs are every open PR's head commit, visible or not.
+	PRHeadSHAs map[string]bool
+	// How many open PRs the last checks fetch covered, and how many it
+	// left out to stay within its query budget.
+	FetchedPRs int
+	OmittedPRs int
+
+	Quota Quota
+
+	// jobs holds completed standalone runs' jobs, reused until the run
+	// changes so each poll only re-lists jobs for active runs. Shared by
+	// pointer across copies; the cache does its own locking.
+	jobs *ghclient.CompletedRunJobs
+}
+
+// NewRepo returns a watch of owner/repo with nothing fetched yet.
+func NewRepo(api ghclient.API, owner, repo string, fadeSuccess, fadeFailure time.Duration) Repo {
+	return Repo{
+		API:         api,
+		Owner:       owner,
+		Repo:        repo,
+		FadeSuccess: fadeSuccess,
+		FadeFailure: fadeFailure,
+		PRs:         make(map[int]PRView),
+		jobs:        ghclient.NewCompletedRunJobs(),
+	}
+}
+
+// SetFilter switches to filter, dropping what was fetched under the old
+// one. It reports whether the filter changed.
+func (r *Repo) SetFilter(filter ghclient.RepoFilter) bool {
+	if filter.Equal(r.Filter) {
+		return false
+	}
+	r.Filter = filter
+	r.FilterGen++
+	r.PRs = make(map[int]PRView)
+	r.Runs = nil
+	r.FetchedPRs, r.OmittedPRs = 0, 0
+	return true
+}
+
+// FadeWindow is the longer of the two fade windows, which bounds how far
+// back completed standalone runs are listed.
+func (r Repo) FadeWindow() time.Duration {
+	return max(r.FadeSuccess, r.FadeFailure)
+}
+
+// fade returns how long a check or run with conclusion stays listed.
+func (r Repo) fade(conclusion string) time.Duration {
+	if ghclient.FailureConclusion(conclusion) {
+		return r.FadeFailure
+	}
+	return r.FadeSuccess
+}
+
+// PRNumbers returns the visible PRs' numbers in ascending order.
+func (r Repo) PRNumbers() []int {
+	nums := make([]int, 0, len(r.PRs))
+	for n := range r.PRs {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// RepoChecks is the open PRs' checks, as FetchChecks returns them.
+type RepoChecks struct {
+	PRData map[int]ghclient.PRCheckData
+	// OmittedPRs counts open PRs the query budget left out (the least
+	// active ones).
+	OmittedPRs         int
+	RateLimitRemaining int
+	Err                error
+	// FilterGen is the Repo.FilterGen the fetch was issued under.
+	FilterGen int
+}
+
+// FetchChecks issues the batched GraphQL query for every open PR matching
+// the filter.
+func (r Repo) FetchChecks(ctx context.Context) RepoChecks {
+	prData, omitted, rateLimit, err := r.API.FetchRepoCheckRuns(ctx, r.Owner, r.Repo, r.Filter)
+	return RepoChecks{
+		PRData:             prData,
+		OmittedPRs:         omitted,
+		RateLimitRemaining: rateLimit,
+		Err:                err,
+		FilterGen:          r.FilterGen,
+	}
+}
+
+// ApplyChecks adopts a successful FetchChecks result. A PR stays visible
+// while it has an active (in_progress/queued/waiting) check, or a
+// completed one whose CompletedAt is within its fade window. Extras from
+// the last ApplyRuns carry over.
+func (r *Repo) ApplyChecks(c RepoChecks, now time.Time) {
+	// Take the minimum across sources within the poll, so neither can
+	// raise the value past what the other observed.
+	r.Quota.Lower(c.RateLimitRemaining, ghclient.ResourceGraphQL, ResetAt(r.API, ghclient.ResourceGraphQL))
+	r.FetchedPRs = len(c.PRData)
+	r.OmittedPRs = c.OmittedPRs
+
+	r.PRHeadSHAs = make(map[string]bool, len(c.PRData))
+	for _, pr := range c.PRData {
+		r.PRHeadSHAs[pr.HeadSHA] = true
+	}
+
+	prs := make(map[int]PRView)
+	for prNum, pr := range c.PRData {
+		var visible []ghclient.CheckRunInfo
+		for _, cr := range pr.CheckRuns {
+			if cr.Status == "in_progress" || cr.Status == "queued" || cr.Status == "waiting" {
+				visible = append(visible, cr)
+				continue
+			}
+			if cr.Status == "completed" && cr.CompletedAt != nil && now.Sub(*cr.CompletedAt) < r.fade(cr.Conclusion) {
+				visible = append(visible, cr)
+			}
+		}
+		if len(visible) == 0 {
+			continue
+		}
+		view := PRView{
+			Title:          pr.Title,
+			CheckRuns:      visible,
+			HeadPushedTime: pr.HeadPushedTime,
+			HeadSHA:        pr.HeadSHA,
+		}
+		if prev, ok := r.PRs[prNum]; ok {
+			view.ExtraCheckRuns = prev.ExtraCheckRuns
+		}
+		prs[prNum] = view
+	}
+	r.PRs = prs
+
+	debug.Log("repo checks update", "repo", r.Owner+"/"+r.Repo, "total_prs", len(c.PRData), "active_prs", len(prs),
+		"rate_limit_remaining", c.RateLimitRemaining)
+}
+
+// RepoRuns is the standalone runs, as FetchRuns returns them.
+type RepoRuns struct {
+	Runs               []ghclient.BranchRunData
+	RateLimitRemaining int
+	Err                error
+	// FilterGen is as in RepoChecks.
+	FilterGen int
+}
+
+// FetchRuns lists the runs matching the filter that are active or
+// finished within the fade window, with their jobs. Job enrichment failure
+// is non-fatal: runs are still returned with empty Jobs so their headers
+// can render. Completed runs already in the job cache skip the jobs call.
+func (r Repo) FetchRuns(ctx context.Context) RepoRuns {
+	runs, rateLimit, err := r.API.FetchRepoWorkflowRuns(ctx, r.Owner, r.Repo, r.FadeWindow(), r.Filter)
+	if err != nil {
+		return RepoRuns{Err: err, FilterGen: r.FilterGen}
+	}
+	enriched, rl2, err := r.API.EnrichRepoRunsWithJobs(ctx, r.Owner, r.Repo, runs, r.jobs)
+	if err != nil {
+		debug.Log("enrich repo runs error", "err", err)
+	}
+	return RepoRuns{Runs: enriched, RateLimitRemaining: min(rateLimit, rl2), FilterGen: r.FilterGen}
+}
+
+// ApplyRuns adopts a successful FetchRuns result and returns the visible
+// runs: active ones, and completed ones started within their fade window.
+// Runs is what's left of them once reconciled against the PRs; see
+// dedupeAndAttachExtraJobs.
+func (r *Repo) ApplyRuns(rs RepoRuns, now time.Time) []ghclient.BranchRunData {
+	r.Quota.Lower(rs.RateLimitRemaining, ghclient.ResourceCore, ResetAt(r.API, ghclient.ResourceCore))
+
+	var visible []ghclient.BranchRunData
+	for _, run := range rs.Runs {
+		if IsActiveRun(run.Status) {
+			visible = append(visible, run)
+			continue
+		}
+		if run.Status == "completed" && !run.RunStartedAt.IsZero() && now.Sub(run.RunStartedAt) < r.fade(run.Conclusion) {
+			visible = append(visible, run)
+		}
+	}
+	r.Runs = r.dedupeAndAttachExtraJobs(visible)
+
+	debug.Log("repo runs update", "repo", r.Owner+"/"+r.Repo, "total", len(rs.Runs), "visible", len(visible),
+		"standalone", len(r.Runs), "rate_limit_remaining", rs.RateLimitRemaining)
+	return visible
+}
+
+// Poll fetches and applies the PRs' checks and the standalone runs. The
+// two sources are independent: one failing leaves the other's result
+// applied and its own last good state in place. A rate limit is recorded
+// in Quota.
+func (r *Repo) Poll(ctx context.Context) error {
+	now := time.Now()
+	r.Quota.StartPoll()
+	checks := r.FetchChecks(ctx)
+	if checks.Err == nil {
+		r.ApplyChecks(checks, now)
+	} else {
+		r.Quota.Limited(r.API, checks.Err)
+	}
+	runs := r.FetchRuns(ctx)
+	if runs.Err == nil {
+		r.ApplyRuns(runs, now)
+	} else {
+		r.Quota.Limited(r.API, runs.Err)
+	}
+	return errors.Join(checks.Err, runs.Err)
+}
+
+// IsActiveRun reports whether a run with status is always shown.
+func IsActiveRun(status string) bool {
+	switch status {
+	case "in_progress", "queued", "waiting", "pending":
+		return true
+	}
+	return false
+}
+
+// Activity reports whether anything in the repo is queued or running (or
+// nothing has been fetched yet), and how many standalone runs are active,
+// each costing a jobs list per poll.
+func (r Repo) Activity() (active bool, activeRuns int) {
+	active = !r.Quota.Received
+	for _, pr := range r.PRs {
+		active = active || AnyActive(pr.CheckRuns) || AnyActive(pr.ExtraCheckRuns)
+	}
+	for _, run := range r.Runs {
+		if run.Status != "completed" {
+			active = true
+			activeRuns++
+		}
+	}
+	return active, activeRuns
+}
+
+// NextExpectedFinish is NextExpectedFinish across every running PR check
+// and standalone-run job, each against its own workflow's averages.
+// Standalone-run jobs come from the REST jobs list, which doesn't name the
+// workflow, so they go by their run's.
+func (r Repo) NextExpectedFinish(averages map[int64]map[string]time.Duration, now time.Time) time.Duration {
+	next := time.Duration(-1)
+	consider := func(check ghclient.CheckRunInfo, workflowID int64) {
+		left := NextExpectedFinish([]ghclient.CheckRunInfo{check}, averages[workflowID], now)
+		if left >= 0 && (next < 0 || left < next) {
+			next = left
+		}
+	}
+	for _, pr := range r.PRs {
+		for _, check := range slices.Concat(pr.CheckRuns, pr.ExtraCheckRuns) {
+			consider(check, check.WorkflowID)
+		}
+	}
+	for _, run := range r.Runs {
+		for _, job := range run.Jobs {
+			consider(job, run.WorkflowID)
+		}
+	}
+	return next
+}
+
+// NextPoll is the adaptive interval around base, paced by activity, the
+// averages by workflow ID (may be nil) and quota; extraCost adds to a poll.
+func (r Repo) NextPoll(base time.Duration, averages map[int64]map[string]time.Duration, extraCost int, now time.Time) time.Duration {
+	active, activeRuns := r.Activity()
+	cost := 4 + (r.FetchedPRs+ghclient.RepoPRsPerQuery-1)/ghclient.RepoPRsPerQuery + activeRuns + extraCost
+	return Interval(base, PollState{
+		Active:      active,
+		NextFinish:  r.NextExpectedFinish(averages, now),
+		Remaining:   r.Quota.Remaining,
+		Received:    r.Quota.Received,
+		ResetAt:     r.Quota.ResetAt(r.API),
+		CostPerPoll: cost,
+	}, now)
+}
+
+// jobDedupKey returns the canonical dedup key for a check/job: lowercase
+// "headSHA\x00workflow\x00name". SHA scopes the match to the same commit, and
+// (workflow, name) identifies the job. Lowercasing guards against GitHub
+// returning case variants of the same workflow/job name across the GraphQL
+// and REST paths. A NUL byte separates the fields so a workflow or job name
+// containing "|" can't collide with another split across fields. An empty
+// HeadSHA produces a key prefixed with "\x00", which only matches other
+// empty-SHA entries — so jobs missing a SHA never dedup against PR checks
+// that do have one (and vice versa).
+func jobDedupKey(headSHA, workflowName, name string) string {
+	return strings.ToLower(strings.Join([]string{headSHA, workflowName, name}, "\x00"))
+}
+
+// prCheckKeySet builds a set of dedup keys for the GraphQL-sourced CheckRuns
+// across all currently-tracked PRs. Used to suppress jobs arriving via the
+// standalone branch-runs path whose (HeadSHA, WorkflowName, Name) already
+// appear in a PR's authoritative CheckRuns (see dedupeAndAttachExtraJobs).
+// Prior ExtraCheckRuns are intentionally excluded: extras are rebuilt every
+// runs poll from the current visible set, so a still-present extra must be
+// re-attachable rather than treated as already seen.
+func (r Repo) prCheckKeySet() map[string]bool {
+	seen := make(map[string]bool)
+	for _, pr := range r.PRs {
+		for _, cr := range pr.CheckRuns {
+			seen[jobDedupKey(pr.HeadSHA, cr.WorkflowName, cr.Name)] = true
+		}
+	}
+	return seen
+}
+
+// prByHeadSHA returns the lowest PR number whose HeadSHA matches, or 0 if
+// none. Iterating in ascending PR-number order makes the selection
+// deterministic when multiple PRs share a HeadSHA (e.g. a PR and its
+// backport pointing at the same commit). SHAs from GitHub's GraphQL oid
+// and REST head_sha fields are lowercase hex, so a direct comparison is
+// sufficient; jobDedupKey additionally lowercases for the dedup path.
+func (r Repo) prByHeadSHA(sha string) int {
+	for _, prNum := range r.PRNumbers() {
+		pr := r.PRs[prNum]
+		if pr.HeadSHA != "" && pr.HeadSHA == sha {
+			return prNum
+		}
+	}
+	return 0
+}
+
+// dedupeAndAttachExtraJobs reconciles the standalone branch-runs view against
+// the PR section to remove redundant jobs (issue #331). For each visible run:
+// drops jobs whose (HeadSHA, WorkflowName, Name) already appear in a tracked
+// PR's CheckRuns (authoritative); if the run's HeadSHA matches a tracked PR,
+// attaches the leftover ("extra") jobs under that PR's ExtraCheckRuns so they
+// render in the PR group; otherwise keeps the run (with leftover jobs) as
+// standalone. Completed runs with zero leftover jobs are dropped; active runs
+// are always kept. ExtraCheckRuns is rebuilt (not appended to) every call, so
+// extras whose run has faded out of the visible window are dropped too — they
+// don't linger under the PR after their run is gone. The two fetches land
+// in arbitrary order; if PR data has not yet arrived, every run is treated
+// as standalone, reconciling on the next poll once both sources are present.
+func (r *Repo) dedupeAndAttachExtraJobs(visible []ghclient.BranchRunData) []ghclient.BranchRunData {
+	if len(r.PRs) == 0 {
+		return visible
+	}
+
+	seen := r.prCheckKeySet()
+	// Reset every tracked PR's ExtraCheckRuns up front: extras are rebuilt
+	// from the current visible runs each poll, so extras whose run has faded
+	// out of the window (or now produces no leftover) are dropped rather than
+	// lingering under the PR. Attachments below repopulate for PRs that still
+	// have a matching visible run with leftover jobs.
+	for prNum, pr := range r.PRs {
+		if len(pr.ExtraCheckRuns) > 0 {
+			pr.ExtraCheckRuns = nil
+			r.PRs[prNum] = pr
+		}
+	}
+	// Collect attachments and apply after the loop to keep the dedup key set
+	// stable during iteration.
+	attachments := make(map[int][]ghclient.CheckRunInfo)
+	var attachmentOrder []int
+
+	var standalone []ghclient.BranchRunData
+	for _, run := range visible {
+		var leftover []ghclient.CheckRunInfo
+		for _, job := range run.Jobs {
+			key := jobDedupKey(run.HeadSHA, job.WorkflowName, job.Name)
+			if seen[key] {
+				continue
+			}
+			leftover = append(leftover, job)
+			// Record the extra in the seen set so we don't attach the same
+			// job twice if multiple runs on the same SHA report it.
+			seen[key] = true
+		}
+
+		if prNum := r.prByHeadSHA(run.HeadSHA); prNum != 0 {
+			// Run shares a commit with a tracked PR. Attach leftovers under
+			// it; drop the run from the standalone section regardless (its
+			// commit is owned by a PR).
+			if len(leftover) > 0 {
+				if _, ok := attachments[prNum]; !ok {
+					attachmentOrder = append(attachmentOrder, prNum)
+				}
+				attachments[prNum] = append(attachments[prNum], leftover...)
+			}
+			continue
+		}
+
+		// No matching PR: truly standalone. Keep the run with its (possibly
+		// reduced) job list. Drop completed runs that have no jobs left.
+		if len(leftover) == 0 && !IsActiveRun(run.Status) {
+			continue
+		}
+		run.Jobs = leftover
+		standalone = append(standalone, run)
+	}
+
+	for _, prNum := range attachmentOrder {
+		pr := r.PRs[prNum]
+		pr.ExtraCheckRuns = attachments[prNum]
+		r.PRs[prNum] = pr
+	}
+
+	return standalone
+}
@@ -0,0 +1,177 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+func TestIsActiveRun(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"in_progress", true},
+		{"queued", true},
+		{"waiting", true},
+		{"pending", true},
+		{"completed", false},
+		{"", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := IsActiveRun(tt.status); got != tt.want {
+				t.Errorf("IsActiveRun(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoPRNumbers(t *testing.T) {
+	r := Repo{
+		PRs: map[int]PRView{
+			3: {Title: "c"},
+			1: {Title: "a"},
+			2: {Title: "b"},
+		},
+	}
+	got := r.PRNumbers()
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("PRNumbers[%d] = %d, want %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRepoFadeWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		fadeSuccess time.Duration
+		fadeFailure time.Duration
+		want        time.Duration
+	}{
+		{"failure larger", 15 * time.Minute, 30 * time.Minute, 30 * time.Minute},
+		{"success larger", 30 * time.Minute, 15 * time.Minute, 30 * time.Minute},
+		{"equal", 15 * time.Minute, 15 * time.Minute, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Repo{FadeSuccess: tt.fadeSuccess, FadeFailure: tt.fadeFailure}
+			if got := r.FadeWindow(); got != tt.want {
+				t.Errorf("FadeWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobDedupKey(t *testing.T) {
+	const sep = "\x00"
+	tests := []struct {
+		headSHA, workflow, name string
+		want                    string
+	}{
+		{"abc", "CI", "Build", "abc" + sep + "ci" + sep + "build"},
+		{"", "", "lint", sep + sep + "lint"},
+		{"DEF", "CI", "Build", "def" + sep + "ci" + sep + "build"},
+		// A workflow name containing "|" must not collide with a name-only split.
+		{"abc", "CI|extra", "build", "abc" + sep + "ci|extra" + sep + "build"},
+		{"abc", "CI", "extra|build", "abc" + sep + "ci" + sep + "extra|build"},
+	}
+	for _, tt := range tests {
+		if got := jobDedupKey(tt.headSHA, tt.workflow, tt.name); got != tt.want {
+			t.Errorf("jobDedupKey(%q,%q,%q) = %q, want %q", tt.headSHA, tt.workflow, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestPRByHeadSHA verifies PR lookup by head SHA.
+func TestPRByHeadSHA(t *testing.T) {
+	r := Repo{
+		PRs: map[int]PRView{
+			1: {HeadSHA: "aaa"},
+			2: {HeadSHA: "bbb"},
+		},
+	}
+	if got := r.prByHeadSHA("bbb"); got != 2 {
+		t.Errorf("prByHeadSHA(bbb) = %d, want 2", got)
+	}
+	if got := r.prByHeadSHA("zzz"); got != 0 {
+		t.Errorf("prByHeadSHA(zzz) = %d, want 0", got)
+	}
+	if got := r.prByHeadSHA(""); got != 0 {
+		t.Errorf("prByHeadSHA(empty) = %d, want 0 (empty SHA never matches)", got)
+	}
+}
+
+// TestPRByHeadSHADeterministic verifies that when multiple PRs share the same
+// HeadSHA, prByHeadSHA returns the lowest PR number deterministically rather
+// than whichever entry Go's map iteration happens to yield first.
+func TestPRByHeadSHADeterministic(t *testing.T) {
+	const sharedSHA = "deadbeef"
+	// Insert in reverse order to ensure the result isn't just "first inserted".
+	// Use enough entries to make map-iteration nondeterminism likely to surface
+	// across runs, and run the lookup many times to catch any ordering drift.
+	r := Repo{
+		PRs: map[int]PRView{
+			42: {HeadSHA: sharedSHA},
+			7:  {HeadSHA: sharedSHA},
+			99: {HeadSHA: sharedSHA},
+		},
+	}
+	for i := 0; i < 50; i++ {
+		if got := r.prByHeadSHA(sharedSHA); got != 7 {
+			t.Fatalf("iteration %d: prByHeadSHA(%q) = %d, want 7 (lowest matching PR number)", i, sharedSHA, got)
+		}
+	}
+}
+
+// resetAPI reports fixed quota reset times; other API calls are unused.
+type resetAPI struct {
+	ghclient.API
+	resets map[string]time.Time
+}
+
+func (a resetAPI) RateLimitPausedUntil(...string) time.Time { return time.Time{} }
+
+func (a resetAPI) RateLimitResetAt(resource string) time.Time { return a.resets[resource] }
+
+func TestRepoQuotaRecoversAfterReset(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	base := 5 * time.Second
+	api := resetAPI{resets: map[string]time.Time{ghclient.ResourceCore: now.Add(10 * time.Minute)}}
+	r := Repo{API: api}
+	var plenty Repo
+	plenty.ApplyRuns(RepoRuns{RateLimitRemaining: 5000}, now)
+	normal := plenty.NextPoll(base, nil, 0, now)
+
+	r.Quota.StartPoll()
+	r.ApplyRuns(RepoRuns{RateLimitRemaining: 50}, now)
+	if got := r.NextPoll(base, nil, 0, now); got <= normal {
+		t.Errorf("low quota: next poll in %v, want slower than %v", got, normal)
+	}
+
+	later := now.Add(11 * time.Minute)
+	if hold := r.Quota.Hold(api, base, later); hold != 0 {
+		t.Errorf("held %v after the reset, want a poll", hold)
+	}
+	if got := r.NextPoll(base, nil, 0, later); got != normal {
+		t.Errorf("after the reset: next poll in %v, want %v", got, normal)
+	}
+
+	api.resets[ghclient.ResourceCore] = later.Add(time.Hour)
+	r.Quota.StartPoll()
+	r.ApplyRuns(RepoRuns{RateLimitRemaining: 5000}, later)
+	if r.Quota.Remaining != 5000 {
+		t.Errorf("Remaining = %d, want 5000 from the new window", r.Quota.Remaining)
+	}
+	if got := r.NextPoll(base, nil, 0, later); got != normal {
+		t.Errorf("refilled quota: next poll in %v, want %v", got, normal)
+	}
+}
@@ -0,0 +1,274 @@
+// Package webhook receives GitHub webhook deliveries so the TUI can refresh
+// the moment a check changes instead of waiting for its next poll.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+)
+
+// SecretEnv names the environment variable holding the webhook secret.
+// It is read from the environment rather than a flag so it doesn't show up
+// in process listings.
+const SecretEnv = "GH_OBSERVER_WEBHOOK_SECRET"
+
+// maxPayloadBytes is GitHub's cap on a delivery's size.
+const maxPayloadBytes = 25 << 20
+
+// eventBuffer is how many deliveries may wait for the TUI. Deliveries past
+// that are dropped: the TUI has a refresh pending already, and it fetches
+// current state rather than replaying events, so nothing is lost.
+const eventBuffer = 64
+
+// Events are the X-GitHub-Event types gh-observer reacts to; others are
+// acknowledged and ignored.
+var Events = []string{"check_run", "check_suite", "workflow_run", "workflow_job", "pull_request_review"}
+
+// Event is the part of a delivery needed to decide whether it concerns what
+// is being watched. Fields the event type doesn't carry are zero.
+type Event struct {
+	Name      string // X-GitHub-Event, e.g. "check_run"
+	Action    string // e.g. "completed"
+	Repo      string // owner/name
+	HeadSHA   string
+	PRNumbers []int
+	RunID     int64 // the workflow run, when the event is about one
+}
+
+// pullRequestRef is a PR as listed on check and workflow payloads.
+type pullRequestRef struct {
+	Number int `json:"number"`
+}
+
+// payload selects what Event needs from every supported event type.
+type payload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	CheckRun *struct {
+		HeadSHA      string           `json:"head_sha"`
+		DetailsURL   string           `json:"details_url"`
+		PullRequests []pullRequestRef `json:"pull_requests"`
+	} `json:"check_run"`
+	CheckSuite *struct {
+		HeadSHA      string           `json:"head_sha"`
+		PullRequests []pullRequestRef `json:"pull_requests"`
+	} `json:"check_suite"`
+	WorkflowRun *struct {
+		ID           int64            `json:"id"`
+		HeadSHA      string           `json:"head_sha"`
+		PullRequests []pullRequestRef `json:"pull_requests"`
+	} `json:"workflow_run"`
+	WorkflowJob *struct {
+		RunID   int64  `json:"run_id"`
+		HeadSHA string `json:"head_sha"`
+	} `json:"workflow_job"`
+	PullRequest *struct {
+		Number int `json:"number"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+// parseEvent builds an Event from a delivery of type name.
+func parseEvent(name string, body []byte) (Event, error) {
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return Event{}, err
+	}
+	ev := Event{Name: name, Action: p.Action, Repo: p.Repository.FullName}
+	prNumbers := func(prs []pullRequestRef) {
+		for _, pr := range prs {
+			ev.PRNumbers = append(ev.PRNumbers, pr.Number)
+		}
+	}
+	switch {
+	case name == "check_run" && p.CheckRun != nil:
+		ev.HeadSHA = p.CheckRun.HeadSHA
+		prNumbers(p.CheckRun.PullRequests)
+		if runID, err := ghclient.ParseRunIDFromURL(p.CheckRun.DetailsURL); err == nil {
+			ev.RunID = runID
+		}
+	case name == "check_suite" && p.CheckSuite != nil:
+		ev.HeadSHA = p.CheckSuite.HeadSHA
+		prNumbers(p.CheckSuite.PullRequests)
+	case name == "workflow_run" && p.WorkflowRun != nil:
+		ev.HeadSHA, ev.RunID = p.WorkflowRun.HeadSHA, p.WorkflowRun.ID
+		prNumbers(p.WorkflowRun.PullRequests)
+	case name == "workflow_job" && p.WorkflowJob != nil:
+		ev.HeadSHA, ev.RunID = p.WorkflowJob.HeadSHA, p.WorkflowJob.RunID
+	case name == "pull_request_review" && p.PullRequest != nil:
+		ev.HeadSHA = p.PullRequest.Head.SHA
+		ev.PRNumbers = []int{p.PullRequest.Number}
+	default:
+		return Event{}, errors.New("payload is missing its " + name + " object")
+	}
+	return ev, nil
+}
+
+// ErrBadSignature is returned by Verify when a delivery's signature is
+// missing or doesn't match the secret.
+var ErrBadSignature = errors.New("webhook signature mismatch")
+
+// Verify checks an X-Hub-Signature-256 header ("sha256=<hex HMAC>") against
+// body and secret, in constant time.
+func Verify(secret []byte, body []byte, signature string) error {
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrBadSignature
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// Receiver is an http.Handler for webhook deliveries. Verified deliveries
+// of the supported Events are parsed and sent on the Events channel; pings
+// and other event types are acknowledged and dropped. A delivery whose
+// signature doesn't verify is rejected with 401 and never parsed.
+type Receiver struct {
+	secret []byte
+	events chan Event
+}
+
+// NewReceiver returns a Receiver verifying deliveries against secret.
+func NewReceiver(secret string) *Receiver {
+	return &Receiver{secret: []byte(secret), events: make(chan Event, eventBuffer)}
+}
+
+// Events returns the channel verified deliveries arrive on.
+func (r *Receiver) Events() <-chan Event {
+	return r.events
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "unreadable body", http.StatusBadRequest)
+		return
+	}
+	if err := Verify(r.secret, body, req.Header.Get("X-Hub-Signature-256")); err != nil {
+		debug.Log("webhook rejected", "delivery", req.Header.Get("X-GitHub-Delivery"), "err", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	name := req.Header.Get("X-GitHub-Event")
+	if !slices.Contains(Events, name) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ev, err := parseEvent(name, body)
+	if err != nil {
+		debug.Log("webhook payload unparseable", "event", name, "err", err)
+		http.Error(w, "bad payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	select {
+	case r.events <- ev:
+	default:
+		debug.Log("webhook dropped, TUI behind", "event", name)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Listen serves a Receiver on addr (e.g. ":8080") until the returned stop
+// func is called. The listener is bound before Listen returns, so a bad or
+// busy address fails up front.
+func Listen(addr, secret string) (*Receiver, func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	recv := NewReceiver(secret)
+	srv := &http.Server{Handler: recv, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			debug.Log("webhook server stopped", "err", err)
+		}
+	}()
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}
+	return recv, stop, nil
+}
+
+// Hub copies each delivery to every subscriber, so a view drilled into
+// from the repo overview hears the deliveries the overview does. A nil
+// Hub (webhooks off) has no deliveries.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewHub forwards deliveries from events to its subscribers until events
+// is closed. It returns nil for a nil events.
+func NewHub(events <-chan Event) *Hub {
+	if events == nil {
+		return nil
+	}
+	h := &Hub{subs: make(map[chan Event]struct{})}
+	go func() {
+		for ev := range events {
+			h.mu.Lock()
+			for sub := range h.subs {
+				select {
+				case sub <- ev:
+				default:
+					debug.Log("webhook dropped, subscriber behind", "repo", ev.Repo)
+				}
+			}
+			h.mu.Unlock()
+		}
+	}()
+	return h
+}
+
+// Subscribe returns a channel receiving every delivery from now until ctx
+// is done, when it is closed. It returns nil on a nil Hub.
+func (h *Hub) Subscribe(ctx context.Context) <-chan Event {
+	if h == nil {
+		return nil
+	}
+	sub := make(chan Event, eventBuffer)
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		h.mu.Lock()
+		delete(h.subs, sub)
+		h.mu.Unlock()
+		close(sub)
+	})
+	return sub
+}
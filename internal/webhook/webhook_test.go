@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerify(t *testing.T) {
+	body := `{"action":"completed"}`
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", signature: sign("s3cret", body)},
+		{name: "wrong secret", signature: sign("other", body), wantErr: true},
+		{name: "missing", signature: "", wantErr: true},
+		{name: "sha1 header format", signature: strings.Replace(sign("s3cret", body), "sha256=", "sha1=", 1), wantErr: true},
+		{name: "not hex", signature: "sha256=zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify([]byte("s3cret"), []byte(body), tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		body    string
+		want    Event
+		wantErr bool
+	}{
+		{
+			name:  "check_run",
+			event: "check_run",
+			body: `{"action":"completed","repository":{"full_name":"octo/hello"},"check_run":{"head_sha":"abc",
+				"details_url":"https://github.com/octo/hello/actions/runs/42/job/7","pull_requests":[{"number":5}]}}`,
+			want: Event{Name: "check_run", Action: "completed", Repo: "octo/hello", HeadSHA: "abc", PRNumbers: []int{5}, RunID: 42},
+		},
+		{
+			name:  "check_run from another app",
+			event: "check_run",
+			body:  `{"action":"created","repository":{"full_name":"octo/hello"},"check_run":{"head_sha":"abc","details_url":"https://ci.example.com/1"}}`,
+			want:  Event{Name: "check_run", Action: "created", Repo: "octo/hello", HeadSHA: "abc"},
+		},
+		{
+			name:  "check_suite",
+			event: "check_suite",
+			body:  `{"action":"requested","repository":{"full_name":"octo/hello"},"check_suite":{"head_sha":"abc","pull_requests":[{"number":5},{"number":6}]}}`,
+			want:  Event{Name: "check_suite", Action: "requested", Repo: "octo/hello", HeadSHA: "abc", PRNumbers: []int{5, 6}},
+		},
+		{
+			name:  "workflow_run",
+			event: "workflow_run",
+			body:  `{"action":"in_progress","repository":{"full_name":"octo/hello"},"workflow_run":{"id":42,"head_sha":"abc","pull_requests":[]}}`,
+			want:  Event{Name: "workflow_run", Action: "in_progress", Repo: "octo/hello", HeadSHA: "abc", RunID: 42},
+		},
+		{
+			name:  "workflow_job",
+			event: "workflow_job",
+			body:  `{"action":"queued","repository":{"full_name":"octo/hello"},"workflow_job":{"run_id":42,"head_sha":"abc"}}`,
+			want:  Event{Name: "workflow_job", Action: "queued", Repo: "octo/hello", HeadSHA: "abc", RunID: 42},
+		},
+		{
+			name:  "pull_request_review",
+			event: "pull_request_review",
+			body:  `{"action":"submitted","repository":{"full_name":"octo/hello"},"pull_request":{"number":5,"head":{"sha":"abc"}}}`,
+			want:  Event{Name: "pull_request_review", Action: "submitted", Repo: "octo/hello", HeadSHA: "abc", PRNumbers: []int{5}},
+		},
+		{name: "missing object", event: "workflow_job", body: `{"action":"queued"}`, wantErr: true},
+		{name: "invalid JSON", event: "check_run", body: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEvent(tt.event, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiverServeHTTP(t *testing.T) {
+	const secret = "s3cret"
+	runBody := `{"action":"completed","repository":{"full_name":"octo/hello"},"workflow_run":{"id":42,"head_sha":"abc"}}`
+	tests := []struct {
+		name       string
+		method     string
+		event      string
+		body       string
+		signature  string
+		wantStatus int
+		wantEvent  bool
+	}{
+		{name: "verified delivery", event: "workflow_run", body: runBody, signature: sign(secret, runBody), wantStatus: http.StatusAccepted, wantEvent: true},
+		{name: "bad signature", event: "workflow_run", body: runBody, signature: sign("other", runBody), wantStatus: http.StatusUnauthorized},
+		{name: "ping", event: "ping", body: `{"zen":"hi"}`, signature: sign(secret, `{"zen":"hi"}`), wantStatus: http.StatusNoContent},
+		{name: "unsupported event", event: "push", body: runBody, signature: sign(secret, runBody), wantStatus: http.StatusNoContent},
+		{name: "bad payload", event: "workflow_run", body: `{}`, signature: sign(secret, `{}`), wantStatus: http.StatusBadRequest},
+		{name: "GET", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recv := NewReceiver(secret)
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-Hub-Signature-256", tt.signature)
+			rec := httptest.NewRecorder()
+			recv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			select {
+			case ev := <-recv.Events():
+				if !tt.wantEvent {
+					t.Errorf("unexpected event %+v", ev)
+				} else if ev.RunID != 42 || ev.Repo != "octo/hello" {
+					t.Errorf("event = %+v, want run 42 on octo/hello", ev)
+				}
+			default:
+				if tt.wantEvent {
+					t.Error("no event delivered")
+				}
+			}
+		})
+	}
+}
+
+func TestHub(t *testing.T) {
+	if sub := (*Hub)(nil).Subscribe(context.Background()); sub != nil {
+		t.Error("nil hub subscription isn't nil")
+	}
+
+	events := make(chan Event)
+	hub := NewHub(events)
+	overview := hub.Subscribe(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	view := hub.Subscribe(ctx)
+
+	ev := Event{Name: "check_run", Repo: "octo/hello"}
+	events <- ev
+	for name, sub := range map[string]<-chan Event{"overview": overview, "view": view} {
+		select {
+		case got := <-sub:
+			if !reflect.DeepEqual(got, ev) {
+				t.Errorf("%s got %+v, want %+v", name, got, ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s got nothing", name)
+		}
+	}
+
+	// A cancelled subscription is closed and no longer fed.
+	cancel()
+	select {
+	case _, ok := <-view:
+		if ok {
+			t.Error("cancelled subscription still receiving")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancelled subscription not closed")
+	}
+	events <- ev
+	select {
+	case <-overview:
+	case <-time.After(time.Second):
+		t.Fatal("overview lost its delivery after the view left")
+	}
+}
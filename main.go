@@ -3,16 +3,21 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/fini-net/gh-observer/internal/config"
 	"github.com/fini-net/gh-observer/internal/debug"
+	"github.com/fini-net/gh-observer/internal/eventlog"
 	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/notify"
 	"github.com/fini-net/gh-observer/internal/timing"
 	"github.com/fini-net/gh-observer/internal/tui"
+	"github.com/fini-net/gh-observer/internal/webhook"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -25,10 +30,23 @@ func main() {
 
 var quickFlag bool
 var debugFlag bool
-var repoFlag string
+var repoFlags []string
+var orgFlag string
+var topicFlag string
+var mineFlag bool
+var reviewRequestedFlag bool
+var recordFlag string
+var replayFlag string
+var replaySpeedFlag float64
+var webhookListenFlag string
+var authorFlag string
+var labelFlags []string
+var baseFlag string
+var noDraftsFlag bool
+var notifyFlags []string
 
 // repoFlagAutoSentinel is the NoOptDefVal for --repo: when the user passes
-// --repo with no value, pflag fills repoFlag with this sentinel so we can
+// --repo with no value, pflag fills repoFlags with this sentinel so we can
 // distinguish "no value given (auto-detect)" from "value given explicitly".
 //
 // The required properties are (a) not parseable as "owner/repo" or a
@@ -42,14 +60,27 @@ const repoFlagAutoSentinel = "_"
 func init() {
 	rootCmd.Flags().BoolVarP(&quickFlag, "quick", "q", false, "Skip fetching historical average runtimes")
 	rootCmd.Flags().BoolVarP(&debugFlag, "debug", "d", false, "Log suppressed errors and internal state to a file")
-	rootCmd.Flags().StringVar(&repoFlag, "repo", "", "Watch all active workflows on a repo persistently (owner/repo or URL; bare --repo auto-detects from current git remote)")
-	// Allow `--repo` with no value: pflag fills repoFlag with this sentinel
+	rootCmd.Flags().StringSliceVar(&repoFlags, "repo", nil, "Watch all active workflows on one or more repos persistently (owner/repo or URL; bare --repo auto-detects from current git remote)")
+	// Allow `--repo` with no value: pflag fills repoFlags with this sentinel
 	// so resolveRepoArg can distinguish "no value given (auto-detect)" from
 	// "value given explicitly". The sentinel must be not parseable as
 	// owner/repo or a GitHub URL and not something a user would type on
 	// purpose; ParseRepoArg explicitly rejects all-underscore segments so
 	// "_" works and `--repo _` errors cleanly.
 	rootCmd.Flags().Lookup("repo").NoOptDefVal = repoFlagAutoSentinel
+	rootCmd.Flags().StringVar(&orgFlag, "org", "", "Watch all active workflows across an organization's repos persistently")
+	rootCmd.Flags().StringVar(&topicFlag, "topic", "", "With --org, only repos tagged with this topic")
+	rootCmd.Flags().BoolVar(&mineFlag, "mine", false, "Watch your open PRs across every repo persistently")
+	rootCmd.Flags().BoolVar(&reviewRequestedFlag, "review-requested", false, "With --mine, also watch open PRs awaiting your review")
+	rootCmd.Flags().StringVar(&recordFlag, "record", "", "Record all GitHub API traffic (token scrubbed) to a directory for later --replay")
+	rootCmd.Flags().StringVar(&replayFlag, "replay", "", "Replay a session recorded with --record instead of contacting GitHub")
+	rootCmd.Flags().Float64Var(&replaySpeedFlag, "replay-speed", 1, "Speed multiplier for --replay (e.g. 10 plays a recording ten times faster)")
+	rootCmd.Flags().StringVar(&authorFlag, "author", "", "With --repo or --org, only PRs by and runs triggered by this login (@me for yourself)")
+	rootCmd.Flags().StringSliceVar(&labelFlags, "label", nil, "With --repo, --org or --mine, only PRs with any of these labels (repeatable)")
+	rootCmd.Flags().StringVar(&baseFlag, "base", "", "With --repo, --org or --mine, only PRs targeting and runs on this branch")
+	rootCmd.Flags().BoolVar(&noDraftsFlag, "no-drafts", false, "With --repo, --org or --mine, hide draft PRs")
+	rootCmd.Flags().StringSliceVar(&notifyFlags, "notify", nil, "Notify through these backends (bell, osc9, osc777, desktop, or none), overriding notify.backends in the config")
+	rootCmd.Flags().StringVar(&webhookListenFlag, "webhook-listen", "", "Refresh on GitHub webhook deliveries to this address (e.g. :8080) instead of polling; secret from $"+webhook.SecretEnv)
 }
 
 var rootCmd = &cobra.Command{
@@ -69,10 +100,42 @@ Use --repo to persistently watch all active workflows on a repository:
   gh observer --repo              # auto-detect from current git remote
   gh observer --repo owner/repo
   gh observer --repo https://github.com/owner/repo
+  gh observer --repo --author @me --base main   # my PRs and main-branch runs
+
+Pass several repos, or --org, to watch them together in one dashboard with
+a collapsible section per repo:
+  gh observer --repo owner/api owner/web owner/worker
+  gh observer --org owner --topic backend
+
+Use --mine to watch your own open PRs wherever they are, and add
+--review-requested for the PRs waiting on your review too:
+  gh observer --mine --review-requested
+
+Use --record to capture a session's API traffic, and --replay to play it
+back later (for example, attached to a bug report):
+  gh observer 123 --record /tmp/pr-123
+  gh observer --replay /tmp/pr-123 --replay-speed 10
+
+Use --notify to be told when checks finish or first fail, a Copilot
+review lands or a main branch run fails, by terminal bell, OSC 9/777
+terminal notification or (on Linux) desktop notification:
+  gh observer 123 --notify bell,desktop
+
+Use --webhook-listen to refresh as soon as GitHub delivers a check_run,
+check_suite, workflow_run, workflow_job or pull_request_review webhook,
+polling only every webhook_reconcile_interval. Deliveries must be signed
+with the secret in $GH_OBSERVER_WEBHOOK_SECRET:
+  GH_OBSERVER_WEBHOOK_SECRET=... gh observer 123 --webhook-listen :8080
 
 If installed via go install rather than as a gh extension, replace
 "gh observer" with "gh-observer" in the examples above.`,
-	Args: cobra.MaximumNArgs(1),
+	// Bare --repo takes any number of repos as positionals; see run.
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("repo") {
+			return nil
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		exitCode := run(cmd, args)
 		os.Exit(exitCode)
@@ -85,7 +148,7 @@ type runMode int
 const (
 	modePR   runMode = iota // Watch a PR's checks
 	modeRun                 // Watch an Actions workflow run
-	modeRepo                // Watch all active workflows on a repo persistently
+	modeRepo                // Watch all active workflows on one or more repos persistently
 )
 
 // runArgs holds the parsed arguments for either mode.
@@ -95,6 +158,17 @@ type runArgs struct {
 	repo     string
 	prNumber int
 	runID    int64
+	filter   ghclient.RepoFilter
+
+	// Repo mode only: the repos to watch (owner/repo above is the first),
+	// or the org (and topic) to find them in once a session exists. With
+	// mine there are no fixed repos: a search finds the viewer's PRs (and,
+	// with reviewRequested, those awaiting their review) on every poll.
+	repos           []ghclient.RepoRef
+	org             string
+	topic           string
+	mine            bool
+	reviewRequested bool
 }
 
 func run(cmd *cobra.Command, args []string) int {
@@ -109,32 +183,85 @@ func run(cmd *cobra.Command, args []string) int {
 		fmt.Fprintf(os.Stderr, "Debug log: %s\n", debug.LogPath())
 	}
 
-	repoMode := cmd.Flags().Changed("repo")
-	bareRepoFlag := repoMode && repoFlag == repoFlagAutoSentinel
+	orgMode := orgFlag != ""
+	bareRepoFlag := cmd.Flags().Changed("repo") && slices.Equal(repoFlags, []string{repoFlagAutoSentinel})
 
 	// Validate flag combinations early.
 	//
 	// With NoOptDefVal set on --repo, a bare token after `--repo` is not
 	// consumed by the flag (only `--repo=VALUE` is). So accept the form
-	// `gh-observer --repo owner/repo` by treating a single trailing
-	// positional as the repo value when --repo was given bare. The
-	// `--repo=VALUE` form still rejects positionals.
-	if bareRepoFlag && len(args) == 1 {
-		repoFlag = args[0]
+	// `gh-observer --repo owner/repo [owner/other...]` by treating the
+	// trailing positionals as the repo values when --repo was given bare.
+	// The `--repo=VALUE` form still rejects positionals.
+	if bareRepoFlag && len(args) > 0 {
+		repoFlags = args
 		args = nil
 	}
+	if topicFlag != "" && !orgMode {
+		fmt.Fprintf(os.Stderr, "Error: --topic requires --org\n")
+		return 1
+	}
+	if orgMode && cmd.Flags().Changed("repo") {
+		fmt.Fprintf(os.Stderr, "Error: --org cannot be used with --repo\n")
+		return 1
+	}
+	if reviewRequestedFlag && !mineFlag {
+		fmt.Fprintf(os.Stderr, "Error: --review-requested requires --mine\n")
+		return 1
+	}
+	if mineFlag && (orgMode || cmd.Flags().Changed("repo")) {
+		fmt.Fprintf(os.Stderr, "Error: --mine cannot be used with --repo or --org\n")
+		return 1
+	}
+	if mineFlag && authorFlag != "" {
+		fmt.Fprintf(os.Stderr, "Error: --mine already picks the author; drop --author\n")
+		return 1
+	}
+	// --org and --mine are repo mode over the org's repos or wherever the
+	// viewer's PRs are; the checks below apply to all three.
+	repoMode := cmd.Flags().Changed("repo") || orgMode || mineFlag
 	if repoMode && len(args) > 0 {
-		fmt.Fprintf(os.Stderr, "Error: --repo flag cannot be used with positional arguments\n")
+		fmt.Fprintf(os.Stderr, "Error: --repo, --org and --mine flags cannot be used with positional arguments\n")
+		return 1
+	}
+	if len(repoFlags) > ghclient.MaxDashboardRepos {
+		fmt.Fprintf(os.Stderr, "Error: --repo takes at most %d repos\n", ghclient.MaxDashboardRepos)
 		return 1
 	}
-	if repoMode && quickFlag {
-		fmt.Fprintf(os.Stderr, "Error: --repo flag cannot be used with --quick\n")
+	repoFilter := ghclient.RepoFilter{Author: authorFlag, Labels: labelFlags, Base: baseFlag, NoDrafts: noDraftsFlag}
+	if !repoMode && !repoFilter.IsZero() {
+		fmt.Fprintf(os.Stderr, "Error: --author, --label, --base and --no-drafts require --repo, --org or --mine\n")
 		return 1
 	}
 	if repoMode && !term.IsTerminal(int(os.Stdout.Fd())) {
 		fmt.Fprintf(os.Stderr, "Error: --repo flag requires an interactive terminal\n")
 		return 1
 	}
+	if recordFlag != "" && (orgMode || mineFlag || len(repoFlags) > 1) {
+		fmt.Fprintf(os.Stderr, "Error: --record supports watching a single repo, not several, an --org or --mine\n")
+		return 1
+	}
+	if replayFlag != "" && recordFlag != "" {
+		fmt.Fprintf(os.Stderr, "Error: --record cannot be used with --replay\n")
+		return 1
+	}
+	if replayFlag != "" && (repoMode || len(args) > 0) {
+		fmt.Fprintf(os.Stderr, "Error: --replay takes what to watch from the recording; drop --repo, --org, --mine and positional arguments\n")
+		return 1
+	}
+	if webhookListenFlag != "" && replayFlag != "" {
+		fmt.Fprintf(os.Stderr, "Error: --webhook-listen cannot be used with --replay\n")
+		return 1
+	}
+	if webhookListenFlag != "" && !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintf(os.Stderr, "Error: --webhook-listen requires an interactive terminal\n")
+		return 1
+	}
+	webhookSecret := os.Getenv(webhook.SecretEnv)
+	if webhookListenFlag != "" && webhookSecret == "" {
+		fmt.Fprintf(os.Stderr, "Error: --webhook-listen requires the webhook secret in $%s\n", webhook.SecretEnv)
+		return 1
+	}
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -151,41 +278,162 @@ func run(cmd *cobra.Command, args []string) int {
 		cfg.Colors.Queued,
 	)
 
-	// Handle repo mode up front: it has its own arg resolution and entry point.
-	if repoMode {
-		owner, repo, err := resolveRepoArg(repoFlag)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			return 1
-		}
-		return runRepoMode(ctx, cfg, styles, owner, repo)
+	if replayFlag != "" {
+		return runReplay(ctx, cfg, styles)
 	}
 
-	// Parse arguments
-	parsed, err := parseArgs(args)
+	notifier, err := newNotifier(cmd, cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
 
+	// Repo mode has its own arg resolution; the other modes parse the
+	// positional argument.
+	var parsed runArgs
+	switch {
+	case mineFlag:
+		parsed = runArgs{mode: modeRepo, mine: true, reviewRequested: reviewRequestedFlag, filter: repoFilter}
+	case orgMode:
+		parsed = runArgs{mode: modeRepo, org: orgFlag, topic: topicFlag, filter: repoFilter}
+	case repoMode:
+		repos, err := resolveRepoArgs(repoFlags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		parsed = runArgs{mode: modeRepo, owner: repos[0].Owner, repo: repos[0].Name, repos: repos, filter: repoFilter}
+	default:
+		parsed, err = parseArgs(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
 	// Get GitHub token
 	token, err := ghclient.GetToken()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get GitHub token: %v\n", err)
 		return 1
 	}
+	session, closeSession, err := newSession(token, parsed, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create GitHub session: %v\n", err)
+		return 1
+	}
+	defer closeSession()
+
+	if parsed.org != "" {
+		if parsed.repos, err = findOrgRepos(ctx, session, parsed.org, parsed.topic); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
 
+	var events <-chan webhook.Event
+	if webhookListenFlag != "" {
+		recv, stop, err := webhook.Listen(webhookListenFlag, webhookSecret)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to listen for webhooks: %v\n", err)
+			return 1
+		}
+		defer stop()
+		events = recv.Events()
+	}
+
+	return dispatchMode(ctx, session, parsed, cfg, styles, events, notifier)
+}
+
+// newNotifier builds the notifier from the notify config, --notify
+// replacing its backends. It is nil when no backend is picked.
+func newNotifier(cmd *cobra.Command, cfg *config.Config) (*notify.Notifier, error) {
+	names := cfg.Notify.Backends
+	if cmd.Flags().Changed("notify") {
+		names = notifyFlags
+	}
+	backends, err := notify.ParseBackends(names)
+	if err != nil {
+		return nil, err
+	}
+	triggers, err := notify.ParseTriggers(cfg.Notify.Triggers)
+	if err != nil {
+		return nil, err
+	}
+	if len(backends) == 0 {
+		return nil, nil
+	}
+	return notify.New(backends, triggers, cfg.Notify.MainBranches), nil
+}
+
+// dispatchMode dispatches to the entry point for the parsed mode. events
+// carries webhook deliveries, or is nil without --webhook-listen; notifier
+// is nil without notifications.
+func dispatchMode(ctx context.Context, api ghclient.API, parsed runArgs, cfg *config.Config, styles tui.Styles, events <-chan webhook.Event, notifier *notify.Notifier) int {
 	switch parsed.mode {
 	case modePR:
-		return runPRMode(ctx, token, parsed, cfg, styles)
+		return runPRMode(ctx, api, parsed, cfg, styles, events, notifier)
 	case modeRun:
-		return runActionsMode(ctx, token, parsed, cfg, styles)
+		return runActionsMode(ctx, api, parsed, cfg, styles, events, notifier)
+	case modeRepo:
+		return runRepoMode(ctx, api, cfg, styles, parsed, events, notifier)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown mode\n")
 		return 1
 	}
 }
 
+// resolveRepoArgs resolves each --repo value (see resolveRepoArg),
+// dropping repeats.
+func resolveRepoArgs(vals []string) ([]ghclient.RepoRef, error) {
+	var repos []ghclient.RepoRef
+	for _, val := range vals {
+		owner, repo, err := resolveRepoArg(val)
+		if err != nil {
+			return nil, err
+		}
+		ref := ghclient.RepoRef{Owner: owner, Name: repo}
+		if !slices.Contains(repos, ref) {
+			repos = append(repos, ref)
+		}
+	}
+	return repos, nil
+}
+
+// findOrgRepos lists the repos --org (and --topic) selects, most recently
+// updated first, warning when MaxDashboardRepos leaves some out.
+func findOrgRepos(ctx context.Context, api ghclient.API, org, topic string) ([]ghclient.RepoRef, error) {
+	repos, total, err := api.FindOrgRepos(ctx, org, topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repos in %s: %v", org, err)
+	}
+	if len(repos) == 0 {
+		if topic != "" {
+			return nil, fmt.Errorf("no repos in %s with topic %s", org, topic)
+		}
+		return nil, fmt.Errorf("no repos in %s", org)
+	}
+	if total > len(repos) {
+		fmt.Fprintf(os.Stderr, "Watching the %d most recently updated of %d repos in %s\n", len(repos), total, org)
+	}
+	return repos, nil
+}
+
+// openEventLog opens the recent-events state file. Repo mode still runs
+// when it can't be read, without keeping events, since they're a
+// convenience rather than what the user asked to watch.
+func openEventLog() *eventlog.Log {
+	path, err := eventlog.DefaultPath()
+	if err == nil {
+		var log *eventlog.Log
+		if log, err = eventlog.Open(path); err == nil {
+			return log
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Recent events won't be kept: %v\n", err)
+	return nil
+}
+
 // resolveRepoArg resolves the owner/repo from the --repo flag value.
 // If the value is empty or the auto-detect sentinel (passed by pflag when
 // --repo is given with no value), it auto-detects the current repo from the
@@ -240,16 +488,17 @@ func parseArgs(args []string) (runArgs, error) {
 }
 
 // runPRMode handles watching a PR's checks.
-func runPRMode(ctx context.Context, token string, parsed runArgs, cfg *config.Config, styles tui.Styles) int {
+func runPRMode(ctx context.Context, api ghclient.API, parsed runArgs, cfg *config.Config, styles tui.Styles, events <-chan webhook.Event, notifier *notify.Notifier) int {
 	owner, repo, prNumber := parsed.owner, parsed.repo, parsed.prNumber
 
 	// Check if running in a terminal
 	if !term.IsTerminal(int(os.Stdout.Fd())) {
-		return runSnapshot(ctx, token, owner, repo, prNumber, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations(), cfg.WaitForCopilot)
+		return runSnapshot(ctx, api, owner, repo, prNumber, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations(), cfg.WaitForCopilot)
 	}
 
 	// Create model
-	model := tui.NewModel(ctx, token, owner, repo, prNumber, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations(), cfg.WaitForCopilot, cfg.CopilotMaxWait, cfg.CopilotPollInterval, cfg.CopilotInitialDelay)
+	model := tui.NewModel(ctx, api, owner, repo, prNumber, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations(), cfg.WaitForCopilot, cfg.CopilotMaxWait, cfg.CopilotPollInterval, cfg.CopilotInitialDelay).
+		WithWebhooks(events, cfg.WebhookReconcileInterval).WithNotifier(notifier)
 
 	// Run TUI
 	p := tea.NewProgram(model)
@@ -267,16 +516,17 @@ func runPRMode(ctx context.Context, token string, parsed runArgs, cfg *config.Co
 }
 
 // runActionsMode handles watching an Actions workflow run.
-func runActionsMode(ctx context.Context, token string, parsed runArgs, cfg *config.Config, styles tui.Styles) int {
+func runActionsMode(ctx context.Context, api ghclient.API, parsed runArgs, cfg *config.Config, styles tui.Styles, events <-chan webhook.Event, notifier *notify.Notifier) int {
 	owner, repo, runID := parsed.owner, parsed.repo, parsed.runID
 
 	// Check if running in a terminal
 	if !term.IsTerminal(int(os.Stdout.Fd())) {
-		return runRunSnapshot(ctx, owner, repo, runID, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations())
+		return runRunSnapshot(ctx, api, owner, repo, runID, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations())
 	}
 
 	// Create run model
-	model := tui.NewRunModel(ctx, token, owner, repo, runID, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations())
+	model := tui.NewRunModel(ctx, api, owner, repo, runID, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag, cfg.PresumedAveragesDurations()).
+		WithWebhooks(events, cfg.WebhookReconcileInterval).WithNotifier(notifier)
 
 	// Run TUI
 	p := tea.NewProgram(model)
@@ -293,31 +543,76 @@ func runActionsMode(ctx context.Context, token string, parsed runArgs, cfg *conf
 	return 0
 }
 
-// runRepoMode handles persistent watching of all active workflows on a repo.
-// It is always interactive (snapshot mode is rejected earlier in run()).
-func runRepoMode(ctx context.Context, cfg *config.Config, styles tui.Styles, owner, repo string) int {
-	token, err := ghclient.GetToken()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get GitHub token: %v\n", err)
-		return 1
+// runRepoMode handles persistent watching of all active workflows on one
+// repo, on several in a dashboard, or of the viewer's PRs wherever they
+// are (--mine). It is always interactive (snapshot mode is rejected
+// earlier in run()).
+func runRepoMode(ctx context.Context, api ghclient.API, cfg *config.Config, styles tui.Styles, parsed runArgs, events <-chan webhook.Event, notifier *notify.Notifier) int {
+	eventLog := openEventLog()
+	// The overview and any view drilled into from it each get every
+	// delivery.
+	hub := webhook.NewHub(events)
+	events = hub.Subscribe(ctx)
+	var overview tui.Overview
+	switch {
+	case parsed.mine:
+		dash := tui.NewMineDashboardModel(
+			ctx, api, parsed.reviewRequested,
+			cfg.RepoRefreshInterval, styles, cfg.EnableLinks,
+			cfg.FadeSuccess, cfg.FadeFailure,
+		).WithWebhooks(events, cfg.WebhookReconcileInterval).WithFilter(parsed.filter).WithEventLog(eventLog).
+			WithNotifier(notifier)
+		if quickFlag {
+			dash = dash.WithoutAverages()
+		}
+		overview = dash
+	case len(parsed.repos) == 1:
+		model := tui.NewRepoModel(
+			ctx, api, parsed.repos[0].Owner, parsed.repos[0].Name,
+			cfg.RepoRefreshInterval, styles, cfg.EnableLinks,
+			cfg.FadeSuccess, cfg.FadeFailure,
+		).WithWebhooks(events, cfg.WebhookReconcileInterval).WithFilter(parsed.filter).WithEventLog(eventLog).
+			WithNotifier(notifier)
+		if quickFlag {
+			model = model.WithoutAverages()
+		}
+		overview = model
+	default:
+		dash := tui.NewDashboardModel(
+			ctx, api, parsed.repos,
+			cfg.RepoRefreshInterval, styles, cfg.EnableLinks,
+			cfg.FadeSuccess, cfg.FadeFailure,
+		).WithWebhooks(events, cfg.WebhookReconcileInterval).WithFilter(parsed.filter).WithEventLog(eventLog).
+			WithNotifier(notifier)
+		if quickFlag {
+			dash = dash.WithoutAverages()
+		}
+		overview = dash
 	}
 
-	model := tui.NewRepoModel(
-		ctx, token, owner, repo,
-		cfg.RepoRefreshInterval, styles, cfg.EnableLinks,
-		cfg.FadeSuccess, cfg.FadeFailure,
-	)
+	// enter on a selected PR or run pushes the same full view its own mode
+	// would show, notifying and hearing webhooks as that mode would. The
+	// overview keeps polling underneath.
+	openPR := func(ctx context.Context, owner, repo string, prNumber int) tea.Model {
+		return tui.NewModel(ctx, api, owner, repo, prNumber, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag,
+			cfg.PresumedAveragesDurations(), cfg.WaitForCopilot, cfg.CopilotMaxWait, cfg.CopilotPollInterval, cfg.CopilotInitialDelay).
+			WithWebhooks(hub.Subscribe(ctx), cfg.WebhookReconcileInterval).WithNotifier(notifier)
+	}
+	openRun := func(ctx context.Context, owner, repo string, runID int64) tea.Model {
+		return tui.NewRunModel(ctx, api, owner, repo, runID, cfg.RefreshInterval, styles, cfg.EnableLinks, quickFlag,
+			cfg.PresumedAveragesDurations()).
+			WithWebhooks(hub.Subscribe(ctx), cfg.WebhookReconcileInterval).WithNotifier(notifier)
+	}
 
-	p := tea.NewProgram(model)
+	p := tea.NewProgram(tui.NewNavigator(ctx, overview, openPR, openRun))
 	finalModel, err := p.Run()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		return 1
 	}
 
-	// RepoModel.Update can return either a value or pointer RepoModel
-	// (the per-message handlers use pointer receivers), so assert on the
-	// ExitCode method rather than a concrete type to handle both forms.
+	// Assert on the ExitCode method rather than a concrete type so this
+	// holds whatever model wraps the overview.
 	type exitCoder interface {
 		ExitCode() int
 	}
@@ -329,20 +624,14 @@ func runRepoMode(ctx context.Context, cfg *config.Config, styles tui.Styles, own
 }
 
 // runSnapshot prints a one-time snapshot of PR check status (non-interactive mode)
-func runSnapshot(ctx context.Context, token, owner, repo string, prNumber int, enableLinks bool, quick bool, presumedAverages map[string]time.Duration, waitForCopilot bool) int {
-	client, err := ghclient.NewClient(ctx)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create GitHub client: %v\n", err)
-		return 1
-	}
-
-	prInfo, err := ghclient.FetchPRInfo(ctx, client, owner, repo, prNumber)
+func runSnapshot(ctx context.Context, api ghclient.API, owner, repo string, prNumber int, enableLinks bool, quick bool, presumedAverages map[string]time.Duration, waitForCopilot bool) int {
+	prInfo, err := api.FetchPRInfo(ctx, owner, repo, prNumber)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to fetch PR info: %v\n", err)
 		return 1
 	}
 
-	checkRuns, headPushedTime, _, err := ghclient.FetchCheckRunsGraphQL(ctx, token, owner, repo, prNumber)
+	checkRuns, headPushedTime, _, err := api.FetchCheckRuns(ctx, owner, repo, prNumber)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to fetch check runs: %v\n", err)
 		return 1
@@ -361,20 +650,19 @@ func runSnapshot(ctx context.Context, token, owner, repo string, prNumber int, e
 		return 0
 	}
 
-	var jobAverages map[string]time.Duration
+	jobAverages := make(map[string]time.Duration)
 	if !quick {
-		client, err := ghclient.NewClient(ctx)
+		// One batched history query covers every workflow on the PR.
+		_, workflowIDs, err := api.DiscoverWorkflows(ctx, owner, repo, checkRuns, nil, nil)
 		if err == nil {
-			avgs, _, _, err := ghclient.FetchJobAverages(ctx, client, owner, repo, checkRuns, nil, nil)
-			if err == nil {
-				jobAverages = avgs
+			histories, _ := api.FetchWorkflowHistories(ctx, owner, repo, ghclient.WorkflowRefs(checkRuns, workflowIDs))
+			for _, id := range workflowIDs {
+				if history := histories[id]; history != nil {
+					maps.Copy(jobAverages, history.Averages)
+				}
 			}
 		}
 	}
-
-	if jobAverages == nil {
-		jobAverages = make(map[string]time.Duration)
-	}
 	ghclient.ApplyPresumedAverages(jobAverages, checkRuns, presumedAverages)
 
 	widths := tui.CalculateColumnWidths(checkRuns, headPushedTime, jobAverages)
@@ -403,7 +691,7 @@ func runSnapshot(ctx context.Context, token, owner, repo string, prNumber int, e
 
 	// Copilot review snapshot (issue #409)
 	if waitForCopilot {
-		review, _, copilotErr := ghclient.FetchCopilotReview(ctx, token, owner, repo, prNumber, prInfo.HeadSHA)
+		review, _, copilotErr := api.FetchCopilotReview(ctx, owner, repo, prNumber, prInfo.HeadSHA)
 		if copilotErr != nil {
 			fmt.Printf("Copilot: unavailable (%v)\n", copilotErr)
 		} else if review.NotRequested && !review.Stale {
@@ -424,25 +712,14 @@ func runSnapshot(ctx context.Context, token, owner, repo string, prNumber int, e
 }
 
 // runRunSnapshot prints a one-time snapshot of Actions run status (non-interactive mode)
-func runRunSnapshot(ctx context.Context, owner, repo string, runID int64, enableLinks bool, quick bool, presumedAverages map[string]time.Duration) int {
-	token, err := ghclient.GetToken()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to get GitHub token: %v\n", err)
-		return 1
-	}
-	client, err := ghclient.NewClientFromToken(token)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create GitHub client: %v\n", err)
-		return 1
-	}
-
-	runInfo, _, err := ghclient.FetchRunInfo(ctx, client, token, owner, repo, runID)
+func runRunSnapshot(ctx context.Context, api ghclient.API, owner, repo string, runID int64, enableLinks bool, quick bool, presumedAverages map[string]time.Duration) int {
+	runInfo, _, err := api.FetchRunInfo(ctx, owner, repo, runID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to fetch run info: %v\n", err)
 		return 1
 	}
 
-	jobs, _, err := ghclient.FetchRunJobs(ctx, client, owner, repo, runID)
+	jobs, _, err := api.FetchRunJobs(ctx, owner, repo, runID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to fetch jobs: %v\n", err)
 		return 1
@@ -469,7 +746,7 @@ func runRunSnapshot(ctx context.Context, owner, repo string, runID int64, enable
 	var jobAverages map[string]time.Duration
 	if !quick {
 		checkRuns := ghclient.WorkflowJobInfoToCheckRuns(jobs)
-		avgs, _, _, err := ghclient.FetchJobAverages(ctx, client, owner, repo, checkRuns, nil, nil)
+		avgs, _, _, err := api.FetchJobAverages(ctx, owner, repo, checkRuns, nil, nil)
 		if err == nil {
 			jobAverages = avgs
 		}
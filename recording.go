@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/config"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/tui"
+	"golang.org/x/term"
+)
+
+// replayToken authenticates replayed requests. They never leave the
+// process, but the clients insist on a non-empty token.
+const replayToken = "replay"
+
+// recordingModes names each mode in a recording's metadata.
+var recordingModes = map[runMode]string{
+	modePR:   "pr",
+	modeRun:  "run",
+	modeRepo: "repo",
+}
+
+// newSession builds the process's GitHub session. With --record, its
+// traffic also goes to the recording directory; the returned func closes
+// the recording.
+func newSession(token string, parsed runArgs, cfg *config.Config) (ghclient.API, func(), error) {
+	concurrency := ghclient.WithFetchConcurrency(cfg.FetchConcurrency)
+	if recordFlag == "" {
+		session, err := ghclient.NewSession(token, concurrency)
+		return session, func() {}, err
+	}
+	rec, err := ghclient.NewRecorder(recordFlag, token, ghclient.RecordingMeta{
+		Mode:     recordingModes[parsed.mode],
+		Owner:    parsed.owner,
+		Repo:     parsed.repo,
+		PRNumber: parsed.prNumber,
+		RunID:    parsed.runID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start recording: %v", err)
+	}
+	closeRec := func() {
+		if err := rec.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save recording: %v\n", err)
+		}
+	}
+	session, err := ghclient.NewSession(token, concurrency, ghclient.WithRecorder(rec))
+	if err != nil {
+		closeRec()
+		return nil, nil, err
+	}
+	return session, closeRec, nil
+}
+
+// runReplay plays back a --record directory: same mode and target as the
+// recorded session, with every API call answered from the recording.
+func runReplay(ctx context.Context, cfg *config.Config, styles tui.Styles) int {
+	replay, err := ghclient.OpenReplay(replayFlag, replaySpeedFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open recording: %v\n", err)
+		return 1
+	}
+
+	meta := replay.Meta()
+	parsed := runArgs{owner: meta.Owner, repo: meta.Repo, prNumber: meta.PRNumber, runID: meta.RunID,
+		repos: []ghclient.RepoRef{{Owner: meta.Owner, Name: meta.Repo}}}
+	found := false
+	for mode, name := range recordingModes {
+		if name == meta.Mode {
+			parsed.mode, found = mode, true
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "Recording has unknown mode %q\n", meta.Mode)
+		return 1
+	}
+	if parsed.mode == modeRepo && !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintf(os.Stderr, "Error: replaying a --repo recording requires an interactive terminal\n")
+		return 1
+	}
+
+	// Poll as often, in recorded time, as the live session did.
+	cfg.RefreshInterval = time.Duration(float64(cfg.RefreshInterval) / replaySpeedFlag)
+	cfg.RepoRefreshInterval = time.Duration(float64(cfg.RepoRefreshInterval) / replaySpeedFlag)
+
+	session, err := ghclient.NewSession(replayToken,
+		ghclient.WithFetchConcurrency(cfg.FetchConcurrency), ghclient.WithBaseTransport(replay))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create GitHub session: %v\n", err)
+		return 1
+	}
+	return dispatchMode(ctx, session, parsed, cfg, styles, nil, nil)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fini-net/gh-observer/internal/config"
+	"github.com/fini-net/gh-observer/internal/daemon"
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var serveListenFlag string
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListenFlag, "listen", "", "Unix socket path or loopback host:port to serve on (default: serve_listen, else $XDG_RUNTIME_DIR/gh-observer.sock)")
+	serveCmd.Flags().BoolVarP(&debugFlag, "debug", "d", false, "Log suppressed errors and internal state to a file")
+	serveCmd.Flags().StringVar(&authorFlag, "author", "", "On repo targets, only PRs by and runs triggered by this login (@me for yourself)")
+	serveCmd.Flags().StringSliceVar(&labelFlags, "label", nil, "On repo targets, only PRs with any of these labels (repeatable)")
+	serveCmd.Flags().StringVar(&baseFlag, "base", "", "On repo targets, only PRs targeting and runs on this branch")
+	serveCmd.Flags().BoolVar(&noDraftsFlag, "no-drafts", false, "On repo targets, hide draft PRs")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [TARGET...]",
+	Short: "Watch PRs and repos in the background and serve their status as JSON",
+	Long: `Keep watching a set of PRs and repos and serve their current check status
+as JSON over a Unix socket (or a loopback HTTP port), so editors, shell
+prompts, tmux and status bars can share one poller and one rate limit.
+
+A TARGET is owner/repo#123 or a PR URL for one PR, or owner/repo or a repo
+URL for the PRs and runs on the repo that are running or recently
+finished, as --repo shows them (narrowed by --author, --label, --base and
+--no-drafts). Targets listed under serve_targets in the config file are
+watched too.
+
+  gh observer serve octo/hello#12 octo/infra
+  curl --unix-socket "$XDG_RUNTIME_DIR/gh-observer.sock" http://localhost/v1/status
+
+Endpoints:
+  GET /v1/status                   every target's status and a version
+  GET /v1/status?since=VERSION     wait (up to ?timeout=, default 30s) for
+                                   a newer version
+  GET /v1/status?target=KEY        one target, e.g. ?target=octo/hello%2312
+  GET /v1/events                   server-sent events on every change`,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runServe(context.Background(), args))
+	},
+}
+
+// runServe resolves the targets and listen address, then polls and serves
+// until interrupted.
+func runServe(ctx context.Context, args []string) int {
+	if debugFlag {
+		if err := debug.Enable(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to enable debug logging: %v\n", err)
+			return 1
+		}
+		defer debug.Close()
+		fmt.Fprintf(os.Stderr, "Debug log: %s\n", debug.LogPath())
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	targets, err := serveTargets(append(args, cfg.ServeTargets...))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(targets) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: nothing to watch; pass targets or list them under serve_targets in the config\n")
+		return 1
+	}
+
+	addr := serveListenFlag
+	if addr == "" {
+		addr = cfg.ServeListen
+	}
+	if addr == "" {
+		if addr, err = daemon.DefaultSocketPath(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: locating socket directory: %v\n", err)
+			return 1
+		}
+	}
+
+	token, err := ghclient.GetToken()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get GitHub token: %v\n", err)
+		return 1
+	}
+	session, err := ghclient.NewSession(token, ghclient.WithFetchConcurrency(cfg.FetchConcurrency))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create GitHub session: %v\n", err)
+		return 1
+	}
+
+	ln, err := daemon.Listen(addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to listen on %s: %v\n", addr, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Watching %d target(s), serving on %s\n", len(targets), ln.Addr())
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store := daemon.NewStore(targets)
+	go daemon.Watch(ctx, session, store, targets, daemon.Options{
+		PRInterval:   cfg.RefreshInterval,
+		RepoInterval: cfg.RepoRefreshInterval,
+		FadeSuccess:  cfg.FadeSuccess,
+		FadeFailure:  cfg.FadeFailure,
+		Filter:       ghclient.RepoFilter{Author: authorFlag, Labels: labelFlags, Base: baseFlag, NoDrafts: noDraftsFlag},
+	})
+	if err := daemon.Serve(ctx, ln, daemon.NewHandler(store)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serveTargets parses target arguments, dropping duplicates so a target
+// given on the command line and in the config is watched once.
+func serveTargets(args []string) ([]daemon.Target, error) {
+	var targets []daemon.Target
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		t, err := daemon.ParseTarget(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[t.Key()] {
+			seen[t.Key()] = true
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fini-net/gh-observer/internal/debug"
+	ghclient "github.com/fini-net/gh-observer/internal/github"
+	"github.com/fini-net/gh-observer/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var statsWorkflowFlag string
+var statsSinceFlag string
+var statsFormatFlag string
+var statsRepoFlag string
+var statsLimitFlag int
+
+// defaultStatsRunLimit bounds how many runs `stats` inspects. Each run costs
+// one jobs call on top of one list call per 100 runs, so the default keeps a
+// full 30-day report on a busy repo well inside an hour's REST quota.
+const defaultStatsRunLimit = 300
+
+func init() {
+	statsCmd.Flags().StringVar(&statsWorkflowFlag, "workflow", "", "Workflow file name, display name, or ID (default: all workflows)")
+	statsCmd.Flags().StringVar(&statsSinceFlag, "since", "30d", "How far back to look (e.g. 30d, 12w, 72h, or 2026-07-01)")
+	statsCmd.Flags().StringVar(&statsFormatFlag, "format", stats.FormatTable, "Output format: table, csv, or json")
+	statsCmd.Flags().StringVar(&statsRepoFlag, "repo", "", "Repository (owner/repo or URL; default: current git remote)")
+	statsCmd.Flags().IntVar(&statsLimitFlag, "limit", defaultStatsRunLimit, "Maximum number of runs to inspect (newest first)")
+	statsCmd.Flags().BoolVarP(&debugFlag, "debug", "d", false, "Log suppressed errors and internal state to a file")
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show workflow duration, queue, and reliability trends",
+	Long: `Print per-workflow and per-job trends over completed runs: weekly p50/p90
+duration and queue latency, failure rate, success streaks, and how the p50
+moved between the first and last weeks of the window.
+
+  gh observer stats
+  gh observer stats --workflow ci.yml --since 12w
+  gh observer stats --repo owner/repo --format csv > ci.csv`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(runStats(context.Background()))
+	},
+}
+
+// runStats resolves the repo and workflow, fetches completed runs in the
+// window, and writes the aggregated report to stdout.
+func runStats(ctx context.Context) int {
+	if debugFlag {
+		if err := debug.Enable(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to enable debug logging: %v\n", err)
+			return 1
+		}
+		defer debug.Close()
+		fmt.Fprintf(os.Stderr, "Debug log: %s\n", debug.LogPath())
+	}
+
+	switch statsFormatFlag {
+	case stats.FormatTable, stats.FormatCSV, stats.FormatJSON:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --format %q (use table, csv, or json)\n", statsFormatFlag)
+		return 1
+	}
+	if statsLimitFlag <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --limit must be positive\n")
+		return 1
+	}
+
+	now := time.Now()
+	since, err := stats.ParseSince(statsSinceFlag, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	owner, repo, err := resolveRepoArg(statsRepoFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	token, err := ghclient.GetToken()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get GitHub token: %v\n", err)
+		return 1
+	}
+	api, err := ghclient.NewSession(token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create GitHub session: %v\n", err)
+		return 1
+	}
+
+	var workflow ghclient.Workflow
+	if statsWorkflowFlag != "" {
+		workflow, err = api.ResolveWorkflow(ctx, owner, repo, statsWorkflowFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	runs, err := api.FetchStatsRuns(ctx, owner, repo, workflow.ID, since, statsLimitFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to fetch workflow runs: %v\n", err)
+		return 1
+	}
+	if len(runs) == statsLimitFlag {
+		fmt.Fprintf(os.Stderr, "Note: stopped at --limit %d runs; older runs in the window are not included\n", statsLimitFlag)
+	}
+
+	report := stats.Build(runs, since, now)
+	report.Owner, report.Repo, report.Workflow = owner, repo, workflow.Name
+
+	if err := stats.Write(os.Stdout, report, statsFormatFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write stats: %v\n", err)
+		return 1
+	}
+	return 0
+}